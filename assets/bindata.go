@@ -3,6 +3,7 @@
 // Package assets generated by go-bindata.
 // sources:
 // templates/cloudformation/iam_user_osdCcsAdmin.json
+// templates/cloudformation/rosa_network.json
 package assets
 
 import (
@@ -85,6 +86,1133 @@ func templatesCloudformationIam_user_osdccsadminJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesCloudformationRosa_networkJson = []byte(`{
+  "AWSTemplateFormatVersion": "2010-09-09",
+  "Description": "VPC with public and private subnets for Red Hat OpenShift Service on AWS clusters",
+  "Parameters": {
+    "Name": {
+      "Type": "String",
+      "Description": "Name used to tag the resources of the network"
+    },
+    "VpcCidr": {
+      "Type": "String",
+      "Default": "10.0.0.0/16",
+      "Description": "CIDR of the VPC"
+    },
+    "SubnetBits": {
+      "Type": "Number",
+      "Default": "13",
+      "Description": "Number of host bits of each subnet, the VPC is split in 6 subnets"
+    },
+    "AvailabilityZones": {
+      "Type": "String",
+      "Default": "1",
+      "AllowedValues": [
+        "1",
+        "3"
+      ],
+      "Description": "Number of availability zones of the network"
+    },
+    "PrivateLink": {
+      "Type": "String",
+      "Default": "false",
+      "AllowedValues": [
+        "true",
+        "false"
+      ],
+      "Description": "Whether the public subnets can't be used by load balancers"
+    },
+    "Egress": {
+      "Type": "String",
+      "Default": "true",
+      "AllowedValues": [
+        "true",
+        "false"
+      ],
+      "Description": "Whether the private subnets have egress to the internet through NAT gateways"
+    }
+  },
+  "Conditions": {
+    "ThreeAZs": {
+      "Fn::Equals": [
+        {
+          "Ref": "AvailabilityZones"
+        },
+        "3"
+      ]
+    },
+    "HasEgress": {
+      "Fn::Equals": [
+        {
+          "Ref": "Egress"
+        },
+        "true"
+      ]
+    },
+    "HasEgressAndThreeAZs": {
+      "Fn::And": [
+        {
+          "Condition": "HasEgress"
+        },
+        {
+          "Condition": "ThreeAZs"
+        }
+      ]
+    },
+    "PublicLoadBalancers": {
+      "Fn::And": [
+        {
+          "Condition": "HasEgress"
+        },
+        {
+          "Fn::Not": [
+            {
+              "Fn::Equals": [
+                {
+                  "Ref": "PrivateLink"
+                },
+                "true"
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "Resources": {
+    "VPC": {
+      "Type": "AWS::EC2::VPC",
+      "Properties": {
+        "CidrBlock": {
+          "Ref": "VpcCidr"
+        },
+        "EnableDnsSupport": true,
+        "EnableDnsHostnames": true,
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "vpc"
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "InternetGateway": {
+      "Type": "AWS::EC2::InternetGateway",
+      "Condition": "HasEgress",
+      "Properties": {
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "igw"
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "InternetGatewayAttachment": {
+      "Type": "AWS::EC2::VPCGatewayAttachment",
+      "Condition": "HasEgress",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "InternetGatewayId": {
+          "Ref": "InternetGateway"
+        }
+      }
+    },
+    "PublicRouteTable": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "HasEgress",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "public"
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PublicRoute": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasEgress",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "GatewayId": {
+          "Ref": "InternetGateway"
+        }
+      }
+    },
+    "PublicSubnet1": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasEgress",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "3",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "0",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "public",
+                  {
+                    "Fn::Select": [
+                      "0",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Fn::If": [
+              "PublicLoadBalancers",
+              {
+                "Key": "kubernetes.io/role/elb",
+                "Value": "1"
+              },
+              {
+                "Ref": "AWS::NoValue"
+              }
+            ]
+          }
+        ]
+      }
+    },
+    "PublicSubnet1RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasEgress",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet1"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway1EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasEgress",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc",
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "0",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "NatGateway1": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasEgress",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway1EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet1"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "0",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateSubnet1": {
+      "Type": "AWS::EC2::Subnet",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "0",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "0",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "0",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable1": {
+      "Type": "AWS::EC2::RouteTable",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "0",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute1": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasEgress",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable1"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway1"
+        }
+      }
+    },
+    "PrivateSubnet1RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet1"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable1"
+        }
+      }
+    },
+    "PublicSubnet2": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "4",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "1",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "public",
+                  {
+                    "Fn::Select": [
+                      "1",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Fn::If": [
+              "PublicLoadBalancers",
+              {
+                "Key": "kubernetes.io/role/elb",
+                "Value": "1"
+              },
+              {
+                "Ref": "AWS::NoValue"
+              }
+            ]
+          }
+        ]
+      }
+    },
+    "PublicSubnet2RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet2"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway2EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasEgressAndThreeAZs",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc",
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "1",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "NatGateway2": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway2EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet2"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "1",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateSubnet2": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "1",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "1",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "1",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable2": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "1",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute2": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable2"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway2"
+        }
+      }
+    },
+    "PrivateSubnet2RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet2"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable2"
+        }
+      }
+    },
+    "PublicSubnet3": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "5",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "2",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "public",
+                  {
+                    "Fn::Select": [
+                      "2",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Fn::If": [
+              "PublicLoadBalancers",
+              {
+                "Key": "kubernetes.io/role/elb",
+                "Value": "1"
+              },
+              {
+                "Ref": "AWS::NoValue"
+              }
+            ]
+          }
+        ]
+      }
+    },
+    "PublicSubnet3RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet3"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway3EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasEgressAndThreeAZs",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc",
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "2",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "NatGateway3": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway3EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet3"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "nat",
+                  {
+                    "Fn::Select": [
+                      "2",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateSubnet3": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            "2",
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                "6",
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Fn::Select": [
+            "2",
+            {
+              "Fn::GetAZs": ""
+            }
+          ]
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "2",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable3": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Join": [
+                "-",
+                [
+                  {
+                    "Ref": "Name"
+                  },
+                  "private",
+                  {
+                    "Fn::Select": [
+                      "2",
+                      {
+                        "Fn::GetAZs": ""
+                      }
+                    ]
+                  }
+                ]
+              ]
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute3": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasEgressAndThreeAZs",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable3"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway3"
+        }
+      }
+    },
+    "PrivateSubnet3RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "ThreeAZs",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet3"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable3"
+        }
+      }
+    },
+    "S3Endpoint": {
+      "Type": "AWS::EC2::VPCEndpoint",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "VpcEndpointType": "Gateway",
+        "ServiceName": {
+          "Fn::Join": [
+            "",
+            [
+              "com.amazonaws.",
+              {
+                "Ref": "AWS::Region"
+              },
+              ".s3"
+            ]
+          ]
+        },
+        "RouteTableIds": {
+          "Fn::If": [
+            "ThreeAZs",
+            [
+              {
+                "Ref": "PrivateRouteTable1"
+              },
+              {
+                "Ref": "PrivateRouteTable2"
+              },
+              {
+                "Ref": "PrivateRouteTable3"
+              }
+            ],
+            [
+              {
+                "Ref": "PrivateRouteTable1"
+              }
+            ]
+          ]
+        }
+      }
+    }
+  },
+  "Outputs": {
+    "VpcId": {
+      "Description": "ID of the VPC",
+      "Value": {
+        "Ref": "VPC"
+      }
+    },
+    "PrivateSubnetIds": {
+      "Description": "Comma-separated IDs of the private subnets",
+      "Value": {
+        "Fn::If": [
+          "ThreeAZs",
+          {
+            "Fn::Join": [
+              ",",
+              [
+                {
+                  "Ref": "PrivateSubnet1"
+                },
+                {
+                  "Ref": "PrivateSubnet2"
+                },
+                {
+                  "Ref": "PrivateSubnet3"
+                }
+              ]
+            ]
+          },
+          {
+            "Ref": "PrivateSubnet1"
+          }
+        ]
+      }
+    },
+    "PublicSubnetIds": {
+      "Condition": "HasEgress",
+      "Description": "Comma-separated IDs of the public subnets",
+      "Value": {
+        "Fn::If": [
+          "ThreeAZs",
+          {
+            "Fn::Join": [
+              ",",
+              [
+                {
+                  "Ref": "PublicSubnet1"
+                },
+                {
+                  "Ref": "PublicSubnet2"
+                },
+                {
+                  "Ref": "PublicSubnet3"
+                }
+              ]
+            ]
+          },
+          {
+            "Ref": "PublicSubnet1"
+          }
+        ]
+      }
+    }
+  }
+}
+`)
+
+func templatesCloudformationRosa_networkJsonBytes() ([]byte, error) {
+	return _templatesCloudformationRosa_networkJson, nil
+}
+
+func templatesCloudformationRosa_networkJson() (*asset, error) {
+	bytes, err := templatesCloudformationRosa_networkJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cloudformation/rosa_network.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -138,6 +1266,7 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"templates/cloudformation/iam_user_osdCcsAdmin.json": templatesCloudformationIam_user_osdccsadminJson,
+	"templates/cloudformation/rosa_network.json":         templatesCloudformationRosa_networkJson,
 }
 
 // AssetDir returns the file names below a certain
@@ -184,6 +1313,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": &bintree{nil, map[string]*bintree{
 		"cloudformation": &bintree{nil, map[string]*bintree{
 			"iam_user_osdCcsAdmin.json": &bintree{templatesCloudformationIam_user_osdccsadminJson, map[string]*bintree{}},
+			"rosa_network.json":         &bintree{templatesCloudformationRosa_networkJson, map[string]*bintree{}},
 		}},
 	}},
 }}
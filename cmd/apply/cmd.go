@@ -0,0 +1,354 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/cmd/create/admin"
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	file   string
+	prune  bool
+	dryRun bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply the day-2 resources of a cluster from a file",
+	Long: "Compare the machine pools, identity providers, ingresses, tuning configs and users " +
+		"defined in a file with the ones of the cluster, print the changes and apply them. The " +
+		"file can either be of kind 'ClusterResources' or a complete cluster definition. Only the " +
+		"types of resources listed in the file are managed.",
+	Example: `  # Apply the resources defined in a file to the cluster named "mycluster"
+  rosa apply -f cluster-resources.yaml -c mycluster
+
+  # Show the changes without applying them
+  rosa apply -f cluster-resources.yaml -c mycluster --dry-run
+
+  # Also delete the resources that are not in the file
+  rosa apply -f cluster-resources.yaml -c mycluster --prune --yes`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	ocm.AddClusterFlag(Cmd)
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"Path to the file that defines the resources of the cluster.",
+	)
+	Cmd.MarkFlagRequired("file")
+	flags.BoolVar(
+		&args.prune,
+		"prune",
+		false,
+		"Delete the resources that are not defined in the file.",
+	)
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Only print the changes, without applying them.",
+	)
+	confirm.AddFlag(flags)
+	arguments.AddProfileFlag(flags)
+	arguments.AddRegionFlag(flags)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	desired, err := clusterspec.LoadResources(args.file)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+	if cluster.State() != cmv1.ClusterStateReady {
+		r.Reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+	if desired.UpgradePolicy != nil {
+		r.Reporter.Warnf("The upgrade policy is not managed by 'rosa apply', " +
+			"use 'rosa upgrade cluster' to schedule upgrades")
+	}
+
+	r.Reporter.Debugf("Loading resources of cluster '%s'", clusterKey)
+	current, err := loadState(r, cluster, desired)
+	if err != nil {
+		r.Reporter.Errorf("Failed to load resources of cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	changes, err := clusterspec.Plan(desired, current, args.prune)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		r.Reporter.Infof("Cluster '%s' is up to date", clusterKey)
+		return
+	}
+
+	fmt.Printf("Changes to cluster '%s':\n", clusterKey)
+	for _, change := range changes {
+		fmt.Print(formatChange(change))
+	}
+	if args.dryRun {
+		return
+	}
+	if !confirm.Confirm("apply %d changes to cluster '%s'", len(changes), clusterKey) {
+		os.Exit(0)
+	}
+
+	for _, change := range changes {
+		err = applyChange(r, cluster, change)
+		if err != nil {
+			r.Reporter.Errorf("Failed to %s %s: %v", change.Action, describeChange(change), err)
+			os.Exit(1)
+		}
+		r.Reporter.Infof("Applied: %s %s", change.Action, describeChange(change))
+	}
+}
+
+// loadState loads the resources of the types that are defined in the desired state.
+func loadState(r *rosa.Runtime, cluster *cmv1.Cluster,
+	desired *clusterspec.Resources) (*clusterspec.State, error) {
+	var err error
+	state := &clusterspec.State{Cluster: cluster}
+	if desired.MachinePools != nil {
+		if ocm.IsHyperShiftCluster(cluster) {
+			state.NodePools, err = r.OCMClient.GetNodePools(cluster.ID())
+		} else {
+			state.MachinePools, err = r.OCMClient.GetMachinePools(cluster.ID())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if desired.IdentityProviders != nil {
+		idps, err := r.OCMClient.GetIdentityProviders(cluster.ID())
+		if err != nil {
+			return nil, err
+		}
+		// The identity provider of the cluster admin is managed with 'rosa create admin'
+		for _, idp := range idps {
+			if idp.Name() != admin.ClusterAdminIDPname {
+				state.IdentityProviders = append(state.IdentityProviders, idp)
+			}
+		}
+	}
+	if desired.Ingresses != nil {
+		state.Ingresses, err = r.OCMClient.GetIngresses(cluster.ID())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if desired.TuningConfigs != nil {
+		state.TuningConfigs, err = r.OCMClient.GetTuningConfigs(cluster.ID())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if desired.Users != nil {
+		state.Users = map[string][]string{}
+		for _, group := range []string{clusterspec.ClusterAdminsGroup, clusterspec.DedicatedAdminsGroup} {
+			users, err := r.OCMClient.GetUsers(cluster.ID(), group)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				if user.ID() != admin.ClusterAdminUsername {
+					state.Users[group] = append(state.Users[group], user.ID())
+				}
+			}
+		}
+	}
+	return state, nil
+}
+
+func describeChange(change clusterspec.Change) string {
+	if change.Kind == clusterspec.KindUser {
+		return fmt.Sprintf("user '%s' in group '%s'", change.Name, change.Group)
+	}
+	return fmt.Sprintf("%s '%s'", change.Kind, change.Name)
+}
+
+func formatChange(change clusterspec.Change) string {
+	symbol := map[clusterspec.Action]string{
+		clusterspec.ActionCreate: "+",
+		clusterspec.ActionUpdate: "~",
+		clusterspec.ActionDelete: "-",
+	}[change.Action]
+	result := fmt.Sprintf("  %s %s %s\n", symbol, change.Action, describeChange(change))
+	for _, field := range change.Fields {
+		result += fmt.Sprintf("      %s: %s -> %s\n", field.Field, field.From, field.To)
+	}
+	return result
+}
+
+func applyChange(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	switch change.Kind {
+	case clusterspec.KindMachinePool:
+		return applyMachinePool(r, cluster, change)
+	case clusterspec.KindIdentityProvider:
+		return applyIdentityProvider(r, cluster, change)
+	case clusterspec.KindIngress:
+		return applyIngress(r, cluster, change)
+	case clusterspec.KindTuningConfig:
+		return applyTuningConfig(r, cluster, change)
+	case clusterspec.KindUser:
+		return applyUser(r, cluster, change)
+	}
+	return fmt.Errorf("unsupported kind of resource '%s'", change.Kind)
+}
+
+func applyMachinePool(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	if change.Action == clusterspec.ActionDelete {
+		if ocm.IsHyperShiftCluster(cluster) {
+			return r.OCMClient.DeleteNodePool(cluster.ID(), change.ID)
+		}
+		return r.OCMClient.DeleteMachinePool(cluster.ID(), change.ID)
+	}
+	machinePool := change.Desired.(*clusterspec.MachinePool)
+	update := change.Action == clusterspec.ActionUpdate
+	if ocm.IsHyperShiftCluster(cluster) {
+		nodePool, err := machinePool.NodePoolBuilder(update).Build()
+		if err != nil {
+			return err
+		}
+		if update {
+			_, err = r.OCMClient.UpdateNodePool(cluster.ID(), nodePool)
+		} else {
+			_, err = r.OCMClient.CreateNodePool(cluster.ID(), nodePool)
+		}
+		return err
+	}
+	builder, err := machinePool.MachinePoolBuilder(update)
+	if err != nil {
+		return err
+	}
+	object, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	if update {
+		_, err = r.OCMClient.UpdateMachinePool(cluster.ID(), object)
+	} else {
+		_, err = r.OCMClient.CreateMachinePool(cluster.ID(), object)
+	}
+	return err
+}
+
+func applyIdentityProvider(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	if change.Action == clusterspec.ActionDelete {
+		return r.OCMClient.DeleteIdentityProvider(cluster.ID(), change.ID)
+	}
+	idp := change.Desired.(*clusterspec.IdentityProvider)
+	builder, err := idp.Builder()
+	if err != nil {
+		return err
+	}
+	if change.Action == clusterspec.ActionUpdate {
+		object, err := builder.ID(change.ID).Build()
+		if err != nil {
+			return err
+		}
+		_, err = r.OCMClient.UpdateIdentityProvider(cluster.ID(), object)
+		return err
+	}
+	object, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.OCMClient.CreateIdentityProvider(cluster.ID(), object)
+	return err
+}
+
+func applyIngress(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	if change.Action == clusterspec.ActionDelete {
+		return r.OCMClient.DeleteIngress(cluster.ID(), change.ID)
+	}
+	builder := change.Desired.(*clusterspec.Ingress).Builder()
+	if change.Action == clusterspec.ActionUpdate {
+		object, err := builder.ID(change.ID).Build()
+		if err != nil {
+			return err
+		}
+		_, err = r.OCMClient.UpdateIngress(cluster.ID(), object)
+		return err
+	}
+	object, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.OCMClient.CreateIngress(cluster.ID(), object)
+	return err
+}
+
+func applyTuningConfig(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	if change.Action == clusterspec.ActionDelete {
+		return r.OCMClient.DeleteTuningConfig(cluster.ID(), change.ID)
+	}
+	builder := change.Desired.(*clusterspec.TuningConfig).Builder()
+	if change.Action == clusterspec.ActionUpdate {
+		object, err := builder.ID(change.ID).Build()
+		if err != nil {
+			return err
+		}
+		_, err = r.OCMClient.UpdateTuningConfig(cluster.ID(), object)
+		return err
+	}
+	object, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.OCMClient.CreateTuningConfig(cluster.ID(), object)
+	return err
+}
+
+func applyUser(r *rosa.Runtime, cluster *cmv1.Cluster, change clusterspec.Change) error {
+	if change.Action == clusterspec.ActionDelete {
+		return r.OCMClient.DeleteUser(cluster.ID(), change.Group, change.ID)
+	}
+	user, err := cmv1.NewUser().ID(change.ID).Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.OCMClient.CreateUser(cluster.ID(), change.Group, user)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/rosa"
 )
@@ -112,6 +113,14 @@ func attachManagedPolicies(r *rosa.Runtime, input *accountRolesCreationInput, ro
 }
 
 func (mp *managedPoliciesCreator) printCommands(r *rosa.Runtime, input *accountRolesCreationInput) error {
+	commands, err := mp.buildCommands(input)
+	if err != nil {
+		return err
+	}
+	return iac.Print(r.Reporter, "the classic account roles and policies", awscb.JoinCommands(commands)+"\n")
+}
+
+func (mp *managedPoliciesCreator) buildCommands(input *accountRolesCreationInput) ([]string, error) {
 	commands := []string{}
 	for file, role := range aws.AccountRoles {
 		accRoleName := aws.GetRoleName(input.prefix, role.Name)
@@ -124,7 +133,7 @@ func (mp *managedPoliciesCreator) printCommands(r *rosa.Runtime, input *accountR
 		for _, policyKey := range policyKeys {
 			policyARN, err := aws.GetManagedPolicyARN(input.policies, policyKey)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			attachRolePolicy := buildAttachRolePolicyCommand(accRoleName, policyARN)
@@ -132,10 +141,7 @@ func (mp *managedPoliciesCreator) printCommands(r *rosa.Runtime, input *accountR
 		}
 	}
 
-	r.Reporter.Infof("Run the following commands to create the classic account roles and policies:\n")
-	fmt.Println(awscb.JoinCommands(commands) + "\n")
-
-	return nil
+	return commands, nil
 }
 
 func (mp *managedPoliciesCreator) getRoleTags(roleType string, input *accountRolesCreationInput) map[string]string {
@@ -166,6 +172,11 @@ func (up *unmanagedPoliciesCreator) createRoles(r *rosa.Runtime, input *accountR
 }
 
 func (up *unmanagedPoliciesCreator) printCommands(r *rosa.Runtime, input *accountRolesCreationInput) error {
+	return iac.Print(r.Reporter, "the classic account roles and policies",
+		awscb.JoinCommands(up.buildCommands(input))+"\n")
+}
+
+func (up *unmanagedPoliciesCreator) buildCommands(input *accountRolesCreationInput) []string {
 	commands := []string{}
 	for file, role := range aws.AccountRoles {
 		accRoleName := aws.GetRoleName(input.prefix, role.Name)
@@ -185,10 +196,7 @@ func (up *unmanagedPoliciesCreator) printCommands(r *rosa.Runtime, input *accoun
 		commands = append(commands, createRole, createPolicy, attachRolePolicy)
 	}
 
-	r.Reporter.Infof("Run the following commands to create the classic account roles and policies:\n")
-	fmt.Println(awscb.JoinCommands(commands) + "\n")
-
-	return nil
+	return commands
 }
 
 func (up *unmanagedPoliciesCreator) getRoleTags(roleType string, input *accountRolesCreationInput) map[string]string {
@@ -211,15 +219,31 @@ func (db *doubleRolesCreator) createRoles(r *rosa.Runtime, input *accountRolesCr
 }
 
 func (db *doubleRolesCreator) printCommands(r *rosa.Runtime, input *accountRolesCreationInput) error {
-	// Build classic account roles command
 	unmanagedCreator := unmanagedPoliciesCreator{}
-	err := unmanagedCreator.printCommands(r, input)
+	hcpCreator := hcpManagedPoliciesCreator{}
+
+	// Templates can't be split, so both sets of roles go into the same one
+	format, err := iac.Format()
+	if err != nil {
+		return err
+	}
+	if format != iac.AWSCLI {
+		hcpCommands, err := hcpCreator.buildCommands(input)
+		if err != nil {
+			return err
+		}
+		commands := append(unmanagedCreator.buildCommands(input), hcpCommands...)
+		return iac.Print(r.Reporter, "the classic and hosted CP account roles and policies",
+			awscb.JoinCommands(commands))
+	}
+
+	// Build classic account roles command
+	err = unmanagedCreator.printCommands(r, input)
 	if err != nil {
 		return err
 	}
 
 	// Build Hypershift account roles command
-	hcpCreator := hcpManagedPoliciesCreator{}
 	return hcpCreator.printCommands(r, input)
 }
 
@@ -303,6 +327,14 @@ func (hcp *hcpManagedPoliciesCreator) createRoles(r *rosa.Runtime, input *accoun
 }
 
 func (hcp *hcpManagedPoliciesCreator) printCommands(r *rosa.Runtime, input *accountRolesCreationInput) error {
+	commands, err := hcp.buildCommands(input)
+	if err != nil {
+		return err
+	}
+	return iac.Print(r.Reporter, "the hosted CP account roles and policies", awscb.JoinCommands(commands)+"\n")
+}
+
+func (hcp *hcpManagedPoliciesCreator) buildCommands(input *accountRolesCreationInput) ([]string, error) {
 	commands := []string{}
 	for file, role := range aws.HCPAccountRoles {
 		accRoleName := aws.GetRoleName(input.prefix, role.Name)
@@ -313,17 +345,14 @@ func (hcp *hcpManagedPoliciesCreator) printCommands(r *rosa.Runtime, input *acco
 		policyKey := fmt.Sprintf("sts_hcp_%s_permission_policy", file)
 		policyARN, err := aws.GetManagedPolicyARN(input.policies, policyKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		attachRolePolicy := buildAttachRolePolicyCommand(accRoleName, policyARN)
 		commands = append(commands, createRole, attachRolePolicy)
 	}
 
-	r.Reporter.Infof("Run the following commands to create the hosted CP account roles and policies:\n")
-	fmt.Println(awscb.JoinCommands(commands) + "\n")
-
-	return nil
+	return commands, nil
 }
 
 func (hcp *hcpManagedPoliciesCreator) getRoleTags(roleType string, input *accountRolesCreationInput) map[string]string {
@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterautoscaler"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var autoscalerArgs *ocm.AutoscalerConfig
+
+var Cmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "Create an autoscaler for a cluster",
+	Long: "Configure the autoscaler of a cluster. The autoscaler adjusts the size of the " +
+		"machine pools that have autoscaling enabled.",
+	Example: `  # Interactively create an autoscaler for a cluster named "mycluster"
+  rosa create autoscaler --cluster=mycluster --interactive
+
+  # Create an autoscaler that can scale down the cluster
+  rosa create autoscaler --cluster=mycluster --scale-down-enabled --scale-down-unneeded-time=10m`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	ocm.AddClusterFlag(Cmd)
+	interactive.AddFlag(flags)
+	autoscalerArgs = clusterautoscaler.AddFlags(flags, "")
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+
+	if ocm.IsHyperShiftCluster(cluster) {
+		r.Reporter.Errorf("Hosted Control Plane clusters do not support cluster-autoscaler configuration")
+		os.Exit(1)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		r.Reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	autoscaler, err := r.OCMClient.GetClusterAutoscaler(cluster.ID())
+	if err != nil {
+		r.Reporter.Errorf("Failed to get autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+	if autoscaler != nil {
+		r.Reporter.Errorf("Cluster '%s' already has an autoscaler, use 'rosa edit autoscaler' to change it",
+			clusterKey)
+		os.Exit(1)
+	}
+
+	if !clusterautoscaler.IsSetViaCLI(cmd.Flags(), "") && !interactive.Enabled() {
+		interactive.Enable()
+		r.Reporter.Infof("Enabling interactive mode")
+	}
+
+	config, err := clusterautoscaler.GetAutoscalerOptions(cmd.Flags(), "", false, autoscalerArgs)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	r.Reporter.Debugf("Creating autoscaler for cluster '%s'", clusterKey)
+	_, err = r.OCMClient.CreateClusterAutoscaler(cluster.ID(), config)
+	if err != nil {
+		r.Reporter.Errorf("Failed to create autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+	r.Reporter.Infof("Successfully created autoscaler configuration for cluster '%s'", clusterKey)
+}
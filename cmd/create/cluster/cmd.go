@@ -40,9 +40,15 @@ import (
 	installLogs "github.com/openshift/rosa/cmd/logs/install"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/clusterautoscaler"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/fedramp"
 	"github.com/openshift/rosa/pkg/helper"
 	mpHelpers "github.com/openshift/rosa/pkg/helper/machinepools"
+	networkplan "github.com/openshift/rosa/pkg/helper/network"
+	"github.com/openshift/rosa/pkg/helper/proxy"
+	"github.com/openshift/rosa/pkg/helper/quotas"
 	"github.com/openshift/rosa/pkg/helper/roles"
 	"github.com/openshift/rosa/pkg/helper/versions"
 	"github.com/openshift/rosa/pkg/ingress"
@@ -53,6 +59,7 @@ import (
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/properties"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
 // nolint
@@ -63,12 +70,14 @@ var kmsArnRE = regexp.MustCompile(
 const (
 	OidcConfigIdFlag      = "oidc-config-id"
 	ClassicOidcConfigFlag = "classic-oidc-config"
+	fromFileFlag          = "from-file"
 
 	defaultIngressRouteSelectorFlag            = "default-ingress-route-selector"
 	defaultIngressExcludedNamespacesFlag       = "default-ingress-excluded-namespaces"
 	defaultIngressWildcardPolicyFlag           = "default-ingress-wildcard-policy"
 	defaultIngressNamespaceOwnershipPolicyFlag = "default-ingress-namespace-ownership-policy"
 
+	clusterAutoscalerFlagsPrefix                = "autoscaler-"
 	autoscalerBalanceSimilarNodeGroupsFlag      = "autoscaler-balance-similar-node-groups"
 	autoscalerSkipNodesWithLocalStorageFlag     = "autoscaler-skip-nodes-with-local-storage"
 	autoscalerLogVerbosityFlag                  = "autoscaler-log-verbosity"
@@ -105,6 +114,8 @@ var args struct {
 	properties []string
 	// Use local AWS credentials instead of the 'osdCcsAdmin' user
 	useLocalCredentials bool
+	// Read the cluster definition from a file
+	fromFile string
 
 	// Disable SCP checks in the installer
 	disableSCPChecks bool
@@ -138,16 +149,7 @@ var args struct {
 	defaultMachinePoolLabels string
 
 	// Autoscaler Configurations
-	autoscalerBalanceSimilarNodeGroups    bool
-	autoscalerSkipNodesWithLocalStorage   bool
-	autoscalerLogVerbosity                int
-	autoscalerMaxPodGracePeriod           int
-	autoscalerPodPriorityThreshold        int
-	autoscalerIgnoreDaemonsetsUtilization bool
-	autoscalerMaxNodeProvisionTime        string
-	autoscalerBalancingIgnoredLabels      []string
-	autoscalerResourceLimits              ResourceLimits
-	autoscalerScaleDown                   ScaleDownConfig
+	autoscaler *ocm.AutoscalerConfig
 
 	// Networking options
 	networkType string
@@ -155,6 +157,7 @@ var args struct {
 	serviceCIDR net.IPNet
 	podCIDR     net.IPNet
 	hostPrefix  int
+	networkPlan string
 
 	// The Subnet IDs to use when installing the cluster.
 	// SubnetIDs should come in pairs; two per availability zone, one private and one public,
@@ -223,26 +226,6 @@ var args struct {
 	baseDomain          string
 }
 
-type ResourceLimits struct {
-	MaxNodesTotal int
-	Cores         ResourceRange
-	Memory        ResourceRange
-}
-
-type ResourceRange struct {
-	Min int
-	Max int
-}
-
-type ScaleDownConfig struct {
-	Enabled              bool
-	UnneededTime         string
-	UtilizationThreshold float64
-	DelayAfterAdd        string
-	DelayAfterDelete     string
-	DelayAfterFailure    string
-}
-
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Create cluster",
@@ -251,7 +234,10 @@ var Cmd = &cobra.Command{
   rosa create cluster --cluster-name=mycluster
 
   # Create a cluster in the us-east-2 region
-  rosa create cluster --cluster-name=mycluster --region=us-east-2`,
+  rosa create cluster --cluster-name=mycluster --region=us-east-2
+
+  # Create a cluster from a definition file, overriding the name from the file
+  rosa create cluster --from-file=cluster.yaml --cluster-name=mycluster2`,
 	Run: run,
 }
 
@@ -549,148 +535,7 @@ func init() {
 	)
 
 	// Cluster Autoscaler flags
-	flags.BoolVar(
-		&args.autoscalerBalanceSimilarNodeGroups,
-		autoscalerBalanceSimilarNodeGroupsFlag,
-		false,
-		"Identify node groups with the same instance type and label set, "+
-			"and aim to balance respective sizes of those node groups.",
-	)
-
-	flags.BoolVar(
-		&args.autoscalerSkipNodesWithLocalStorage,
-		autoscalerSkipNodesWithLocalStorageFlag,
-		false,
-		"If true cluster autoscaler will never delete nodes with pods with local storage, e.g. EmptyDir or HostPath.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerLogVerbosity,
-		autoscalerLogVerbosityFlag,
-		1,
-		"Autoscaler log level. Default is 1, 4 is a good option when trying to debug the autoscaler.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerMaxPodGracePeriod,
-		autoscalerMaxPodGracePeriodFlag,
-		0,
-		"Gives pods graceful termination time before scaling down, measured in seconds.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerPodPriorityThreshold,
-		autoscalerPodPriorityThresholdFlag,
-		0,
-		"The priority that a pod must exceed to cause the cluster autoscaler to deploy additional nodes. "+
-			"Expects an integer, can be negative.",
-	)
-
-	flags.BoolVar(
-		&args.autoscalerIgnoreDaemonsetsUtilization,
-		autoscalerIgnoreDaemonsetsUtilizationFlag,
-		false,
-		"Should cluster-autoscaler ignore DaemonSet pods when calculating resource utilization for scaling down.",
-	)
-
-	flags.StringVar(
-		&args.autoscalerMaxNodeProvisionTime,
-		autoscalerMaxNodeProvisionTimeFlag,
-		"",
-		"Maximum time cluster-autoscaler waits for node to be provisioned. "+
-			"Expects string comprised of an integer and time unit (ns|us|µs|ms|s|m|h), examples: 20m, 1h.",
-	)
-
-	flags.StringSliceVar(
-		&args.autoscalerBalancingIgnoredLabels,
-		autoscalerBalancingIgnoredLabelsFlag,
-		nil,
-		"A comma-separated list of label keys that cluster autoscaler should ignore when considering node group similarity.",
-	)
-
-	// Resource Limits
-	flags.IntVar(
-		&args.autoscalerResourceLimits.MaxNodesTotal,
-		autoscalerMaxNodesTotalFlag,
-		1000,
-		"Total amount of nodes that can exist in the cluster, including non-scaled nodes.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerResourceLimits.Cores.Min,
-		autoscalerMinCoresFlag,
-		0,
-		"Minimum limit for the amount of cores to deploy in the cluster.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerResourceLimits.Cores.Max,
-		autoscalerMaxCoresFlag,
-		100,
-		"Maximum limit for the amount of cores to deploy in the cluster.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerResourceLimits.Memory.Min,
-		autoscalerMinMemoryFlag,
-		0,
-		"Minimum limit for the amount of memory, in GiB, in the cluster.",
-	)
-
-	flags.IntVar(
-		&args.autoscalerResourceLimits.Memory.Max,
-		autoscalerMaxMemoryFlag,
-		4096,
-		"Maximum limit for the amount of memory, in GiB, in the cluster.",
-	)
-
-	// TODO: handle GPU limitations
-
-	// Scale down Configuration
-
-	flags.BoolVar(
-		&args.autoscalerScaleDown.Enabled,
-		autoscalerScaleDownEnabledFlag,
-		false,
-		"Should cluster-autoscaler be able to scale down the cluster.",
-	)
-
-	flags.StringVar(
-		&args.autoscalerScaleDown.UnneededTime,
-		autoscalerScaleDownUnneededTimeFlag,
-		"",
-		"Increasing value will make nodes stay up longer, waiting for pods to be scheduled "+
-			"while decreasing value will make nodes be deleted sooner.",
-	)
-
-	flags.Float64Var(
-		&args.autoscalerScaleDown.UtilizationThreshold,
-		autoscalerScaleDownUtilizationThresholdFlag,
-		0.5,
-		"Node utilization level, defined as sum of requested resources divided by capacity, "+
-			"below which a node can be considered for scale down. Value should be between 0 and 1.",
-	)
-
-	flags.StringVar(
-		&args.autoscalerScaleDown.DelayAfterAdd,
-		autoscalerScaleDownDelayAfterAddFlag,
-		"",
-		"After a scale-up, consider scaling down only after this amount of time.",
-	)
-
-	flags.StringVar(
-		&args.autoscalerScaleDown.DelayAfterDelete,
-		autoscalerScaleDownDelayAfterDeleteFlag,
-		"",
-		"After a scale-down, consider scaling down again only after this amount of time.",
-	)
-
-	flags.StringVar(
-		&args.autoscalerScaleDown.DelayAfterFailure,
-		autoscalerScaleDownDelayAfterFailureFlag,
-		"",
-		"After a failing scale-down, consider scaling down again only after this amount of time.",
-	)
+	args.autoscaler = clusterautoscaler.AddFlags(flags, clusterAutoscalerFlagsPrefix)
 
 	// End of cluster-wide autoscaling flags
 
@@ -750,6 +595,13 @@ func init() {
 		"Subnet prefix length to assign to each individual node. For example, if host prefix is set "+
 			"to \"23\", then each node is assigned a /23 subnet out of the given CIDR.",
 	)
+	flags.StringVar(
+		&args.networkPlan,
+		"network-plan",
+		"",
+		"Path to a plan saved with 'rosa plan network --output-file'. Its CIDRs and host prefix are used "+
+			"as the defaults of the networking options.",
+	)
 	flags.BoolVar(
 		&args.private,
 		"private",
@@ -921,6 +773,14 @@ The password must
 			"associated with intended shared VPC, e.g., '1vo8.p1.openshiftapps.com'.",
 	)
 
+	flags.StringVar(
+		&args.fromFile,
+		fromFileFlag,
+		"",
+		"Path to a YAML or JSON file with the cluster definition. "+
+			"Options given on the command line take precedence over the values in the file.",
+	)
+
 	aws.AddModeFlag(Cmd)
 	interactive.AddFlag(flags)
 	output.AddFlag(Cmd)
@@ -932,7 +792,18 @@ func networkTypeCompletion(cmd *cobra.Command, args []string, toComplete string)
 }
 
 func run(cmd *cobra.Command, _ []string) {
-	r := rosa.NewRuntime().WithAWS().WithOCM()
+	r := rosa.NewRuntime()
+
+	// The definition file needs to be applied before the AWS client is created, as it can set the region
+	if args.fromFile != "" {
+		err := applyClusterSpecFile(cmd, args.fromFile)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+	}
+
+	r.WithAWS().WithOCM()
 	defer r.Cleanup()
 
 	// validate flags for shared vpc
@@ -1900,6 +1771,18 @@ func run(cmd *cobra.Command, _ []string) {
 		r.Reporter.Errorf("Error retrieving default cluster flavors")
 		os.Exit(1)
 	}
+	if args.networkPlan != "" {
+		plan, err := networkplan.LoadPlan(args.networkPlan)
+		if err == nil {
+			dMachinecidr, dServicecidr, dPodcidr, err = plan.Parse()
+		}
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		dhostPrefix = plan.HostPrefix
+		r.Reporter.Infof("Using network plan '%s': %s", args.networkPlan, plan.Describe())
+	}
 
 	// Machine CIDR:
 	machineCIDR := args.machineCIDR
@@ -2269,59 +2152,7 @@ func run(cmd *cobra.Command, _ []string) {
 		isHostedCP,
 		multiAZ)
 
-	var isClusterAutoscalerSet bool
-	autoscalerBalanceSimilarNodeGroups := args.autoscalerBalanceSimilarNodeGroups
-	autoscalerSkipNodesWithLocalStorage := args.autoscalerSkipNodesWithLocalStorage
-	autoscalerLogVerbosity := args.autoscalerLogVerbosity
-	autoscalerMaxPodGracePeriod := args.autoscalerMaxPodGracePeriod
-	autoscalerPodPriorityThreshold := args.autoscalerPodPriorityThreshold
-	autoscalerIgnoreDaemonsetsUtilization := args.autoscalerIgnoreDaemonsetsUtilization
-	autoscalerMaxNodeProvisionTime := args.autoscalerMaxNodeProvisionTime
-	autoscalerBalancingIgnoredLabels := args.autoscalerBalancingIgnoredLabels
-	maxNodesTotal := args.autoscalerResourceLimits.MaxNodesTotal
-	minCores := args.autoscalerResourceLimits.Cores.Min
-	maxCores := args.autoscalerResourceLimits.Cores.Max
-	minMemory := args.autoscalerResourceLimits.Memory.Min
-	maxMemory := args.autoscalerResourceLimits.Memory.Max
-	scaleDownEnabled := args.autoscalerScaleDown.Enabled
-	scaleDownUnneededTime := args.autoscalerScaleDown.UnneededTime
-	scaleDownUtilizationThreshold := args.autoscalerScaleDown.UtilizationThreshold
-	scaleDownDelayAfterAdd := args.autoscalerScaleDown.DelayAfterAdd
-	scaleDownDelayAfterDelete := args.autoscalerScaleDown.DelayAfterDelete
-	scaleDownDelayAfterFailure := args.autoscalerScaleDown.DelayAfterFailure
-
-	isAutoscalerBalanceSimilarNodeGroupsSet := cmd.Flags().Changed(autoscalerBalanceSimilarNodeGroupsFlag)
-	isAutoscalerSkipNodesWithLocalStorageSet := cmd.Flags().Changed(autoscalerSkipNodesWithLocalStorageFlag)
-	isAutoscalerLogVerbositySet := cmd.Flags().Changed(autoscalerLogVerbosityFlag)
-	isAutoscalerBalancingIgnoredLabelsSet := cmd.Flags().Changed(autoscalerBalancingIgnoredLabelsFlag)
-	isAutoscalerIgnoreDaemonsetsUtilizationSet := cmd.Flags().Changed(autoscalerIgnoreDaemonsetsUtilizationFlag)
-	isAutoscalerMaxPodGracePeriodSet := cmd.Flags().Changed(autoscalerMaxPodGracePeriodFlag)
-	isAutoscalerPodPriorityThresholdSet := cmd.Flags().Changed(autoscalerPodPriorityThresholdFlag)
-	isAutoscalerMaxNodeProvisionTimeSet := cmd.Flags().Changed(autoscalerMaxNodeProvisionTime)
-	isMaxNodesTotalSet := cmd.Flags().Changed(autoscalerMaxNodesTotalFlag)
-	isMinCoresSet := cmd.Flags().Changed(autoscalerMinCoresFlag)
-	isMaxCoresSet := cmd.Flags().Changed(autoscalerMaxCoresFlag)
-	isMinMemorySet := cmd.Flags().Changed(autoscalerMinMemoryFlag)
-	isMaxMemorySet := cmd.Flags().Changed(autoscalerMaxMemoryFlag)
-	isScaleDownEnabledSet := cmd.Flags().Changed(autoscalerScaleDownEnabledFlag)
-	isScaleDownUnneededTimeSet := cmd.Flags().Changed(autoscalerScaleDownUnneededTimeFlag)
-	isScaleDownUtilizationThresholdSet := cmd.Flags().Changed(autoscalerScaleDownUtilizationThresholdFlag)
-	isScaleDownDelayAfterAddSet := cmd.Flags().Changed(autoscalerScaleDownDelayAfterAddFlag)
-	isScaleDownDelayAfterDeleteSet := cmd.Flags().Changed(autoscalerScaleDownDelayAfterDeleteFlag)
-	isScaleDownDelayAfterFailureSet := cmd.Flags().Changed(autoscalerScaleDownDelayAfterFailureFlag)
-
-	if isAutoscalerBalanceSimilarNodeGroupsSet || isAutoscalerSkipNodesWithLocalStorageSet ||
-		isAutoscalerLogVerbositySet || isAutoscalerBalancingIgnoredLabelsSet ||
-		isAutoscalerIgnoreDaemonsetsUtilizationSet || isAutoscalerMaxPodGracePeriodSet ||
-		isAutoscalerPodPriorityThresholdSet || isAutoscalerMaxNodeProvisionTimeSet ||
-		isMaxNodesTotalSet || isMinCoresSet ||
-		isMaxCoresSet || isMinMemorySet ||
-		isMaxMemorySet || isScaleDownEnabledSet ||
-		isScaleDownUnneededTimeSet || isScaleDownUtilizationThresholdSet ||
-		isScaleDownDelayAfterAddSet || isScaleDownDelayAfterDeleteSet ||
-		isScaleDownDelayAfterFailureSet {
-		isClusterAutoscalerSet = true
-	}
+	var autoscalerConfig *ocm.AutoscalerConfig
 
 	if autoscaling {
 		// if the user set compute-nodes and enabled autoscaling
@@ -2371,368 +2202,19 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 
-		if !isClusterAutoscalerSet && interactive.Enabled() {
-			isClusterAutoscalerSet, err = interactive.GetBool(interactive.Input{
-				Question: "Configure cluster-autoscaler",
-				Help:     "Set cluster-wide autoscaling configurations",
-				Default:  false,
-				Required: false,
-			})
-			if err != nil {
-				r.Reporter.Errorf("Expected a valid value for configure-cluster-autoscaler: %s", err)
-				os.Exit(1)
-			}
+		autoscalerConfig, err = clusterautoscaler.GetAutoscalerOptions(cmd.Flags(), clusterAutoscalerFlagsPrefix,
+			true, args.autoscaler)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
-
-		if isClusterAutoscalerSet {
-			if interactive.Enabled() && !isAutoscalerBalanceSimilarNodeGroupsSet {
-				autoscalerBalanceSimilarNodeGroups, err = interactive.GetBool(interactive.Input{
-					Question: "Balance similar node groups",
-					Help:     cmd.Flags().Lookup(autoscalerBalanceSimilarNodeGroupsFlag).Usage,
-					Default:  autoscalerBalanceSimilarNodeGroups,
-					Required: false,
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerBalanceSimilarNodeGroupsFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isAutoscalerSkipNodesWithLocalStorageSet {
-				autoscalerSkipNodesWithLocalStorage, err = interactive.GetBool(interactive.Input{
-					Question: "Skip nodes with local storage",
-					Help:     cmd.Flags().Lookup(autoscalerSkipNodesWithLocalStorageFlag).Usage,
-					Default:  autoscalerSkipNodesWithLocalStorage,
-					Required: false,
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerSkipNodesWithLocalStorageFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isAutoscalerLogVerbositySet {
-				autoscalerLogVerbosity, err = interactive.GetInt(interactive.Input{
-					Question: "Log verbosity",
-					Help:     cmd.Flags().Lookup(autoscalerLogVerbosityFlag).Usage,
-					Default:  autoscalerLogVerbosity,
-					Required: false,
-					Validators: []interactive.Validator{
-						intValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerLogVerbosityFlag, err)
-					os.Exit(1)
-				}
-			}
-			err = intValidator(autoscalerLogVerbosity)
-			if err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isAutoscalerBalancingIgnoredLabelsSet {
-				autoscalerBalancingIgnoredLabelsInput, err := interactive.GetString(interactive.Input{
-					Question: "Labels that cluster autoscaler should ignore when considering node group similarity",
-					Help:     cmd.Flags().Lookup(autoscalerBalancingIgnoredLabelsFlag).Usage,
-					Default:  strings.Join(args.autoscalerBalancingIgnoredLabels, ","),
-					Required: false,
-					Validators: []interactive.Validator{
-						ocm.ValidateBalancingIgnoredLabels,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid set of labels for %s: %s", autoscalerBalancingIgnoredLabelsFlag, err)
-					os.Exit(1)
-				}
-
-				autoscalerBalancingIgnoredLabels = strings.Split(autoscalerBalancingIgnoredLabelsInput, ",")
-			}
-
-			if interactive.Enabled() && !isAutoscalerIgnoreDaemonsetsUtilizationSet && !autoscalerIgnoreDaemonsetsUtilization {
-				autoscalerIgnoreDaemonsetsUtilization, err = interactive.GetBool(interactive.Input{
-					Question: "Ignore daemonsets utilization",
-					Help:     cmd.Flags().Lookup(autoscalerIgnoreDaemonsetsUtilizationFlag).Usage,
-					Default:  autoscalerIgnoreDaemonsetsUtilization,
-					Required: false,
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid boolean value for %s: %s", autoscalerIgnoreDaemonsetsUtilizationFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isAutoscalerMaxNodeProvisionTimeSet {
-				autoscalerMaxNodeProvisionTime, err = interactive.GetString(interactive.Input{
-					Question: "Maximum node provision time",
-					Help:     cmd.Flags().Lookup(autoscalerMaxNodeProvisionTimeFlag).Usage,
-					Required: false,
-					Default:  autoscalerMaxNodeProvisionTime,
-					Validators: []interactive.Validator{
-						durationStringValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMaxNodeProvisionTimeFlag, err)
-					os.Exit(1)
-				}
-			}
-			if err := durationStringValidator(autoscalerMaxNodeProvisionTime); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && autoscalerMaxPodGracePeriod == 0 && !isAutoscalerMaxPodGracePeriodSet {
-				autoscalerMaxPodGracePeriod, err = interactive.GetInt(interactive.Input{
-					Question: "Maximum pod grace period",
-					Help:     cmd.Flags().Lookup(autoscalerMaxPodGracePeriodFlag).Usage,
-					Required: false,
-					Default:  autoscalerMaxPodGracePeriod,
-				})
-				if err != nil {
-					r.Reporter.Errorf("%s", err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && autoscalerPodPriorityThreshold == 0 && !isAutoscalerPodPriorityThresholdSet {
-				autoscalerPodPriorityThreshold, err = interactive.GetInt(interactive.Input{
-					Question: "Pod priority threshold",
-					Help:     cmd.Flags().Lookup(autoscalerPodPriorityThresholdFlag).Usage,
-					Required: false,
-					Default:  autoscalerPodPriorityThreshold,
-				})
-				if err != nil {
-					r.Reporter.Errorf("%s", err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isMaxNodesTotalSet {
-				maxNodesTotal, err = interactive.GetInt(interactive.Input{
-					Question: "Maximum amount of nodes in the cluster",
-					Help:     cmd.Flags().Lookup(autoscalerMaxNodesTotalFlag).Usage,
-					Required: false,
-					Default:  maxNodesTotal,
-					Validators: []interactive.Validator{
-						nonNegativeIntValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMaxNodesTotalFlag, err)
-					os.Exit(1)
-				}
-			}
-			if err := nonNegativeIntValidator(maxNodesTotal); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isMinCoresSet {
-				minCores, err = interactive.GetInt(interactive.Input{
-					Question: "Minimum number of cores to deploy in cluster",
-					Help:     cmd.Flags().Lookup(autoscalerMinCoresFlag).Usage,
-					Required: false,
-					Default:  minCores,
-					Validators: []interactive.Validator{
-						nonNegativeIntValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMinCoresFlag, err)
-					os.Exit(1)
-				}
-			}
-			if err := nonNegativeIntValidator(minCores); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isMaxCoresSet {
-				maxCores, err = interactive.GetInt(interactive.Input{
-					Question: "Maximum number of cores to deploy in cluster",
-					Help:     cmd.Flags().Lookup(autoscalerMaxCoresFlag).Usage,
-					Required: false,
-					Default:  maxCores,
-					Validators: []interactive.Validator{
-						nonNegativeIntValidator,
-						getValidMaxRangeValidator(minCores),
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMaxCoresFlag, err)
-					os.Exit(1)
-				}
-			}
-			if err := nonNegativeIntValidator(maxCores); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-			if err := getValidMaxRangeValidator(minCores)(maxCores); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isMinMemorySet {
-				minMemory, err = interactive.GetInt(interactive.Input{
-					Question: "Minimum amount of memory, in GiB, in the cluster",
-					Help:     cmd.Flags().Lookup(autoscalerMinMemoryFlag).Usage,
-					Required: false,
-					Default:  minMemory,
-					Validators: []interactive.Validator{
-						nonNegativeIntValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMinMemoryFlag, err)
-				}
-			}
-			if err := nonNegativeIntValidator(minMemory); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isMaxMemorySet {
-				maxMemory, err = interactive.GetInt(interactive.Input{
-					Question: "Maximum amount of memory, in GiB, in the cluster",
-					Help:     cmd.Flags().Lookup(autoscalerMaxMemoryFlag).Usage,
-					Required: false,
-					Default:  maxMemory,
-					Validators: []interactive.Validator{
-						nonNegativeIntValidator,
-						getValidMaxRangeValidator(minMemory),
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerMaxMemoryFlag, err)
-					os.Exit(1)
-				}
-			}
-			if err := nonNegativeIntValidator(maxMemory); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-			if err := getValidMaxRangeValidator(minMemory)(maxMemory); err != nil {
-				r.Reporter.Errorf("%s", err)
-				os.Exit(1)
-			}
-
-			// scale-down configs
-
-			if interactive.Enabled() && !scaleDownEnabled && !isScaleDownEnabledSet {
-				scaleDownEnabled, err = interactive.GetBool(interactive.Input{
-					Question: "Should scale-down be enabled",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownEnabledFlag).Usage,
-					Default:  scaleDownEnabled,
-					Required: false,
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownEnabledFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isScaleDownUnneededTimeSet && scaleDownUnneededTime == "" {
-				scaleDownUnneededTime, err = interactive.GetString(interactive.Input{
-					Question: "How long a node should be unneeded before it is eligible for scale down",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownUnneededTimeFlag).Usage,
-					Default:  scaleDownUnneededTime,
-					Required: false,
-					Validators: []interactive.Validator{
-						durationStringValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownUnneededTimeFlag, err)
-					os.Exit(1)
-				}
-
-				if err := durationStringValidator(scaleDownUnneededTime); err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownUnneededTimeFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isScaleDownUtilizationThresholdSet {
-				scaleDownUtilizationThreshold, err = interactive.GetFloat(interactive.Input{
-					Question: "Node utilization threshold",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownUtilizationThresholdFlag).Usage,
-					Default:  scaleDownUtilizationThreshold,
-					Required: false,
-					Validators: []interactive.Validator{
-						zeroToOneFloatValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownUtilizationThresholdFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isScaleDownDelayAfterAddSet {
-				scaleDownDelayAfterAdd, err = interactive.GetString(interactive.Input{
-					Question: "How long after scale up should scale down evaluation resume",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownDelayAfterAddFlag).Usage,
-					Default:  scaleDownDelayAfterAdd,
-					Required: false,
-					Validators: []interactive.Validator{
-						durationStringValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterAddFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if err := durationStringValidator(scaleDownDelayAfterAdd); err != nil {
-				r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterAddFlag, err)
-				os.Exit(1)
-			}
-
-			if interactive.Enabled() && !isScaleDownDelayAfterDeleteSet {
-				scaleDownDelayAfterDelete, err = interactive.GetString(interactive.Input{
-					Question: "How long after node deletion should scale down evaluation resume",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownDelayAfterDeleteFlag).Usage,
-					Default:  scaleDownDelayAfterDelete,
-					Required: false,
-					Validators: []interactive.Validator{
-						durationStringValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterDeleteFlag, err)
-					os.Exit(1)
-				}
-
-				if err := durationStringValidator(scaleDownDelayAfterDelete); err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterDeleteFlag, err)
-					os.Exit(1)
-				}
-			}
-
-			if interactive.Enabled() && !isScaleDownDelayAfterFailureSet {
-				scaleDownDelayAfterFailure, err = interactive.GetString(interactive.Input{
-					Question: "How long after node deletion failure should scale down evaluation resume.",
-					Help:     cmd.Flags().Lookup(autoscalerScaleDownDelayAfterFailureFlag).Usage,
-					Default:  scaleDownDelayAfterFailure,
-					Required: false,
-					Validators: []interactive.Validator{
-						durationStringValidator,
-					},
-				})
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterFailureFlag, err)
-					os.Exit(1)
-				}
-
-				if err := durationStringValidator(scaleDownDelayAfterFailure); err != nil {
-					r.Reporter.Errorf("Expected a valid value for %s: %s", autoscalerScaleDownDelayAfterFailureFlag, err)
-					os.Exit(1)
-				}
-			}
+	} else if clusterautoscaler.IsSetViaCLI(cmd.Flags(), clusterAutoscalerFlagsPrefix) {
+		err = clusterautoscaler.Validate(args.autoscaler, clusterAutoscalerFlagsPrefix)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
+		autoscalerConfig = args.autoscaler
 	}
 
 	// Compute nodes:
@@ -3073,6 +2555,13 @@ func run(cmd *cobra.Command, _ []string) {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	if len(noProxySlice) > 0 || additionalTrustBundleFile != "" {
+		err = proxy.Preflight(r, noProxySlice, additionalTrustBundleFile)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+	}
 
 	// Get certificate contents
 	var additionalTrustBundle *string
@@ -3320,37 +2809,7 @@ func run(cmd *cobra.Command, _ []string) {
 		clusterConfig.SharedVPCRoleArn = sharedVPCRoleARN
 		clusterConfig.BaseDomain = baseDomain
 	}
-	if isClusterAutoscalerSet {
-		clusterConfig.AutoscalerConfig = &ocm.AutoscalerConfig{
-			BalanceSimilarNodeGroups:    autoscalerBalanceSimilarNodeGroups,
-			SkipNodesWithLocalStorage:   autoscalerSkipNodesWithLocalStorage,
-			LogVerbosity:                autoscalerLogVerbosity,
-			MaxPodGracePeriod:           autoscalerMaxPodGracePeriod,
-			BalancingIgnoredLabels:      autoscalerBalancingIgnoredLabels,
-			IgnoreDaemonsetsUtilization: autoscalerIgnoreDaemonsetsUtilization,
-			MaxNodeProvisionTime:        autoscalerMaxNodeProvisionTime,
-			PodPriorityThreshold:        autoscalerPodPriorityThreshold,
-			ResourceLimits: ocm.ResourceLimits{
-				MaxNodesTotal: maxNodesTotal,
-				Cores: ocm.ResourceRange{
-					Min: minCores,
-					Max: maxCores,
-				},
-				Memory: ocm.ResourceRange{
-					Min: minMemory,
-					Max: maxMemory,
-				},
-			},
-			ScaleDown: ocm.ScaleDownConfig{
-				Enabled:              scaleDownEnabled,
-				UnneededTime:         scaleDownUnneededTime,
-				UtilizationThreshold: scaleDownUtilizationThreshold,
-				DelayAfterAdd:        scaleDownDelayAfterAdd,
-				DelayAfterDelete:     scaleDownDelayAfterDelete,
-				DelayAfterFailure:    scaleDownDelayAfterFailure,
-			},
-		}
-	}
+	clusterConfig.AutoscalerConfig = autoscalerConfig
 
 	props := args.properties
 	if args.fakeCluster {
@@ -3375,6 +2834,36 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 	}
 
+	quotaNodes := computeNodes
+	if autoscaling {
+		quotaNodes = maxReplicas
+	}
+	if len(subnetIDs) > 0 {
+		validateNetwork(r, aws.NetworkPreflightOptions{
+			SubnetIDs:    subnetIDs,
+			MachineCIDR:  cidrOrNil(machineCIDR),
+			ServiceCIDR:  cidrOrNil(serviceCIDR),
+			PodCIDR:      cidrOrNil(podCIDR),
+			PrivateLink:  privateLink,
+			Hypershift:   isHostedCP,
+			ComputeNodes: quotaNodes,
+		})
+	}
+	quotaRootDiskSize := defaultMachinePoolRootDiskSize
+	if machinePoolRootDisk != nil {
+		quotaRootDiskSize = machinePoolRootDisk.Size
+	}
+	quotas.Preflight(r, workflows.QuotaOptions{
+		Cluster:             true,
+		Hypershift:          isHostedCP,
+		MultiAZ:             multiAZ,
+		Private:             private,
+		ExistingVPC:         len(subnetIDs) > 0,
+		ComputeMachineType:  computeMachineType,
+		ComputeNodes:        quotaNodes,
+		ComputeRootDiskSize: quotaRootDiskSize,
+	})
+
 	if !output.HasFlag() || r.Reporter.IsTerminal() {
 		r.Reporter.Infof("Creating cluster '%s'", clusterName)
 		if interactive.Enabled() {
@@ -3385,14 +2874,14 @@ func run(cmd *cobra.Command, _ []string) {
 		r.Reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
 	}
 
-	cluster, err := r.OCMClient.CreateCluster(clusterConfig)
+	cluster, err := r.OCMClient.CreateClusterWithAWS(clusterConfig, r.AWSClient)
 	if err != nil {
 		if args.dryRun {
 			r.Reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
 		} else {
 			r.Reporter.Errorf("Failed to create cluster: %s", err)
 		}
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 
 	if args.dryRun {
@@ -3524,89 +3013,6 @@ func handleOidcConfigOptions(r *rosa.Runtime, cmd *cobra.Command, isSTS bool, is
 	return oidcConfig
 }
 
-func zeroToOneFloatValidator(val interface{}) error {
-	if val == "" {
-		return nil
-	}
-	number, err := strconv.ParseFloat(fmt.Sprintf("%v", val), 64)
-	if err != nil {
-		return fmt.Errorf("Failed parsing '%v' into a floating-point number.", val)
-	}
-	if number > 1 || number < 0 {
-		return fmt.Errorf("Expecting a floating-point number between 0 and 1.")
-	}
-	return nil
-}
-
-func durationStringValidator(val interface{}) error {
-	if val == "" {
-		return nil
-	}
-	input, ok := val.(string)
-
-	if !ok {
-		return fmt.Errorf("Can only validate strings, got %v", val)
-	}
-
-	if input == "" {
-		return nil
-	}
-
-	re := regexp.MustCompile("^([0-9]+(.[0-9]+)?(ns|us|µs|ms|s|m|h))+$")
-	regexPass := re.MatchString(input)
-	if !regexPass {
-		return fmt.Errorf("Expecting an integer plus unit of time (without spaces). " +
-			"Options for time units include: ns, us, µs, ms, s, m, h. Examples: 2000000ns, 180s, 2m, etc.")
-	}
-	return nil
-
-}
-
-func nonNegativeIntValidator(val interface{}) error {
-	if val == "" { // if a value is not passed it should not throw an error (optional value)
-		return nil
-	}
-	number, err := strconv.Atoi(fmt.Sprintf("%v", val))
-	if err != nil {
-		return fmt.Errorf("Failed parsing '%v' to an integer number.", val)
-	}
-
-	if number < 0 {
-		return fmt.Errorf("Number must be greater or equal to zero.")
-	}
-
-	return nil
-}
-
-// getValidMaxRangeValidator returns a validator function that asserts a given
-// number is greater than or equal to a fixed minimal number.
-func getValidMaxRangeValidator(min int) func(interface{}) error {
-	return func(val interface{}) error {
-		if val == "" { // Allowing optional inputs
-			return nil
-		}
-
-		max, err := strconv.Atoi(fmt.Sprintf("%v", val))
-		if err != nil {
-			return fmt.Errorf("Failed parsing '%v' to an integer number.", val)
-		}
-
-		if max < min {
-			return fmt.Errorf("max value must be greater or equal than min value %d.", min)
-		}
-
-		return nil
-	}
-}
-
-func intValidator(val interface{}) error {
-	if val == "" { // if a value is not passed it should not throw an error (optional value)
-		return nil
-	}
-	_, err := strconv.Atoi(fmt.Sprintf("%v", val))
-	return err
-}
-
 func minReplicaValidator(multiAZ bool, isHostedCP bool, privateSubnetsCount int) interactive.Validator {
 	return func(val interface{}) error {
 		minReplicas, err := strconv.Atoi(fmt.Sprintf("%v", val))
@@ -3758,6 +3164,39 @@ func parseRFC3339(s string) (time.Time, error) {
 	return time.Parse(time.RFC3339, s)
 }
 
+// validateNetwork runs the local preflight of the subnets, printing the findings, and exits if any
+// of them is an error.
+func validateNetwork(r *rosa.Runtime, options aws.NetworkPreflightOptions) {
+	findings, err := r.AWSClient.ValidateNetwork(options)
+	if err != nil {
+		r.Reporter.Warnf("Unable to check the subnets: %v", err)
+		return
+	}
+	for _, finding := range findings {
+		if finding.Severity == aws.NetworkSeverityWarning {
+			r.Reporter.Warnf("%s. To fix it: %s", finding.Message, finding.Remediation)
+		}
+	}
+	if aws.HasNetworkErrors(findings) {
+		failed := []*aws.NetworkFinding{}
+		for _, finding := range findings {
+			if finding.Severity == aws.NetworkSeverityError {
+				failed = append(failed, finding)
+			}
+		}
+		r.Reporter.Errorf("The subnets aren't configured correctly for the cluster:\n%s",
+			aws.DescribeNetworkFindings(failed))
+		os.Exit(1)
+	}
+}
+
+func cidrOrNil(cidr net.IPNet) *net.IPNet {
+	if ocm.IsEmptyCIDR(cidr) {
+		return nil
+	}
+	return &cidr
+}
+
 func buildCommand(spec ocm.Spec, operatorRolesPrefix string,
 	operatorRolePath string, userSelectedAvailabilityZones bool, labels string) string {
 	command := "rosa create cluster"
@@ -4017,6 +3456,30 @@ func buildTagsCommand(tags map[string]string) []string {
 	return formattedTags
 }
 
+// applyClusterSpecFile loads the cluster definition file and sets every option it defines that
+// wasn't given explicitly on the command line. Going through the flags means that the values
+// from the file are subject to exactly the same validation and defaulting as the options.
+func applyClusterSpecFile(cmd *cobra.Command, path string) error {
+	spec, err := clusterspec.Load(path)
+	if err != nil {
+		return err
+	}
+	for _, flag := range spec.FlagValues() {
+		if cmd.Flags().Lookup(flag.Name) == nil {
+			return fmt.Errorf("Unknown option '%s' in cluster definition file '%s'", flag.Name, path)
+		}
+		if cmd.Flags().Changed(flag.Name) {
+			continue
+		}
+		err = cmd.Flags().Set(flag.Name, flag.Value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for '%s' in cluster definition file '%s': %v",
+				flag.Value, flag.Name, path, err)
+		}
+	}
+	return nil
+}
+
 func getRolePrefix(clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, helper.RandomLabel(4))
 }
@@ -2,10 +2,15 @@ package cluster
 
 import (
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 )
@@ -136,3 +141,58 @@ var _ = Describe("Validates OCP version", func() {
 		})
 	})
 })
+
+var _ = Describe("Cluster definition file", func() {
+	var cmd *cobra.Command
+	var path string
+
+	BeforeEach(func() {
+		cmd = &cobra.Command{}
+		cmd.Flags().String("cluster-name", "", "")
+		cmd.Flags().Int("replicas", 2, "")
+		cmd.Flags().StringSlice("subnet-ids", nil, "")
+		path = filepath.Join(GinkgoT().TempDir(), "cluster.yaml")
+	})
+
+	It("Sets the options that are defined in the file", func() {
+		Expect(os.WriteFile(path, []byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: from-file
+compute:
+  replicas: 3
+network:
+  subnetIDs: [subnet-1, subnet-2]
+`), 0600)).To(Succeed())
+		Expect(applyClusterSpecFile(cmd, path)).To(Succeed())
+		Expect(cmd.Flags().GetString("cluster-name")).To(Equal("from-file"))
+		Expect(cmd.Flags().GetInt("replicas")).To(Equal(3))
+		Expect(cmd.Flags().GetStringSlice("subnet-ids")).To(Equal([]string{"subnet-1", "subnet-2"}))
+		Expect(cmd.Flags().Changed("replicas")).To(BeTrue())
+	})
+
+	It("Gives precedence to the command line options", func() {
+		Expect(os.WriteFile(path, []byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: from-file
+`), 0600)).To(Succeed())
+		Expect(cmd.Flags().Set("cluster-name", "from-flag")).To(Succeed())
+		Expect(applyClusterSpecFile(cmd, path)).To(Succeed())
+		Expect(cmd.Flags().GetString("cluster-name")).To(Equal("from-flag"))
+	})
+
+	It("Reports invalid values with the option name", func() {
+		Expect(os.WriteFile(path, []byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: from-file
+network:
+  machineCIDR: not-a-cidr
+`), 0600)).To(Succeed())
+		cmd.Flags().IPNet("machine-cidr", net.IPNet{}, "")
+		err := applyClusterSpecFile(cmd, path)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("for 'machine-cidr'"))
+	})
+})
@@ -11,11 +11,13 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/helper"
 	mpHelpers "github.com/openshift/rosa/pkg/helper/machinepools"
+	"github.com/openshift/rosa/pkg/helper/quotas"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 	"github.com/spf13/cobra"
 )
 
@@ -417,6 +419,7 @@ func addMachinePool(cmd *cobra.Command, clusterKey string, cluster *cmv1.Cluster
 	_, _, _, _, defaultRootDiskSize, _ :=
 		r.OCMClient.GetDefaultClusterFlavors(cluster.Flavour().ID())
 
+	quotaRootDiskSize := defaultRootDiskSize
 	if args.rootDiskSize != "" || interactive.Enabled() {
 		var rootDiskSizeStr string
 		if args.rootDiskSize == "" {
@@ -458,6 +461,20 @@ func addMachinePool(cmd *cobra.Command, clusterKey string, cluster *cmv1.Cluster
 		if rootDiskSize != defaultRootDiskSize {
 			mpBuilder.RootVolume(cmv1.NewRootVolume().AWS(cmv1.NewAWSVolume().Size(rootDiskSize)))
 		}
+		quotaRootDiskSize = rootDiskSize
+	}
+
+	// Spot instances don't count towards the quotas of on-demand instances
+	if !useSpotInstances {
+		quotaNodes := replicas
+		if autoscaling {
+			quotaNodes = maxReplicas
+		}
+		quotas.Preflight(r, workflows.QuotaOptions{
+			ComputeMachineType:  instanceType,
+			ComputeNodes:        quotaNodes,
+			ComputeRootDiskSize: quotaRootDiskSize,
+		})
 	}
 
 	machinePool, err := mpBuilder.Build()
@@ -12,10 +12,12 @@ import (
 
 	"github.com/openshift/rosa/pkg/helper/machinepools"
 	mpHelpers "github.com/openshift/rosa/pkg/helper/machinepools"
+	"github.com/openshift/rosa/pkg/helper/quotas"
 	"github.com/openshift/rosa/pkg/helper/versions"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
 func addNodePool(cmd *cobra.Command, clusterKey string, cluster *cmv1.Cluster, r *rosa.Runtime) {
@@ -363,6 +365,16 @@ func addNodePool(cmd *cobra.Command, clusterKey string, cluster *cmv1.Cluster, r
 		npBuilder.Version(cmv1.NewVersion().ID(version))
 	}
 
+	quotaNodes := replicas
+	if autoscaling {
+		quotaNodes = maxReplicas
+	}
+	quotas.Preflight(r, workflows.QuotaOptions{
+		Hypershift:         true,
+		ComputeMachineType: instanceType,
+		ComputeNodes:       quotaNodes,
+	})
+
 	nodePool, err := npBuilder.Build()
 	if err != nil {
 		r.Reporter.Errorf("Failed to create machine pool for hosted cluster '%s': %v", clusterKey, err)
@@ -0,0 +1,248 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	name        string
+	cidr        net.IPNet
+	multiAZ     bool
+	privateLink bool
+	noEgress    bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Create a VPC for clusters",
+	Long: "Create a VPC ready to install clusters into, with a CloudFormation stack built from a template " +
+		"embedded in the tool. The VPC has a private and a public subnet in each of 1 or 3 availability " +
+		"zones, NAT gateways for the egress of the private subnets and the tags required by the load " +
+		"balancers. Networks for clusters that use PrivateLink don't tag the public subnets for load " +
+		"balancers, and networks without egress have no public subnets, internet gateway or NAT gateways. " +
+		"The subnet IDs to use with 'rosa create cluster --subnet-ids' are printed once the network is ready.",
+	Example: `  # Create a network with subnets in 3 availability zones
+  rosa create network --name mynetwork --multi-az --mode auto
+
+  # Print the template and the command to create a network for a PrivateLink cluster without egress
+  rosa create network --name mynetwork --private-link --no-egress --mode manual`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the network, used to name the CloudFormation stack and to tag the resources.",
+	)
+	flags.IPNetVar(
+		&args.cidr,
+		"cidr",
+		net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(16, 32)},
+		fmt.Sprintf("CIDR of the VPC, it should be between /%d and /%d. It is also the machine CIDR of the "+
+			"clusters installed into the network.", aws.NetworkPrefixMin, aws.NetworkPrefixMax),
+	)
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Create subnets in 3 availability zones instead of 1.",
+	)
+	flags.BoolVar(
+		&args.privateLink,
+		"private-link",
+		false,
+		"Create the network for clusters that use PrivateLink, its public subnets aren't used by load balancers.",
+	)
+	flags.BoolVar(
+		&args.noEgress,
+		"no-egress",
+		false,
+		"Create only private subnets, without internet gateway or NAT gateways. Requires '--private-link'.",
+	)
+
+	aws.AddModeFlag(Cmd)
+	interactive.AddFlag(flags)
+	output.AddFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("%s", err)
+	}
+
+	if interactive.Enabled() {
+		args.name, err = interactive.GetString(interactive.Input{
+			Question: "Network name",
+			Help:     cmd.Flags().Lookup("name").Usage,
+			Default:  args.name,
+			Required: true,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid network name: %s", err)
+		}
+		args.cidr, err = interactive.GetIPNet(interactive.Input{
+			Question: "VPC CIDR",
+			Help:     cmd.Flags().Lookup("cidr").Usage,
+			Default:  args.cidr,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid CIDR value: %s", err)
+		}
+		args.multiAZ, err = interactive.GetBool(interactive.Input{
+			Question: "Multiple availability zones",
+			Help:     cmd.Flags().Lookup("multi-az").Usage,
+			Default:  args.multiAZ,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid multi-AZ value: %s", err)
+		}
+		args.privateLink, err = interactive.GetBool(interactive.Input{
+			Question: "PrivateLink",
+			Help:     cmd.Flags().Lookup("private-link").Usage,
+			Default:  args.privateLink,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid PrivateLink value: %s", err)
+		}
+		if args.privateLink {
+			args.noEgress, err = interactive.GetBool(interactive.Input{
+				Question: "Without egress",
+				Help:     cmd.Flags().Lookup("no-egress").Usage,
+				Default:  args.noEgress,
+			})
+			if err != nil {
+				return exitcode.InvalidInput.Errorf("Expected a valid egress value: %s", err)
+			}
+		}
+		mode, err = interactive.GetOption(interactive.Input{
+			Question: "Network creation mode",
+			Help:     cmd.Flags().Lookup("mode").Usage,
+			Default:  aws.ModeAuto,
+			Options:  aws.Modes,
+			Required: true,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid network creation mode: %s", err)
+		}
+	}
+	if args.name == "" {
+		return exitcode.InvalidInput.Errorf("Expected a name for the network")
+	}
+
+	options := aws.NetworkStackOptions{
+		Name:        args.name,
+		CIDR:        &args.cidr,
+		MultiAZ:     args.multiAZ,
+		PrivateLink: args.privateLink,
+		Egress:      !args.noEgress,
+	}
+	parameters, err := options.Parameters()
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		if !confirm.Prompt(true, "Create network '%s'?", args.name) {
+			return nil
+		}
+		r.Reporter.Infof("Creating network '%s', this can take a few minutes", args.name)
+		network, err := r.AWSClient.CreateNetworkStack(options)
+		if err != nil {
+			return err
+		}
+		if output.HasFlag() {
+			return output.Print(network)
+		}
+		r.Reporter.Infof("Created network '%s' with VPC '%s'", args.name, network.VpcID)
+		r.Reporter.Infof("Private subnets: %s", strings.Join(network.PrivateSubnetIDs, ","))
+		if len(network.PublicSubnetIDs) > 0 {
+			r.Reporter.Infof("Public subnets: %s", strings.Join(network.PublicSubnetIDs, ","))
+		}
+		r.Reporter.Infof("To create a cluster in the network run:\n\n"+
+			"\trosa create cluster %s\n", createClusterFlags(network))
+	case aws.ModeManual:
+		template, err := aws.NetworkTemplate()
+		if err != nil {
+			return err
+		}
+		stackName := aws.NetworkStackName(args.name)
+		r.Reporter.Infof("Save the following template to '%s.json' and run this command to create "+
+			"the network:\n\n%s\n", stackName, buildCommand(stackName, parameters))
+		fmt.Println(template)
+	default:
+		return exitcode.InvalidInput.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+	return nil
+}
+
+func buildCommand(stackName string, parameters map[string]string) string {
+	keys := helper.MapKeys(parameters)
+	sort.Strings(keys)
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = fmt.Sprintf("ParameterKey=%s,ParameterValue=%s", key, parameters[key])
+	}
+	return awscb.NewCloudFormationCommandBuilder().
+		SetCommand(awscb.CreateStack).
+		AddParam(awscb.StackName, stackName).
+		AddParam(awscb.TemplateBody, fmt.Sprintf("file://%s.json", stackName)).
+		AddParam(awscb.Parameters, strings.Join(values, " ")).
+		AddTags(map[string]string{
+			tags.NetworkName:   parameters["Name"],
+			tags.RedHatManaged: "true",
+		}).
+		Build()
+}
+
+func createClusterFlags(network *aws.NetworkStack) string {
+	flags := []string{"--subnet-ids " + strings.Join(network.SubnetIDs(args.privateLink), ",")}
+	flags = append(flags, "--machine-cidr "+args.cidr.String())
+	if args.multiAZ {
+		flags = append(flags, "--multi-az")
+	}
+	if args.privateLink {
+		flags = append(flags, "--private-link")
+	}
+	return strings.Join(flags, " ")
+}
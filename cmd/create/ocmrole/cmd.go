@@ -25,6 +25,7 @@ import (
 	linkocmrole "github.com/openshift/rosa/cmd/link/ocmrole"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive"
@@ -102,6 +103,7 @@ func init() {
 	flags.MarkHidden("mp")
 
 	aws.AddModeFlag(Cmd)
+	iac.AddFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -116,6 +118,11 @@ func run(cmd *cobra.Command, argv []string) {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	_, err = iac.Format()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 
 	env, err := ocm.GetEnv()
 	if err != nil {
@@ -305,7 +312,6 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the ocm role and policies:\n")
 		}
 		var commands string
 		commands, err = buildCommands(
@@ -323,8 +329,11 @@ func run(cmd *cobra.Command, argv []string) {
 			r.Reporter.Errorf("Failed to generate commands for manual mode: %v", err)
 			os.Exit(1)
 		}
-
-		fmt.Println(commands)
+		err = iac.Print(r.Reporter, "the ocm role and policies", commands)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
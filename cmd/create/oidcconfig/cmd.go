@@ -17,11 +17,7 @@ limitations under the License.
 package oidcconfig
 
 import (
-	// nolint:gosec
-
-	//#nosec GSC-G505 -- Import blacklist: crypto/sha1
-
-	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -33,14 +29,17 @@ import (
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	. "github.com/openshift/rosa/pkg/constants"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/helper/oidc_config"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 	"github.com/spf13/cobra"
 	"github.com/zgalor/weberr"
 )
@@ -66,8 +65,6 @@ var Cmd = &cobra.Command{
 }
 
 const (
-	maxLengthUserPrefix = 15
-
 	rawFilesFlag   = "raw-files"
 	userPrefixFlag = "prefix"
 	managedFlag    = "managed"
@@ -106,6 +103,7 @@ func init() {
 	)
 
 	aws.AddModeFlag(Cmd)
+	iac.AddFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -141,6 +139,11 @@ func run(cmd *cobra.Command, argv []string) {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	_, err = iac.Format()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 
 	// Get AWS region
 	region, err := aws.GetRegion(arguments.GetRegion())
@@ -220,7 +223,7 @@ func run(cmd *cobra.Command, argv []string) {
 					Question:   "Prefix for OIDC",
 					Help:       cmd.Flags().Lookup(userPrefixFlag).Usage,
 					Default:    args.userPrefix,
-					Validators: []interactive.Validator{interactive.MaxLength(maxLengthUserPrefix)},
+					Validators: []interactive.Validator{interactive.MaxLength(workflows.MaxOidcConfigPrefixLength)},
 				})
 				if err != nil {
 					r.Reporter.Errorf("Expected a valid prefix for the configuration: %s", err)
@@ -233,38 +236,19 @@ func run(cmd *cobra.Command, argv []string) {
 				if !output.HasFlag() && r.Reporter.IsTerminal() && mode == aws.ModeAuto {
 					r.Reporter.Infof("Using %s for the installer role", args.installerRoleArn)
 				}
-				err := aws.ARNValidator(args.installerRoleArn)
-				if err != nil {
-					r.Reporter.Errorf("Expected a valid ARN: %s", err)
-					os.Exit(1)
-				}
-				roleExists, _, err := r.AWSClient.CheckRoleExists(roleName)
+				err := workflows.ValidateInstallerRole(r.AWSClient, args.installerRoleArn)
 				if err != nil {
-					r.Reporter.Errorf("There was a problem checking if role '%s' exists: %v", args.installerRoleArn, err)
-					os.Exit(1)
-				}
-				if !roleExists {
-					r.Reporter.Errorf("Role '%s' does not exist", args.installerRoleArn)
-					os.Exit(1)
-				}
-				isValid, err := r.AWSClient.ValidateAccountRoleVersionCompatibility(
-					roleName, aws.InstallerAccountRole, MinorVersionForGetSecret)
-				if err != nil {
-					r.Reporter.Errorf("There was a problem listing role tags: %v", err)
-					os.Exit(1)
-				}
-				if !isValid {
-					r.Reporter.Errorf("Role '%s' is not of minimum version '%s'", args.installerRoleArn, MinorVersionForGetSecret)
-					os.Exit(1)
+					r.Reporter.Errorf("%s", err)
+					os.Exit(int(exitcode.Of(err)))
 				}
 			}
 		}
 
 		args.userPrefix = strings.Trim(args.userPrefix, " \t")
 
-		if len([]rune(args.userPrefix)) > maxLengthUserPrefix {
+		if len([]rune(args.userPrefix)) > workflows.MaxOidcConfigPrefixLength {
 			r.Reporter.Errorf("Expected a valid prefix for the configuration: "+
-				"length of prefix is limited to %d characters", maxLengthUserPrefix)
+				"length of prefix is limited to %d characters", workflows.MaxOidcConfigPrefixLength)
 			os.Exit(1)
 		}
 	}
@@ -329,87 +313,29 @@ type CreateUnmanagedOidcConfigAutoStrategy struct {
 	oidcConfig *oidc_config.OidcConfigInput
 }
 
-const (
-	discoveryDocumentKey = ".well-known/openid-configuration"
-	jwksKey              = "keys.json"
-)
-
 func (s *CreateUnmanagedOidcConfigAutoStrategy) execute(r *rosa.Runtime) {
-	bucketUrl := s.oidcConfig.IssuerUrl
-	bucketName := s.oidcConfig.BucketName
-	discoveryDocument := s.oidcConfig.DiscoveryDocument
-	jwks := s.oidcConfig.Jwks
-	privateKey := s.oidcConfig.PrivateKey
-	privateKeySecretName := s.oidcConfig.PrivateKeySecretName
-	installerRoleArn := args.installerRoleArn
 	var spin *spinner.Spinner
 	if !output.HasFlag() && r.Reporter.IsTerminal() {
 		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-		r.Reporter.Infof("Setting up unmanaged OIDC configuration '%s'", bucketName)
+		r.Reporter.Infof("Setting up unmanaged OIDC configuration '%s'", s.oidcConfig.BucketName)
 	}
 	if spin != nil {
 		spin.Start()
 	}
-	err := r.AWSClient.CreateS3Bucket(bucketName, args.region)
-	if err != nil {
-		r.Reporter.Errorf("There was a problem creating S3 bucket '%s': %s", bucketName, err)
-		os.Exit(1)
-	}
-	err = r.AWSClient.PutPublicReadObjectInS3Bucket(
-		bucketName, strings.NewReader(discoveryDocument), discoveryDocumentKey)
-	if err != nil {
-		r.Reporter.Errorf("There was a problem populating discovery "+
-			"document to S3 bucket '%s': %s", bucketName, err)
-		os.Exit(1)
-	}
-	err = r.AWSClient.PutPublicReadObjectInS3Bucket(bucketName, bytes.NewReader(jwks), jwksKey)
-	if err != nil {
-		if spin != nil {
-			spin.Stop()
-		}
-		r.Reporter.Errorf("There was a problem populating JWKS "+
-			"to S3 bucket '%s': %s", bucketName, err)
-		os.Exit(1)
-	}
-	secretARN, err := r.AWSClient.CreateSecretInSecretsManager(privateKeySecretName, string(privateKey[:]))
-	if err != nil {
-		r.Reporter.Errorf("There was a problem saving private key to secrets manager: %s", err)
-		os.Exit(1)
-	}
-	oidcConfig, err := v1.NewOidcConfig().
-		Managed(false).
-		SecretArn(secretARN).
-		IssuerUrl(bucketUrl).
-		InstallerRoleArn(installerRoleArn).
-		Build()
-	if err == nil {
-		oidcConfig, err = r.OCMClient.CreateOidcConfig(oidcConfig)
+	result, err := workflows.CreateOidcConfig(context.Background(), newClients(r), workflows.OidcConfigOptions{
+		Prefix:           args.userPrefix,
+		Region:           args.region,
+		InstallerRoleARN: args.installerRoleArn,
+		Input:            s.oidcConfig,
+	})
+	if spin != nil {
+		spin.Stop()
 	}
 	if err != nil {
-		if spin != nil {
-			spin.Stop()
-		}
-		r.Reporter.Errorf("There was a problem building your unmanaged OIDC Configuration %v.\n"+
-			"Please refer to documentation and try again through:\n"+
-			"\trosa register oidc-config --issuer-url %s --secret-arn %s --installer-role-arn %s",
-			err, bucketUrl, secretARN, installerRoleArn)
-		os.Exit(1)
-	}
-	if output.HasFlag() {
-		err = output.Print(oidcConfig)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-	if r.Reporter.IsTerminal() {
-		if spin != nil {
-			spin.Stop()
-		}
-		output := fmt.Sprintf(InformOperatorRolesOutput, oidcConfig.ID())
-		r.Reporter.Infof(output)
+		r.Reporter.Errorf("%s", err)
+		os.Exit(int(exitcode.Of(err)))
 	}
+	printOidcConfig(r, result.OidcConfig)
 }
 
 type CreateUnmanagedOidcConfigManualStrategy struct {
@@ -480,7 +406,7 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		SetCommand(awscb.PutObject).
 		AddParam(awscb.Body, fmt.Sprintf("./%s", discoveryDocumentFilename)).
 		AddParam(awscb.Bucket, bucketName).
-		AddParam(awscb.Key, discoveryDocumentKey).
+		AddParam(awscb.Key, workflows.DiscoveryDocumentKey).
 		AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
 		Build()
 	commands = append(commands, putDiscoveryDocumentCommand)
@@ -495,7 +421,7 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		SetCommand(awscb.PutObject).
 		AddParam(awscb.Body, fmt.Sprintf("./%s", jwksFilename)).
 		AddParam(awscb.Bucket, bucketName).
-		AddParam(awscb.Key, jwksKey).
+		AddParam(awscb.Key, workflows.JwksKey).
 		AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
 		Build()
 	commands = append(commands, putJwksCommand)
@@ -512,7 +438,11 @@ func (s *CreateUnmanagedOidcConfigManualStrategy) execute(r *rosa.Runtime) {
 		Build()
 	commands = append(commands, createSecretCommand)
 	commands = append(commands, fmt.Sprintf("rm %s", privateKeyFilename))
-	fmt.Println(awscb.JoinCommands(commands))
+	err = iac.Print(r.Reporter, "the OIDC configuration", awscb.JoinCommands(commands))
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 	if r.Reporter.IsTerminal() {
 		r.Reporter.Infof("Please run commands above to generate OIDC compliant configuration in your AWS account. " +
 			"To register this OIDC Configuration, please run the following command:\n" +
@@ -534,22 +464,31 @@ func (s *CreateManagedOidcConfigAutoStrategy) execute(r *rosa.Runtime) {
 	if spin != nil {
 		spin.Start()
 	}
-	oidcConfig, err := v1.NewOidcConfig().Managed(true).Build()
-	if err != nil {
-		r.Reporter.Errorf("There was a problem building the managed OIDC Configuration: %v", err)
-		os.Exit(1)
+	result, err := workflows.CreateOidcConfig(context.Background(), newClients(r), workflows.OidcConfigOptions{
+		Managed: true,
+	})
+	if spin != nil {
+		spin.Stop()
 	}
-	oidcConfig, err = r.OCMClient.CreateOidcConfig(oidcConfig)
 	if err != nil {
-		if spin != nil {
-			spin.Stop()
-		}
-		r.Reporter.Errorf("There was a problem registering your managed OIDC Configuration: %v", err)
-		os.Exit(1)
+		r.Reporter.Errorf("%s", err)
+		os.Exit(int(exitcode.Of(err)))
+	}
+	s.oidcConfigInput.IssuerUrl = result.OidcConfig.IssuerUrl()
+	printOidcConfig(r, result.OidcConfig)
+}
+
+func newClients(r *rosa.Runtime) *workflows.Clients {
+	return &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
 	}
-	s.oidcConfigInput.IssuerUrl = oidcConfig.IssuerUrl()
+}
+
+func printOidcConfig(r *rosa.Runtime, oidcConfig *v1.OidcConfig) {
 	if output.HasFlag() {
-		err = output.Print(oidcConfig)
+		err := output.Print(oidcConfig)
 		if err != nil {
 			r.Reporter.Errorf("%s", err)
 			os.Exit(1)
@@ -557,11 +496,7 @@ func (s *CreateManagedOidcConfigAutoStrategy) execute(r *rosa.Runtime) {
 		os.Exit(0)
 	}
 	if r.Reporter.IsTerminal() {
-		if spin != nil {
-			spin.Stop()
-		}
-		output := fmt.Sprintf(InformOperatorRolesOutput, oidcConfig.ID())
-		r.Reporter.Infof(output)
+		r.Reporter.Infof(fmt.Sprintf(InformOperatorRolesOutput, oidcConfig.ID()))
 	}
 }
 
@@ -17,13 +17,8 @@ limitations under the License.
 package oidcprovider
 
 import (
-	// nolint:gosec
-	"bytes"
-	"crypto/sha1" //#nosec GSC-G505 -- Import blacklist: crypto/sha1
-	"encoding/hex"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"os"
 	"strings"
 
@@ -32,12 +27,14 @@ import (
 
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
 var Cmd = &cobra.Command{
@@ -72,6 +69,7 @@ func init() {
 
 	ocm.AddOptionalClusterFlag(Cmd)
 	aws.AddModeFlag(Cmd)
+	iac.AddFlag(Cmd)
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
@@ -109,6 +107,11 @@ func run(cmd *cobra.Command, argv []string) {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	_, err = iac.Format()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 
 	// Determine if interactive mode is needed
 	if !isProgmaticallyCalled && !interactive.Enabled() &&
@@ -225,13 +228,14 @@ func run(cmd *cobra.Command, argv []string) {
 				ocm.Response:  ocm.Failure,
 			})
 		}
-		if r.Reporter.IsTerminal() {
-			r.Reporter.Infof("Run the following commands to create the OIDC provider:\n")
-		}
 		r.OCMClient.LogEvent("ROSACreateOIDCProviderModeManual", map[string]string{
 			ocm.ClusterID: clusterKey,
 		})
-		fmt.Println(commands)
+		err = iac.Print(r.Reporter, "the OIDC provider", commands)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
@@ -239,18 +243,24 @@ func run(cmd *cobra.Command, argv []string) {
 }
 
 func createProvider(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) error {
-	thumbprint, err := getThumbprint(oidcEndpointUrl)
+	result, err := workflows.CreateOidcProvider(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, workflows.OidcProviderOptions{
+		IssuerURL: oidcEndpointUrl,
+		ClusterID: clusterId,
+	})
 	if err != nil {
 		return err
 	}
-	r.Reporter.Debugf("Using thumbprint '%s'", thumbprint)
-
-	oidcProviderARN, err := r.AWSClient.CreateOpenIDConnectProvider(oidcEndpointUrl, thumbprint, clusterId)
-	if err != nil {
-		return err
+	if result.Existed {
+		r.Reporter.Infof("OIDC provider already exists.")
+		return nil
 	}
+	r.Reporter.Debugf("Using thumbprint '%s'", result.Thumbprint)
 	if !output.HasFlag() || r.Reporter.IsTerminal() {
-		r.Reporter.Infof("Created OIDC provider with ARN '%s'", oidcProviderARN)
+		r.Reporter.Infof("Created OIDC provider with ARN '%s'", result.ARN)
 	}
 
 	return nil
@@ -259,7 +269,7 @@ func createProvider(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) e
 func buildCommands(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) (string, error) {
 	commands := []string{}
 
-	thumbprint, err := getThumbprint(oidcEndpointUrl)
+	thumbprint, err := workflows.Thumbprint(oidcEndpointUrl)
 	if err != nil {
 		return "", err
 	}
@@ -285,39 +295,3 @@ func buildCommands(r *rosa.Runtime, oidcEndpointUrl string, clusterId string) (s
 
 	return awscb.JoinCommands(commands), nil
 }
-
-func getThumbprint(oidcEndpointURL string) (string, error) {
-	connect, err := url.ParseRequestURI(oidcEndpointURL)
-	if err != nil {
-		return "", err
-	}
-
-	response, err := http.Get(fmt.Sprintf("https://%s:443", connect.Host))
-	if err != nil {
-		return "", err
-	}
-
-	certChain := response.TLS.PeerCertificates
-
-	// Grab the CA in the chain
-	for _, cert := range certChain {
-		if cert.IsCA {
-			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
-				return sha1Hash(cert.Raw), nil
-			}
-		}
-	}
-
-	// Fall back to using the last certficiate in the chain
-	cert := certChain[len(certChain)-1]
-	return sha1Hash(cert.Raw), nil
-}
-
-// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
-func sha1Hash(data []byte) string {
-	// nolint:gosec
-	hasher := sha1.New()
-	hasher.Write(data)
-	hashed := hasher.Sum(nil)
-	return hex.EncodeToString(hashed)
-}
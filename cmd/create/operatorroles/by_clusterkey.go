@@ -1,6 +1,7 @@
 package operatorroles
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -8,11 +9,13 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
 func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
@@ -26,7 +29,7 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 		os.Exit(1)
 	}
 	// Check to see if IAM operator roles have already created
-	missingRoles, err := validateOperatorRoles(r, cluster)
+	missingRoles, err := workflows.MissingOperatorRoles(r.AWSClient, cluster)
 	if err != nil {
 		if strings.Contains(err.Error(), "AccessDenied") {
 			r.Reporter.Debugf("Failed to verify if operator roles exist: %s", err)
@@ -52,14 +55,12 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 		!args.forcePolicyCreation {
 		r.Reporter.Infof("Cluster '%s' is %s and does not need additional configuration.",
 			clusterKey, cluster.State())
+		if err := r.PrintPlan(); err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
-	roleName, err := aws.GetInstallerAccountRoleName(cluster)
-	if err != nil {
-		r.Reporter.Errorf("Expected parsing role account role '%s': %v", cluster.AWS().STS().RoleARN(), err)
-		os.Exit(1)
-	}
-
 	path, err := aws.GetPathFromAccountRole(cluster, aws.AccountRoles[aws.InstallerAccountRole].Name)
 	if err != nil {
 		r.Reporter.Errorf("Expected a valid path for '%s': %v", cluster.AWS().STS().RoleARN(), err)
@@ -70,11 +71,6 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 			"This ARN path will be used for subsequent created operator roles and policies.",
 			path, cluster.AWS().STS().RoleARN())
 	}
-	accountRoleVersion, err := r.AWSClient.GetAccountRoleVersion(roleName)
-	if err != nil {
-		r.Reporter.Errorf("Error getting account role version %s", err)
-		os.Exit(1)
-	}
 	managedPolicies := cluster.AWS().STS().ManagedPolicies()
 	if args.forcePolicyCreation && managedPolicies {
 		r.Reporter.Warnf("Forcing creation of policies only works for unmanaged policies")
@@ -98,8 +94,22 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 		if !output.HasFlag() || r.Reporter.IsTerminal() {
 			r.Reporter.Infof("Creating roles using '%s'", r.Creator.ARN)
 		}
-		err = createRoles(r, operatorRolePolicyPrefix, permissionsBoundary, cluster,
-			accountRoleVersion, policies, defaultPolicyVersion, credRequests, managedPolicies, hostedCPPolicies)
+		result, err := workflows.CreateOperatorRoles(context.Background(), &workflows.Clients{
+			OCM:     r.OCMClient,
+			AWS:     r.AWSClient,
+			Creator: r.Creator,
+		}, workflows.OperatorRolesOptions{
+			Cluster:             cluster,
+			PermissionsBoundary: permissionsBoundary,
+			ForcePolicyCreation: args.forcePolicyCreation,
+			Policies:            policies,
+			PolicyVersion:       defaultPolicyVersion,
+		})
+		if result != nil && (!output.HasFlag() || r.Reporter.IsTerminal()) {
+			for _, role := range result.Roles {
+				r.Reporter.Infof("Created role '%s' with ARN '%s'", role.Name, role.ARN)
+			}
+		}
 		if err != nil {
 			r.Reporter.Errorf("There was an error creating the operator roles: %s", err)
 			isThrottle := "false"
@@ -130,12 +140,15 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the operator roles:\n")
 		}
 		r.OCMClient.LogEvent("ROSACreateOperatorRolesModeManual", map[string]string{
 			ocm.ClusterID: clusterKey,
 		})
-		fmt.Println(commands)
+		err = iac.Print(r.Reporter, "the operator roles", commands)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
@@ -144,108 +157,6 @@ func handleOperatorRoleCreationByClusterKey(r *rosa.Runtime, env string,
 	return nil
 }
 
-func createRoles(r *rosa.Runtime,
-	prefix string, permissionsBoundary string,
-	cluster *cmv1.Cluster, accountRoleVersion string, policies map[string]*cmv1.AWSSTSPolicy,
-	defaultVersion string, credRequests map[string]*cmv1.STSOperator, managedPolicies bool, hostedCPPolicies bool) error {
-	for credrequest, operator := range credRequests {
-		ver := cluster.Version()
-		if ver != nil && operator.MinVersion() != "" {
-			isSupported, err := ocm.CheckSupportedVersion(ocm.GetVersionMinor(ver.ID()), operator.MinVersion())
-			if err != nil {
-				r.Reporter.Errorf("Error validating operator role '%s' version %s", operator.Name(), err)
-				os.Exit(1)
-			}
-			if !isSupported {
-				continue
-			}
-		}
-		roleName, _ := aws.FindOperatorRoleNameBySTSOperator(cluster, operator)
-		if roleName == "" {
-			return fmt.Errorf("Failed to find operator IAM role")
-		}
-
-		path, err := aws.GetPathFromAccountRole(cluster, aws.AccountRoles[aws.InstallerAccountRole].Name)
-		if err != nil {
-			return err
-		}
-
-		var policyARN string
-		filename := aws.GetOperatorPolicyKey(credrequest, hostedCPPolicies, false)
-		if managedPolicies {
-			policyARN, err = aws.GetManagedPolicyARN(policies, filename)
-			if err != nil {
-				return err
-			}
-		} else {
-			policyARN = aws.GetOperatorPolicyARN(r.Creator.AccountID, prefix, operator.Namespace(),
-				operator.Name(), path)
-			policyDetails := aws.GetPolicyDetails(policies, filename)
-
-			operatorPolicyTags := map[string]string{
-				tags.OpenShiftVersion:  accountRoleVersion,
-				tags.RolePrefix:        prefix,
-				tags.RedHatManaged:     helper.True,
-				tags.OperatorNamespace: operator.Namespace(),
-				tags.OperatorName:      operator.Name(),
-			}
-
-			if args.forcePolicyCreation {
-				policyARN, err = r.AWSClient.ForceEnsurePolicy(policyARN, policyDetails,
-					defaultVersion, operatorPolicyTags, path)
-				if err != nil {
-					return err
-				}
-			} else {
-				policyARN, err = r.AWSClient.EnsurePolicy(policyARN, policyDetails,
-					defaultVersion, operatorPolicyTags, path)
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		policyDetails := aws.GetPolicyDetails(policies, "operator_iam_role_policy")
-		policy, err := aws.GenerateOperatorRolePolicyDoc(cluster, r.Creator.AccountID, operator, policyDetails)
-		if err != nil {
-			return err
-		}
-
-		r.Reporter.Debugf("Creating role '%s'", roleName)
-		tagsList := map[string]string{
-			tags.OperatorNamespace: operator.Namespace(),
-			tags.OperatorName:      operator.Name(),
-			tags.RedHatManaged:     helper.True,
-		}
-		if !ocm.IsOidcConfigReusable(cluster) {
-			tagsList[tags.ClusterID] = cluster.ID()
-		}
-		if managedPolicies {
-			tagsList[tags.ManagedPolicies] = helper.True
-		}
-		if hostedCPPolicies {
-			tagsList[tags.HypershiftPolicies] = helper.True
-		}
-
-		roleARN, err := r.AWSClient.EnsureRole(roleName, policy, permissionsBoundary, accountRoleVersion,
-			tagsList, path, managedPolicies)
-		if err != nil {
-			return err
-		}
-		if !output.HasFlag() || r.Reporter.IsTerminal() {
-			r.Reporter.Infof("Created role '%s' with ARN '%s'", roleName, roleARN)
-		}
-
-		r.Reporter.Debugf("Attaching permission policy '%s' to role '%s'", policyARN, roleName)
-		err = r.AWSClient.AttachRolePolicy(roleName, policyARN)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func buildCommands(r *rosa.Runtime, env string,
 	prefix string, permissionsBoundary string, defaultPolicyVersion string, cluster *cmv1.Cluster,
 	policies map[string]*cmv1.AWSSTSPolicy, credRequests map[string]*cmv1.STSOperator,
@@ -353,29 +264,6 @@ func buildCommands(r *rosa.Runtime, env string,
 	return awscb.JoinCommands(commands), nil
 }
 
-func validateOperatorRoles(r *rosa.Runtime, cluster *cmv1.Cluster) ([]string, error) {
-	var missingRoles []string
-	operatorIAMRoles := cluster.AWS().STS().OperatorIAMRoles()
-	if len(operatorIAMRoles) == 0 {
-		return missingRoles, fmt.Errorf("No Operator IAM roles found for cluster %s", cluster.Name())
-	}
-	for _, operatorIAMRole := range operatorIAMRoles {
-		roleARN := operatorIAMRole.RoleARN()
-		roleName, err := aws.GetResourceIdFromARN(roleARN)
-		if err != nil {
-			return missingRoles, err
-		}
-		exists, _, err := r.AWSClient.CheckRoleExists(roleName)
-		if err != nil {
-			return missingRoles, err
-		}
-		if !exists {
-			missingRoles = append(missingRoles, roleName)
-		}
-	}
-	return missingRoles, nil
-}
-
 func validateOperatorRolesMatchOidcProvider(r *rosa.Runtime, cluster *cmv1.Cluster) error {
 	operatorRolesList, err := convertV1OperatorIAMRoleIntoOcmOperatorIamRole(
 		cluster.AWS().STS().OperatorIAMRoles())
@@ -13,6 +13,7 @@ import (
 
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive"
@@ -208,12 +209,15 @@ func handleOperatorRoleCreationByPrefix(r *rosa.Runtime, env string,
 		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the operator roles:\n")
 		}
 		r.OCMClient.LogEvent("ROSACreateOperatorRolesModeManual", map[string]string{
 			ocm.OperatorRolesPrefix: operatorRolesPrefix,
 		})
-		fmt.Println(commands)
+		err = iac.Print(r.Reporter, "the operator roles", commands)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
 		os.Exit(1)
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -125,7 +126,8 @@ func init() {
 	)
 	flags.MarkHidden("channel-group")
 
-	aws.AddModeFlag(Cmd)
+	aws.AddPlanModeFlag(Cmd)
+	iac.AddFlag(Cmd)
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
 }
@@ -153,7 +155,8 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
-	mode, err := aws.GetMode()
+	mode := r.GetPlanMode()
+	_, err = iac.Format()
 	if err != nil {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
@@ -190,7 +193,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
-	if interactive.Enabled() && !isProgmaticallyCalled {
+	if interactive.Enabled() && !isProgmaticallyCalled && r.Plan == nil {
 		mode, err = interactive.GetOption(interactive.Input{
 			Question: "Role creation mode",
 			Help:     cmd.Flags().Lookup("mode").Usage,
@@ -254,16 +257,24 @@ func run(cmd *cobra.Command, argv []string) error {
 			r.Reporter.Errorf("Error getting latest version: %s", err)
 			os.Exit(1)
 		}
-		return handleOperatorRoleCreationByPrefix(r, env, permissionsBoundary,
+		err = handleOperatorRoleCreationByPrefix(r, env, permissionsBoundary,
 			mode, policies, latestPolicyVersion)
+		if err != nil {
+			return err
+		}
+		return r.PrintPlan()
 	}
 	latestPolicyVersion, err := r.OCMClient.GetLatestVersion(cluster.Version().ChannelGroup())
 	if err != nil {
 		r.Reporter.Errorf("Error getting latest version: %s", err)
 		os.Exit(1)
 	}
-	return handleOperatorRoleCreationByClusterKey(r, env, permissionsBoundary,
+	err = handleOperatorRoleCreationByClusterKey(r, env, permissionsBoundary,
 		mode, policies, latestPolicyVersion)
+	if err != nil {
+		return err
+	}
+	return r.PrintPlan()
 }
 
 func convertV1OperatorIAMRoleIntoOcmOperatorIamRole(
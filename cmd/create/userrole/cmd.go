@@ -25,6 +25,7 @@ import (
 	linkuser "github.com/openshift/rosa/cmd/link/userrole"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
 	"github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive"
@@ -79,6 +80,7 @@ func init() {
 	)
 
 	aws.AddModeFlag(Cmd)
+	iac.AddFlag(Cmd)
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
 }
@@ -92,6 +94,11 @@ func run(cmd *cobra.Command, argv []string) {
 		r.Reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	_, err = iac.Format()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 
 	env, err := ocm.GetEnv()
 	if err != nil {
@@ -236,7 +243,6 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 		if r.Reporter.IsTerminal() {
 			r.Reporter.Infof("All policy files saved to the current directory")
-			r.Reporter.Infof("Run the following commands to create the account roles and policies:\n")
 		}
 		commands := buildCommands(
 			prefix,
@@ -246,7 +252,11 @@ func run(cmd *cobra.Command, argv []string) {
 			env,
 			permissionsBoundary,
 		)
-		fmt.Println(commands)
+		err = iac.Print(r.Reporter, "the user role", commands)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 
 	default:
 		r.Reporter.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 	"github.com/spf13/cobra"
 )
@@ -44,6 +45,10 @@ var Cmd = &cobra.Command{
 	},
 }
 
+func init() {
+	output.AddFlag(Cmd)
+}
+
 func run(_ *cobra.Command, argv []string) {
 	r := rosa.NewRuntime().WithOCM()
 	defer r.Cleanup()
@@ -59,6 +64,15 @@ func run(_ *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		err = output.Print(addOn)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	printDescription(addOn)
 	printCredentialRequests(addOn.CredentialsRequests())
 	printParameters(addOn.Parameters())
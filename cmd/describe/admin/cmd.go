@@ -24,6 +24,7 @@ import (
 
 	cadmin "github.com/openshift/rosa/cmd/create/admin"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -38,6 +39,15 @@ var Cmd = &cobra.Command{
 
 func init() {
 	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
+}
+
+// adminOutput is the representation of the cluster administrator used by the structured output
+// formats.
+type adminOutput struct {
+	Exists   bool   `json:"exists"`
+	Username string `json:"username"`
+	APIURL   string `json:"api_url"`
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -56,6 +66,19 @@ func run(cmd *cobra.Command, _ []string) {
 	// check if cluster-admin user already exists
 	existingClusterAdminIdp, _ := cadmin.FindExistingClusterAdminIDP(cluster, r)
 
+	if output.HasFlag() {
+		err := output.Print(adminOutput{
+			Exists:   existingClusterAdminIdp != nil,
+			Username: cadmin.ClusterAdminUsername,
+			APIURL:   cluster.API().URL(),
+		})
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if existingClusterAdminIdp != nil {
 		r.Reporter.Infof("There is an admin on cluster '%s'. To login, run the following command:\n"+
 			"   oc login %s --username %s", clusterKey, cluster.API().URL(), cadmin.ClusterAdminUsername)
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "Show details of the autoscaler of a cluster",
+	Long:  "Show details of the autoscaler configuration of a cluster.",
+	Example: `  # Describe the autoscaler of a cluster named "mycluster"
+  rosa describe autoscaler --cluster=mycluster`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+
+	if ocm.IsHyperShiftCluster(cluster) {
+		r.Reporter.Errorf("Hosted Control Plane clusters do not support cluster-autoscaler configuration")
+		os.Exit(1)
+	}
+
+	r.Reporter.Debugf("Loading autoscaler for cluster '%s'", clusterKey)
+	autoscaler, err := r.OCMClient.GetClusterAutoscaler(cluster.ID())
+	if err != nil {
+		r.Reporter.Errorf("Failed to get autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+	if autoscaler == nil {
+		r.Reporter.Errorf("Cluster '%s' has no autoscaler", clusterKey)
+		os.Exit(1)
+	}
+
+	if output.HasFlag() {
+		err = output.Print(autoscaler)
+		if err != nil {
+			r.Reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	fmt.Print(describeAutoscaler(autoscaler))
+}
+
+func describeAutoscaler(autoscaler *cmv1.ClusterAutoscaler) string {
+	limits := autoscaler.ResourceLimits()
+	scaleDown := autoscaler.ScaleDown()
+	return fmt.Sprintf("\n"+
+		"Balance similar node groups:               %s\n"+
+		"Skip nodes with local storage:             %s\n"+
+		"Log verbosity:                             %d\n"+
+		"Labels ignored for node balancing:         %s\n"+
+		"Ignore DaemonSets utilization:             %s\n"+
+		"Maximum node provision time:               %s\n"+
+		"Maximum pod grace period:                  %d\n"+
+		"Pod priority threshold:                    %d\n"+
+		"Resource limits:\n"+
+		" - Maximum Nodes:                          %d\n"+
+		" - Minimum Number of Cores:                %d\n"+
+		" - Maximum Number of Cores:                %d\n"+
+		" - Minimum Memory (GiB):                   %d\n"+
+		" - Maximum Memory (GiB):                   %d\n"+
+		"Scale down:\n"+
+		" - Enabled:                                %s\n"+
+		" - Node unneeded time:                     %s\n"+
+		" - Node utilization threshold:             %s\n"+
+		" - Delay after node added:                 %s\n"+
+		" - Delay after node removed:               %s\n"+
+		" - Delay after node removal failure:       %s\n",
+		yesNo(autoscaler.BalanceSimilarNodeGroups()),
+		yesNo(autoscaler.SkipNodesWithLocalStorage()),
+		autoscaler.LogVerbosity(),
+		strings.Join(autoscaler.BalancingIgnoredLabels(), ", "),
+		yesNo(autoscaler.IgnoreDaemonsetsUtilization()),
+		autoscaler.MaxNodeProvisionTime(),
+		autoscaler.MaxPodGracePeriod(),
+		autoscaler.PodPriorityThreshold(),
+		limits.MaxNodesTotal(),
+		limits.Cores().Min(),
+		limits.Cores().Max(),
+		limits.Memory().Min(),
+		limits.Memory().Max(),
+		yesNo(scaleDown.Enabled()),
+		scaleDown.UnneededTime(),
+		scaleDown.UtilizationThreshold(),
+		scaleDown.DelayAfterAdd(),
+		scaleDown.DelayAfterDelete(),
+		scaleDown.DelayAfterFailure(),
+	)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/openshift/rosa/cmd/describe/addon"
 	"github.com/openshift/rosa/cmd/describe/admin"
+	"github.com/openshift/rosa/cmd/describe/autoscaler"
 	"github.com/openshift/rosa/cmd/describe/cluster"
 	"github.com/openshift/rosa/cmd/describe/installation"
 	"github.com/openshift/rosa/cmd/describe/service"
@@ -38,6 +39,7 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
+	Cmd.AddCommand(autoscaler.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
 	Cmd.AddCommand(service.Cmd)
 	Cmd.AddCommand(installation.Cmd)
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -48,6 +49,8 @@ func init() {
 		"",
 		"The id of the service to describe",
 	)
+
+	output.AddFlag(Cmd)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -68,6 +71,15 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		err = output.Print(service)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	fmt.Printf(`%-28s%s
 %-28s%s
 %-28s%s
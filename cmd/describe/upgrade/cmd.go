@@ -17,13 +17,15 @@ limitations under the License.
 package upgrade
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 	"github.com/spf13/cobra"
 )
@@ -35,7 +37,7 @@ var Cmd = &cobra.Command{
 	Long:    "Show details of an upgrade",
 	Example: `  # Describe an upgrade-policy"
   rosa describe upgrade`,
-	Run:    run,
+	RunE:   run,
 	Hidden: false,
 }
 
@@ -57,16 +59,13 @@ func init() {
 	)
 
 	confirm.AddFlag(flags)
+	output.AddFlag(Cmd)
 }
 
-func run(_ *cobra.Command, _ []string) {
+func run(_ *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r)
 }
 
 func runWithRuntime(r *rosa.Runtime) error {
@@ -96,6 +95,9 @@ func describeHypershiftUpgrades(r *rosa.Runtime, clusterID string, nodePoolID st
 		if err != nil {
 			return fmt.Errorf("Failed to get upgrades for cluster '%s': %v", clusterKey, err)
 		}
+		if output.HasFlag() {
+			return output.Print(upgrades)
+		}
 		if len(upgrades) < 1 {
 			r.Reporter.Infof("No scheduled upgrades for cluster '%s'", clusterKey)
 			return nil
@@ -110,6 +112,9 @@ func describeHypershiftUpgrades(r *rosa.Runtime, clusterID string, nodePoolID st
 			return fmt.Errorf("Failed to get upgrades for machine pool '%s' in cluster '%s': %v", nodePoolID,
 				clusterKey, err)
 		}
+		if output.HasFlag() {
+			return output.Print(upgrades)
+		}
 		if upgrades == nil || len(upgrades) < 1 {
 			r.Reporter.Infof("No scheduled upgrades for machine pool '%s' in cluster '%s'", nodePoolID, clusterKey)
 			return nil
@@ -158,6 +163,13 @@ func describeClassicUpgrades(r *rosa.Runtime, clusterID string) error {
 	if err != nil {
 		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterID, err)
 	}
+	if output.HasFlag() {
+		result, err := classicUpgradesOutput(upgrades, upgradeState)
+		if err != nil {
+			return err
+		}
+		return output.Print(result)
+	}
 	if len(upgrades) < 1 {
 		r.Reporter.Infof("No scheduled upgrades for cluster id '%s'", clusterID)
 		return nil
@@ -184,3 +196,37 @@ func describeClassicUpgrades(r *rosa.Runtime, clusterID string) error {
 	}
 	return nil
 }
+
+// classicUpgradesOutput returns the representation of the upgrade policies of a classic cluster
+// used by the structured output formats. The policies don't contain their state, so the state of
+// the scheduled upgrade is added to each of them, like in the text output.
+func classicUpgradesOutput(upgrades []*cmv1.UpgradePolicy,
+	upgradeState *cmv1.UpgradePolicyState) ([]map[string]interface{}, error) {
+	var b bytes.Buffer
+	err := cmv1.MarshalUpgradePolicyList(upgrades, &b)
+	if err != nil {
+		return nil, err
+	}
+	result := []map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &result)
+	if err != nil {
+		return nil, err
+	}
+	if upgradeState == nil {
+		return result, nil
+	}
+	b.Reset()
+	err = cmv1.MarshalUpgradePolicyState(upgradeState, &b)
+	if err != nil {
+		return nil, err
+	}
+	var state map[string]interface{}
+	err = json.Unmarshal(b.Bytes(), &state)
+	if err != nil {
+		return nil, err
+	}
+	for _, upgrade := range result {
+		upgrade["state"] = state
+	}
+	return result, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "clusters CLUSTER CLUSTER",
+	Aliases: []string{"cluster"},
+	Short:   "Compare the configuration of two clusters",
+	Long: "Compare the configuration of two clusters, including their machine pools, ingresses, " +
+		"identity providers, add-ons and upgrade policy, and print the fields that differ.",
+	Example: `  # Compare the clusters named "staging" and "production"
+  rosa diff clusters staging production
+
+  # Print the differences as JSON
+  rosa diff clusters staging production --output json`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 2 {
+			return fmt.Errorf("Expected exactly two command line parameters containing the names " +
+				"or identifiers of the clusters")
+		}
+		for _, clusterKey := range argv {
+			if !ocm.IsValidClusterKey(clusterKey) {
+				return fmt.Errorf("Cluster name, identifier or external identifier '%s' isn't valid: it "+
+					"must contain only letters, digits, dashes and underscores", clusterKey)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	output.AddFlag(Cmd)
+}
+
+// Result is the output of the command. The left and right values of each difference belong to
+// the first and second cluster.
+type Result struct {
+	Left        string                   `json:"left"`
+	Right       string                   `json:"right"`
+	Differences []clusterspec.Difference `json:"differences"`
+}
+
+// AddOn is the part of an add-on installation that is compared.
+type AddOn struct {
+	Version string `json:"version,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+// document is what is compared for each cluster: its definition, without the fields that are
+// always different, and its add-ons.
+type document struct {
+	*clusterspec.Cluster
+	AddOns map[string]AddOn `json:"addons,omitempty"`
+}
+
+func run(_ *cobra.Command, argv []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	documents := make([]*document, len(argv))
+	for i, clusterKey := range argv {
+		r.Reporter.Debugf("Loading cluster '%s'", clusterKey)
+		cluster, err := r.OCMClient.GetCluster(clusterKey, r.Creator)
+		if err != nil {
+			r.Reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		documents[i], err = loadDocument(r, cluster)
+		if err != nil {
+			r.Reporter.Errorf("Failed to load configuration of cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	differences, err := clusterspec.Compare(documents[0], documents[1])
+	if err != nil {
+		r.Reporter.Errorf("Failed to compare clusters: %v", err)
+		os.Exit(1)
+	}
+	result := Result{
+		Left:        argv[0],
+		Right:       argv[1],
+		Differences: differences,
+	}
+	if result.Differences == nil {
+		result.Differences = []clusterspec.Difference{}
+	}
+
+	if len(differences) == 0 && !output.HasFlag() {
+		r.Reporter.Infof("Clusters '%s' and '%s' have the same configuration", argv[0], argv[1])
+		return
+	}
+	table := output.NewTable("FIELD", argv[0], argv[1])
+	for _, difference := range differences {
+		table.AddRow(difference.Field, formatValue(difference.Left), formatValue(difference.Right))
+	}
+	err = output.PrintTable(result, table)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+func loadDocument(r *rosa.Runtime, cluster *cmv1.Cluster) (*document, error) {
+	spec, err := clusterspec.Export(r.OCMClient, cluster)
+	if err != nil {
+		return nil, err
+	}
+	// Names and generated identifiers are different for every cluster
+	spec.Name = ""
+	if spec.Resources != nil {
+		for i := range spec.Resources.Ingresses {
+			spec.Resources.Ingresses[i].ID = ""
+		}
+	}
+
+	installations, err := r.OCMClient.GetAddOnInstallations(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get add-ons: %v", err)
+	}
+	addOns := map[string]AddOn{}
+	for _, installation := range installations {
+		addOns[installation.Addon().ID()] = AddOn{
+			Version: installation.AddonVersion().ID(),
+			State:   string(installation.State()),
+		}
+	}
+	return &document{Cluster: spec, AddOns: addOns}, nil
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -73,7 +73,7 @@ func init() {
 		"Delete classic account roles",
 	)
 
-	aws.AddModeFlag(Cmd)
+	aws.AddPlanModeFlag(Cmd)
 	confirm.AddFlag(flags)
 }
 
@@ -86,11 +86,7 @@ func run(cmd *cobra.Command, _ []string) {
 		interactive.Enable()
 	}
 
-	mode, err := aws.GetMode()
-	if err != nil {
-		r.Reporter.Errorf("%s", err)
-		os.Exit(1)
-	}
+	mode := r.GetPlanMode()
 
 	env, err := ocm.GetEnv()
 	if err != nil {
@@ -133,7 +129,7 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if interactive.Enabled() {
+	if interactive.Enabled() && r.Plan == nil {
 		mode, err = interactive.GetOption(interactive.Input{
 			Question: "Account role deletion mode",
 			Help:     cmd.Flags().Lookup("mode").Usage,
@@ -175,6 +171,12 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 	}
+
+	err = r.PrintPlan()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
 
 func setDeleteRoles(isClassicFlagSet bool, isHostedCPFlagSet bool) (bool, bool) {
@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	name string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Delete a VPC created with 'rosa create network'",
+	Long: "Delete the CloudFormation stack of a network created with 'rosa create network', along with " +
+		"the VPC, subnets and gateways it contains. The clusters installed into the network must be " +
+		"deleted first.",
+	Example: `  # Delete a network
+  rosa delete network --name mynetwork --mode auto`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the network to delete.",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("%s", err)
+	}
+	if args.name == "" {
+		return exitcode.InvalidInput.Errorf("Expected the name of the network")
+	}
+
+	network, err := r.AWSClient.GetNetworkStack(args.name)
+	if err != nil {
+		return err
+	}
+	if network == nil {
+		return exitcode.NotFound.Errorf("Network '%s' doesn't exist", args.name)
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		if !confirm.Confirm("delete network '%s' with VPC '%s'", args.name, network.VpcID) {
+			return nil
+		}
+		r.Reporter.Infof("Deleting network '%s', this can take a few minutes", args.name)
+		err = r.AWSClient.DeleteStack(network.StackName)
+		if err != nil {
+			return fmt.Errorf("Failed to delete network '%s': %v", args.name, err)
+		}
+		r.Reporter.Infof("Deleted network '%s'", args.name)
+	case aws.ModeManual:
+		r.Reporter.Infof("Run the following command to delete the network:\n")
+		fmt.Println(awscb.NewCloudFormationCommandBuilder().
+			SetCommand(awscb.DeleteStack).
+			AddParam(awscb.StackName, network.StackName).
+			Build())
+	default:
+		return exitcode.InvalidInput.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+	return nil
+}
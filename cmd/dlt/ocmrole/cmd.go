@@ -73,8 +73,7 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	orgID, _, err := r.OCMClient.GetCurrentOrganization()
 	if err != nil {
-		r.Reporter.Errorf("Error getting organization account: %v", err)
-		return err
+		return fmt.Errorf("Error getting organization account: %w", err)
 	}
 
 	if len(argv) > 0 {
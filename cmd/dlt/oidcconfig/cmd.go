@@ -19,7 +19,6 @@ package oidcconfig
 import (
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
@@ -31,6 +30,7 @@ import (
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
 	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/rosa"
@@ -48,8 +48,7 @@ var Cmd = &cobra.Command{
 
 const (
 	//nolint
-	OidcConfigIdFlag          = "oidc-config-id"
-	prefixForPrivateKeySecret = "rosa-private-key-"
+	OidcConfigIdFlag = "oidc-config-id"
 )
 
 var args struct {
@@ -154,19 +153,11 @@ func buildOidcConfigInput(r *rosa.Runtime) OidcConfigInput {
 				"please run the command supplying region parameter.", parsedSecretArn.Region, args.region)
 			os.Exit(1)
 		}
-		secretResourceName, err := aws.GetResourceIdFromSecretArn(secretArn)
+		bucketName, err = oidc_config.GetBucketNameFromSecretArn(secretArn)
 		if err != nil {
 			r.Reporter.Errorf("There was a problem parsing secret ARN '%s' : %v", secretArn, err)
 			os.Exit(1)
 		}
-		// The secret when creating from ROSA options has the following format
-		// rosa-private-key-<prefix>-oidc-<random-hash-length-4>-<random-aws-created-hash>
-		// The bucket is expected to be <prefix>-oidc-<random-hash-length-4>
-		bucketName = strings.TrimPrefix(secretResourceName, prefixForPrivateKeySecret)
-		index := strings.LastIndex(bucketName, "-")
-		if index != -1 {
-			bucketName = bucketName[:index]
-		}
 	}
 
 	issuerUrl := oidcConfig.IssuerUrl()
@@ -0,0 +1,237 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var args struct {
+	types        []string
+	includeRoles bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "orphans",
+	Aliases: []string{"orphan"},
+	Short:   "Delete orphaned resources",
+	Long: "Delete the operator roles, account roles, OIDC providers and OIDC configurations created by ROSA " +
+		"that no cluster uses anymore. Each resource is confirmed before it is deleted.\n\n" +
+		"Only the clusters visible to the current OCM organization are checked, so the resources used by " +
+		"clusters of other OCM organizations that share the AWS account are found as orphans too. For that " +
+		"reason account and operator roles are only deleted with the '--include-roles' flag.",
+	Example: `  # Delete all the orphaned resources of the current AWS account
+  rosa delete orphans --mode auto --include-roles
+
+  # Print the commands that delete the orphaned operator roles
+  rosa delete orphans --type operator-role --mode manual`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringSliceVar(
+		&args.types,
+		"type",
+		[]string{},
+		fmt.Sprintf("Types of the resources to delete. Valid options are %s.", workflows.OrphanTypes),
+	)
+	Cmd.RegisterFlagCompletionFunc("type", typeCompletion)
+
+	flags.BoolVar(
+		&args.includeRoles,
+		"include-roles",
+		false,
+		"Also delete the orphaned account and operator roles. Roles used by clusters of other OCM "+
+			"organizations that share the AWS account are found as orphans too, so check them first.",
+	)
+
+	aws.AddModeFlag(Cmd)
+}
+
+func typeCompletion(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return workflows.OrphanTypes, cobra.ShellCompDirectiveDefault
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("%s", err)
+	}
+
+	// Determine if interactive mode is needed
+	if !interactive.Enabled() && !cmd.Flags().Changed("mode") {
+		interactive.Enable()
+	}
+
+	if interactive.Enabled() {
+		mode, err = interactive.GetOption(interactive.Input{
+			Question: "Orphaned resources deletion mode",
+			Help:     cmd.Flags().Lookup("mode").Usage,
+			Default:  aws.ModeAuto,
+			Options:  aws.Modes,
+			Required: true,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid orphaned resources deletion mode: %s", err)
+		}
+	}
+
+	env, err := ocm.GetEnv()
+	if err != nil {
+		return fmt.Errorf("Failed to determine OCM environment: %v", err)
+	}
+
+	clients := &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}
+	var spin *spinner.Spinner
+	if r.Reporter.IsTerminal() {
+		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		r.Reporter.Infof("Looking for orphaned resources")
+		spin.Start()
+	}
+	orphans, err := workflows.FindOrphans(context.Background(), clients, workflows.FindOrphansOptions{
+		Types: args.types,
+		Env:   env,
+	})
+	if spin != nil {
+		spin.Stop()
+	}
+	if err != nil {
+		return err
+	}
+	orphans = filterRoles(r, orphans)
+	if len(orphans) == 0 {
+		r.Reporter.Infof("There are no orphaned resources to delete")
+		return nil
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		r.OCMClient.LogEvent("ROSADeleteOrphansModeAuto", nil)
+		return deleteOrphans(r, clients, orphans)
+	case aws.ModeManual:
+		r.OCMClient.LogEvent("ROSADeleteOrphansModeManual", nil)
+		return printCommands(r, clients, orphans)
+	default:
+		return exitcode.InvalidInput.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+}
+
+// filterRoles warns that only the clusters visible to the current OCM organization have been
+// checked and, unless the '--include-roles' flag is used, leaves the roles out of the orphans.
+func filterRoles(r *rosa.Runtime, orphans []*workflows.Orphan) []*workflows.Orphan {
+	if len(orphans) == 0 {
+		return orphans
+	}
+	r.Reporter.Warnf("Only the clusters visible to the current OCM organization have been checked. "+
+		"Resources used by clusters of other OCM organizations that share AWS account '%s' are "+
+		"listed as orphaned too.", r.Creator.AccountID)
+	if args.includeRoles {
+		return orphans
+	}
+	result := []*workflows.Orphan{}
+	skipped := 0
+	for _, orphan := range orphans {
+		if orphan.IsRole() {
+			skipped++
+			continue
+		}
+		result = append(result, orphan)
+	}
+	if skipped > 0 {
+		r.Reporter.Warnf("Skipping %d orphaned account and operator roles. Use the '--include-roles' "+
+			"flag to delete them.", skipped)
+	}
+	return result
+}
+
+func deleteOrphans(r *rosa.Runtime, clients *workflows.Clients, orphans []*workflows.Orphan) error {
+	failed := 0
+	for _, orphan := range orphans {
+		if !confirm.Prompt(true, "Delete %s '%s'? %s", orphan.Type, orphan.Name, orphan.Reason) {
+			continue
+		}
+		r.Reporter.Infof("Deleting %s '%s'", orphan.Type, orphan.Name)
+		err := workflows.DeleteOrphan(context.Background(), clients, orphan)
+		if err != nil {
+			r.Reporter.Warnf("%s", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("Failed to delete %d of %d orphaned resources", failed, len(orphans))
+	}
+	r.Reporter.Infof("Successfully deleted the orphaned resources")
+	return nil
+}
+
+// printCommands prints the commands that delete the AWS resources. The OIDC configurations are
+// deleted from OCM right away, as 'rosa delete oidc-config' does in manual mode.
+func printCommands(r *rosa.Runtime, clients *workflows.Clients, orphans []*workflows.Orphan) error {
+	commands := []string{}
+	oidcConfigs := []*workflows.Orphan{}
+	for _, orphan := range orphans {
+		orphanCommands, err := workflows.OrphanCommands(clients, orphan)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, orphanCommands...)
+		if orphan.Type == workflows.OrphanOidcConfig {
+			oidcConfigs = append(oidcConfigs, orphan)
+		}
+	}
+	if r.Reporter.IsTerminal() {
+		r.Reporter.Infof("Run the following commands to delete the orphaned resources:\n")
+	}
+	fmt.Println(awscb.JoinCommands(commands))
+
+	for _, orphan := range oidcConfigs {
+		if !confirm.Prompt(true, "Delete OIDC configuration '%s' from OCM?", orphan.Name) {
+			continue
+		}
+		err := r.OCMClient.DeleteOidcConfig(orphan.Name)
+		if err != nil {
+			return fmt.Errorf("Failed to delete OIDC configuration '%s' from OCM: %v", orphan.Name, err)
+		}
+		r.Reporter.Infof("Registered OIDC Config ID '%s' has been removed from OCM and can no longer be used. "+
+			"Remember to run given commands to clean up aws resources", orphan.Name)
+	}
+	return nil
+}
@@ -18,7 +18,6 @@ package upgrade
 
 import (
 	"fmt"
-	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
@@ -36,7 +35,7 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"upgrades"},
 	Short:   "Cancel cluster upgrade",
 	Long:    "Cancel scheduled cluster upgrade",
-	Run:     run,
+	RunE:    run,
 }
 
 func init() {
@@ -55,14 +54,10 @@ func init() {
 	confirm.AddFlag(flags)
 }
 
-func run(_ *cobra.Command, _ []string) {
+func run(_ *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r)
 }
 
 func runWithRuntime(r *rosa.Runtime) error {
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterautoscaler"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var autoscalerArgs *ocm.AutoscalerConfig
+
+var Cmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "Edit the autoscaler of a cluster",
+	Long: "Edit the autoscaler of a cluster. Only the settings that are passed in the command " +
+		"line are changed.",
+	Example: `  # Interactively edit the autoscaler of a cluster named "mycluster"
+  rosa edit autoscaler --cluster=mycluster --interactive
+
+  # Limit the total number of nodes of the cluster
+  rosa edit autoscaler --cluster=mycluster --max-nodes-total=50`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	ocm.AddClusterFlag(Cmd)
+	autoscalerArgs = clusterautoscaler.AddFlags(flags, "")
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+
+	if ocm.IsHyperShiftCluster(cluster) {
+		r.Reporter.Errorf("Hosted Control Plane clusters do not support cluster-autoscaler configuration")
+		os.Exit(1)
+	}
+
+	autoscaler, err := r.OCMClient.GetClusterAutoscaler(cluster.ID())
+	if err != nil {
+		r.Reporter.Errorf("Failed to get autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+	if autoscaler == nil {
+		r.Reporter.Errorf("Cluster '%s' has no autoscaler, use 'rosa create autoscaler' to create one",
+			clusterKey)
+		os.Exit(1)
+	}
+	current, err := ocm.GetAutoscalerConfig(autoscaler)
+	if err != nil {
+		r.Reporter.Errorf("Failed to read autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if !clusterautoscaler.IsSetViaCLI(cmd.Flags(), "") && !interactive.Enabled() {
+		interactive.Enable()
+		r.Reporter.Infof("Enabling interactive mode")
+	}
+
+	// The current values are the defaults of the interactive questions and are kept for the
+	// flags that aren't set
+	clusterautoscaler.SetUnchanged(cmd.Flags(), "", autoscalerArgs, current)
+	config, err := clusterautoscaler.GetAutoscalerOptions(cmd.Flags(), "", false, autoscalerArgs)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	r.Reporter.Debugf("Updating autoscaler for cluster '%s'", clusterKey)
+	_, err = r.OCMClient.UpdateClusterAutoscaler(cluster.ID(), config)
+	if err != nil {
+		r.Reporter.Errorf("Failed to update autoscaler configuration for cluster '%s': %s", clusterKey, err)
+		os.Exit(1)
+	}
+	r.Reporter.Infof("Successfully updated autoscaler configuration for cluster '%s'", clusterKey)
+}
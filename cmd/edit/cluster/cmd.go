@@ -26,6 +26,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/helper/proxy"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -482,12 +483,21 @@ func run(cmd *cobra.Command, _ []string) {
 			additionalTrustBundleFile = &additionalTrustBundleFileValue
 		}
 	}
+	preflightTrustBundleFile := ""
 	if additionalTrustBundleFile != nil && *additionalTrustBundleFile != doubleQuotesToRemove {
 		err = ocm.ValidateAdditionalTrustBundle(*additionalTrustBundleFile)
 		if err != nil {
 			r.Reporter.Errorf("%s", err)
 			os.Exit(1)
 		}
+		preflightTrustBundleFile = *additionalTrustBundleFile
+	}
+	if len(noProxySlice) > 0 || preflightTrustBundleFile != "" {
+		err = proxy.Preflight(r, noProxySlice, preflightTrustBundleFile)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 	}
 
 	// Audit Log Forwarding
@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Export a cluster",
+	Long: "Export an existing cluster and its machine pools, identity providers, ingresses, " +
+		"tuning configs, autoscaler and upgrade policy. Without an output format the commands that " +
+		"create the cluster are printed, otherwise a cluster definition that can be used with " +
+		"'rosa create cluster --from-file'. Secrets are never exported.",
+	Example: `  # Print the commands that create a copy of the cluster named "mycluster"
+  rosa export cluster --cluster=mycluster
+
+  # Save the definition of the cluster named "mycluster" to a file
+  rosa export cluster --cluster=mycluster --output=yaml > mycluster.yaml`,
+	Run:  run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	output.AddFlag(Cmd)
+	ocm.AddClusterFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+
+	r.Reporter.Debugf("Exporting cluster '%s'", clusterKey)
+	spec, err := clusterspec.Export(r.OCMClient, cluster)
+	if err != nil {
+		r.Reporter.Errorf("Failed to export cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if output.HasFlag() {
+		err = output.Print(spec)
+		if err != nil {
+			r.Reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	for _, command := range spec.Commands() {
+		fmt.Println(command)
+	}
+}
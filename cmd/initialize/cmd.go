@@ -31,6 +31,7 @@ import (
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/aws/region"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -195,7 +196,7 @@ func run(cmd *cobra.Command, argv []string) {
 	err = quota.Cmd.RunE(cmd, argv)
 	if err != nil {
 		r.Reporter.Errorf("%v", err)
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 
 	// Ensure that there is an AWS user to create all the resources needed by the cluster:
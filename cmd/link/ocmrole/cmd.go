@@ -14,6 +14,7 @@ limitations under the License.
 package ocmrole
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -21,6 +22,7 @@ import (
 	errors "github.com/zgalor/weberr"
 
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/rosa"
@@ -71,14 +73,12 @@ func run(cmd *cobra.Command, argv []string) (err error) {
 
 	orgAccount, _, err := r.OCMClient.GetCurrentOrganization()
 	if err != nil {
-		r.Reporter.Errorf("Error getting organization account: %v", err)
-		return err
+		return fmt.Errorf("Error getting organization account: %w", err)
 	}
 
 	if args.organizationID != "" && orgAccount != args.organizationID {
-		r.Reporter.Errorf("Invalid organization ID '%s'. "+
+		return exitcode.InvalidInput.Errorf("Invalid organization ID '%s'. "+
 			"It doesnt match with the user session '%s'.", args.organizationID, orgAccount)
-		return err
 	}
 
 	if r.Reporter.IsTerminal() {
@@ -133,14 +133,12 @@ func run(cmd *cobra.Command, argv []string) (err error) {
 	linked, err := r.OCMClient.LinkOrgToRole(orgAccount, roleArn)
 	if err != nil {
 		if errors.GetType(err) == errors.Forbidden || strings.Contains(err.Error(), "ACCT-MGMT-11") {
-			r.Reporter.Errorf("Only organization admin can run this command. "+
+			return exitcode.Forbidden.Errorf("Only organization admin can run this command. "+
 				"Please ask someone with the organization admin role to run the following command \n\n"+
 				"\t rosa link ocm-role --role-arn %s --organization-id %s", roleArn, orgAccount)
-			return err
 		}
-		r.Reporter.Errorf("Unable to link role arn '%s' with the organization id : '%s' : %v",
+		return fmt.Errorf("Unable to link role arn '%s' with the organization id : '%s' : %w",
 			roleArn, orgAccount, err)
-		return err
 	}
 	if !linked {
 		r.Reporter.Infof("Role-arn '%s' is already linked with the organization account '%s'", roleArn, orgAccount)
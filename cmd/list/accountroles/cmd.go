@@ -17,9 +17,7 @@ limitations under the License.
 package accountroles
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -93,30 +91,18 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(accountRoles) == 0 {
+	if len(accountRoles) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No account roles available")
 		os.Exit(0)
 	}
-	if output.HasFlag() {
-		err = output.Print(accountRoles)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ROLE NAME\tROLE TYPE\tROLE ARN\tOPENSHIFT VERSION\tAWS Managed\n")
+	table := output.NewTable("ROLE NAME", "ROLE TYPE", "ROLE ARN", "OPENSHIFT VERSION", "AWS Managed")
 	for _, accountRole := range accountRoles {
 		awsManaged := "No"
 		if accountRole.ManagedPolicy {
 			awsManaged = "Yes"
 		}
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\t%s\t%s\n",
+		table.AddRow(
 			accountRole.RoleName,
 			accountRole.RoleType,
 			accountRole.RoleARN,
@@ -124,5 +110,9 @@ func run(_ *cobra.Command, _ []string) {
 			awsManaged,
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(accountRoles, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
@@ -17,14 +17,13 @@ limitations under the License.
 package addon
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -52,6 +51,15 @@ func init() {
 		"",
 		"Name or ID of the cluster to list the add-ons of (required).",
 	)
+	output.AddFlag(Cmd)
+}
+
+// availableAddOn is the representation of an available add-on used by the structured output
+// formats.
+type availableAddOn struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -78,22 +86,30 @@ func run(_ *cobra.Command, _ []string) {
 			r.Reporter.Errorf("Failed to fetch add-ons: %v", err)
 			os.Exit(1)
 		}
-		if len(addOnResources) == 0 {
+		if len(addOnResources) == 0 && !output.HasFlag() {
 			r.Reporter.Infof("There are no add-ons available")
 			os.Exit(0)
 		}
 
-		// Create the writer that will be used to print the tabulated results:
-		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintf(writer, "ID\t\tNAME\t\tAVAILABILITY\n")
+		addOns := make([]availableAddOn, 0, len(addOnResources))
+		table := output.NewTable("ID", "NAME", "AVAILABILITY")
 		for _, addOnResource := range addOnResources {
+			addOns = append(addOns, availableAddOn{
+				ID:        addOnResource.AddOn.ID(),
+				Name:      addOnResource.AddOn.Name(),
+				Available: addOnResource.Available,
+			})
 			availability := "unavailable"
 			if addOnResource.Available {
 				availability = "available"
 			}
-			fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", addOnResource.AddOn.ID(), addOnResource.AddOn.Name(), availability)
+			table.AddRow(addOnResource.AddOn.ID(), addOnResource.AddOn.Name(), availability)
+		}
+		err = output.PrintTable(addOns, table)
+		if err != nil {
+			r.Reporter.Errorf("%v", err)
+			os.Exit(1)
 		}
-		writer.Flush()
 
 		os.Exit(0)
 	}
@@ -112,16 +128,18 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(clusterAddOns) == 0 {
+	if len(clusterAddOns) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no add-ons installed on cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\t\tNAME\t\tSTATE\n")
+	table := output.NewTable("ID", "NAME", "STATE")
 	for _, clusterAddOn := range clusterAddOns {
-		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", clusterAddOn.ID, clusterAddOn.Name, clusterAddOn.State)
+		table.AddRow(clusterAddOn.ID, clusterAddOn.Name, clusterAddOn.State)
+	}
+	err = output.PrintTable(clusterAddOns, table)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
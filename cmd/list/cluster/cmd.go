@@ -18,13 +18,18 @@ package cluster
 
 import (
 	"fmt"
-	"github.com/openshift/rosa/pkg/aws"
 	"os"
-	"text/tabwriter"
+	"sort"
+	"strings"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/properties"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -34,72 +39,338 @@ var Cmd = &cobra.Command{
 	Short:   "List clusters",
 	Long:    "List clusters.",
 	Example: `  # List all clusters
-  rosa list clusters`,
+  rosa list clusters
+
+  # List the hosted control plane clusters that are ready in a region
+  rosa list clusters --hosted-cp --state ready --region us-east-1
+
+  # List clusters using the OCM search syntax
+  rosa list clusters --search "name like 'prod-%'"
+
+  # Show the version and region of the clusters, newest first
+  rosa list clusters --columns id,name,version,region --sort-by created:desc`,
 	Args: cobra.NoArgs,
 	Run:  run,
 }
 
-var listAll bool
+var args struct {
+	listAll       bool
+	search        string
+	state         string
+	version       string
+	hostedCP      bool
+	sts           bool
+	createdBefore string
+	sortBy        string
+	columns       string
+}
+
+// column is a column of the table of clusters. The field is the name used to sort by the column
+// in the OCM search API, empty when the column can't be sorted.
+type column struct {
+	header string
+	field  string
+	value  func(*cmv1.Cluster) string
+}
+
+var columns = map[string]column{
+	"id":   {header: "ID", field: "id", value: (*cmv1.Cluster).ID},
+	"name": {header: "NAME", field: "name", value: (*cmv1.Cluster).Name},
+	"state": {header: "STATE", field: "state", value: func(cluster *cmv1.Cluster) string {
+		return string(cluster.State())
+	}},
+	"topology": {header: "TOPOLOGY", value: topology},
+	"version": {header: "VERSION", field: "openshift_version", value: func(cluster *cmv1.Cluster) string {
+		if cluster.OpenshiftVersion() != "" {
+			return cluster.OpenshiftVersion()
+		}
+		return cluster.Version().RawID()
+	}},
+	"region": {header: "REGION", field: "region.id", value: func(cluster *cmv1.Cluster) string {
+		return cluster.Region().ID()
+	}},
+	"multi-az": {header: "MULTI-AZ", field: "multi_az", value: func(cluster *cmv1.Cluster) string {
+		return fmt.Sprintf("%t", cluster.MultiAZ())
+	}},
+	"created": {header: "CREATED", field: "creation_timestamp", value: func(cluster *cmv1.Cluster) string {
+		return formatTime(cluster.CreationTimestamp())
+	}},
+	"expiration": {header: "EXPIRATION", field: "expiration_timestamp", value: func(cluster *cmv1.Cluster) string {
+		return formatTime(cluster.ExpirationTimestamp())
+	}},
+	"creator": {header: "CREATOR", value: func(cluster *cmv1.Cluster) string {
+		return cluster.Properties()[properties.CreatorARN]
+	}},
+}
+
+const defaultColumns = "id,name,state,topology"
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
 	output.AddFlag(Cmd)
-	flags.BoolVarP(&listAll, "all", "a", false, "List all clusters across different AWS "+
+	flags.BoolVarP(&args.listAll, "all", "a", false, "List all clusters across different AWS "+
 		"accounts under the same Red Hat organization")
+
+	flags.StringVar(
+		&args.search,
+		"search",
+		"",
+		"Only list the clusters that match the search, using the OCM search syntax, "+
+			"for example \"name like 'prod-%'\".",
+	)
+	flags.StringVar(
+		&args.state,
+		"state",
+		"",
+		"Only list the clusters in the given state, for example 'ready'.",
+	)
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Only list the clusters with the given OpenShift version. A minor version like '4.14' "+
+			"matches all its patch versions.",
+	)
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"Only list hosted control plane clusters, or only classic clusters with '--hosted-cp=false'.",
+	)
+	flags.BoolVar(
+		&args.sts,
+		"sts",
+		false,
+		"Only list clusters that use STS, or only clusters that don't with '--sts=false'.",
+	)
+	flags.StringVar(
+		&args.createdBefore,
+		"created-before",
+		"",
+		"Only list the clusters created before the given date, in RFC 3339 format or as YYYY-MM-DD.",
+	)
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		fmt.Sprintf("Column used to sort the clusters, optionally followed by ':desc' for a descending "+
+			"order. Supported columns: %s.", strings.Join(sortableColumns(), ", ")),
+	)
+	flags.StringVar(
+		&args.columns,
+		"columns",
+		defaultColumns,
+		fmt.Sprintf("Comma separated list of columns to show. Supported columns: %s.",
+			strings.Join(columnNames(), ", ")),
+	)
 }
 
-func run(_ *cobra.Command, _ []string) {
+func run(cmd *cobra.Command, _ []string) {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
 
+	filters := Filters{
+		Search:        args.search,
+		State:         args.state,
+		Version:       args.version,
+		CreatedBefore: args.createdBefore,
+	}
+	// The global '--region' flag also selects the region of the listed clusters:
+	if cmd.Flags().Changed("region") {
+		filters.Region = arguments.GetRegion()
+	}
+	if cmd.Flags().Changed("hosted-cp") {
+		filters.HostedCP = &args.hostedCP
+	}
+	if cmd.Flags().Changed("sts") {
+		filters.STS = &args.sts
+	}
+	search, err := filters.Expression()
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	orderBy, err := orderBy(args.sortBy)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	selected, err := parseColumns(args.columns)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
 	// Retrieve the list of clusters:
 	var creator *aws.Creator
-	if listAll {
+	if args.listAll {
 		creator = nil
 	} else {
 		creator = r.Creator
 	}
-	clusters, err := r.OCMClient.GetClusters(creator, 1000)
+	r.Reporter.Debugf("Listing clusters matching \"%s\" ordered by \"%s\"", search, orderBy)
+	clusters, err := r.OCMClient.ListClusters(creator, search, orderBy)
 	if err != nil {
 		r.Reporter.Errorf("Failed to get clusters: %v", err)
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(clusters)
+	if len(clusters) == 0 && !output.HasFlag() {
+		r.Reporter.Infof("No clusters available")
+		os.Exit(0)
+	}
+
+	headers := make([]string, len(selected))
+	for i, column := range selected {
+		headers[i] = column.header
+	}
+	table := output.NewTable(headers...)
+	for _, cluster := range clusters {
+		values := make([]interface{}, len(selected))
+		for i, column := range selected {
+			values[i] = column.value(cluster)
+		}
+		table.AddRow(values...)
+	}
+	err = output.PrintTable(clusters, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+}
+
+// Filters are the conditions that the listed clusters must match. Nil pointers and empty strings
+// don't filter anything.
+type Filters struct {
+	Search        string
+	State         string
+	Region        string
+	Version       string
+	HostedCP      *bool
+	STS           *bool
+	CreatedBefore string
+}
+
+// Expression returns the OCM search expression that matches the filters.
+func (f Filters) Expression() (string, error) {
+	var terms []string
+	if f.Search != "" {
+		terms = append(terms, fmt.Sprintf("(%s)", f.Search))
+	}
+	if f.State != "" {
+		terms = append(terms, fmt.Sprintf("state = %s", quote(strings.ToLower(f.State))))
+	}
+	if f.Region != "" {
+		terms = append(terms, fmt.Sprintf("region.id = %s", quote(f.Region)))
+	}
+	if f.Version != "" {
+		// A version with less than three components matches all the versions that start with it
+		if strings.Count(f.Version, ".") < 2 {
+			terms = append(terms, fmt.Sprintf("openshift_version LIKE %s", quote(f.Version+".%")))
+		} else {
+			terms = append(terms, fmt.Sprintf("openshift_version = %s", quote(f.Version)))
+		}
+	}
+	if f.HostedCP != nil {
+		terms = append(terms, fmt.Sprintf("hypershift.enabled = '%t'", *f.HostedCP))
+	}
+	if f.STS != nil {
+		terms = append(terms, fmt.Sprintf("aws.sts.enabled = '%t'", *f.STS))
+	}
+	if f.CreatedBefore != "" {
+		createdBefore, err := parseTime(f.CreatedBefore)
 		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
+			return "", err
 		}
-		os.Exit(0)
+		terms = append(terms, fmt.Sprintf("creation_timestamp < '%s'", createdBefore.Format(time.RFC3339)))
 	}
+	return strings.Join(terms, " AND "), nil
+}
 
-	if len(clusters) == 0 {
-		r.Reporter.Infof("No clusters available")
-		os.Exit(0)
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		result, err := time.Parse(layout, value)
+		if err == nil {
+			return result, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("Invalid date '%s', expected a date in RFC 3339 format or as YYYY-MM-DD", value)
+}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\tTOPOLOGY\n")
-	for _, cluster := range clusters {
-		typeOutput := "Classic"
-		if cluster.AWS() != nil && cluster.AWS().STS() != nil && cluster.AWS().STS().Enabled() {
-			typeOutput = "Classic (STS)"
+// quote returns the value as a string literal of the OCM search syntax.
+func quote(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
+
+// orderBy returns the OCM order expression for a value of the --sort-by flag.
+func orderBy(sortBy string) (string, error) {
+	if sortBy == "" {
+		return "", nil
+	}
+	name, direction, _ := strings.Cut(sortBy, ":")
+	column, ok := columns[name]
+	if !ok || column.field == "" {
+		return "", fmt.Errorf("Can't sort by '%s', expected one of: %s", name,
+			strings.Join(sortableColumns(), ", "))
+	}
+	switch direction {
+	case "", "asc":
+		return fmt.Sprintf("%s asc", column.field), nil
+	case "desc":
+		return fmt.Sprintf("%s desc", column.field), nil
+	}
+	return "", fmt.Errorf("Invalid sort direction '%s', expected 'asc' or 'desc'", direction)
+}
+
+func parseColumns(value string) ([]column, error) {
+	var result []column
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		column, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown column '%s', expected one of: %s", name,
+				strings.Join(columnNames(), ", "))
 		}
-		if cluster.Hypershift().Enabled() {
-			typeOutput = "Hosted CP"
+		result = append(result, column)
+	}
+	return result, nil
+}
+
+func columnNames() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortableColumns() []string {
+	var names []string
+	for _, name := range columnNames() {
+		if columns[name].field != "" {
+			names = append(names, name)
 		}
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\t%s\n",
-			cluster.ID(),
-			cluster.Name(),
-			cluster.State(),
-			typeOutput,
-		)
-	}
-	writer.Flush()
+	}
+	return names
+}
+
+func topology(cluster *cmv1.Cluster) string {
+	typeOutput := "Classic"
+	if cluster.AWS() != nil && cluster.AWS().STS() != nil && cluster.AWS().STS().Enabled() {
+		typeOutput = "Classic (STS)"
+	}
+	if cluster.Hypershift().Enabled() {
+		typeOutput = "Hosted CP"
+	}
+	return typeOutput
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339)
 }
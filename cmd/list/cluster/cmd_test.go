@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filters", func() {
+	It("Returns an empty search without filters", func() {
+		search, err := Filters{}.Expression()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(search).To(BeEmpty())
+	})
+
+	It("Combines the search with the filters", func() {
+		hostedCP := true
+		sts := false
+		search, err := Filters{
+			Search:        "name like 'prod-%'",
+			State:         "Ready",
+			Region:        "us-east-1",
+			Version:       "4.14.2",
+			HostedCP:      &hostedCP,
+			STS:           &sts,
+			CreatedBefore: "2023-10-01",
+		}.Expression()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(search).To(Equal("(name like 'prod-%') AND state = 'ready' AND region.id = 'us-east-1' AND " +
+			"openshift_version = '4.14.2' AND hypershift.enabled = 'true' AND aws.sts.enabled = 'false' AND " +
+			"creation_timestamp < '2023-10-01T00:00:00Z'"))
+	})
+
+	It("Matches all the patch versions of a minor version", func() {
+		search, err := Filters{Version: "4.14"}.Expression()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(search).To(Equal("openshift_version LIKE '4.14.%'"))
+	})
+
+	It("Escapes quotes", func() {
+		search, err := Filters{State: "it's"}.Expression()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(search).To(Equal("state = 'it''s'"))
+	})
+
+	It("Rejects invalid dates", func() {
+		_, err := Filters{CreatedBefore: "yesterday"}.Expression()
+		Expect(err).To(MatchError(ContainSubstring("Invalid date 'yesterday'")))
+	})
+})
+
+var _ = Describe("Sort by", func() {
+	It("Sorts in ascending order by default", func() {
+		order, err := orderBy("created")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(order).To(Equal("creation_timestamp asc"))
+	})
+
+	It("Sorts in descending order", func() {
+		order, err := orderBy("region:desc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(order).To(Equal("region.id desc"))
+	})
+
+	It("Rejects columns that can't be sorted", func() {
+		_, err := orderBy("topology")
+		Expect(err).To(MatchError(ContainSubstring("Can't sort by 'topology'")))
+	})
+
+	It("Rejects invalid directions", func() {
+		_, err := orderBy("name:up")
+		Expect(err).To(MatchError("Invalid sort direction 'up', expected 'asc' or 'desc'"))
+	})
+})
+
+var _ = Describe("Columns", func() {
+	It("Parses the default columns", func() {
+		selected, err := parseColumns(defaultColumns)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selected).To(HaveLen(4))
+		Expect(selected[3].header).To(Equal("TOPOLOGY"))
+	})
+
+	It("Rejects unknown columns", func() {
+		_, err := parseColumns("id,owner")
+		Expect(err).To(MatchError(ContainSubstring("Unknown column 'owner'")))
+	})
+})
@@ -17,9 +17,7 @@ limitations under the License.
 package dnsdomains
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -71,35 +69,27 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(dnsDomains)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(dnsDomains) == 0 {
+	if len(dnsDomains) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no DNS Domains for your organization")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tCLUSTER ID\tRESERVED TIME\tUSER DEFINED\n")
+	table := output.NewTable("ID", "CLUSTER ID", "RESERVED TIME", "USER DEFINED")
 	for _, dnsdomain := range dnsDomains {
 		userDefind := "No"
 		if dnsdomain.UserDefined() {
 			userDefind = "Yes"
 		}
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+		table.AddRow(
 			dnsdomain.ID(),
 			dnsdomain.Cluster().ID(),
 			dnsdomain.ReservedAtTimestamp().Format(time.RFC3339),
 			userDefind,
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(dnsDomains, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
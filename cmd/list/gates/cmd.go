@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	semver "github.com/hashicorp/go-version"
 
@@ -173,42 +172,28 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 	}
 
-	if output.HasFlag() {
-		err = output.Print(versionGates)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
 	cols, _ := consolesize.GetConsoleSize()
 	descriptionSize := float64(cols) * 0.30
-	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-	fmt.Fprintln(writer, "Gate Description\tSTS\tOCP Version\tDocumentation URL\t")
-
+	table := output.NewTable("Gate Description", "STS", "OCP Version", "Documentation URL")
 	for _, gate := range versionGates {
-		wrappedDescription := wordWrap(strings.TrimSuffix(gate.Description(), "\n"), int(descriptionSize))
-
-		for i, line := range strings.Split(wrappedDescription, "\n") {
-			if i == 0 {
-				fmt.Fprintf(writer,
-					"%s\t%t\t%s\t%s\t\n",
-					line,
-					gate.STSOnly(),
-					gate.VersionRawIDPrefix(),
-					gate.DocumentationURL(),
-				)
-			} else {
-				fmt.Fprintf(writer,
-					"%s\t \t \t \t\n",
-					line,
-				)
-			}
+		description := strings.TrimSuffix(gate.Description(), "\n")
+		// Long descriptions are wrapped only for the table, the lines continue in the
+		// following rows:
+		if !output.HasFlag() {
+			description = wordWrap(description, int(descriptionSize))
 		}
+		table.AddRow(
+			description,
+			gate.STSOnly(),
+			gate.VersionRawIDPrefix(),
+			gate.DocumentationURL(),
+		)
+	}
+	err = output.PrintTable(versionGates, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func parseMajorMinor(version string) (string, error) {
@@ -17,9 +17,7 @@ limitations under the License.
 package idp
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -64,33 +62,30 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(idps)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(idps) == 0 {
+	if len(idps) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no identity providers configured for cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	if len(idps) == 1 && !ocm.HasAuthURLSupport(idps[0]) {
-		fmt.Fprintf(writer, "NAME\t\tTYPE\n")
-	} else {
-		fmt.Fprintf(writer, "NAME\t\tTYPE\t\tAUTH URL\n")
+	showAuthURL := len(idps) != 1 || ocm.HasAuthURLSupport(idps[0])
+	table := output.NewTable("NAME", "TYPE")
+	if showAuthURL {
+		table = output.NewTable("NAME", "TYPE", "AUTH URL")
 	}
 	for _, idp := range idps {
+		if !showAuthURL {
+			table.AddRow(idp.Name(), ocm.IdentityProviderType(idp))
+			continue
+		}
 		oauthURL, err := ocm.GetOAuthURL(cluster, idp)
 		if err != nil {
 			r.Reporter.Warnf("Error building OAuth URL for %s: %v", idp.Name(), err)
 		}
-		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", idp.Name(), ocm.IdentityProviderType(idp), oauthURL)
+		table.AddRow(idp.Name(), ocm.IdentityProviderType(idp), oauthURL)
+	}
+	err = output.PrintTable(idps, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
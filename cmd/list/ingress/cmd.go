@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -66,29 +65,17 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(ingresses)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(ingresses) == 0 {
+	if len(ingresses) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no ingresses configured for cluster '%s'", clusterKey)
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tAPPLICATION ROUTER\tPRIVATE\tDEFAULT\tROUTE SELECTORS\tLB-TYPE"+
-		"\tEXCLUDED NAMESPACE\tWILDCARD POLICY\tNAMESPACE OWNERSHIP\tHOSTNAME\tTLS SECRET REF\n")
+	table := output.NewTable("ID", "APPLICATION ROUTER", "PRIVATE", "DEFAULT", "ROUTE SELECTORS", "LB-TYPE",
+		"EXCLUDED NAMESPACE", "WILDCARD POLICY", "NAMESPACE OWNERSHIP", "HOSTNAME", "TLS SECRET REF")
 	for _, ingress := range ingresses {
-		fmt.Fprintf(writer, "%s\thttps://%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		table.AddRow(
 			ingress.ID(),
-			ingress.DNSName(),
+			fmt.Sprintf("https://%s", ingress.DNSName()),
 			isPrivate(ingress.Listening()),
 			isDefault(ingress),
 			printRouteSelectors(ingress),
@@ -100,7 +87,11 @@ func run(_ *cobra.Command, _ []string) {
 			ingress.ClusterRoutesTlsSecretRef(),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(ingresses, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
 
 func isPrivate(listeningMethod cmv1.ListeningMethod) string {
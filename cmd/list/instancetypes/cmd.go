@@ -19,7 +19,6 @@ package instancetypes
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -59,36 +58,27 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		var instanceTypes []*cmv1.MachineType
-		for _, machine := range machineTypes.Items {
-			instanceTypes = append(instanceTypes, machine.MachineType)
-		}
-		err = output.Print(instanceTypes)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\t\n")
-
+	// The structured formats include all the machine types, while the table only shows the
+	// ones that are available:
+	var instanceTypes []*cmv1.MachineType
+	table := output.NewTable("ID", "CATEGORY", "CPU_CORES", "MEMORY")
 	for _, machine := range machineTypes.Items {
+		instanceTypes = append(instanceTypes, machine.MachineType)
 		if !machine.Available {
 			continue
 		}
 		availableMachine := machine.MachineType
-		fmt.Fprintf(writer,
-			"%s\t%s\t%d\t%s\n",
+		table.AddRow(
 			availableMachine.ID(), availableMachine.Category(), int(availableMachine.CPU().Value()),
 			ByteCountIEC(int(availableMachine.Memory().Value()),
 				availableMachine.Memory().Unit()),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(instanceTypes, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
 
 func ByteCountIEC(b int, uValue string) string {
@@ -3,7 +3,6 @@ package machinepool
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/helper"
@@ -20,23 +19,10 @@ func listMachinePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster)
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(machinePools)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintf(writer,
-		"ID\tAUTOSCALING\tREPLICAS\tINSTANCE TYPE\tLABELS\t\tTAINTS\t"+
-			"\tAVAILABILITY ZONES\t\tSUBNETS\t\tSPOT INSTANCES\tDISK SIZE\t\n")
+	table := output.NewTable("ID", "AUTOSCALING", "REPLICAS", "INSTANCE TYPE", "LABELS", "TAINTS",
+		"AVAILABILITY ZONES", "SUBNETS", "SPOT INSTANCES", "DISK SIZE")
 	for _, machinePool := range machinePools {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\t\t%s\t\t%s\t%s\n",
+		table.AddRow(
 			machinePool.ID(),
 			printMachinePoolAutoscaling(machinePool.Autoscaling()),
 			printMachinePoolReplicas(machinePool.Autoscaling(), machinePool.Replicas()),
@@ -49,7 +35,11 @@ func listMachinePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster)
 			printMachinePoolDiskSize(machinePool),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(machinePools, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
 
 func printMachinePoolAutoscaling(autoscaling *cmv1.MachinePoolAutoscaling) string {
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -20,13 +20,11 @@ func listNodePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster) {
 		os.Exit(1)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tAUTOSCALING\tDESIRED REPLICAS\tCURRENT REPLICAS\t"+
-		"INSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONE\tSUBNET\tVERSION\tAUTOREPAIR\tTUNING CONFIGS\tMESSAGE\t\n")
+	table := output.NewTable("ID", "AUTOSCALING", "DESIRED REPLICAS", "CURRENT REPLICAS",
+		"INSTANCE TYPE", "LABELS", "TAINTS", "AVAILABILITY ZONE", "SUBNET", "VERSION", "AUTOREPAIR",
+		"TUNING CONFIGS", "MESSAGE")
 	for _, nodePool := range nodePools {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+		table.AddRow(
 			nodePool.ID(),
 			printNodePoolAutoscaling(nodePool.Autoscaling()),
 			printNodePoolReplicas(nodePool.Autoscaling(), nodePool.Replicas()),
@@ -42,7 +40,11 @@ func listNodePools(r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster) {
 			printNodePoolMessage(nodePool.Status()),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(nodePools, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
 
 func printNodePoolAutoscaling(autoscaling *cmv1.NodePoolAutoscaling) string {
@@ -19,7 +19,6 @@ package ocmroles
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -69,22 +68,12 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(ocmRoles) == 0 {
+	if len(ocmRoles) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No ocm roles available")
 		os.Exit(0)
 	}
-	if output.HasFlag() {
-		err = output.Print(ocmRoles)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprint(writer, "ROLE NAME\tROLE ARN\tLINKED\tADMIN\tAWS Managed\n")
+	table := output.NewTable("ROLE NAME", "ROLE ARN", "LINKED", "ADMIN", "AWS Managed")
 	for _, ocmRole := range ocmRoles {
 		var awsManaged string
 		if ocmRole.ManagedPolicy {
@@ -92,10 +81,13 @@ func run(_ *cobra.Command, _ []string) {
 		} else {
 			awsManaged = "No"
 		}
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ocmRole.RoleName, ocmRole.RoleARN, ocmRole.Linked, ocmRole.Admin,
-			awsManaged)
+		table.AddRow(ocmRole.RoleName, ocmRole.RoleARN, ocmRole.Linked, ocmRole.Admin, awsManaged)
+	}
+	err = output.PrintTable(ocmRoles, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
-	writer.Flush()
 }
 
 func listOCMRoles(r *rosa.Runtime) ([]aws.Role, error) {
@@ -17,9 +17,7 @@ limitations under the License.
 package oidcconfig
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -53,31 +51,23 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(oidcConfigs)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(oidcConfigs) == 0 {
+	if len(oidcConfigs) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no OIDC Configurations for your organization")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tMANAGED\tISSUER URL\tSECRET ARN\n")
+	table := output.NewTable("ID", "MANAGED", "ISSUER URL", "SECRET ARN")
 	for _, oidcConfig := range oidcConfigs {
-		fmt.Fprintf(writer, "%s\t%v\t%s\t%s\n",
+		table.AddRow(
 			oidcConfig.ID(),
 			oidcConfig.Managed(),
 			oidcConfig.IssuerUrl(),
 			oidcConfig.SecretArn(),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(oidcConfigs, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
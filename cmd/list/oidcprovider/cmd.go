@@ -19,7 +19,6 @@ package oidcprovider
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -76,55 +75,39 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(providers) == 0 {
+	if len(providers) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("No OIDC providers available")
 		os.Exit(0)
 	}
-	providersInUse := map[string]bool{}
-	for _, provider := range providers {
+	for i, provider := range providers {
 		resourceName, err := aws.GetResourceIdFromOidcProviderARN(provider.Arn)
 		if err != nil {
 			r.Reporter.Errorf("%v", err)
 			os.Exit(1)
 		}
-		has, err := r.OCMClient.
+		providers[i].InUse, err = r.OCMClient.
 			HasAClusterUsingOidcProvider(
 				fmt.Sprintf("https://%s", resourceName), r.Creator.AccountID)
 		if err != nil {
 			r.Reporter.Errorf("%v", err)
 			os.Exit(1)
 		}
-		providersInUse[provider.Arn] = has
 	}
-	if output.HasFlag() {
-		outList := []map[string]interface{}{}
-		for _, provider := range providers {
-			outList = append(outList, map[string]interface{}{
-				"arn": provider.Arn, "cluster_id": provider.ClusterId, "in_use": providersInUse[provider.Arn]})
-		}
-		err = output.Print(outList)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "OIDC PROVIDER ARN\tCluster ID\tIn Use\n")
+	table := output.NewTable("OIDC PROVIDER ARN", "Cluster ID", "In Use")
 	for _, provider := range providers {
 		providerInUse := "No"
-		if ok := providersInUse[provider.Arn]; ok {
+		if provider.InUse {
 			providerInUse = "Yes"
 		}
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%v\n",
+		table.AddRow(
 			provider.Arn,
 			provider.ClusterId,
 			providerInUse,
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(providers, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
@@ -19,12 +19,12 @@ package operatorroles
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
@@ -119,7 +119,7 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(operatorsMap) == 0 {
+	if len(operatorsMap) == 0 && !output.HasFlag() {
 		noOperatorRolesOutput := "No operator roles available"
 		if args.version != "" {
 			noOperatorRolesOutput = fmt.Sprintf("%s in version '%s'", noOperatorRolesOutput, args.version)
@@ -130,11 +130,11 @@ func run(cmd *cobra.Command, _ []string) {
 	if output.HasFlag() {
 		var resource interface{} = operatorsMap
 		if args.prefix != "" {
-			if _, ok := operatorsMap[args.prefix]; !ok {
-				r.Reporter.Infof("No operator roles available for prefix '%s'", args.prefix)
-				os.Exit(0)
+			operatorRoles, ok := operatorsMap[args.prefix]
+			if !ok {
+				operatorRoles = []aws.OperatorRoleDetail{}
 			}
-			resource = operatorsMap[args.prefix]
+			resource = operatorRoles
 		}
 		err = output.Print(resource)
 		if err != nil {
@@ -144,8 +144,6 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	if clusterId != "" {
 		for key, value := range operatorsMap {
 			if value[0].ClusterID == clusterId {
@@ -154,16 +152,18 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 	}
 	if args.prefix == "" {
-		fmt.Fprintf(writer, "ROLE PREFIX\tAMOUNT IN BUNDLE\n")
+		table := output.NewTable("ROLE PREFIX", "AMOUNT IN BUNDLE")
 		for _, key := range prefixes {
-			fmt.Fprintf(
-				writer,
-				"%s\t%d\n",
+			table.AddRow(
 				key,
 				len(operatorsMap[key]),
 			)
 		}
-		writer.Flush()
+		err = output.PrintTable(operatorsMap, table)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 		if !confirm.Prompt(true, "Would you like to detail a specific prefix") {
 			os.Exit(0)
 		}
@@ -196,8 +196,8 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 
-		fmt.Fprintf(writer, "OPERATOR NAME\tOPERATOR NAMESPACE\tROLE NAME\t"+
-			"ROLE ARN\tCLUSTER ID\tVERSION\tPOLICIES\tAWS Managed\tIN USE\n")
+		table := output.NewTable("OPERATOR NAME", "OPERATOR NAMESPACE", "ROLE NAME",
+			"ROLE ARN", "CLUSTER ID", "VERSION", "POLICIES", "AWS Managed", "IN USE")
 		for _, operatorRole := range operatorsMap[args.prefix] {
 			awsManaged := "No"
 			inUse := "No"
@@ -207,9 +207,7 @@ func run(cmd *cobra.Command, _ []string) {
 			if hasClusterUsingOperatorRolesPrefix {
 				inUse = "Yes"
 			}
-			fmt.Fprintf(
-				writer,
-				"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			table.AddRow(
 				operatorRole.OperatorName,
 				operatorRole.OperatorNamespace,
 				operatorRole.RoleName,
@@ -221,6 +219,10 @@ func run(cmd *cobra.Command, _ []string) {
 				inUse,
 			)
 		}
-		writer.Flush()
+		err = output.PrintTable(operatorsMap[args.prefix], table)
+		if err != nil {
+			r.Reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
 	}
 }
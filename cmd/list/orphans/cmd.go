@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var args struct {
+	types []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "orphans",
+	Aliases: []string{"orphan"},
+	Short:   "List orphaned resources",
+	Long: "List the operator roles, account roles, OIDC providers and OIDC configurations created by ROSA " +
+		"that no cluster uses anymore, and the reason why each of them is considered orphaned.",
+	Example: `  # List all the orphaned resources of the current AWS account
+  rosa list orphans
+
+  # List the orphaned operator roles and OIDC providers
+  rosa list orphans --type operator-role,oidc-provider`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringSliceVar(
+		&args.types,
+		"type",
+		[]string{},
+		fmt.Sprintf("Types of the resources to list. Valid options are %s.", workflows.OrphanTypes),
+	)
+	Cmd.RegisterFlagCompletionFunc("type", typeCompletion)
+
+	output.AddFlag(Cmd)
+}
+
+func typeCompletion(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return workflows.OrphanTypes, cobra.ShellCompDirectiveDefault
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	env, err := ocm.GetEnv()
+	if err != nil {
+		return fmt.Errorf("Failed to determine OCM environment: %v", err)
+	}
+
+	var spin *spinner.Spinner
+	if r.Reporter.IsTerminal() {
+		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		r.Reporter.Infof("Looking for orphaned resources")
+		spin.Start()
+	}
+	orphans, err := workflows.FindOrphans(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, workflows.FindOrphansOptions{
+		Types: args.types,
+		Env:   env,
+	})
+	if spin != nil {
+		spin.Stop()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(orphans) == 0 && !output.HasFlag() {
+		r.Reporter.Infof("There are no orphaned resources")
+		return nil
+	}
+	table := output.NewTable("TYPE", "NAME", "PREFIX", "REASON")
+	for _, orphan := range orphans {
+		table.AddRow(
+			orphan.Type,
+			orphan.Name,
+			orphan.Prefix,
+			orphan.Reason,
+		)
+	}
+	return output.PrintTable(orphans, table)
+}
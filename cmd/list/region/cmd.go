@@ -17,9 +17,7 @@ limitations under the License.
 package region
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/output"
@@ -110,28 +108,18 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(availableRegions)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	headerFormat := "ID\t\tNAME\t\tMULTI-AZ SUPPORT\t\tHOSTED-CP SUPPORT\n"
-	fmt.Fprint(writer, headerFormat)
-
+	table := output.NewTable("ID", "NAME", "MULTI-AZ SUPPORT", "HOSTED-CP SUPPORT")
 	for _, region := range availableRegions {
-		fmt.Fprintf(writer,
-			"%s\t\t%s\t\t%t\t\t%t\n",
+		table.AddRow(
 			region.ID(),
 			region.DisplayName(),
 			region.SupportsMultiAZ(),
 			region.SupportsHypershift(),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(availableRegions, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
@@ -17,9 +17,7 @@ limitations under the License.
 package tuningconfigs
 
 import (
-	"fmt"
 	"os"
-	"text/tabwriter"
 
 	"github.com/openshift/rosa/pkg/input"
 	"github.com/openshift/rosa/pkg/ocm"
@@ -60,29 +58,21 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(tuningConfigs)
-		if err != nil {
-			r.Reporter.Errorf("%v", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	if len(tuningConfigs) == 0 {
+	if len(tuningConfigs) == 0 && !output.HasFlag() {
 		r.Reporter.Infof("There are no tuning configs for this cluster.")
 		os.Exit(0)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-
-	fmt.Fprintf(writer, "ID\tNAME\n")
+	table := output.NewTable("ID", "NAME")
 	for _, tuningConfig := range tuningConfigs {
-		fmt.Fprintf(writer, "%s\t%s\n",
+		table.AddRow(
 			tuningConfig.ID(),
 			tuningConfig.Name(),
 		)
 	}
-	writer.Flush()
+	err = output.PrintTable(tuningConfigs, table)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
 }
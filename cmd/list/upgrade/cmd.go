@@ -18,10 +18,8 @@ package upgrade
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/cmd/upgrade/machinepool"
@@ -29,6 +27,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -41,7 +40,7 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"upgrade"},
 	Short:   "List available cluster upgrades",
 	Long:    "List available and scheduled cluster version upgrades",
-	Run:     run,
+	RunE:    run,
 }
 
 func init() {
@@ -58,16 +57,20 @@ func init() {
 	)
 
 	confirm.AddFlag(flags)
+	output.AddFlag(Cmd)
 }
 
-func run(cmd *cobra.Command, _ []string) {
+// upgradeOutput is the representation of an available upgrade used by the structured output
+// formats.
+type upgradeOutput struct {
+	Version string   `json:"version"`
+	Notes   []string `json:"notes"`
+}
+
+func run(cmd *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r, cmd)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r, cmd)
 }
 
 func runWithRuntime(r *rosa.Runtime, _ *cobra.Command) error {
@@ -113,7 +116,7 @@ func runWithRuntime(r *rosa.Runtime, _ *cobra.Command) error {
 			return fmt.Errorf("Failed to get available upgrades for cluster '%s': %v", clusterKey, err)
 		}
 
-		if len(availableUpgrades) == 0 {
+		if len(availableUpgrades) == 0 && !output.HasFlag() {
 			r.Reporter.Infof("There are no available upgrades for cluster '%s'", clusterKey)
 			return nil
 		}
@@ -136,9 +139,8 @@ func runWithRuntime(r *rosa.Runtime, _ *cobra.Command) error {
 		}
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "VERSION\tNOTES\n")
+	upgrades := make([]upgradeOutput, 0, len(availableUpgrades))
+	table := output.NewTable("VERSION", "NOTES")
 	for i, availableUpgrade := range availableUpgrades {
 		notes := make([]string, 0)
 		if i == 0 || availableUpgrade == latestRev {
@@ -162,10 +164,10 @@ func runWithRuntime(r *rosa.Runtime, _ *cobra.Command) error {
 				}
 			}
 		}
-		fmt.Fprintf(writer, "%s\t%s\n", availableUpgrade, strings.Join(notes, " - "))
+		upgrades = append(upgrades, upgradeOutput{Version: availableUpgrade, Notes: notes})
+		table.AddRow(availableUpgrade, strings.Join(notes, " - "))
 	}
-	writer.Flush()
-	return nil
+	return output.PrintTable(upgrades, table)
 }
 
 func formatScheduledUpgrade(availableUpgrade string,
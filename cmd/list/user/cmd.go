@@ -17,17 +17,16 @@ limitations under the License.
 package user
 
 import (
-	"fmt"
-	"math"
 	"os"
+	"sort"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/cmd/create/idp"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
@@ -43,6 +42,7 @@ var Cmd = &cobra.Command{
 
 func init() {
 	ocm.AddClusterFlag(Cmd)
+	output.AddFlag(Cmd)
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -80,32 +80,43 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 {
+	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 && !output.HasFlag() {
 		r.Reporter.Warnf("There are no users configured for cluster '%s'", clusterKey)
 		os.Exit(1)
 	}
 
-	longestUserId := 0.0
 	groups := make(map[string][]string)
 	for _, user := range clusterAdmins {
-		longestUserId = math.Max(longestUserId, float64(len(user.ID())))
 		groups[user.ID()] = []string{"cluster-admins"}
 	}
 	for _, user := range dedicatedAdmins {
-		longestUserId = math.Max(longestUserId, float64(len(user.ID())))
 		if _, ok := groups[user.ID()]; ok {
 			groups[user.ID()] = []string{"cluster-admins", "dedicated-admins"}
 		} else {
 			groups[user.ID()] = []string{"dedicated-admins"}
 		}
 	}
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, int(longestUserId)+2, 4, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tGROUPS\t\n")
-
-	for u, r := range groups {
-		fmt.Fprintf(writer, "%s\t%s\t\n", u, strings.Join(r, ", "))
-		writer.Flush()
+	users := make([]userGroups, 0, len(ids))
+	table := output.NewTable("ID", "GROUPS")
+	for _, id := range ids {
+		users = append(users, userGroups{ID: id, Groups: groups[id]})
+		table.AddRow(id, strings.Join(groups[id], ", "))
 	}
+	err = output.PrintTable(users, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+}
+
+// userGroups is the representation of a user used by the structured output formats.
+type userGroups struct {
+	ID     string   `json:"id"`
+	Groups []string `json:"groups"`
 }
@@ -17,10 +17,8 @@ limitations under the License.
 package version
 
 import (
-	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -90,23 +88,15 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if output.HasFlag() {
-		err = output.Print(availableVersions)
-		if err != nil {
-			r.Reporter.Errorf("%s", err)
-			os.Exit(1)
-		}
-		os.Exit(0)
-	}
-
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	var table *output.Table
 	if isHostedCp {
-		r.Reporter.Infof("Hosted cluster upgrades are cluster-based. To list available upgrades for a cluster, "+
-			"please use '%s'", upgrade.Cmd.CommandPath())
-		fmt.Fprintf(writer, "VERSION\t\tDEFAULT\n")
+		if !output.HasFlag() {
+			r.Reporter.Infof("Hosted cluster upgrades are cluster-based. To list available upgrades for a cluster, "+
+				"please use '%s'", upgrade.Cmd.CommandPath())
+		}
+		table = output.NewTable("VERSION", "DEFAULT")
 	} else {
-		fmt.Fprintf(writer, "VERSION\t\tDEFAULT\t\tAVAILABLE UPGRADES\n")
+		table = output.NewTable("VERSION", "DEFAULT", "AVAILABLE UPGRADES")
 	}
 
 	for _, version := range availableVersions {
@@ -114,16 +104,15 @@ func run(cmd *cobra.Command, _ []string) {
 		if version.Default() {
 			isDefault = "yes"
 		}
-		availableUpgrades := ""
-		if !isHostedCp {
-			availableUpgrades = strings.Join(version.AvailableUpgrades(), ", ")
+		if isHostedCp {
+			table.AddRow(version.RawID(), isDefault)
+		} else {
+			table.AddRow(version.RawID(), isDefault, strings.Join(version.AvailableUpgrades(), ", "))
 		}
-		fmt.Fprintf(writer,
-			"%s\t\t%s\t\t%s\n",
-			version.RawID(),
-			isDefault,
-			availableUpgrades,
-		)
 	}
-	writer.Flush()
+	err = output.PrintTable(availableVersions, table)
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+	networkplan "github.com/openshift/rosa/pkg/helper/network"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	vpcID       string
+	subnetIDs   []string
+	machineCIDR net.IPNet
+	reserved    []string
+	maxNodes    int
+	podsPerNode int
+	outputFile  string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Propose the CIDRs of a cluster",
+	Long: "Propose machine, service and pod CIDRs and a host prefix that don't overlap each other, the VPC " +
+		"the cluster is installed into, the reserved ranges nor the join and transit subnets of " +
+		"OVN-Kubernetes, and that give enough addresses for the maximum number of nodes and pods per node. " +
+		"Each plan explains how many nodes it supports, limited by the pod CIDR and the machine CIDR. The " +
+		"recommended plan can be saved with '--output-file' and used as the defaults of 'rosa create cluster' " +
+		"with '--network-plan'.",
+	Example: `  # Plan the CIDRs of a cluster installed into an existing VPC
+  rosa plan network --vpc-id vpc-0d5d5d5d5d5d5d5d5 --reserved-cidrs 10.0.0.0/8,192.168.0.0/16
+
+  # Plan the CIDRs for 500 nodes and save the recommended plan for 'rosa create cluster'
+  rosa plan network --subnet-ids subnet-03046a9b92b5014fb --max-nodes 500 --output-file plan.json
+  rosa create cluster --cluster-name mycluster --network-plan plan.json --interactive`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.vpcID,
+		"vpc-id",
+		"",
+		"ID of the VPC the cluster will be installed into. Its CIDR is used as the machine CIDR.",
+	)
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"The subnet IDs the cluster will be installed into, to use the CIDR of their VPC as the machine CIDR.",
+	)
+	flags.IPNetVar(
+		&args.machineCIDR,
+		"machine-cidr",
+		net.IPNet{},
+		"Machine CIDR to use when the cluster doesn't use an existing VPC. If not set a free range is proposed.",
+	)
+	flags.StringSliceVar(
+		&args.reserved,
+		"reserved-cidrs",
+		nil,
+		"Comma-separated list of ranges used elsewhere, for example in the corporate network, "+
+			"that the cluster must not overlap.",
+	)
+	flags.IntVar(
+		&args.maxNodes,
+		"max-nodes",
+		180,
+		"Maximum number of nodes the cluster will have.",
+	)
+	flags.IntVar(
+		&args.podsPerNode,
+		"pods-per-node",
+		250,
+		"Maximum number of pods each node will run.",
+	)
+	flags.StringVar(
+		&args.outputFile,
+		"output-file",
+		"",
+		"Save the recommended plan to this file, to use it with 'rosa create cluster --network-plan'.",
+	)
+	output.AddFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	if args.vpcID != "" && len(args.subnetIDs) > 0 {
+		return exitcode.InvalidInput.Errorf("Only one of '--vpc-id' and '--subnet-ids' can be used")
+	}
+	useExistingVPC := args.vpcID != "" || len(args.subnetIDs) > 0
+	if useExistingVPC && cmd.Flags().Changed("machine-cidr") {
+		return exitcode.InvalidInput.Errorf("The machine CIDR is the CIDR of the VPC, " +
+			"'--machine-cidr' can't be used along with '--vpc-id' or '--subnet-ids'")
+	}
+	reserved := []*net.IPNet{}
+	for _, value := range args.reserved {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid reserved CIDR: %s", err)
+		}
+		reserved = append(reserved, network)
+	}
+
+	r := rosa.NewRuntime().WithOCM()
+	if useExistingVPC {
+		r.WithAWS()
+	}
+	defer r.Cleanup()
+
+	dMachinecidr, dPodcidr, dServicecidr, _, _, _ := r.OCMClient.GetDefaultClusterFlavors("osd-4")
+	if dMachinecidr == nil || dPodcidr == nil || dServicecidr == nil {
+		return fmt.Errorf("Error retrieving default cluster flavors")
+	}
+	options := networkplan.PlanOptions{
+		Reserved:           reserved,
+		MaxNodes:           args.maxNodes,
+		PodsPerNode:        args.podsPerNode,
+		DefaultMachineCIDR: dMachinecidr,
+		DefaultServiceCIDR: dServicecidr,
+		DefaultPodCIDR:     dPodcidr,
+	}
+	if useExistingVPC {
+		vpcCIDR, err := r.AWSClient.GetVPCCIDR(args.vpcID, args.subnetIDs)
+		if err != nil {
+			return err
+		}
+		options.MachineCIDR = vpcCIDR
+	} else if cmd.Flags().Changed("machine-cidr") {
+		options.MachineCIDR = &args.machineCIDR
+	}
+
+	plans, err := networkplan.PlanCIDRs(options)
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+
+	if args.outputFile != "" {
+		data, err := json.MarshalIndent(plans[0], "", "  ")
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(args.outputFile, append(data, '\n'), 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to save network plan: %v", err)
+		}
+	}
+
+	if output.HasFlag() {
+		return output.Print(plans)
+	}
+	table := output.NewTable("PLAN", "MACHINE CIDR", "SERVICE CIDR", "POD CIDR", "HOST PREFIX", "MAX NODES",
+		"MAX PODS PER NODE")
+	for i, plan := range plans {
+		table.AddRow(i+1, plan.MachineCIDR, plan.ServiceCIDR, plan.PodCIDR, fmt.Sprintf("/%d", plan.HostPrefix),
+			plan.MaxNodes, plan.MaxPodsPerNode)
+	}
+	err = output.PrintTable(plans, table)
+	if err != nil {
+		return err
+	}
+	for i, plan := range plans {
+		r.Reporter.Infof("Plan %d: %s", i+1, plan.Describe())
+	}
+	r.Reporter.Infof("To create a cluster with the recommended plan run:\n\n"+
+		"\trosa create cluster %s\n", plans[0].CreateFlags())
+	if args.outputFile != "" {
+		r.Reporter.Infof("Saved the recommended plan to '%s', use it with "+
+			"'rosa create cluster --network-plan %s'", args.outputFile, args.outputFile)
+	}
+	return nil
+}
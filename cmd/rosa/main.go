@@ -23,13 +23,16 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/cmd/apply"
 	"github.com/openshift/rosa/cmd/completion"
 	"github.com/openshift/rosa/cmd/create"
 	"github.com/openshift/rosa/cmd/describe"
+	"github.com/openshift/rosa/cmd/diff"
 	"github.com/openshift/rosa/cmd/dlt"
 	"github.com/openshift/rosa/cmd/docs"
 	"github.com/openshift/rosa/cmd/download"
 	"github.com/openshift/rosa/cmd/edit"
+	"github.com/openshift/rosa/cmd/export"
 	"github.com/openshift/rosa/cmd/grant"
 	"github.com/openshift/rosa/cmd/hibernate"
 	"github.com/openshift/rosa/cmd/initialize"
@@ -39,41 +42,66 @@ import (
 	"github.com/openshift/rosa/cmd/login"
 	"github.com/openshift/rosa/cmd/logout"
 	"github.com/openshift/rosa/cmd/logs"
+	"github.com/openshift/rosa/cmd/plan"
 	"github.com/openshift/rosa/cmd/register"
 	"github.com/openshift/rosa/cmd/resume"
 	"github.com/openshift/rosa/cmd/revoke"
+	"github.com/openshift/rosa/cmd/rotate"
 	"github.com/openshift/rosa/cmd/uninstall"
 	"github.com/openshift/rosa/cmd/unlink"
 	"github.com/openshift/rosa/cmd/upgrade"
 	"github.com/openshift/rosa/cmd/verify"
 	"github.com/openshift/rosa/cmd/version"
+	"github.com/openshift/rosa/cmd/wait"
 	"github.com/openshift/rosa/cmd/whoami"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/color"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/reporter"
 )
 
+// started is set once the flags have been parsed and the selected command starts running.
+var started bool
+
 var root = &cobra.Command{
 	Use:   "rosa",
 	Short: "Command line tool for ROSA.",
 	Long: "Command line tool for Red Hat OpenShift Service on AWS.\n" +
 		"For further documentation visit " +
 		"https://access.redhat.com/documentation/en-us/red_hat_openshift_service_on_aws\n",
+	PersistentPreRunE: func(cmd *cobra.Command, argv []string) error {
+		// From here on errors are returned by the commands themselves, so they are reported by
+		// the main function instead of printing them along with the usage:
+		started = true
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		err := reporter.ValidateLogFormat()
+		if err != nil {
+			return exitcode.InvalidInput.Wrap(err)
+		}
+		reporter.SetCommand(cmd.CommandPath())
+		return nil
+	},
 }
 
 func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
 	color.AddFlag(root)
+	reporter.AddFlag(root)
 	arguments.AddDebugFlag(fs)
 
 	// Register the subcommands:
+	root.AddCommand(apply.Cmd)
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(create.Cmd)
 	root.AddCommand(describe.Cmd)
+	root.AddCommand(diff.Cmd)
 	root.AddCommand(dlt.Cmd)
 	root.AddCommand(docs.Cmd)
 	root.AddCommand(download.Cmd)
 	root.AddCommand(edit.Cmd)
+	root.AddCommand(export.Cmd)
 	root.AddCommand(grant.Cmd)
 	root.AddCommand(list.Cmd)
 	root.AddCommand(initialize.Cmd)
@@ -81,12 +109,15 @@ func init() {
 	root.AddCommand(login.Cmd)
 	root.AddCommand(logout.Cmd)
 	root.AddCommand(logs.Cmd)
+	root.AddCommand(plan.Cmd)
 	root.AddCommand(register.Cmd)
 	root.AddCommand(revoke.Cmd)
+	root.AddCommand(rotate.Cmd)
 	root.AddCommand(uninstall.Cmd)
 	root.AddCommand(upgrade.Cmd)
 	root.AddCommand(verify.Cmd)
 	root.AddCommand(version.Cmd)
+	root.AddCommand(wait.Cmd)
 	root.AddCommand(whoami.Cmd)
 	root.AddCommand(hibernate.GenerateCommand())
 	root.AddCommand(resume.GenerateCommand())
@@ -98,10 +129,24 @@ func main() {
 	// Execute the root command:
 	root.SetArgs(os.Args[1:])
 	err := root.Execute()
-	if err != nil {
+	if err == nil {
+		return
+	}
+
+	// Errors returned before the command started are about the command line itself, and cobra
+	// has already printed them together with the usage:
+	if !started {
 		if !strings.Contains(err.Error(), "Did you mean this?") {
-			fmt.Fprintf(os.Stderr, "Failed to execute root command: %s\n", err)
+			if reporter.UseJSON() {
+				_ = reporter.CreateReporterOrExit().Errorf("Failed to execute root command: %s", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Failed to execute root command: %s\n", err)
+			}
 		}
-		os.Exit(1)
+		os.Exit(int(exitcode.InvalidInput))
+	}
+	if !reporter.IsReported(err) {
+		_ = reporter.CreateReporterOrExit().Errorf("%s", err)
 	}
+	os.Exit(int(exitcode.Of(err)))
 }
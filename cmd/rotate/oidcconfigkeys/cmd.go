@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcconfigkeys
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var args struct {
+	oidcConfigID string
+	retire       bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-config-keys",
+	Aliases: []string{"oidcconfigkeys", "oidc-config-key"},
+	Short:   "Rotate the signing key of an OIDC config",
+	Long: "Rotate the key used to sign the service account tokens of an unmanaged OIDC config. " +
+		"A new private key is stored in the secret of the config and its public key is published " +
+		"in the JSON Web Key Set of the bucket along with the old keys, so that existing tokens remain " +
+		"valid. Once the tokens signed with the old keys have expired, run the command again with " +
+		"'--retire-old-keys' to remove them from the bucket.",
+	Example: `  # Generate a new signing key for an OIDC config
+  rosa rotate oidc-config-keys --oidc-config-id <oidc_config_id> --mode auto
+
+  # Remove the old keys once the tokens signed with them have expired
+  rosa rotate oidc-config-keys --oidc-config-id <oidc_config_id> --retire-old-keys --mode auto`,
+	RunE: run,
+}
+
+const (
+	oidcConfigIDFlag = "oidc-config-id"
+	retireFlag       = "retire-old-keys"
+)
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.oidcConfigID,
+		oidcConfigIDFlag,
+		"",
+		"Registered ID of the unmanaged OIDC config.",
+	)
+
+	flags.BoolVar(
+		&args.retire,
+		retireFlag,
+		false,
+		"Remove the keys that aren't stored in the secret anymore from the JSON Web Key Set, "+
+			"instead of generating a new key.",
+	)
+
+	aws.AddModeFlag(Cmd)
+	interactive.AddFlag(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	mode, err := aws.GetMode()
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("%s", err)
+	}
+
+	// Determine if interactive mode is needed
+	if !interactive.Enabled() && !cmd.Flags().Changed("mode") {
+		interactive.Enable()
+	}
+
+	if interactive.Enabled() {
+		mode, err = interactive.GetOption(interactive.Input{
+			Question: "OIDC Config key rotation mode",
+			Help:     cmd.Flags().Lookup("mode").Usage,
+			Default:  aws.ModeAuto,
+			Options:  aws.Modes,
+			Required: true,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid OIDC Config key rotation mode: %s", err)
+		}
+	}
+
+	if (args.oidcConfigID == "" || interactive.Enabled()) && !cmd.Flags().Changed(oidcConfigIDFlag) {
+		args.oidcConfigID = interactive.GetOidcConfigID(r, cmd)
+	}
+	if args.oidcConfigID == "" {
+		return exitcode.InvalidInput.Errorf("Expected a valid OIDC Config ID")
+	}
+
+	clients := &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}
+	rotation, err := workflows.PrepareOidcConfigKeyRotation(context.Background(), clients,
+		workflows.OidcConfigKeysOptions{
+			OidcConfigID: args.oidcConfigID,
+			Retire:       args.retire,
+		})
+	if err != nil {
+		return err
+	}
+	if args.retire && len(rotation.RetiredKeyIDs) == 0 {
+		r.Reporter.Infof("OIDC Config '%s' only publishes the key stored in its secret, "+
+			"there are no keys to retire", args.oidcConfigID)
+		return nil
+	}
+
+	switch mode {
+	case aws.ModeAuto:
+		r.OCMClient.LogEvent("ROSARotateOidcConfigKeysModeAuto", nil)
+		return rotateKeys(r, clients, rotation)
+	case aws.ModeManual:
+		r.OCMClient.LogEvent("ROSARotateOidcConfigKeysModeManual", nil)
+		return printCommands(r, rotation)
+	default:
+		return exitcode.InvalidInput.Errorf("Invalid mode. Allowed values are %s", aws.Modes)
+	}
+}
+
+func rotateKeys(r *rosa.Runtime, clients *workflows.Clients, rotation *workflows.OidcConfigKeyRotation) error {
+	if args.retire {
+		if !confirm.Prompt(true, "Remove keys '%s' from the JSON Web Key Set of OIDC Config '%s'?",
+			strings.Join(rotation.RetiredKeyIDs, "', '"), args.oidcConfigID) {
+			return nil
+		}
+	} else if !confirm.Prompt(true, "Rotate the signing key of OIDC Config '%s'?", args.oidcConfigID) {
+		return nil
+	}
+
+	err := workflows.ApplyOidcConfigKeyRotation(context.Background(), clients, rotation)
+	if err != nil {
+		return err
+	}
+	if args.retire {
+		r.Reporter.Infof("Removed keys '%s' from the JSON Web Key Set of OIDC Config '%s'",
+			strings.Join(rotation.RetiredKeyIDs, "', '"), args.oidcConfigID)
+		return nil
+	}
+	r.Reporter.Infof("Published key '%s' and stored its private key in secret '%s'. "+
+		"Once the tokens signed with the old keys have expired, run the following command to retire them:\n\n"+
+		"\trosa rotate oidc-config-keys --oidc-config-id %s --retire-old-keys\n",
+		rotation.KeyIDs[0], rotation.SecretARN, args.oidcConfigID)
+	return nil
+}
+
+func printCommands(r *rosa.Runtime, rotation *workflows.OidcConfigKeyRotation) error {
+	jwksFilename := fmt.Sprintf("jwks-%s.json", rotation.BucketName)
+	err := helper.SaveDocument(string(rotation.Jwks), jwksFilename)
+	if err != nil {
+		return fmt.Errorf("There was a problem saving JSON Web Key Set to a file: %s", err)
+	}
+	privateKeyFilename := ""
+	if !args.retire {
+		privateKeyFilename = fmt.Sprintf("rosa-private-key-%s.key", rotation.BucketName)
+		err = helper.SaveDocument(string(rotation.PrivateKey), privateKeyFilename)
+		if err != nil {
+			return fmt.Errorf("There was a problem saving private key to a file: %s", err)
+		}
+	}
+
+	if r.Reporter.IsTerminal() {
+		r.Reporter.Infof("All documents saved to the current directory")
+		if args.retire {
+			r.Reporter.Infof("Run the following commands to retire the old keys of OIDC Config '%s':\n",
+				args.oidcConfigID)
+		} else {
+			r.Reporter.Infof("Run the following commands to rotate the signing key of OIDC Config '%s'. "+
+				"The key set has to be uploaded before the secret is updated:\n", args.oidcConfigID)
+		}
+	}
+	commands := workflows.OidcConfigKeyRotationCommands(rotation, jwksFilename, privateKeyFilename)
+	fmt.Println(awscb.JoinCommands(commands))
+	if r.Reporter.IsTerminal() && !args.retire {
+		r.Reporter.Infof("Once the tokens signed with the old keys have expired, run the following command "+
+			"to retire them:\n\n\trosa rotate oidc-config-keys --oidc-config-id %s --retire-old-keys\n",
+			args.oidcConfigID)
+	}
+	return nil
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
@@ -93,6 +94,7 @@ func init() {
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
+	output.AddFlag(Cmd)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -110,6 +112,12 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	prefix := args.prefix
 
+	// The output format is used to review the policy changes, so the informative messages are
+	// written to the standard error:
+	if output.HasFlag() {
+		rprtr.SetInfoOutput(os.Stderr)
+	}
+
 	version := args.version
 	isVersionChosen := version != ""
 	channelGroup := args.channelGroup
@@ -211,6 +219,28 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
+	policies, err := ocmClient.GetPolicies("")
+	if err != nil {
+		reporter.Errorf("Expected a valid role creation mode: %s", err)
+		os.Exit(1)
+	}
+
+	policyDiffs, err := roles.AccountRolePolicyDiffs(awsClient, prefix, creator.AccountID, policyPath, policies)
+	if err != nil {
+		reporter.Errorf("Failed to compare the account role policies: %v", err)
+		os.Exit(1)
+	}
+	err = roles.PrintPolicyDiffs(r, policyDiffs)
+	if err != nil {
+		reporter.Errorf("Failed to print the account role policy changes: %v", err)
+		os.Exit(1)
+	}
+	// With an output format only the changes are printed, so that they can be reviewed before
+	// running the upgrade:
+	if output.HasFlag() {
+		return nil
+	}
+
 	// Determine if interactive mode is needed
 	if !interactive.Enabled() && !cmd.Flags().Changed("mode") {
 		interactive.Enable()
@@ -230,12 +260,6 @@ func run(cmd *cobra.Command, argv []string) error {
 		}
 		aws.SetModeKey(mode)
 	}
-	policies, err := ocmClient.GetPolicies("")
-	if err != nil {
-		reporter.Errorf("Expected a valid role creation mode: %s", err)
-		os.Exit(1)
-	}
-
 	switch mode {
 	case aws.ModeAuto:
 		if isUpgradeNeedForAccountRolePolicies {
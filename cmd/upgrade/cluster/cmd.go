@@ -61,7 +61,7 @@ var Cmd = &cobra.Command{
 
   # Schedule a cluster upgrade within the hour
   rosa upgrade cluster -c mycluster --version 4.12.20`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
@@ -131,14 +131,10 @@ func init() {
 	confirm.AddFlag(flags)
 }
 
-func run(cmd *cobra.Command, _ []string) {
+func run(cmd *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r, cmd)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r, cmd)
 }
 
 func runWithRuntime(r *rosa.Runtime, cmd *cobra.Command) error {
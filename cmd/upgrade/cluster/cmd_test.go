@@ -109,7 +109,7 @@ var _ = Describe("Upgrade", Ordered, func() {
 
   # Schedule a cluster upgrade within the hour
   rosa upgrade cluster -c mycluster --version 4.5.20`,
-			Run: run,
+			RunE: run,
 		}
 		ocm.SetClusterKey("cluster1")
 		r = rosa.NewRuntime()
@@ -18,7 +18,6 @@ package machinepool
 
 import (
 	"fmt"
-	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/helper/versions"
@@ -46,7 +45,7 @@ var Cmd = &cobra.Command{
 
   # Schedule a machinepool upgrade within the hour
   rosa upgrade machinepool np1 -c mycluster --version 4.12.20`,
-	Run: run,
+	RunE: run,
 	Args: func(_ *cobra.Command, argv []string) error {
 		if len(argv) != 1 {
 			return fmt.Errorf(
@@ -88,14 +87,10 @@ func init() {
 	interactive.AddFlag(flags)
 }
 
-func run(cmd *cobra.Command, argv []string) {
+func run(cmd *cobra.Command, argv []string) error {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r, cmd, argv)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r, cmd, argv)
 }
 
 func runWithRuntime(r *rosa.Runtime, cmd *cobra.Command, argv []string) error {
@@ -37,6 +37,7 @@ import (
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
@@ -70,7 +71,7 @@ func init() {
 
 	ocm.AddClusterFlag(Cmd)
 
-	aws.AddModeFlag(Cmd)
+	aws.AddPlanModeFlag(Cmd)
 
 	flags.StringVar(
 		&args.clusterUpgradeVersion,
@@ -98,6 +99,7 @@ func init() {
 
 	confirm.AddFlag(flags)
 	interactive.AddFlag(flags)
+	output.AddFlag(Cmd)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -120,14 +122,18 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	args.isInvokedFromClusterUpgrade = isInvokedFromClusterUpgrade
 
+	// The output format is used to review the policy changes, so the informative messages are
+	// written to the standard error:
+	if output.HasFlag() {
+		rprtr.SetInfoOutput(os.Stderr)
+	}
+
 	r.GetClusterKey()
 	cluster = r.FetchCluster()
 
-	mode, err := aws.GetMode()
-	if err != nil {
-		reporter.Errorf("%s", err)
-		os.Exit(1)
-	}
+	mode := r.GetPlanMode()
+	// In plan mode the AWS client of the runtime has been replaced:
+	awsClient = r.AWSClient
 
 	clusterUpgradeVersion := args.clusterUpgradeVersion
 
@@ -171,7 +177,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		interactive.Enable()
 	}
 
-	if interactive.Enabled() && !skipInteractive {
+	if interactive.Enabled() && !skipInteractive && r.Plan == nil && !output.HasFlag() {
 		var err error
 		mode, err = interactive.GetOption(interactive.Input{
 			Question: "Roles upgrade mode",
@@ -217,7 +223,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		}
 		r.Reporter.Infof("Cluster '%s' operator roles have attached managed policies. "+
 			"An upgrade isn't needed", cluster.Name())
-		return nil
+		return r.PrintPlan()
 	}
 
 	policyVersion := args.policyUpgradeversion
@@ -241,6 +247,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		return printPolicyDiffs(r, cluster, creator.AccountID, credRequests)
+	}
+
 	var spin *spinner.Spinner
 	if reporter.IsTerminal() {
 		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
@@ -278,6 +288,18 @@ func run(cmd *cobra.Command, argv []string) error {
 			os.Exit(1)
 		}
 
+		policyDiffs, err := roles.AccountRolePolicyDiffsFromCluster(awsClient, cluster, creator.AccountID,
+			accountRolePolicies)
+		if err != nil {
+			reporter.Errorf("Failed to compare the account role policies: %v", err)
+			os.Exit(1)
+		}
+		err = roles.PrintPolicyDiffs(r, policyDiffs)
+		if err != nil {
+			reporter.Errorf("Failed to print the account role policy changes: %v", err)
+			os.Exit(1)
+		}
+
 		switch mode {
 		case aws.ModeAuto:
 			if isUpgradeNeedForAccountRolePolicies {
@@ -380,8 +402,8 @@ func run(cmd *cobra.Command, argv []string) error {
 			"Operator roles/policies associated with the cluster '%s' are already up-to-date.",
 			cluster.ID(),
 		)
-		if args.isInvokedFromClusterUpgrade {
-			return nil
+		if args.isInvokedFromClusterUpgrade || r.Plan != nil {
+			return r.PrintPlan()
 		}
 		os.Exit(0)
 	}
@@ -393,6 +415,18 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 
 	if isOperatorPolicyUpgradeNeeded {
+		policyDiffs, err := roles.OperatorRolePolicyDiffs(r.AWSClient, cluster, r.Creator.AccountID,
+			operatorRolePolicies, credRequests, operatorRolePolicyPrefix)
+		if err != nil {
+			r.Reporter.Errorf("Failed to compare the operator role policies: %v", err)
+			os.Exit(1)
+		}
+		err = roles.PrintPolicyDiffs(r, policyDiffs)
+		if err != nil {
+			r.Reporter.Errorf("Failed to print the operator role policy changes: %v", err)
+			os.Exit(1)
+		}
+
 		err = upgradeOperatorPolicies(
 			mode,
 			r,
@@ -454,7 +488,38 @@ func run(cmd *cobra.Command, argv []string) error {
 			"\trosa upgrade cluster --cluster %s\n", cluster.ID())
 		os.Exit(0)
 	}
-	return nil
+	return r.PrintPlan()
+}
+
+// printPolicyDiffs prints the changes that upgrading the account and operator role policies of the
+// cluster makes to their permissions, without upgrading them.
+func printPolicyDiffs(r *rosa.Runtime, cluster *v1.Cluster, accountID string,
+	credRequests map[string]*v1.STSOperator) error {
+	accountRolePolicies, err := r.OCMClient.GetPolicies("")
+	if err != nil {
+		return fmt.Errorf("Failed to get account role policies: %v", err)
+	}
+	policyDiffs, err := roles.AccountRolePolicyDiffsFromCluster(r.AWSClient, cluster, accountID,
+		accountRolePolicies)
+	if err != nil {
+		return fmt.Errorf("Failed to compare the account role policies: %v", err)
+	}
+
+	operatorRolePolicies, err := r.OCMClient.GetPolicies("OperatorRole")
+	if err != nil {
+		return fmt.Errorf("Failed to get operator role policies: %v", err)
+	}
+	operatorRolePolicyPrefix, err := aws.GetOperatorRolePolicyPrefixFromCluster(cluster, r.AWSClient)
+	if err != nil {
+		return err
+	}
+	operatorPolicyDiffs, err := roles.OperatorRolePolicyDiffs(r.AWSClient, cluster, accountID,
+		operatorRolePolicies, credRequests, operatorRolePolicyPrefix)
+	if err != nil {
+		return fmt.Errorf("Failed to compare the operator role policies: %v", err)
+	}
+
+	return roles.PrintPolicyDiffs(r, append(policyDiffs, operatorPolicyDiffs...))
 }
 
 func LogError(key string, ocmClient *ocm.Client, defaultPolicyVersion string, err error, reporter *rprtr.Object) {
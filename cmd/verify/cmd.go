@@ -21,8 +21,11 @@ import (
 
 	"github.com/openshift/rosa/cmd/verify/network"
 	"github.com/openshift/rosa/cmd/verify/oc"
+	"github.com/openshift/rosa/cmd/verify/oidcconfig"
 	"github.com/openshift/rosa/cmd/verify/permissions"
+	"github.com/openshift/rosa/cmd/verify/proxy"
 	"github.com/openshift/rosa/cmd/verify/quota"
+	"github.com/openshift/rosa/cmd/verify/roles"
 	"github.com/openshift/rosa/cmd/verify/rosa"
 )
 
@@ -35,7 +38,10 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(network.Cmd)
 	Cmd.AddCommand(oc.Cmd)
+	Cmd.AddCommand(oidcconfig.Cmd)
 	Cmd.AddCommand(permissions.Cmd)
+	Cmd.AddCommand(proxy.Cmd)
 	Cmd.AddCommand(quota.Cmd)
+	Cmd.AddCommand(roles.Cmd)
 	Cmd.AddCommand(rosa.Cmd)
 }
@@ -18,7 +18,7 @@ package network
 
 import (
 	"fmt"
-	"os"
+	"net"
 	"strings"
 	"time"
 
@@ -28,17 +28,25 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/openshift/rosa/pkg/rosa"
 )
 
 var args struct {
-	region     string
-	roleArn    string
-	statusOnly bool
-	subnetIDs  []string
-	watch      bool
+	region      string
+	roleArn     string
+	statusOnly  bool
+	subnetIDs   []string
+	watch       bool
+	local       bool
+	machineCIDR net.IPNet
+	serviceCIDR net.IPNet
+	podCIDR     net.IPNet
+	privateLink bool
+	hostedCP    bool
+	replicas    int
 }
 
 var Cmd = makeCmd()
@@ -49,8 +57,12 @@ func makeCmd() *cobra.Command {
 		Short: "Verify VPC subnets are configured correctly",
 		Long:  "Verify that the VPC subnets are configured correctly.",
 		Example: `  # Verify two subnets
-	rosa verify network --subnet-ids subnet-03046a9b92b5014fb,subnet-03046a9c92b5014fb`,
-		Run: run,
+	rosa verify network --subnet-ids subnet-03046a9b92b5014fb,subnet-03046a9c92b5014fb
+
+  # Check the configuration of the subnets locally with the EC2 API before creating a cluster
+	rosa verify network --local --subnet-ids subnet-03046a9b92b5014fb,subnet-03046a9c92b5014fb \
+	--region us-east-1 --machine-cidr 10.0.0.0/16 --replicas 3`,
+		RunE: run,
 	}
 }
 
@@ -62,6 +74,7 @@ const (
 	statusOnlyFlag = "status-only"
 	subnetIDsFlag  = "subnet-ids"
 	watchFlag      = "watch"
+	localFlag      = "local"
 
 	NetworkVerifyPending NetworkVerifyState = "pending"
 	NetworkVerifyPassed  NetworkVerifyState = "passed"
@@ -111,16 +124,64 @@ func initFlags(cmd *cobra.Command) {
 		false,
 		"Check status of previously submitted subnets.",
 	)
+
+	flags.BoolVar(
+		&args.local,
+		localFlag,
+		false,
+		"Check the configuration of the subnets with the EC2 API instead of the network verifier: "+
+			"CIDRs, public and private subnets, egress routes, DNS attributes of the VPC, load balancer "+
+			"tags and free IPs. It doesn't need --role-arn. The other options of the cluster are taken "+
+			"from --cluster or from the flags below.",
+	)
+
+	flags.IPNetVar(
+		&args.machineCIDR,
+		"machine-cidr",
+		net.IPNet{},
+		"Block of IP addresses used by the cluster nodes, checked with --local.",
+	)
+
+	flags.IPNetVar(
+		&args.serviceCIDR,
+		"service-cidr",
+		net.IPNet{},
+		"Block of IP addresses for services, checked with --local.",
+	)
+
+	flags.IPNetVar(
+		&args.podCIDR,
+		"pod-cidr",
+		net.IPNet{},
+		"Block of IP addresses from which Pod IP addresses are allocated, checked with --local.",
+	)
+
+	flags.BoolVar(
+		&args.privateLink,
+		"private-link",
+		false,
+		"The cluster uses PrivateLink, so it only has private subnets. Used with --local.",
+	)
+
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"The cluster has a hosted control plane. Used with --local.",
+	)
+
+	flags.IntVar(
+		&args.replicas,
+		"replicas",
+		2,
+		"Number of compute nodes, used with --local to check the free IPs of the private subnets.",
+	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
+func run(cmd *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithAWS().WithOCM()
 	defer r.Cleanup()
-	err := runWithRuntime(r, cmd)
-	if err != nil {
-		r.Reporter.Errorf(err.Error())
-		os.Exit(1)
-	}
+	return runWithRuntime(r, cmd)
 }
 
 func runWithRuntime(r *rosa.Runtime, cmd *cobra.Command) error {
@@ -146,6 +207,10 @@ func runWithRuntime(r *rosa.Runtime, cmd *cobra.Command) error {
 		return err
 	}
 
+	if args.local {
+		return verifyLocally(r, cmd, cluster)
+	}
+
 	if cmd.Flags().Changed(roleArnFlag) {
 		err := aws.ARNValidator(args.roleArn)
 		if err != nil {
@@ -266,3 +331,88 @@ func getRegion(cmd *cobra.Command, cluster *cmv1.Cluster) (region string, err er
 
 	return "", fmt.Errorf("Region is required")
 }
+
+// verifyLocally checks the configuration of the subnets with the EC2 API.
+func verifyLocally(r *rosa.Runtime, cmd *cobra.Command, cluster *cmv1.Cluster) error {
+	awsClient, err := aws.NewClient().
+		Region(args.region).
+		Logger(r.Logger).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create AWS client: %v", err)
+	}
+
+	options := aws.NetworkPreflightOptions{
+		SubnetIDs:    args.subnetIDs,
+		MachineCIDR:  cidrFlag(cmd, "machine-cidr", args.machineCIDR),
+		ServiceCIDR:  cidrFlag(cmd, "service-cidr", args.serviceCIDR),
+		PodCIDR:      cidrFlag(cmd, "pod-cidr", args.podCIDR),
+		PrivateLink:  args.privateLink,
+		Hypershift:   args.hostedCP,
+		ComputeNodes: args.replicas,
+	}
+	if cluster != nil {
+		if options.MachineCIDR == nil {
+			options.MachineCIDR = parseCIDR(cluster.Network().MachineCIDR())
+		}
+		if options.ServiceCIDR == nil {
+			options.ServiceCIDR = parseCIDR(cluster.Network().ServiceCIDR())
+		}
+		if options.PodCIDR == nil {
+			options.PodCIDR = parseCIDR(cluster.Network().PodCIDR())
+		}
+		if !cmd.Flags().Changed("private-link") {
+			options.PrivateLink = cluster.AWS().PrivateLink()
+		}
+		if !cmd.Flags().Changed("hosted-cp") {
+			options.Hypershift = cluster.Hypershift().Enabled()
+		}
+		if !cmd.Flags().Changed("replicas") {
+			options.ComputeNodes = cluster.Nodes().Compute()
+			if cluster.Nodes().AutoscaleCompute() != nil {
+				options.ComputeNodes = cluster.Nodes().AutoscaleCompute().MaxReplicas()
+			}
+		}
+	}
+
+	if r.Reporter.IsTerminal() {
+		r.Reporter.Infof("Checking the configuration of the following subnet IDs: %v", args.subnetIDs)
+	}
+	findings, err := awsClient.ValidateNetwork(options)
+	if err != nil {
+		return err
+	}
+	for _, finding := range findings {
+		message := finding.Message
+		if finding.SubnetID != "" {
+			message = fmt.Sprintf("%s: %s", finding.SubnetID, message)
+		}
+		if finding.Severity == aws.NetworkSeverityError {
+			r.Reporter.Errorf("%s\n  To fix it: %s", message, finding.Remediation)
+		} else {
+			r.Reporter.Warnf("%s\n  To fix it: %s", message, finding.Remediation)
+		}
+	}
+	if aws.HasNetworkErrors(findings) {
+		return exitcode.VerificationFailed.Errorf("The subnets aren't configured correctly for the cluster")
+	}
+	if len(findings) == 0 {
+		r.Reporter.Infof("The subnets are configured correctly")
+	}
+	return nil
+}
+
+func cidrFlag(cmd *cobra.Command, name string, value net.IPNet) *net.IPNet {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	return &value
+}
+
+func parseCIDR(value string) *net.IPNet {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil
+	}
+	return network
+}
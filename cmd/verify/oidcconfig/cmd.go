@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var args struct {
+	oidcConfigID        string
+	issuerURL           string
+	operatorRolesPrefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-config",
+	Aliases: []string{"oidcconfig"},
+	Short:   "Verify that an OIDC config can be used by clusters",
+	Long: "Verify that the discovery document and the JSON Web Key Set of an OIDC config are reachable and " +
+		"valid, that the OIDC provider of the AWS account trusts the issuer, and optionally that the " +
+		"operator roles trust it.",
+	Example: `  # Verify a registered OIDC config
+  rosa verify oidc-config --oidc-config-id 23soa2bgvpek9kmes9s7os7a5f2ml7mm
+
+  # Verify an issuer and the operator roles that must trust it
+  rosa verify oidc-config --issuer-url https://mybucket.s3.us-east-1.amazonaws.com --operator-roles-prefix mycluster`,
+	RunE: run,
+}
+
+const (
+	oidcConfigIDFlag = "oidc-config-id"
+	issuerURLFlag    = "issuer-url"
+)
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.oidcConfigID,
+		oidcConfigIDFlag,
+		"",
+		"Registered ID of the OIDC config to verify.",
+	)
+
+	flags.StringVar(
+		&args.issuerURL,
+		issuerURLFlag,
+		"",
+		"Issuer URL of the OIDC config to verify, when it isn't registered.",
+	)
+
+	flags.StringVar(
+		&args.operatorRolesPrefix,
+		"operator-roles-prefix",
+		"",
+		"Prefix of the operator roles that must trust the issuer. "+
+			"Operator roles are only verified when this flag is used.",
+	)
+
+	output.AddFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	if cmd.Flags().Changed(oidcConfigIDFlag) == cmd.Flags().Changed(issuerURLFlag) {
+		return exitcode.InvalidInput.Errorf("Either an OIDC config ID or an issuer URL must be specified")
+	}
+
+	if r.Reporter.IsTerminal() && !output.HasFlag() {
+		r.Reporter.Infof("Verifying OIDC config...")
+	}
+	verification, err := workflows.VerifyOidcConfig(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, workflows.VerifyOidcConfigOptions{
+		OidcConfigID:        args.oidcConfigID,
+		IssuerURL:           args.issuerURL,
+		OperatorRolesPrefix: args.operatorRolesPrefix,
+	})
+	if err != nil {
+		return err
+	}
+
+	if output.HasFlag() {
+		err = output.Print(verification)
+		if err != nil {
+			return err
+		}
+	} else {
+		printVerification(r, verification)
+	}
+
+	if verification.HasIssues() {
+		return exitcode.VerificationFailed.Errorf("OIDC config with issuer '%s' has %d issues",
+			verification.IssuerURL, len(verification.Issues))
+	}
+	if !output.HasFlag() {
+		r.Reporter.Infof("OIDC config with issuer '%s' is valid", verification.IssuerURL)
+	}
+	return nil
+}
+
+func printVerification(r *rosa.Runtime, verification *workflows.OidcConfigVerification) {
+	if verification.ProviderARN != "" {
+		r.Reporter.Infof("OIDC provider: %s", verification.ProviderARN)
+	}
+	if len(verification.KeyIDs) > 0 {
+		r.Reporter.Infof("Published keys: %s", strings.Join(verification.KeyIDs, ", "))
+	}
+	for _, issue := range verification.Issues {
+		fmt.Printf("  - [%s] %s\n", issue.Check, issue.Message)
+		for _, command := range issue.Remediation {
+			fmt.Printf("      %s\n", command)
+		}
+	}
+}
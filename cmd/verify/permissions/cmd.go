@@ -17,6 +17,7 @@ limitations under the License.
 package permissions
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -25,30 +26,67 @@ import (
 
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
+var args struct {
+	boundaryFile string
+	scpFiles     []string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "permissions",
 	Aliases: []string{"scp"},
 	Short:   "Verify AWS permissions are ok for non-STS cluster install",
-	Long:    "Verify AWS permissions needed to create a non-STS cluster are configured as expected",
+	Long: "Verify AWS permissions needed to create a non-STS cluster are configured as expected.\n\n" +
+		"With --boundary-file or --scp-file the permissions of the account and operator role policies " +
+		"are evaluated locally against the given permissions boundary and service control policies.",
 	Example: `  # Verify AWS permissions are configured correctly
   rosa verify permissions
 
   # Verify AWS permissions in a different region
-  rosa verify permissions --region=us-west-2`,
-	Run: run,
+  rosa verify permissions --region=us-west-2
+
+  # Verify that a permissions boundary and an SCP allow the permissions of the account and operator roles
+  rosa verify permissions --boundary-file boundary.json --scp-file scp.json`,
+	RunE: run,
 }
 
+const (
+	boundaryFileFlag = "boundary-file"
+	scpFileFlag      = "scp-file"
+)
+
 func init() {
 	flags := Cmd.Flags()
 
 	arguments.AddProfileFlag(flags)
 	arguments.AddRegionFlag(flags)
+
+	flags.StringVar(
+		&args.boundaryFile,
+		boundaryFileFlag,
+		"",
+		"File with the permissions boundary that will be used to create the roles. The permissions of the "+
+			"account and operator role policies are evaluated locally against it, instead of simulating the "+
+			"permissions of the current credentials.",
+	)
+
+	flags.StringArrayVar(
+		&args.scpFiles,
+		scpFileFlag,
+		[]string{},
+		"File with a service control policy that applies to the account. It can be repeated for each "+
+			"level of the organization. The permissions are evaluated locally like with --boundary-file.",
+	)
+
+	output.AddFlag(Cmd)
 }
 
-func run(cmd *cobra.Command, _ []string) {
+func run(cmd *cobra.Command, _ []string) error {
 	r := rosa.NewRuntime().WithOCM()
 	defer r.Cleanup()
 
@@ -59,6 +97,10 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if args.boundaryFile != "" || len(args.scpFiles) > 0 {
+		return verifyPolicyFiles(r, region)
+	}
+
 	// Create the AWS client:
 	r.AWSClient, err = aws.NewClient().
 		Logger(r.Logger).
@@ -97,4 +139,61 @@ func run(cmd *cobra.Command, _ []string) {
 		r.Reporter.Warnf("Failed to validate SCP policies. Will try to continue anyway...")
 	}
 	r.Reporter.Infof("AWS SCP policies ok")
+	return nil
+}
+
+// verifyPolicyFiles evaluates the permissions of the account and operator role policies against
+// the permissions boundary and SCP files. It doesn't need AWS credentials.
+func verifyPolicyFiles(r *rosa.Runtime, region string) error {
+	options := workflows.VerifyPermissionsOptions{
+		Region: region,
+	}
+	if args.boundaryFile != "" {
+		boundary, err := os.ReadFile(args.boundaryFile)
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Failed to read permissions boundary file: %v", err)
+		}
+		options.PermissionsBoundary = string(boundary)
+	}
+	for _, scpFile := range args.scpFiles {
+		scp, err := os.ReadFile(scpFile)
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Failed to read SCP file: %v", err)
+		}
+		options.SCPs = append(options.SCPs, string(scp))
+	}
+
+	if r.Reporter.IsTerminal() && !output.HasFlag() {
+		r.Reporter.Infof("Verifying the permissions of the account and operator role policies")
+	}
+	denied, err := workflows.VerifyPermissions(context.Background(), &workflows.Clients{
+		OCM: r.OCMClient,
+	}, options)
+	if err != nil {
+		return err
+	}
+
+	if output.HasFlag() {
+		err = output.Print(denied)
+		if err != nil {
+			return err
+		}
+	} else if len(denied) > 0 {
+		table := output.NewTable("POLICY", "ACTION", "RESOURCE", "DECISION", "DENIED BY")
+		for _, permission := range denied {
+			table.AddRow(permission.Policy, permission.Action, permission.Resource, permission.Decision,
+				permission.DeniedBy)
+		}
+		err = output.PrintTable(denied, table)
+		if err != nil {
+			return err
+		}
+	}
+	if len(denied) > 0 {
+		return exitcode.VerificationFailed.Errorf("%d permissions needed by the roles are not allowed", len(denied))
+	}
+	if !output.HasFlag() {
+		r.Reporter.Infof("The permissions needed by the account and operator roles are allowed")
+	}
+	return nil
 }
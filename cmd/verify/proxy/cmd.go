@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+	proxyhelper "github.com/openshift/rosa/pkg/helper/proxy"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	httpProxy                 string
+	httpsProxy                string
+	noProxy                   []string
+	additionalTrustBundleFile string
+	expiryWarningDays         int
+	connect                   bool
+	endpoints                 []string
+	timeout                   time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Verify the cluster-wide proxy configuration",
+	Long: "Verify the cluster-wide proxy configuration before using it to create or edit a cluster. The " +
+		"additional trust bundle is parsed completely to check that it only contains certificates, that " +
+		"they are valid, certificate authorities, not duplicated and with their issuers in the bundle. " +
+		"Certificates that expire soon are reported. The no-proxy entries are checked to be IP addresses, " +
+		"CIDRs or domains. With '--connect' a CONNECT request is sent through the proxy from this machine " +
+		"to each of the endpoints required by clusters, to check that the proxy allows them and that the " +
+		"certificates of intercepted connections are trusted by the additional trust bundle.",
+	Example: `  # Verify a trust bundle and a list of no-proxy entries
+  rosa verify proxy --additional-trust-bundle-file ca.pem --no-proxy .example.com,10.0.0.0/16
+
+  # Test the connections through the proxy to the required endpoints
+  rosa verify proxy --https-proxy http://proxy.example.com:3128 --additional-trust-bundle-file ca.pem --connect`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.httpProxy,
+		"http-proxy",
+		"",
+		"A proxy URL to use for creating HTTP connections outside the cluster. The URL scheme must be http.",
+	)
+	flags.StringVar(
+		&args.httpsProxy,
+		"https-proxy",
+		"",
+		"A proxy URL to use for creating HTTPS connections outside the cluster.",
+	)
+	flags.StringSliceVar(
+		&args.noProxy,
+		"no-proxy",
+		nil,
+		"A comma-separated list of destination domain names, domains, IP addresses or "+
+			"other network CIDRs to exclude proxying.",
+	)
+	flags.StringVar(
+		&args.additionalTrustBundleFile,
+		"additional-trust-bundle-file",
+		"",
+		"A file contains a PEM-encoded X.509 certificate bundle that will be "+
+			"added to the nodes' trusted certificate store.",
+	)
+	flags.IntVar(
+		&args.expiryWarningDays,
+		"expiry-warning-days",
+		proxyhelper.DefaultExpiryWarningDays,
+		"Report the certificates of the trust bundle that expire within this number of days.",
+	)
+	flags.BoolVar(
+		&args.connect,
+		"connect",
+		false,
+		"Test the connections to the required endpoints through the proxy from this machine.",
+	)
+	flags.StringSliceVar(
+		&args.endpoints,
+		"endpoints",
+		proxyhelper.RequiredEndpoints,
+		"Comma-separated list of 'host:port' endpoints to connect to with '--connect'.",
+	)
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		proxyhelper.DefaultConnectTimeout,
+		"Time given to each connection with '--connect'.",
+	)
+	output.AddFlag(Cmd)
+}
+
+type verification struct {
+	Findings    []*proxyhelper.Finding       `json:"findings"`
+	Connections []*proxyhelper.ConnectResult `json:"connections,omitempty"`
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime()
+	defer r.Cleanup()
+
+	if args.httpProxy == "" && args.httpsProxy == "" && len(args.noProxy) == 0 &&
+		args.additionalTrustBundleFile == "" {
+		return exitcode.InvalidInput.Errorf("Expected at least one of the following: http-proxy, https-proxy, " +
+			"no-proxy, additional-trust-bundle-file")
+	}
+	err := ocm.ValidateHTTPProxy(args.httpProxy)
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+	err = interactive.IsURL(args.httpsProxy)
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+	if args.connect && args.httpProxy == "" && args.httpsProxy == "" {
+		return exitcode.InvalidInput.Errorf("An HTTP or HTTPS proxy is required with '--connect'")
+	}
+
+	result := &verification{
+		Findings: proxyhelper.ValidateNoProxy(args.noProxy),
+	}
+	var bundle []byte
+	if args.additionalTrustBundleFile != "" {
+		bundle, err = os.ReadFile(args.additionalTrustBundleFile)
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Failed to read additional trust bundle file: %s", err)
+		}
+		result.Findings = append(result.Findings,
+			proxyhelper.ValidateTrustBundle(bundle, time.Now(), args.expiryWarningDays)...)
+	}
+	if args.connect {
+		connections, findings, err := proxyhelper.Connect(context.Background(), proxyhelper.ConnectOptions{
+			HTTPProxy:   args.httpProxy,
+			HTTPSProxy:  args.httpsProxy,
+			TrustBundle: bundle,
+			Endpoints:   args.endpoints,
+			Timeout:     args.timeout,
+		})
+		if err != nil {
+			return exitcode.InvalidInput.Wrap(err)
+		}
+		result.Connections = connections
+		result.Findings = append(result.Findings, findings...)
+	}
+
+	if output.HasFlag() {
+		err = output.Print(result)
+		if err != nil {
+			return err
+		}
+	} else {
+		printResult(r, result)
+	}
+	if proxyhelper.HasErrors(result.Findings) {
+		return exitcode.VerificationFailed.Errorf("The proxy configuration isn't valid:\n%s",
+			proxyhelper.DescribeFindings(proxyhelper.Errors(result.Findings)))
+	}
+	return nil
+}
+
+func printResult(r *rosa.Runtime, result *verification) {
+	for _, connection := range result.Connections {
+		switch {
+		case connection.Intercepted:
+			r.Reporter.Infof("Connected to '%s' through proxy '%s', intercepted by '%s' which is trusted by the "+
+				"additional trust bundle", connection.Endpoint, connection.Proxy, connection.Issuer)
+		case connection.Success:
+			r.Reporter.Infof("Connected to '%s' through proxy '%s'", connection.Endpoint, connection.Proxy)
+		}
+	}
+	for _, finding := range result.Findings {
+		if finding.Severity == proxyhelper.SeverityWarning {
+			r.Reporter.Warnf("%s. To fix it: %s", finding.Message, finding.Remediation)
+		}
+	}
+	if proxyhelper.HasErrors(result.Findings) {
+		return
+	}
+	if len(result.Findings) > 0 {
+		r.Reporter.Infof("Proxy configuration verified with %d warnings", len(result.Findings))
+		return
+	}
+	r.Reporter.Infof("Proxy configuration verified")
+}
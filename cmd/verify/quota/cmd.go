@@ -17,6 +17,7 @@ limitations under the License.
 package quota
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -24,18 +25,39 @@ import (
 
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper/quotas"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
 )
 
+var args struct {
+	forSpec            bool
+	hostedCP           bool
+	multiAZ            bool
+	private            bool
+	subnetIDs          []string
+	computeMachineType string
+	computeNodes       int
+	workerDiskSize     string
+}
+
 var Cmd = &cobra.Command{
 	Use:   "quota",
 	Short: "Verify AWS quota is ok for cluster install",
-	Long:  "Verify AWS quota needed to create a cluster is configured as expected",
+	Long: "Verify AWS quota needed to create a cluster is configured as expected.\n\n" +
+		"With --for-spec the quotas needed by the nodes, network and load balancers of the cluster " +
+		"described by the flags are compared with the current usage and the limits in the region.",
 	Example: `  # Verify AWS quotas are configured correctly
   rosa verify quota
 
   # Verify AWS quotas in a different region
-  rosa verify quota --region=us-west-2`,
+  rosa verify quota --region=us-west-2
+
+  # Verify that a multi-AZ cluster with 9 m5.2xlarge compute nodes fits in the AWS quotas
+  rosa verify quota --for-spec --multi-az --compute-machine-type m5.2xlarge --replicas 9`,
 	RunE: run,
 }
 
@@ -44,6 +66,60 @@ func init() {
 
 	arguments.AddRegionFlag(flags)
 	arguments.AddProfileFlag(flags)
+
+	flags.BoolVar(
+		&args.forSpec,
+		"for-spec",
+		false,
+		"Check the quotas needed by the cluster described by the other flags, instead of the quotas "+
+			"needed by five multi-AZ clusters.",
+	)
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"The cluster has a hosted control plane.",
+	)
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"The cluster is deployed to multiple availability zones in the region.",
+	)
+	flags.BoolVar(
+		&args.private,
+		"private",
+		false,
+		"The cluster API endpoint is private.",
+	)
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"The subnet IDs of the cluster. When set the cluster doesn't create a VPC, an internet gateway "+
+			"or NAT gateways.",
+	)
+	flags.StringVar(
+		&args.computeMachineType,
+		"compute-machine-type",
+		"m5.xlarge",
+		"Instance type for the compute nodes.",
+	)
+	flags.IntVar(
+		&args.computeNodes,
+		"replicas",
+		2,
+		"Number of compute nodes, or maximum number of compute nodes of an autoscaling cluster. "+
+			"Defaults to 3 for multi-AZ clusters.",
+	)
+	flags.StringVar(
+		&args.workerDiskSize,
+		"worker-disk-size",
+		"",
+		"Root disk size of the compute nodes with a suffix like GiB or TiB.",
+	)
+
+	output.AddFlag(Cmd)
 }
 
 func run(cmd *cobra.Command, _ []string) (err error) {
@@ -53,8 +129,7 @@ func run(cmd *cobra.Command, _ []string) (err error) {
 	// Get AWS region
 	region, err := aws.GetRegion(arguments.GetRegion())
 	if err != nil {
-		r.Reporter.Errorf("Error getting region: %v", err)
-		return err
+		return exitcode.InvalidInput.Errorf("Error getting region: %v", err)
 	}
 
 	// Create the AWS client:
@@ -67,8 +142,11 @@ func run(cmd *cobra.Command, _ []string) (err error) {
 		if strings.Contains(fmt.Sprintf("%s", err), "STS") {
 			r.OCMClient.LogEvent("ROSAInitCredentialsSTS", nil)
 		}
-		r.Reporter.Errorf("Error creating AWS client: %v", err)
-		return err
+		return fmt.Errorf("Error creating AWS client: %w", err)
+	}
+
+	if args.forSpec {
+		return verifyQuotaForSpec(cmd, r)
 	}
 
 	if r.Reporter.IsTerminal() {
@@ -77,9 +155,7 @@ func run(cmd *cobra.Command, _ []string) (err error) {
 	_, err = r.AWSClient.ValidateQuota()
 	if err != nil {
 		r.OCMClient.LogEvent("ROSAVerifyQuotaInsufficient", nil)
-		r.Reporter.Errorf("Insufficient AWS quotas")
-		r.Reporter.Errorf("%v", err)
-		return err
+		return fmt.Errorf("Insufficient AWS quotas: %w", err)
 	}
 	if r.Reporter.IsTerminal() {
 		r.Reporter.Infof("AWS quota ok. " +
@@ -88,3 +164,56 @@ func run(cmd *cobra.Command, _ []string) (err error) {
 	}
 	return nil
 }
+
+func verifyQuotaForSpec(cmd *cobra.Command, r *rosa.Runtime) error {
+	computeNodes := args.computeNodes
+	if args.multiAZ && !cmd.Flags().Changed("replicas") {
+		computeNodes = 3
+	}
+	rootDiskSize, err := ocm.ParseDiskSizeToGigibyte(args.workerDiskSize)
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("Expected a valid worker disk size value: %v", err)
+	}
+	r.Creator, err = r.AWSClient.GetCreator()
+	if err != nil {
+		return fmt.Errorf("Failed to get AWS creator: %w", err)
+	}
+
+	if r.Reporter.IsTerminal() && !output.HasFlag() {
+		r.Reporter.Infof("Validating AWS quota for the cluster...")
+	}
+	checks, err := workflows.VerifyQuota(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, workflows.QuotaOptions{
+		Cluster:             true,
+		Hypershift:          args.hostedCP,
+		MultiAZ:             args.multiAZ,
+		Private:             args.private,
+		ExistingVPC:         len(args.subnetIDs) > 0,
+		ComputeMachineType:  args.computeMachineType,
+		ComputeNodes:        computeNodes,
+		ComputeRootDiskSize: rootDiskSize,
+	})
+	if err != nil {
+		return err
+	}
+	err = quotas.PrintChecks(checks)
+	if err != nil {
+		return err
+	}
+
+	insufficient := quotas.Insufficient(checks)
+	if len(insufficient) > 0 {
+		r.OCMClient.LogEvent("ROSAVerifyQuotaInsufficient", nil)
+		return exitcode.InsufficientQuota.Errorf(
+			"Service quota is insufficient for the following service quota codes:\n%s",
+			quotas.Describe(insufficient))
+	}
+	if r.Reporter.IsTerminal() && !output.HasFlag() {
+		r.Reporter.Infof("AWS quota ok for the cluster. The usage of the load balancers isn't measured, " +
+			"validate it against the limits if cluster installation fails.")
+	}
+	return nil
+}
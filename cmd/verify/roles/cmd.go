@@ -0,0 +1,192 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var args struct {
+	prefix              string
+	hostedCP            bool
+	oidcConfigID        string
+	permissionsBoundary string
+	version             string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "roles",
+	Aliases: []string{"role"},
+	Short:   "Verify IAM roles match what OCM expects",
+	Long: "Verify that the account, operator, OCM and user roles haven't drifted from what OCM expects: " +
+		"trust policies, attached policies and their content, version tags, paths and permissions boundaries.",
+	Example: `  # Verify the roles of a cluster
+  rosa verify roles --cluster mycluster
+
+  # Verify the account and operator roles created with a prefix
+  rosa verify roles --prefix ManagedOpenShift --oidc-config-id 23soa2bgvpek9kmes9s7os7a5f2ml7mm
+
+  # Verify that the roles use a permissions boundary
+  rosa verify roles --cluster mycluster --permissions-boundary arn:aws:iam::123456789012:policy/boundary`,
+	RunE: run,
+}
+
+const (
+	clusterFlag             = "cluster"
+	prefixFlag              = "prefix"
+	permissionsBoundaryFlag = "permissions-boundary"
+)
+
+func init() {
+	flags := Cmd.Flags()
+
+	ocm.AddOptionalClusterFlag(Cmd)
+
+	flags.StringVarP(
+		&args.prefix,
+		prefixFlag,
+		"p",
+		"",
+		"Prefix of the account and operator roles to verify, when there is no cluster.",
+	)
+
+	flags.BoolVar(
+		&args.hostedCP,
+		"hosted-cp",
+		false,
+		"Verify the hosted control plane account roles of the prefix.",
+	)
+
+	flags.StringVar(
+		&args.oidcConfigID,
+		"oidc-config-id",
+		"",
+		"ID of the OIDC configuration that the operator roles of the prefix must trust.",
+	)
+
+	flags.StringVar(
+		&args.permissionsBoundary,
+		permissionsBoundaryFlag,
+		"",
+		"The ARN of the policy that the roles must use as permissions boundary. "+
+			"Permissions boundaries are only verified when this flag is used.",
+	)
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"OpenShift version, for example \"4.13\", that the unmanaged policies must support. "+
+			"Defaults to the version of the cluster.",
+	)
+
+	output.AddFlag(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	if cmd.Flags().Changed(clusterFlag) == cmd.Flags().Changed(prefixFlag) {
+		return exitcode.InvalidInput.Errorf("Either a cluster or a prefix must be specified")
+	}
+	if args.permissionsBoundary != "" {
+		err := aws.ARNValidator(args.permissionsBoundary)
+		if err != nil {
+			return exitcode.InvalidInput.Errorf("Expected a valid policy ARN for permissions boundary: %s", err)
+		}
+	}
+	env, err := ocm.GetEnv()
+	if err != nil {
+		return fmt.Errorf("Failed to determine OCM environment: %v", err)
+	}
+
+	options := workflows.VerifyRolesOptions{
+		Prefix:                   args.prefix,
+		HostedCP:                 args.hostedCP,
+		OidcConfigID:             args.oidcConfigID,
+		Env:                      env,
+		PermissionsBoundary:      args.permissionsBoundary,
+		CheckPermissionsBoundary: cmd.Flags().Changed(permissionsBoundaryFlag),
+		Version:                  args.version,
+	}
+	if cmd.Flags().Changed(clusterFlag) {
+		options.Cluster = r.FetchCluster()
+	}
+
+	if r.Reporter.IsTerminal() && !output.HasFlag() {
+		r.Reporter.Infof("Verifying roles...")
+	}
+	drifts, err := workflows.VerifyRoles(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, options)
+	if err != nil {
+		return err
+	}
+
+	if output.HasFlag() {
+		err = output.Print(drifts)
+		if err != nil {
+			return err
+		}
+	} else {
+		printDrifts(r, drifts)
+	}
+
+	count := 0
+	for _, drift := range drifts {
+		if drift.HasDrift() {
+			count++
+		}
+	}
+	if count > 0 {
+		return exitcode.VerificationFailed.Errorf("%d of %d roles don't match what OCM expects",
+			count, len(drifts))
+	}
+	if !output.HasFlag() {
+		r.Reporter.Infof("All %d roles match what OCM expects", len(drifts))
+	}
+	return nil
+}
+
+func printDrifts(r *rosa.Runtime, drifts []*aws.RoleDrift) {
+	for _, drift := range drifts {
+		if !drift.HasDrift() {
+			r.Reporter.Infof("Role '%s' (%s) is ok", drift.RoleName, drift.RoleType)
+			continue
+		}
+		r.Reporter.Infof("Role '%s' (%s) has drifted:", drift.RoleName, drift.RoleType)
+		for _, issue := range drift.Issues {
+			fmt.Printf("  - [%s] %s\n", issue.Check, issue.Message)
+			for _, command := range issue.Remediation {
+				fmt.Printf("      %s\n", command)
+			}
+		}
+	}
+}
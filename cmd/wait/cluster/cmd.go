@@ -0,0 +1,255 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+var args struct {
+	condition string
+	timeout   time.Duration
+	interval  time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Wait for a cluster to reach a condition",
+	Long: fmt.Sprintf("Wait for a cluster to reach a condition. The supported conditions are:\n\n"+
+		"  state=STATE        The cluster is in the given state, or '%s' once it has been deleted.\n"+
+		"  machinepool=ID     The machine pool has the requested replicas. Hosted control plane only.\n"+
+		"  upgrade=STATE      The scheduled upgrade is in the given state, usually 'completed'.\n"+
+		"  addon=ID           The add-on installation is ready.\n\n"+
+		"The command exits with %d when the timeout expires and with %d when the condition can no "+
+		"longer be met, for example because the cluster is in error state.",
+		StateUninstalled, exitcode.Timeout, exitcode.ConditionFailed),
+	Example: `  # Wait up to 90 minutes for the cluster named "mycluster" to be ready
+  rosa wait cluster -c mycluster --for state=ready --timeout 90m
+
+  # Wait for the cluster to be deleted
+  rosa wait cluster -c mycluster --for state=uninstalled
+
+  # Wait for the scheduled upgrade to complete
+  rosa wait cluster -c mycluster --for upgrade=completed --timeout 3h
+
+  # Wait for an add-on to be installed
+  rosa wait cluster -c mycluster --for addon=cluster-logging-operator`,
+	RunE: run,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	ocm.AddClusterFlag(Cmd)
+
+	flags.StringVar(
+		&args.condition,
+		"for",
+		"",
+		"Condition to wait for, for example 'state=ready'.",
+	)
+	Cmd.MarkFlagRequired("for")
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		time.Hour,
+		"Maximum time to wait for the condition.",
+	)
+
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time between checks of the condition.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	r := rosa.NewRuntime().WithAWS().WithOCM()
+	defer r.Cleanup()
+
+	condition, err := ParseCondition(args.condition)
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+	if args.timeout <= 0 || args.interval <= 0 {
+		return exitcode.InvalidInput.Errorf("Timeout and interval must be greater than zero")
+	}
+
+	clusterKey := r.GetClusterKey()
+	cluster := r.FetchCluster()
+
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+
+	r.Reporter.Infof("Waiting up to %s for cluster '%s' to reach condition '%s'", args.timeout, clusterKey, condition)
+	var outcome Outcome
+	var message string
+	switch condition.Name {
+	case ConditionState:
+		outcome, message, err = waitForState(ctx, r, clusterKey, cluster, condition.Value)
+	case ConditionMachinePool:
+		outcome, message, err = waitForMachinePool(ctx, r, cluster, condition.Value)
+	case ConditionUpgrade:
+		outcome, message, err = waitForUpgrade(ctx, r, cluster, condition.Value)
+	case ConditionAddOn:
+		outcome, message, err = waitForAddOn(ctx, r, cluster, condition.Value)
+	}
+	if err != nil && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("Failed to wait for cluster '%s': %w", clusterKey, err)
+	}
+
+	switch outcome {
+	case OutcomeMet:
+		r.Reporter.Infof("%s", message)
+		return nil
+	case OutcomeFailed:
+		return exitcode.ConditionFailed.Errorf("%s", message)
+	default:
+		if message != "" {
+			message = fmt.Sprintf(": %s", message)
+		}
+		return exitcode.Timeout.Errorf("Timed out after %s waiting for cluster '%s' to reach condition '%s'%s",
+			args.timeout, clusterKey, condition, message)
+	}
+}
+
+// watcher records the outcome of evaluating a condition and reports the state of the resource
+// whenever it changes.
+type watcher struct {
+	r       *rosa.Runtime
+	outcome Outcome
+	message string
+}
+
+// update returns true once the outcome is known, so that the polling stops.
+func (w *watcher) update(outcome Outcome, message string) bool {
+	if outcome == OutcomePending && message != w.message {
+		w.r.Reporter.Infof("%s", message)
+	}
+	w.outcome = outcome
+	w.message = message
+	return outcome != OutcomePending
+}
+
+func waitForState(ctx context.Context, r *rosa.Runtime, clusterKey string, cluster *cmv1.Cluster,
+	target string) (Outcome, string, error) {
+	w := &watcher{r: r}
+	_, err := r.OCMClient.PollCluster(ctx, cluster.ID(), args.interval, func(current *cmv1.Cluster) bool {
+		return w.update(EvaluateClusterState(clusterKey, current, target))
+	})
+	return w.outcome, w.message, err
+}
+
+func waitForMachinePool(ctx context.Context, r *rosa.Runtime, cluster *cmv1.Cluster,
+	nodePoolID string) (Outcome, string, error) {
+	// OCM only reports the current replicas of the node pools of hosted control plane clusters
+	if !ocm.IsHyperShiftCluster(cluster) {
+		return OutcomePending, "", fmt.Errorf("waiting for machine pool replicas is only supported for " +
+			"hosted control plane clusters")
+	}
+	w := &watcher{r: r}
+	_, err := r.OCMClient.PollNodePool(ctx, cluster.ID(), nodePoolID, args.interval,
+		func(current *cmv1.NodePool) bool {
+			return w.update(EvaluateNodePool(nodePoolID, current))
+		})
+	return w.outcome, w.message, err
+}
+
+func waitForUpgrade(ctx context.Context, r *rosa.Runtime, cluster *cmv1.Cluster,
+	target string) (Outcome, string, error) {
+	w := &watcher{r: r}
+	var version string
+	var removed bool
+	if ocm.IsHyperShiftCluster(cluster) {
+		policy, err := r.OCMClient.GetControlPlaneScheduledUpgrade(cluster.ID())
+		if err != nil || policy == nil {
+			return OutcomePending, "", scheduledUpgradeError(err)
+		}
+		version = policy.Version()
+		last, err := r.OCMClient.PollControlPlaneUpgradePolicy(ctx, cluster.ID(), policy.ID(), args.interval,
+			func(current *cmv1.ControlPlaneUpgradePolicy) bool {
+				var state *cmv1.UpgradePolicyState
+				if current != nil {
+					state = current.State()
+				}
+				return w.update(EvaluateUpgradeState(state, target))
+			})
+		if err != nil {
+			return w.outcome, w.message, err
+		}
+		removed = last == nil
+	} else {
+		policy, _, err := r.OCMClient.GetScheduledUpgrade(cluster.ID())
+		if err != nil || policy == nil {
+			return OutcomePending, "", scheduledUpgradeError(err)
+		}
+		version = policy.Version()
+		last, err := r.OCMClient.PollUpgradePolicyState(ctx, cluster.ID(), policy.ID(), args.interval,
+			func(current *cmv1.UpgradePolicyState) bool {
+				return w.update(EvaluateUpgradeState(current, target))
+			})
+		if err != nil {
+			return w.outcome, w.message, err
+		}
+		removed = last == nil
+	}
+	if !removed || target != string(cmv1.UpgradePolicyStateValueCompleted) {
+		return w.outcome, w.message, nil
+	}
+
+	// OCM may delete the upgrade policy once the upgrade is done, in that case the version of the
+	// cluster tells if it has completed
+	upgraded, err := r.OCMClient.GetCluster(cluster.ID(), r.Creator)
+	if err != nil {
+		return w.outcome, w.message, err
+	}
+	if upgraded.Version().RawID() == version {
+		return OutcomeMet, fmt.Sprintf("Cluster has been upgraded to version '%s'", version), nil
+	}
+	return w.outcome, w.message, nil
+}
+
+func scheduledUpgradeError(err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled upgrade: %v", err)
+	}
+	return fmt.Errorf("there is no scheduled upgrade")
+}
+
+func waitForAddOn(ctx context.Context, r *rosa.Runtime, cluster *cmv1.Cluster,
+	addOnID string) (Outcome, string, error) {
+	w := &watcher{r: r}
+	_, err := r.OCMClient.PollAddOnInstallation(ctx, cluster.ID(), addOnID, args.interval,
+		func(current *cmv1.AddOnInstallation) bool {
+			return w.update(EvaluateAddOnInstallation(addOnID, current))
+		})
+	return w.outcome, w.message, err
+}
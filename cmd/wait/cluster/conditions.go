@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Outcome is the result of evaluating a condition against the current version of a resource.
+type Outcome int
+
+const (
+	// OutcomePending means that the condition may still be met.
+	OutcomePending Outcome = iota
+	// OutcomeMet means that the condition is met.
+	OutcomeMet
+	// OutcomeFailed means that the resource reached a state from which the condition can't be met.
+	OutcomeFailed
+)
+
+// Names of the conditions accepted by the --for flag.
+const (
+	ConditionState       = "state"
+	ConditionMachinePool = "machinepool"
+	ConditionUpgrade     = "upgrade"
+	ConditionAddOn       = "addon"
+)
+
+// StateUninstalled is the state used to wait for a cluster to be deleted. OCM has no such state,
+// the cluster just stops existing.
+const StateUninstalled = "uninstalled"
+
+var clusterStates = []string{
+	string(cmv1.ClusterStateError),
+	string(cmv1.ClusterStateHibernating),
+	string(cmv1.ClusterStateInstalling),
+	string(cmv1.ClusterStatePending),
+	string(cmv1.ClusterStatePoweringDown),
+	string(cmv1.ClusterStateReady),
+	string(cmv1.ClusterStateResuming),
+	string(cmv1.ClusterStateUninstalling),
+	string(cmv1.ClusterStateValidating),
+	string(cmv1.ClusterStateWaiting),
+	StateUninstalled,
+}
+
+var upgradeStates = []string{
+	string(cmv1.UpgradePolicyStateValuePending),
+	string(cmv1.UpgradePolicyStateValueScheduled),
+	string(cmv1.UpgradePolicyStateValueStarted),
+	string(cmv1.UpgradePolicyStateValueDelayed),
+	string(cmv1.UpgradePolicyStateValueCompleted),
+	string(cmv1.UpgradePolicyStateValueFailed),
+	string(cmv1.UpgradePolicyStateValueCancelled),
+}
+
+// Condition is the parsed value of the --for flag.
+type Condition struct {
+	Name  string
+	Value string
+}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("%s=%s", c.Name, c.Value)
+}
+
+// ParseCondition parses a condition like 'state=ready' or 'addon=my-addon'.
+func ParseCondition(value string) (Condition, error) {
+	name, target, found := strings.Cut(value, "=")
+	if !found || target == "" {
+		return Condition{}, fmt.Errorf("Condition '%s' isn't valid, it must be in the form 'name=value'", value)
+	}
+	condition := Condition{Name: strings.ToLower(name), Value: target}
+	switch condition.Name {
+	case ConditionState:
+		condition.Value = strings.ToLower(target)
+		if !contains(clusterStates, condition.Value) {
+			return Condition{}, fmt.Errorf("Cluster state '%s' isn't valid, expected one of: %s",
+				target, strings.Join(clusterStates, ", "))
+		}
+	case ConditionUpgrade:
+		condition.Value = strings.ToLower(target)
+		if !contains(upgradeStates, condition.Value) {
+			return Condition{}, fmt.Errorf("Upgrade state '%s' isn't valid, expected one of: %s",
+				target, strings.Join(upgradeStates, ", "))
+		}
+	case ConditionMachinePool, ConditionAddOn:
+	default:
+		return Condition{}, fmt.Errorf("Condition '%s' isn't supported, expected one of: %s", name,
+			strings.Join([]string{ConditionState, ConditionMachinePool, ConditionUpgrade, ConditionAddOn}, ", "))
+	}
+	return condition, nil
+}
+
+// EvaluateClusterState checks if the cluster is in the given state. A nil cluster has been
+// uninstalled. Errors and uninstallation are terminal unless they are what is waited for.
+func EvaluateClusterState(clusterKey string, cluster *cmv1.Cluster, target string) (Outcome, string) {
+	if cluster == nil {
+		message := fmt.Sprintf("Cluster '%s' has been uninstalled", clusterKey)
+		if target == StateUninstalled {
+			return OutcomeMet, message
+		}
+		return OutcomeFailed, message
+	}
+	state := string(cluster.State())
+	message := fmt.Sprintf("Cluster '%s' is in state '%s'", clusterKey, state)
+	switch {
+	case state == target:
+		return OutcomeMet, message
+	case state == string(cmv1.ClusterStateError):
+		if reason := cluster.Status().ProvisionErrorMessage(); reason != "" {
+			message = fmt.Sprintf("%s: %s", message, reason)
+		}
+		return OutcomeFailed, message
+	case state == string(cmv1.ClusterStateUninstalling) && target != StateUninstalled:
+		return OutcomeFailed, message
+	}
+	return OutcomePending, message
+}
+
+// EvaluateNodePool checks if the node pool has the replicas it should have, which for an
+// autoscaling node pool means any number between the minimum and the maximum.
+func EvaluateNodePool(nodePoolID string, nodePool *cmv1.NodePool) (Outcome, string) {
+	if nodePool == nil {
+		return OutcomeFailed, fmt.Sprintf("Machine pool '%s' doesn't exist", nodePoolID)
+	}
+	current := nodePool.Status().CurrentReplicas()
+	if autoscaling, ok := nodePool.GetAutoscaling(); ok {
+		message := fmt.Sprintf("Machine pool '%s' has %d replicas, expected between %d and %d",
+			nodePoolID, current, autoscaling.MinReplica(), autoscaling.MaxReplica())
+		if current >= autoscaling.MinReplica() && current <= autoscaling.MaxReplica() {
+			return OutcomeMet, message
+		}
+		return OutcomePending, message
+	}
+	message := fmt.Sprintf("Machine pool '%s' has %d of %d replicas", nodePoolID, current, nodePool.Replicas())
+	if current == nodePool.Replicas() {
+		return OutcomeMet, message
+	}
+	return OutcomePending, message
+}
+
+// EvaluateUpgradeState checks if an upgrade policy is in the given state. Once the upgrade has
+// completed, failed or been cancelled its state doesn't change anymore.
+func EvaluateUpgradeState(state *cmv1.UpgradePolicyState, target string) (Outcome, string) {
+	if state == nil {
+		return OutcomeFailed, "The upgrade policy no longer exists"
+	}
+	value := state.Value()
+	message := fmt.Sprintf("Upgrade is in state '%s'", value)
+	if description := state.Description(); description != "" {
+		message = fmt.Sprintf("%s: %s", message, description)
+	}
+	if string(value) == target {
+		return OutcomeMet, message
+	}
+	switch value {
+	case cmv1.UpgradePolicyStateValueCompleted,
+		cmv1.UpgradePolicyStateValueFailed,
+		cmv1.UpgradePolicyStateValueCancelled:
+		return OutcomeFailed, message
+	}
+	return OutcomePending, message
+}
+
+// EvaluateAddOnInstallation checks if the add-on has been installed and is ready.
+func EvaluateAddOnInstallation(addOnID string, installation *cmv1.AddOnInstallation) (Outcome, string) {
+	if installation == nil {
+		return OutcomeFailed, fmt.Sprintf("Add-on '%s' isn't installed", addOnID)
+	}
+	state := installation.State()
+	message := fmt.Sprintf("Add-on '%s' is in state '%s'", addOnID, state)
+	if description := installation.StateDescription(); description != "" {
+		message = fmt.Sprintf("%s: %s", message, description)
+	}
+	switch state {
+	case cmv1.AddOnInstallationStateReady:
+		return OutcomeMet, message
+	case cmv1.AddOnInstallationStateFailed, cmv1.AddOnInstallationStateDeleting:
+		return OutcomeFailed, message
+	}
+	return OutcomePending, message
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
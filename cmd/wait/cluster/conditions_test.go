@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Conditions", func() {
+	Context("ParseCondition", func() {
+		It("Parses a cluster state", func() {
+			condition, err := ParseCondition("state=Ready")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal(Condition{Name: ConditionState, Value: "ready"}))
+		})
+		It("Rejects unknown states", func() {
+			_, err := ParseCondition("state=running")
+			Expect(err).To(MatchError(ContainSubstring("Cluster state 'running' isn't valid")))
+		})
+		It("Rejects conditions without a value", func() {
+			_, err := ParseCondition("addon")
+			Expect(err).To(MatchError("Condition 'addon' isn't valid, it must be in the form 'name=value'"))
+		})
+		It("Rejects unknown conditions", func() {
+			_, err := ParseCondition("ingress=default")
+			Expect(err).To(MatchError(ContainSubstring("Condition 'ingress' isn't supported")))
+		})
+	})
+
+	DescribeTable("EvaluateClusterState",
+		func(state cmv1.ClusterState, target string, expected Outcome) {
+			var cluster *cmv1.Cluster
+			if state != "" {
+				var err error
+				cluster, err = cmv1.NewCluster().State(state).Build()
+				Expect(err).ToNot(HaveOccurred())
+			}
+			outcome, _ := EvaluateClusterState("mycluster", cluster, target)
+			Expect(outcome).To(Equal(expected))
+		},
+		Entry("ready", cmv1.ClusterStateReady, "ready", OutcomeMet),
+		Entry("installing", cmv1.ClusterStateInstalling, "ready", OutcomePending),
+		Entry("error", cmv1.ClusterStateError, "ready", OutcomeFailed),
+		Entry("waiting for error", cmv1.ClusterStateError, "error", OutcomeMet),
+		Entry("uninstalling while waiting for ready", cmv1.ClusterStateUninstalling, "ready", OutcomeFailed),
+		Entry("uninstalling", cmv1.ClusterStateUninstalling, StateUninstalled, OutcomePending),
+		Entry("uninstalled", cmv1.ClusterState(""), StateUninstalled, OutcomeMet),
+		Entry("deleted while waiting for hibernating", cmv1.ClusterState(""), "hibernating", OutcomeFailed),
+	)
+
+	It("Evaluates the replicas of node pools", func() {
+		nodePool, err := cmv1.NewNodePool().Replicas(3).
+			Status(cmv1.NewNodePoolStatus().CurrentReplicas(2)).Build()
+		Expect(err).ToNot(HaveOccurred())
+		outcome, message := EvaluateNodePool("workers", nodePool)
+		Expect(outcome).To(Equal(OutcomePending))
+		Expect(message).To(Equal("Machine pool 'workers' has 2 of 3 replicas"))
+
+		nodePool, err = cmv1.NewNodePool().
+			Autoscaling(cmv1.NewNodePoolAutoscaling().MinReplica(2).MaxReplica(4)).
+			Status(cmv1.NewNodePoolStatus().CurrentReplicas(2)).Build()
+		Expect(err).ToNot(HaveOccurred())
+		outcome, _ = EvaluateNodePool("workers", nodePool)
+		Expect(outcome).To(Equal(OutcomeMet))
+
+		outcome, _ = EvaluateNodePool("workers", nil)
+		Expect(outcome).To(Equal(OutcomeFailed))
+	})
+
+	DescribeTable("EvaluateUpgradeState",
+		func(value cmv1.UpgradePolicyStateValue, target string, expected Outcome) {
+			state, err := cmv1.NewUpgradePolicyState().Value(value).Build()
+			Expect(err).ToNot(HaveOccurred())
+			outcome, _ := EvaluateUpgradeState(state, target)
+			Expect(outcome).To(Equal(expected))
+		},
+		Entry("completed", cmv1.UpgradePolicyStateValueCompleted, "completed", OutcomeMet),
+		Entry("started", cmv1.UpgradePolicyStateValueStarted, "completed", OutcomePending),
+		Entry("failed", cmv1.UpgradePolicyStateValueFailed, "completed", OutcomeFailed),
+		Entry("completed while waiting for started", cmv1.UpgradePolicyStateValueCompleted, "started", OutcomeFailed),
+	)
+
+	It("Evaluates add-on installations", func() {
+		installation, err := cmv1.NewAddOnInstallation().State(cmv1.AddOnInstallationStateFailed).
+			StateDescription("missing parameter").Build()
+		Expect(err).ToNot(HaveOccurred())
+		outcome, message := EvaluateAddOnInstallation("my-addon", installation)
+		Expect(outcome).To(Equal(OutcomeFailed))
+		Expect(message).To(Equal("Add-on 'my-addon' is in state 'failed': missing parameter"))
+
+		outcome, _ = EvaluateAddOnInstallation("my-addon", nil)
+		Expect(outcome).To(Equal(OutcomeFailed))
+	})
+})
@@ -17,9 +17,9 @@ limitations under the License.
 package aws
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -49,6 +49,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/fedramp"
 	"github.com/openshift/rosa/pkg/reporter"
 	"github.com/sirupsen/logrus"
@@ -92,6 +93,9 @@ type Client interface {
 	ValidateCredentials() (isValid bool, err error)
 	EnsureOsdCcsAdminUser(stackName string, adminUserName string, awsRegion string) (bool, error)
 	DeleteOsdCcsAdminUser(stackName string) error
+	DeleteStack(stackName string) error
+	CreateNetworkStack(options NetworkStackOptions) (*NetworkStack, error)
+	GetNetworkStack(name string) (*NetworkStack, error)
 	GetAWSAccessKeys() (*AccessKey, error)
 	GetLocalAWSAccessKeys() (*AccessKey, error)
 	GetCreator() (*Creator, error)
@@ -102,6 +106,9 @@ type Client interface {
 	GetVPCPrivateSubnets(subnetID string) ([]*ec2.Subnet, error)
 	FilterVPCsPrivateSubnets(subnets []*ec2.Subnet) ([]*ec2.Subnet, error)
 	ValidateQuota() (bool, error)
+	ValidateQuotaForSpec(spec QuotaSpec) ([]*QuotaCheck, error)
+	ValidateNetwork(options NetworkPreflightOptions) ([]*NetworkFinding, error)
+	GetVPCCIDR(vpcID string, subnetIDs []string) (*net.IPNet, error)
 	TagUserRegion(username string, region string) error
 	GetClusterRegionTagForUser(username string) (string, error)
 	EnsureRole(name string, policy string, permissionsBoundary string,
@@ -141,6 +148,7 @@ type Client interface {
 	HasPermissionsBoundary(roleName string) (bool, error)
 	GetOpenIDConnectProviderByClusterIdTag(clusterID string) (string, error)
 	GetOpenIDConnectProviderByOidcEndpointUrl(oidcEndpointUrl string) (string, error)
+	GetOpenIDConnectProvider(oidcProviderARN string) (*OidcProvider, error)
 	GetInstanceProfilesForRole(role string) ([]string, error)
 	IsUpgradedNeededForAccountRolePolicies(rolePrefix string, version string) (bool, error)
 	IsUpgradedNeededForAccountRolePoliciesUsingCluster(clusterID *cmv1.Cluster, version string) (bool, error)
@@ -181,10 +189,16 @@ type Client interface {
 	CreateS3Bucket(bucketName string, region string) error
 	DeleteS3Bucket(bucketName string) error
 	PutPublicReadObjectInS3Bucket(bucketName string, body io.ReadSeeker, key string) error
+	GetObjectFromS3Bucket(bucketName string, key string) ([]byte, error)
 	CreateSecretInSecretsManager(name string, secret string) (string, error)
+	GetSecretFromSecretsManager(secretArn string) (string, error)
+	PutSecretValueInSecretsManager(secretArn string, secret string) error
 	DeleteSecretInSecretsManager(secretArn string) error
 	ValidateAccountRoleVersionCompatibility(
 		roleName string, roleType string, minVersion string) (bool, error)
+	GetDefaultPolicyDocument(policyArn string) (string, error)
+	VerifyRole(expected ExpectedRole) (*RoleDrift, error)
+	DiffPolicy(policyARN string, target string) (*PolicyDiff, error)
 }
 
 // ClientBuilder contains the information and logic needed to build a new AWS client.
@@ -216,7 +230,7 @@ func CreateNewClientOrExit(logger *logrus.Logger, reporter *reporter.Object) Cli
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 
 	return awsClient
@@ -360,13 +374,14 @@ func (b *ClientBuilder) Build() (Client, error) {
 	_, err = sess.Config.Credentials.Get()
 	if err != nil {
 		b.logger.Debugf("Failed to find credentials: %v", err)
-		return nil, fmt.Errorf("Failed to find credentials. Check your AWS configuration and try again")
+		return nil, exitcode.AWSCredentials.Errorf(
+			"Failed to find credentials. Check your AWS configuration and try again")
 	}
 
 	// Check that the region is set:
 	region := aws.StringValue(sess.Config.Region)
 	if region == "" {
-		return nil, fmt.Errorf("Region is not set. Use --region to set the region")
+		return nil, exitcode.InvalidInput.Errorf("Region is not set. Use --region to set the region")
 	}
 
 	// Update session config
@@ -411,7 +426,8 @@ func (b *ClientBuilder) Build() (Client, error) {
 	}
 
 	if root {
-		return nil, errors.New("using a root account is not supported, please use an IAM user instead")
+		return nil, exitcode.AWSCredentials.Errorf(
+			"using a root account is not supported, please use an IAM user instead")
 	}
 
 	return c, err
@@ -1096,6 +1112,18 @@ func (c *awsClient) PutPublicReadObjectInS3Bucket(bucketName string, body io.Rea
 	return nil
 }
 
+func (c *awsClient) GetObjectFromS3Bucket(bucketName string, key string) ([]byte, error) {
+	getObjectOutput, err := c.s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer getObjectOutput.Body.Close()
+	return io.ReadAll(getObjectOutput.Body)
+}
+
 func (c *awsClient) CreateSecretInSecretsManager(name string, secret string) (string, error) {
 	createSecretResponse, err := c.smClient.CreateSecret(
 		&secretsmanager.CreateSecretInput{
@@ -1113,6 +1141,26 @@ func (c *awsClient) CreateSecretInSecretsManager(name string, secret string) (st
 	return *createSecretResponse.ARN, nil
 }
 
+func (c *awsClient) GetSecretFromSecretsManager(secretArn string) (string, error) {
+	getSecretValueOutput, err := c.smClient.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretArn),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(getSecretValueOutput.SecretString), nil
+}
+
+// PutSecretValueInSecretsManager stores a new value in the secret. The previous value is kept by
+// Secrets Manager as the 'AWSPREVIOUS' version of the secret.
+func (c *awsClient) PutSecretValueInSecretsManager(secretArn string, secret string) error {
+	_, err := c.smClient.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretArn),
+		SecretString: aws.String(secret),
+	})
+	return err
+}
+
 func (c *awsClient) DeleteSecretInSecretsManager(secretArn string) error {
 	_, err := c.smClient.DescribeSecret(&secretsmanager.DescribeSecretInput{
 		SecretId: aws.String(secretArn),
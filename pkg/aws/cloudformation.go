@@ -113,15 +113,19 @@ func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string
 }
 
 func (c *awsClient) CreateStack(cfTemplateBody, stackName string) (bool, error) {
+	return c.createStack(buildCreateStackInput(cfTemplateBody, stackName))
+}
+
+func (c *awsClient) createStack(input *cloudformation.CreateStackInput) (bool, error) {
 	// Create cloudformation stack
-	_, err := c.cfClient.CreateStack(buildCreateStackInput(cfTemplateBody, stackName))
+	_, err := c.cfClient.CreateStack(input)
 	if err != nil {
 		return false, err
 	}
 
 	// Wait until cloudformation stack creates
 	err = c.cfClient.WaitUntilStackCreateComplete(&cloudformation.DescribeStacksInput{
-		StackName: aws.String(stackName),
+		StackName: input.StackName,
 	})
 	if err != nil {
 		switch typed := err.(type) {
@@ -198,6 +202,11 @@ func (c *awsClient) CheckStackReadyOrNotExisting(stackName string) (stackReady b
 }
 
 func (c *awsClient) DeleteOsdCcsAdminUser(stackName string) error {
+	return c.DeleteStack(stackName)
+}
+
+// DeleteStack deletes a cloudformation stack and waits until all its resources are deleted.
+func (c *awsClient) DeleteStack(stackName string) error {
 	deleteStackInput := &cloudformation.DeleteStackInput{
 		StackName: aws.String(stackName),
 	}
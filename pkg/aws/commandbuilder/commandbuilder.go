@@ -15,6 +15,7 @@ const (
 	S3Api Service = "s3api"
 	S3    Service = "s3"
 	SM    Service = "secretsmanager"
+	CF    Service = "cloudformation"
 )
 
 type Command string
@@ -34,6 +35,11 @@ const (
 	CreateOpenIdConnectProvider   Command = "create-open-id-connect-provider"
 	DeleteOpenIdConnectProvider   Command = "delete-open-id-connect-provider"
 	DeleteRolePermissionsBoundary Command = "delete-role-permissions-boundary"
+	PutRolePermissionsBoundary    Command = "put-role-permissions-boundary"
+	UpdateAssumeRolePolicy        Command = "update-assume-role-policy"
+	DeletePolicyVersion           Command = "delete-policy-version"
+	PutRolePolicy                 Command = "put-role-policy"
+	TagUser                       Command = "tag-user"
 	//S3Api
 	CreateBucket         Command = "create-bucket"
 	PutObject            Command = "put-object"
@@ -44,8 +50,12 @@ const (
 	Remove       Command = "rm"
 	RemoveBucket Command = "rb"
 	//SecretsManager
-	CreateSecret Command = "create-secret"
-	DeleteSecret Command = "delete-secret"
+	CreateSecret   Command = "create-secret"
+	DeleteSecret   Command = "delete-secret"
+	PutSecretValue Command = "put-secret-value"
+	//CloudFormation
+	CreateStack Command = "create-stack"
+	DeleteStack Command = "delete-stack"
 )
 
 type Param string
@@ -83,6 +93,11 @@ const (
 	Description  Param = "description"
 	SecretID     Param = "secret-id"
 	Recursive    Param = "recursive"
+
+	//CloudFormation
+	StackName    Param = "stack-name"
+	TemplateBody Param = "template-body"
+	Parameters   Param = "parameters"
 )
 
 type Redirect string
@@ -191,6 +206,10 @@ func NewSecretsManagerCommandBuilder() *CommandBuilder {
 	return &CommandBuilder{service: SM}
 }
 
+func NewCloudFormationCommandBuilder() *CommandBuilder {
+	return &CommandBuilder{service: CF}
+}
+
 func createParamString(awsParam Param, value string) string {
 	return fmt.Sprintf("\t--%s %s", awsParam, value)
 }
@@ -213,3 +232,14 @@ func createTags(m map[string]string) string {
 func JoinCommands(commands []string) string {
 	return strings.Join(commands, "\n\n")
 }
+
+// SplitCommands is the inverse of JoinCommands.
+func SplitCommands(commands string) []string {
+	result := []string{}
+	for _, command := range strings.Split(strings.TrimSpace(commands), "\n\n") {
+		if command != "" {
+			result = append(result, command)
+		}
+	}
+	return result
+}
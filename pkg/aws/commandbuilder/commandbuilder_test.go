@@ -91,5 +91,20 @@ var _ = Describe("Commandbuilder", func() {
 				).To(Equal(command))
 			})
 		})
+
+		var _ = Context("when joining commands", func() {
+			It("splits joined commands", func() {
+				commands := []string{
+					NewIAMCommandBuilder().
+						SetCommand(CreateRole).
+						AddParam(RoleName, "rosa-awscb-test-Installer-Role").
+						Build(),
+					"rosa link user-role",
+				}
+				Expect(SplitCommands(JoinCommands(commands))).To(Equal(commands))
+				Expect(SplitCommands(JoinCommands(commands) + "\n")).To(Equal(commands))
+				Expect(SplitCommands("")).To(BeEmpty())
+			})
+		})
 	})
 })
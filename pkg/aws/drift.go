@@ -0,0 +1,429 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that compare IAM roles with the roles expected by OCM.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+// Names of the checks that detect role drift.
+const (
+	DriftCheckExists              = "exists"
+	DriftCheckPath                = "path"
+	DriftCheckPermissionsBoundary = "permissions-boundary"
+	DriftCheckTrustPolicy         = "trust-policy"
+	DriftCheckPolicies            = "attached-policies"
+	DriftCheckPolicyDocument      = "policy-document"
+	DriftCheckVersion             = "version"
+)
+
+// ExpectedRole describes a role as OCM expects it to be.
+type ExpectedRole struct {
+	Name string
+	Type string
+	Path string
+
+	// TrustPolicy is the expected trust policy. All its principals must be trusted by the role.
+	TrustPolicy string
+
+	// PermissionsBoundary is only checked when CheckPermissionsBoundary is set, as OCM doesn't
+	// know the boundary that was used to create the role.
+	PermissionsBoundary      string
+	CheckPermissionsBoundary bool
+
+	// Policies are the policies that must be attached to the role. Policies that have a document
+	// are unmanaged, and their content and version tags are also checked.
+	Policies []ExpectedPolicy
+
+	// Version is the minimum OpenShift version that the unmanaged policies must support. It
+	// isn't checked when empty.
+	Version string
+
+	// CreateCommand and UpgradeCommand are the commands suggested to fix the drift. Running the
+	// create command again restores a role, its trust policy and permissions boundary, and the
+	// upgrade command restores the content and version of the policies.
+	CreateCommand  string
+	UpgradeCommand string
+}
+
+// ExpectedPolicy is a policy that must be attached to a role.
+type ExpectedPolicy struct {
+	ARN      string
+	Document string
+}
+
+// RoleDrift contains the differences found between a role and the expected role.
+type RoleDrift struct {
+	RoleName string        `json:"role_name"`
+	RoleType string        `json:"role_type"`
+	RoleARN  string        `json:"role_arn,omitempty"`
+	Issues   []*DriftIssue `json:"issues"`
+
+	// TrustPolicy is the current trust policy of the role, so that callers can run additional
+	// checks on it.
+	TrustPolicy string `json:"-"`
+}
+
+// DriftIssue is a difference between a role and the expected role, with the commands that fix it.
+type DriftIssue struct {
+	Check       string   `json:"check"`
+	Message     string   `json:"message"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// AddIssue adds an issue to the drift. Empty remediation commands are ignored.
+func (d *RoleDrift) AddIssue(check string, message string, remediation ...string) {
+	issue := &DriftIssue{
+		Check:   check,
+		Message: message,
+	}
+	for _, command := range remediation {
+		if command != "" {
+			issue.Remediation = append(issue.Remediation, command)
+		}
+	}
+	d.Issues = append(d.Issues, issue)
+}
+
+// HasDrift returns true if any of the checks found a difference.
+func (d *RoleDrift) HasDrift() bool {
+	return len(d.Issues) > 0
+}
+
+// VerifyRole compares the role with the expected role and returns the differences found. Errors
+// are only returned when the role can't be read.
+func (c *awsClient) VerifyRole(expected ExpectedRole) (*RoleDrift, error) {
+	drift := &RoleDrift{
+		RoleName: expected.Name,
+		RoleType: expected.Type,
+		Issues:   []*DriftIssue{},
+	}
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(expected.Name),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			drift.AddIssue(DriftCheckExists, fmt.Sprintf("Role '%s' doesn't exist", expected.Name),
+				expected.CreateCommand)
+			return drift, nil
+		}
+		return nil, err
+	}
+	role := output.Role
+	drift.RoleARN = aws.StringValue(role.Arn)
+
+	path, err := GetPathFromARN(drift.RoleARN)
+	if err != nil {
+		return nil, err
+	}
+	if !equalRolePaths(path, expected.Path) {
+		drift.AddIssue(DriftCheckPath,
+			fmt.Sprintf("Role has path '%s' but '%s' is expected. Roles can't be moved, delete and "+
+				"create the role again", path, expected.Path))
+	}
+
+	if expected.CheckPermissionsBoundary {
+		c.verifyPermissionsBoundary(drift, role, expected)
+	}
+
+	trustPolicy, err := url.QueryUnescape(aws.StringValue(role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, err
+	}
+	drift.TrustPolicy = trustPolicy
+	if expected.TrustPolicy != "" {
+		err = verifyTrustPolicy(drift, trustPolicy, expected)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.verifyPolicies(drift, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if expected.Version != "" && hasUnmanagedPolicies(expected) {
+		compatible, err := c.HasCompatibleVersionTags(role.Tags, expected.Version)
+		if err != nil {
+			return nil, err
+		}
+		if !compatible {
+			drift.AddIssue(DriftCheckVersion,
+				fmt.Sprintf("Role isn't tagged as compatible with version '%s'", expected.Version),
+				expected.UpgradeCommand)
+		}
+	}
+
+	return drift, nil
+}
+
+// equalRolePaths compares role paths, considering that an empty path is the root path.
+func equalRolePaths(a string, b string) bool {
+	if a == "" {
+		a = "/"
+	}
+	if b == "" {
+		b = "/"
+	}
+	return a == b
+}
+
+func (c *awsClient) verifyPermissionsBoundary(drift *RoleDrift, role *iam.Role, expected ExpectedRole) {
+	current := ""
+	if role.PermissionsBoundary != nil {
+		current = aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	if current == expected.PermissionsBoundary {
+		return
+	}
+	if expected.PermissionsBoundary == "" {
+		drift.AddIssue(DriftCheckPermissionsBoundary,
+			fmt.Sprintf("Role has unexpected permissions boundary '%s'", current),
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeleteRolePermissionsBoundary).
+				AddParam(awscb.RoleName, expected.Name).
+				Build())
+		return
+	}
+	message := fmt.Sprintf("Role doesn't have permissions boundary '%s'", expected.PermissionsBoundary)
+	if current != "" {
+		message = fmt.Sprintf("Role has permissions boundary '%s' but '%s' is expected",
+			current, expected.PermissionsBoundary)
+	}
+	drift.AddIssue(DriftCheckPermissionsBoundary, message,
+		awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.PutRolePermissionsBoundary).
+			AddParam(awscb.RoleName, expected.Name).
+			AddParam(awscb.PermissionsBoundary, expected.PermissionsBoundary).
+			Build())
+}
+
+// verifyTrustPolicy checks that the role trusts all the principals of the expected trust policy.
+func verifyTrustPolicy(drift *RoleDrift, trustPolicy string, expected ExpectedRole) error {
+	current, err := ParsePolicyDocument(trustPolicy)
+	if err != nil {
+		return fmt.Errorf("Failed to parse trust policy of role '%s': %v", expected.Name, err)
+	}
+	wanted, err := ParsePolicyDocument(expected.TrustPolicy)
+	if err != nil {
+		return fmt.Errorf("Failed to parse expected trust policy of role '%s': %v", expected.Name, err)
+	}
+	trusted := map[string]bool{}
+	for _, principal := range trustPolicyPrincipals(current) {
+		trusted[principal] = true
+	}
+	for _, principal := range trustPolicyPrincipals(wanted) {
+		if !trusted[principal] {
+			drift.AddIssue(DriftCheckTrustPolicy,
+				fmt.Sprintf("Trust policy doesn't allow principal '%s'", principal),
+				expected.CreateCommand)
+		}
+	}
+	return nil
+}
+
+func trustPolicyPrincipals(document *PolicyDocument) []string {
+	result := []string{}
+	for i := range document.Statement {
+		statement := &document.Statement[i]
+		if statement.Effect != "Allow" || statement.Principal == nil {
+			continue
+		}
+		result = append(result, statement.GetAWSPrincipals()...)
+		result = append(result, statement.Principal.Service...)
+		if statement.Principal.Federated != "" {
+			result = append(result, statement.Principal.Federated)
+		}
+	}
+	return result
+}
+
+func (c *awsClient) verifyPolicies(drift *RoleDrift, expected ExpectedRole) error {
+	attached, err := c.GetAttachedPolicy(aws.String(expected.Name))
+	if err != nil {
+		return err
+	}
+	attachedARNs := map[string]bool{}
+	for _, policy := range attached {
+		if policy.PolicyType == Attached {
+			attachedARNs[policy.PolicyArn] = true
+		}
+	}
+	expectedARNs := map[string]bool{}
+	for _, policy := range expected.Policies {
+		expectedARNs[policy.ARN] = true
+		if !attachedARNs[policy.ARN] {
+			drift.AddIssue(DriftCheckPolicies,
+				fmt.Sprintf("Policy '%s' isn't attached", policy.ARN),
+				awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.AttachRolePolicy).
+					AddParam(awscb.RoleName, expected.Name).
+					AddParam(awscb.PolicyArn, policy.ARN).
+					Build())
+			continue
+		}
+		if policy.Document == "" {
+			continue
+		}
+		err = c.verifyPolicy(drift, policy, expected)
+		if err != nil {
+			return err
+		}
+	}
+	for _, policy := range attached {
+		if policy.PolicyType != Attached || expectedARNs[policy.PolicyArn] {
+			continue
+		}
+		drift.AddIssue(DriftCheckPolicies,
+			fmt.Sprintf("Unexpected policy '%s' is attached", policy.PolicyArn),
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DetachRolePolicy).
+				AddParam(awscb.RoleName, expected.Name).
+				AddParam(awscb.PolicyArn, policy.PolicyArn).
+				Build())
+	}
+	return nil
+}
+
+// verifyPolicy compares the content and the version tags of an unmanaged policy.
+func (c *awsClient) verifyPolicy(drift *RoleDrift, policy ExpectedPolicy, expected ExpectedRole) error {
+	document, err := c.GetDefaultPolicyDocument(policy.ARN)
+	if err != nil {
+		return err
+	}
+	equal, err := EqualPolicyDocuments(document, policy.Document)
+	if err != nil {
+		return fmt.Errorf("Failed to compare policy '%s': %v", policy.ARN, err)
+	}
+	if !equal {
+		drift.AddIssue(DriftCheckPolicyDocument,
+			fmt.Sprintf("Policy '%s' doesn't match the policy expected by OCM", policy.ARN),
+			expected.UpgradeCommand)
+	}
+	if expected.Version == "" {
+		return nil
+	}
+	compatible, err := c.IsPolicyCompatible(policy.ARN, expected.Version)
+	if err != nil {
+		return err
+	}
+	if !compatible {
+		drift.AddIssue(DriftCheckVersion,
+			fmt.Sprintf("Policy '%s' isn't tagged as compatible with version '%s'", policy.ARN, expected.Version),
+			expected.UpgradeCommand)
+	}
+	return nil
+}
+
+func hasUnmanagedPolicies(expected ExpectedRole) bool {
+	for _, policy := range expected.Policies {
+		if policy.Document != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDefaultPolicyDocument returns the document of the default version of the policy.
+func (c *awsClient) GetDefaultPolicyDocument(policyArn string) (string, error) {
+	policy, err := c.iamClient.GetPolicy(&iam.GetPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return "", err
+	}
+	version, err := c.iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: policy.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return "", err
+	}
+	return url.QueryUnescape(aws.StringValue(version.PolicyVersion.Document))
+}
+
+// EqualPolicyDocuments returns true if both policy documents grant the same permissions. The
+// order of the statements and of the actions and resources doesn't matter, and neither does
+// whether single values are written as strings or lists.
+func EqualPolicyDocuments(a string, b string) (bool, error) {
+	normalizedA, err := normalizePolicyDocument(a)
+	if err != nil {
+		return false, err
+	}
+	normalizedB, err := normalizePolicyDocument(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(normalizedA, normalizedB), nil
+}
+
+func normalizePolicyDocument(document string) (interface{}, error) {
+	var value interface{}
+	err := json.Unmarshal([]byte(InterpolatePolicyDocument(document, nil)), &value)
+	if err != nil {
+		return nil, err
+	}
+	return normalizePolicyValue("", value), nil
+}
+
+// normalizePolicyValue converts the values that can be strings or lists to sorted lists, and sorts
+// the statements.
+func normalizePolicyValue(key string, value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, v := range typed {
+			result[k] = normalizePolicyValue(k, v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, v := range typed {
+			result[i] = normalizePolicyValue("", v)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return sortKey(result[i]) < sortKey(result[j])
+		})
+		return result
+	case string:
+		switch key {
+		case "Action", "NotAction", "Resource", "NotResource", "AWS", "Service", "Federated":
+			return []interface{}{typed}
+		}
+		return typed
+	default:
+		return typed
+	}
+}
+
+func sortKey(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
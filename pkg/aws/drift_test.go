@@ -0,0 +1,149 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Role drift", func() {
+	Context("EqualPolicyDocuments", func() {
+		It("Ignores the order of statements and actions", func() {
+			a := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"},
+				{"Effect":"Allow","Action":"ec2:DescribeVpcs","Resource":"*"}]}`
+			b := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["ec2:DescribeVpcs"],"Resource":["*"]},
+				{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject"],"Resource":"*"}]}`
+			equal, err := aws.EqualPolicyDocuments(a, b)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(BeTrue())
+		})
+
+		It("Detects missing actions", func() {
+			a := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"}]}`
+			b := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+			equal, err := aws.EqualPolicyDocuments(a, b)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(BeFalse())
+		})
+	})
+
+	Context("VerifyRole", func() {
+		var (
+			client     aws.Client
+			mockCtrl   *gomock.Controller
+			mockIamAPI *mocks.MockIAMAPI
+			expected   aws.ExpectedRole
+		)
+
+		const (
+			roleArn   = "arn:aws:iam::123456789012:role/test-Installer-Role"
+			policyArn = "arn:aws:iam::123456789012:policy/test-Installer-Role-Policy"
+			document  = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockS3API(mockCtrl),
+				mocks.NewMockSecretsManagerAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				&session.Session{},
+				&aws.AccessKey{},
+				false,
+			)
+			expected = aws.ExpectedRole{
+				Name: "test-Installer-Role",
+				Type: "Installer",
+				Path: "/",
+				TrustPolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+					`"Principal":{"AWS":["arn:aws:iam::710019948333:role/RH-Managed-OpenShift-Installer"]},` +
+					`"Action":"sts:AssumeRole"}]}`,
+				Policies: []aws.ExpectedPolicy{{
+					ARN:      policyArn,
+					Document: document,
+				}},
+				CreateCommand:  "rosa create account-roles --prefix test --classic",
+				UpgradeCommand: "rosa upgrade account-roles --prefix test",
+			}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("Reports missing roles with the command that creates them", func() {
+			mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(nil,
+				awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+
+			drift, err := client.VerifyRole(expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift.HasDrift()).To(BeTrue())
+			Expect(drift.Issues).To(HaveLen(1))
+			Expect(drift.Issues[0].Check).To(Equal(aws.DriftCheckExists))
+			Expect(drift.Issues[0].Remediation).To(Equal([]string{expected.CreateCommand}))
+		})
+
+		It("Reports untrusted principals, detached policies and modified documents", func() {
+			mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+				Role: &iam.Role{
+					Arn: awssdk.String(roleArn),
+					AssumeRolePolicyDocument: awssdk.String(`{"Version":"2012-10-17","Statement":[` +
+						`{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},` +
+						`"Action":"sts:AssumeRole"}]}`),
+				},
+			}, nil)
+			mockIamAPI.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []*iam.AttachedPolicy{{
+					PolicyArn:  awssdk.String(policyArn),
+					PolicyName: awssdk.String("test-Installer-Role-Policy"),
+				}, {
+					PolicyArn:  awssdk.String("arn:aws:iam::aws:policy/AdministratorAccess"),
+					PolicyName: awssdk.String("AdministratorAccess"),
+				}},
+			}, nil)
+			mockIamAPI.EXPECT().ListRolePolicies(gomock.Any()).Return(&iam.ListRolePoliciesOutput{}, nil)
+			mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(&iam.GetPolicyOutput{
+				Policy: &iam.Policy{DefaultVersionId: awssdk.String("v2")},
+			}, nil)
+			mockIamAPI.EXPECT().GetPolicyVersion(gomock.Any()).Return(&iam.GetPolicyVersionOutput{
+				PolicyVersion: &iam.PolicyVersion{
+					Document: awssdk.String(`{"Version":"2012-10-17","Statement":[` +
+						`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+				},
+			}, nil)
+
+			drift, err := client.VerifyRole(expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift.RoleARN).To(Equal(roleArn))
+			checks := []string{}
+			for _, issue := range drift.Issues {
+				checks = append(checks, issue.Check)
+			}
+			Expect(checks).To(Equal([]string{
+				aws.DriftCheckTrustPolicy,
+				aws.DriftCheckPolicyDocument,
+				aws.DriftCheckPolicies,
+			}))
+			Expect(drift.Issues[1].Remediation).To(Equal([]string{expected.UpgradeCommand}))
+			Expect(drift.Issues[2].Remediation[0]).To(ContainSubstring("aws iam detach-role-policy"))
+			Expect(drift.Issues[2].Remediation[0]).To(ContainSubstring("AdministratorAccess"))
+		})
+	})
+})
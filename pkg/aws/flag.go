@@ -27,10 +27,15 @@ var mode string
 const (
 	ModeAuto   = "auto"
 	ModeManual = "manual"
+	ModePlan   = "plan"
 )
 
 var Modes = []string{ModeAuto, ModeManual}
 
+// PlanModes are the modes of the commands that can also show the changes to IAM resources without
+// applying them.
+var PlanModes = []string{ModeAuto, ModeManual, ModePlan}
+
 func AddModeFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(
 		&mode,
@@ -44,6 +49,21 @@ func AddModeFlag(cmd *cobra.Command) {
 	cmd.RegisterFlagCompletionFunc("mode", modeCompletion)
 }
 
+// AddPlanModeFlag adds the mode flag to the given command, including the plan mode.
+func AddPlanModeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&mode,
+		"mode",
+		"m",
+		"",
+		"How to perform the operation. Valid options are:\n"+
+			"auto: Resource changes will be automatic applied using the current AWS account\n\n"+
+			"manual: Commands necessary to modify AWS resources will be output to be run manually\n\n"+
+			"plan: Changes to AWS resources will be computed and output as JSON, without applying them",
+	)
+	cmd.RegisterFlagCompletionFunc("mode", planModeCompletion)
+}
+
 func SetModeKey(key string) {
 	mode = key
 }
@@ -58,6 +78,21 @@ func GetMode() (string, error) {
 	return mode, nil
 }
 
+// GetPlanMode is like GetMode, but it also accepts the plan mode.
+func GetPlanMode() (string, error) {
+	if mode == "" {
+		return "", nil
+	}
+	if !arguments.IsValidMode(PlanModes, mode) {
+		return "", fmt.Errorf("Invalid mode. Allowed values are %s", PlanModes)
+	}
+	return mode, nil
+}
+
+func planModeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return PlanModes, cobra.ShellCompDirectiveDefault
+}
+
 func modeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return Modes, cobra.ShellCompDirectiveDefault
 }
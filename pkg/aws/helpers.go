@@ -453,6 +453,14 @@ func GetUserRoleName(prefix string, role string, userName string) string {
 	return name
 }
 
+func GetOperatorRoleName(prefix string, namespace string, name string) string {
+	role := fmt.Sprintf("%s-%s-%s", prefix, namespace, name)
+	if len(role) > 64 {
+		role = role[0:64]
+	}
+	return role
+}
+
 func GetOperatorPolicyName(prefix string, namespace string, name string) string {
 	policy := fmt.Sprintf("%s-%s-%s", prefix, namespace, name)
 	if len(policy) > 64 {
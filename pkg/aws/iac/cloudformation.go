@@ -0,0 +1,311 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that convert AWS CLI commands into CloudFormation templates.
+
+package iac
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+// ToCloudFormation converts the given AWS CLI commands, as returned by the JoinCommands function of
+// the command builder, into a CloudFormation template. Policy documents referenced by the commands
+// are read from the current directory and included in the template. Secrets are passed as
+// parameters, so that they aren't stored in the template.
+//
+// CloudFormation can't create S3 objects and doesn't support tags on managed policies, so those
+// are returned as commands to run once the stack has been created, like the commands that change
+// resources that may already exist. Other AWS CLI commands are rejected.
+func ToCloudFormation(commands string) (*Template, error) {
+	c := &cloudFormation{
+		names:      newNames(cloudFormationName),
+		resources:  map[string]*cfnResource{},
+		parameters: map[string]interface{}{},
+		roles:      map[string]*cfnResource{},
+		policies:   map[string]*cfnResource{},
+		buckets:    map[string]*cfnResource{},
+		result: &Template{
+			Parameters: map[string]string{},
+		},
+	}
+	parsed, err := parseCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+	for _, command := range parsed {
+		err = c.add(command)
+		if err != nil {
+			return nil, err
+		}
+	}
+	body, err := c.render()
+	if err != nil {
+		return nil, err
+	}
+	c.result.Body = body
+	return c.result, nil
+}
+
+type cloudFormation struct {
+	names      *names
+	order      []*cfnResource
+	resources  map[string]*cfnResource
+	parameters map[string]interface{}
+	roles      map[string]*cfnResource
+	policies   map[string]*cfnResource
+	buckets    map[string]*cfnResource
+	result     *Template
+}
+
+// cfnResource is a resource of a CloudFormation template. Tags are kept apart because S3 buckets
+// receive them from a separate command. For managed policies the ARN is also kept, as it is only
+// known when the policy is attached to a role.
+type cfnResource struct {
+	id         string
+	kind       string
+	properties map[string]interface{}
+	tags       map[string]string
+	arn        string
+}
+
+type cfnTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+func (c *cloudFormation) add(cmd *command) error {
+	switch {
+	case cmd.is(awscb.IAM, awscb.CreateRole):
+		document, err := readDocument(cmd, awscb.AssumeRolePolicyDocument)
+		if err != nil {
+			return err
+		}
+		name := cmd.param(awscb.RoleName)
+		role := c.resource("AWS::IAM::Role", "Role"+name)
+		role.setString("RoleName", name)
+		role.setString("Path", cmd.param(awscb.Path))
+		role.properties["AssumeRolePolicyDocument"] = document
+		role.setString("PermissionsBoundary", cmd.param(awscb.PermissionsBoundary))
+		role.tags = cmd.tags()
+		c.roles[name] = role
+	case cmd.is(awscb.IAM, awscb.CreatePolicy):
+		document, err := readDocument(cmd, awscb.PolicyDocument)
+		if err != nil {
+			return err
+		}
+		name := cmd.param(awscb.PolicyName)
+		policy := c.resource("AWS::IAM::ManagedPolicy", "Policy"+name)
+		policy.setString("ManagedPolicyName", name)
+		policy.setString("Path", cmd.param(awscb.Path))
+		policy.properties["PolicyDocument"] = document
+		policy.tags = cmd.tags()
+		c.policies[policyPathOf(cmd)] = policy
+	case cmd.is(awscb.IAM, awscb.AttachRolePolicy):
+		roleName := cmd.param(awscb.RoleName)
+		policyARN := cmd.param(awscb.PolicyArn)
+		policy, policyFound := c.policies[policyPath(policyARN)]
+		var arn interface{} = policyARN
+		if policyFound {
+			policy.arn = policyARN
+			arn = map[string]string{"Ref": policy.id}
+		}
+		if role, ok := c.roles[roleName]; ok {
+			role.append("ManagedPolicyArns", arn)
+		} else if policyFound {
+			policy.append("Roles", roleName)
+		} else {
+			c.result.Commands = append(c.result.Commands, cmd.raw)
+		}
+	case cmd.is(awscb.IAM, awscb.CreateOpenIdConnectProvider):
+		url := cmd.param(awscb.Url)
+		provider := c.resource("AWS::IAM::OIDCProvider", "OIDCProvider"+strings.TrimPrefix(url, "https://"))
+		provider.setString("Url", url)
+		provider.properties["ClientIdList"] = strings.Fields(cmd.param(awscb.ClientIdList))
+		provider.properties["ThumbprintList"] = strings.Fields(cmd.param(awscb.ThumbprintList))
+		provider.tags = cmd.tags()
+	case cmd.is(awscb.S3Api, awscb.CreateBucket):
+		name := cmd.param(awscb.Bucket)
+		bucket := c.resource("AWS::S3::Bucket", "Bucket"+name)
+		bucket.setString("BucketName", name)
+		bucket.tags = map[string]string{}
+		c.buckets[name] = bucket
+		c.setRegion(cmd)
+	case cmd.is(awscb.S3Api, awscb.PutBucketTagging):
+		bucket, ok := c.buckets[cmd.param(awscb.Bucket)]
+		if !ok {
+			c.result.Commands = append(c.result.Commands, cmd.raw)
+			break
+		}
+		for key, value := range cmd.tagSet() {
+			bucket.tags[key] = value
+		}
+	case cmd.is(awscb.S3Api, awscb.PutPublicAccessBlock):
+		bucket, ok := c.buckets[cmd.param(awscb.Bucket)]
+		if !ok {
+			c.result.Commands = append(c.result.Commands, cmd.raw)
+			break
+		}
+		configuration := map[string]bool{}
+		for key, value := range cmd.settings(awscb.PublicAccessBlockConfiguration) {
+			configuration[key] = strings.EqualFold(value, "true")
+		}
+		bucket.properties["PublicAccessBlockConfiguration"] = configuration
+	case cmd.is(awscb.S3Api, awscb.PutBucketPolicy):
+		document, err := readDocument(cmd, awscb.Policy)
+		if err != nil {
+			return err
+		}
+		name := cmd.param(awscb.Bucket)
+		policy := c.resource("AWS::S3::BucketPolicy", "BucketPolicy"+name)
+		if bucket, ok := c.buckets[name]; ok {
+			policy.properties["Bucket"] = map[string]string{"Ref": bucket.id}
+		} else {
+			policy.setString("Bucket", name)
+		}
+		policy.properties["PolicyDocument"] = document
+	case cmd.is(awscb.SM, awscb.CreateSecret):
+		file, err := cmd.file(awscb.SecretString)
+		if err != nil {
+			return err
+		}
+		name := cmd.param(awscb.Name)
+		secret := c.resource("AWS::SecretsManager::Secret", "Secret"+name)
+		parameter := secret.id + "String"
+		c.parameters[parameter] = map[string]interface{}{
+			"Type":        "String",
+			"NoEcho":      true,
+			"Description": fmt.Sprintf("Contents of the '%s' file", file),
+		}
+		c.result.Parameters[parameter] = file
+		secret.setString("Name", name)
+		secret.setString("Description", cmd.param(awscb.Description))
+		secret.properties["SecretString"] = map[string]string{"Ref": parameter}
+		secret.tags = cmd.tags()
+		c.setRegion(cmd)
+	case cmd.is(awscb.S3Api, awscb.PutObject):
+		c.result.Commands = append(c.result.Commands, cmd.raw)
+	case cmd.isRemove():
+		// The files may still be needed to pass the parameters of the template.
+	case cmd.service == "" || cmd.isFollowUp():
+		c.result.Commands = append(c.result.Commands, cmd.raw)
+	default:
+		return cmd.unsupported()
+	}
+	return nil
+}
+
+func (c *cloudFormation) resource(kind string, name string) *cfnResource {
+	resource := &cfnResource{
+		id:         c.names.next("", name),
+		kind:       kind,
+		properties: map[string]interface{}{},
+	}
+	c.order = append(c.order, resource)
+	c.resources[resource.id] = resource
+	return resource
+}
+
+func (c *cloudFormation) setRegion(cmd *command) {
+	if region := cmd.param(awscb.Region); region != "" {
+		c.result.Region = region
+	}
+}
+
+func (c *cloudFormation) render() (string, error) {
+	resources := map[string]interface{}{}
+	for _, resource := range c.order {
+		// Managed policies don't support tags, so they are added with a command instead:
+		if resource.kind == "AWS::IAM::ManagedPolicy" {
+			if len(resource.tags) > 0 && resource.arn != "" {
+				c.result.Commands = append(c.result.Commands, awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.TagPolicy).
+					AddParam(awscb.PolicyArn, resource.arn).
+					AddTags(resource.tags).
+					Build())
+			}
+		} else if len(resource.tags) > 0 {
+			tags := []cfnTag{}
+			for _, key := range sortedKeys(resource.tags) {
+				tags = append(tags, cfnTag{Key: key, Value: resource.tags[key]})
+			}
+			resource.properties["Tags"] = tags
+		}
+		resources[resource.id] = map[string]interface{}{
+			"Type":       resource.kind,
+			"Properties": resource.properties,
+		}
+	}
+	template := map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Resources":                resources,
+	}
+	if len(c.parameters) > 0 {
+		template["Parameters"] = c.parameters
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(template)
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func (r *cfnResource) setString(name string, value string) {
+	if value != "" {
+		r.properties[name] = value
+	}
+}
+
+func (r *cfnResource) append(name string, value interface{}) {
+	values, _ := r.properties[name].([]interface{})
+	r.properties[name] = append(values, value)
+}
+
+// readDocument reads the JSON document referenced by the given parameter of the command.
+func readDocument(cmd *command, name awscb.Param) (interface{}, error) {
+	file, err := cmd.file(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read document '%s': %v", file, err)
+	}
+	var document interface{}
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse document '%s': %v", file, err)
+	}
+	return document, nil
+}
+
+var invalidCloudFormationNameRE = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// cloudFormationName converts the name of an AWS resource into a valid logical identifier.
+func cloudFormationName(value string) string {
+	return invalidCloudFormationNameRE.ReplaceAllString(value, "")
+}
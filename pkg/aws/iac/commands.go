@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that parse the AWS CLI commands generated with the command
+// builder, so that they can be converted into templates.
+
+package iac
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+// command is an AWS CLI command generated by the command builder.
+type command struct {
+	raw     string
+	service awscb.Service
+	name    awscb.Command
+	params  map[awscb.Param]string
+}
+
+// followUps are the AWS CLI commands that change resources that may already exist, like new versions
+// or tags of policies. They can't be part of a template, so they are returned as commands to run
+// once the template has been applied.
+var followUps = map[awscb.Service][]awscb.Command{
+	awscb.IAM: {
+		awscb.CreatePolicyVersion,
+		awscb.DeletePolicyVersion,
+		awscb.DeleteRolePermissionsBoundary,
+		awscb.DeleteRolePolicy,
+		awscb.DetachRolePolicy,
+		awscb.PutRolePermissionsBoundary,
+		awscb.TagPolicy,
+		awscb.TagRole,
+		awscb.TagUser,
+		awscb.UpdateAssumeRolePolicy,
+	},
+	awscb.SM: {
+		awscb.PutSecretValue,
+	},
+}
+
+// parseCommands parses the given commands, as returned by the JoinCommands function of the command
+// builder. Commands that aren't AWS CLI commands are returned with an empty service.
+func parseCommands(commands string) ([]*command, error) {
+	result := []*command{}
+	for _, raw := range awscb.SplitCommands(commands) {
+		command, err := parseCommand(raw)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, command)
+	}
+	return result, nil
+}
+
+func parseCommand(raw string) (*command, error) {
+	result := &command{
+		raw:    raw,
+		params: map[awscb.Param]string{},
+	}
+	parts := strings.Split(raw, awscb.ParamNewLineSeparator)
+	fields := strings.Fields(parts[0])
+	if len(fields) == 0 || fields[0] != "aws" {
+		return result, nil
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("Failed to parse command '%s': expected 'aws <service> <command>'", parts[0])
+	}
+	result.service = awscb.Service(fields[1])
+	result.name = awscb.Command(fields[2])
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "--") {
+			return nil, fmt.Errorf("Failed to parse command '%s %s': unexpected argument '%s'",
+				result.service, result.name, part)
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(part, "--"), " ")
+		result.params[awscb.Param(name)] = unquote(value)
+	}
+	return result, nil
+}
+
+// is checks if the command is the given AWS CLI command.
+func (c *command) is(service awscb.Service, name awscb.Command) bool {
+	return c.service == service && c.name == name
+}
+
+// isFollowUp checks if the command is one of the commands that are run once the template has been
+// applied.
+func (c *command) isFollowUp() bool {
+	for _, name := range followUps[c.service] {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupported returns the error for an AWS CLI command that the templates can't express.
+func (c *command) unsupported() error {
+	return fmt.Errorf("Command 'aws %s %s' can't be converted into a template", c.service, c.name)
+}
+
+// isRemove checks if the command removes a local file. Those commands are used to clean up the
+// documents saved to the current directory, but the templates still need them.
+func (c *command) isRemove() bool {
+	return c.service == "" && strings.HasPrefix(c.raw, "rm ")
+}
+
+func (c *command) param(name awscb.Param) string {
+	return c.params[name]
+}
+
+// file returns the name of the local file referenced by the given parameter, either with the
+// 'file://' prefix or as a relative path.
+func (c *command) file(name awscb.Param) (string, error) {
+	value := c.params[name]
+	file := strings.TrimPrefix(strings.TrimPrefix(value, "file://"), "./")
+	if file == "" {
+		return "", fmt.Errorf("Parameter '--%s' of command '%s %s' is empty", name, c.service, c.name)
+	}
+	return file, nil
+}
+
+// tags returns the tags of the command, given in the 'Key=key,Value=value' format.
+func (c *command) tags() map[string]string {
+	result := map[string]string{}
+	for _, tag := range strings.Fields(c.params[awscb.Tags]) {
+		key, value, _ := strings.Cut(strings.TrimPrefix(tag, "Key="), ",Value=")
+		result[key] = value
+	}
+	return result
+}
+
+var tagSetRE = regexp.MustCompile(`\{Key=([^,]*),Value=([^}]*)\}`)
+
+// tagSet returns the tags of a bucket, given in the 'TagSet=[{Key=key,Value=value}]' format.
+func (c *command) tagSet() map[string]string {
+	result := map[string]string{}
+	for _, match := range tagSetRE.FindAllStringSubmatch(c.params[awscb.Tagging], -1) {
+		result[match[1]] = match[2]
+	}
+	return result
+}
+
+// objectTags returns the tags of an object, given in the 'key=value&key=value' format.
+func (c *command) objectTags() (map[string]string, error) {
+	values, err := url.ParseQuery(c.params[awscb.Tagging])
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for key := range values {
+		result[key] = values.Get(key)
+	}
+	return result, nil
+}
+
+// settings returns the settings of a parameter given in the 'Name=value,Name=value' format.
+func (c *command) settings(name awscb.Param) map[string]string {
+	result := map[string]string{}
+	for _, setting := range strings.Split(c.params[name], ",") {
+		key, value, found := strings.Cut(setting, "=")
+		if found {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// policyPath returns the path and name of the policy referenced by the given ARN, which is how
+// policies are identified in the templates.
+func policyPath(arn string) string {
+	_, path, _ := strings.Cut(arn, ":policy")
+	return path
+}
+
+// policyPathOf returns the path and name of the policy created by a 'create-policy' command.
+func policyPathOf(c *command) string {
+	path := c.param(awscb.Path)
+	if path == "" {
+		path = "/"
+	}
+	return path + c.param(awscb.PolicyName)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '\'' || first == '"') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// names generates unique identifiers for the resources of a template. Identifiers only need to be
+// unique within a scope, like the type of the resource in Terraform.
+type names struct {
+	used  map[string]bool
+	clean func(string) string
+}
+
+func newNames(clean func(string) string) *names {
+	return &names{
+		used:  map[string]bool{},
+		clean: clean,
+	}
+}
+
+func (n *names) next(scope string, value string) string {
+	base := n.clean(value)
+	name := base
+	for i := 2; n.used[scope+"/"+name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	n.used[scope+"/"+name] = true
+	return name
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--manual-format' command line option.
+
+package iac
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+)
+
+// Formats in which the AWS resources of the manual mode can be printed:
+const (
+	AWSCLI         = "aws-cli"
+	Terraform      = "terraform"
+	CloudFormation = "cloudformation"
+)
+
+var Formats = []string{AWSCLI, Terraform, CloudFormation}
+
+// AddFlag adds the manual format flag to the given command.
+func AddFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&format,
+		"manual-format",
+		AWSCLI,
+		"Format of the output of the manual mode. Valid options are:\n"+
+			"aws-cli: AWS CLI commands\n"+
+			"terraform: Terraform configuration using the AWS provider\n"+
+			"cloudformation: CloudFormation template",
+	)
+	cmd.RegisterFlagCompletionFunc("manual-format", formatCompletion)
+}
+
+// Format returns the format selected with the manual format flag.
+func Format() (string, error) {
+	if format == "" {
+		return AWSCLI, nil
+	}
+	if !arguments.IsValidMode(Formats, format) {
+		return "", fmt.Errorf("Invalid manual format. Allowed values are %s", Formats)
+	}
+	return format, nil
+}
+
+// SetFormat sets the value of the manual format flag.
+func SetFormat(value string) {
+	format = value
+}
+
+func formatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return Formats, cobra.ShellCompDirectiveDefault
+}
+
+// format is the value of the manual format flag.
+var format string
@@ -0,0 +1,487 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iac_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/iac"
+)
+
+const trustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole"}]}`
+
+var _ = Describe("IaC", func() {
+	var roleCommands string
+
+	BeforeEach(func() {
+		roleCommands = awscb.JoinCommands([]string{
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.CreateRole).
+				AddParam(awscb.RoleName, "prefix-Installer-Role").
+				AddParam(awscb.AssumeRolePolicyDocument, "file://trust.json").
+				AddParam(awscb.PermissionsBoundary, "arn:aws:iam::123456789012:policy/boundary").
+				AddParam(awscb.Path, "/rosa/").
+				AddTags(map[string]string{"red-hat-managed": "true"}).
+				Build(),
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.CreatePolicy).
+				AddParam(awscb.PolicyName, "prefix-Installer-Role-Policy").
+				AddParam(awscb.PolicyDocument, "file://permissions.json").
+				AddParam(awscb.Path, "/rosa/").
+				AddTags(map[string]string{"red-hat-managed": "true"}).
+				Build(),
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.AttachRolePolicy).
+				AddParam(awscb.RoleName, "prefix-Installer-Role").
+				AddParam(awscb.PolicyArn, "arn:aws:iam::123456789012:policy/rosa/prefix-Installer-Role-Policy").
+				Build(),
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.AttachRolePolicy).
+				AddParam(awscb.RoleName, "prefix-Installer-Role").
+				AddParam(awscb.PolicyArn, "arn:aws:iam::aws:policy/service-role/ROSAInstallerPolicy").
+				Build(),
+			"rosa link user-role --role-arn arn:aws:iam::123456789012:role/rosa/prefix-Installer-Role",
+		})
+	})
+
+	Context("Terraform", func() {
+		It("Converts roles, policies and attachments", func() {
+			template, err := iac.ToTerraform(roleCommands)
+			Expect(err).To(BeNil())
+			Expect(template.Body).To(ContainSubstring(`resource "aws_iam_role" "prefix-Installer-Role" {
+  name                 = "prefix-Installer-Role"
+  path                 = "/rosa/"
+  assume_role_policy   = file("${path.module}/trust.json")
+  permissions_boundary = "arn:aws:iam::123456789012:policy/boundary"
+
+  tags = {
+    "red-hat-managed" = "true"
+  }
+}
+`))
+			Expect(template.Body).To(ContainSubstring(`resource "aws_iam_policy" "prefix-Installer-Role-Policy" {
+  name   = "prefix-Installer-Role-Policy"
+  path   = "/rosa/"
+  policy = file("${path.module}/permissions.json")
+`))
+			Expect(template.Body).To(ContainSubstring(
+				`resource "aws_iam_role_policy_attachment" "prefix-Installer-Role-prefix-Installer-Role-Policy" {
+  role       = aws_iam_role.prefix-Installer-Role.name
+  policy_arn = aws_iam_policy.prefix-Installer-Role-Policy.arn
+}
+`))
+			Expect(template.Body).To(ContainSubstring(
+				`resource "aws_iam_role_policy_attachment" "prefix-Installer-Role-ROSAInstallerPolicy" {
+  role       = aws_iam_role.prefix-Installer-Role.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/ROSAInstallerPolicy"
+}
+`))
+			Expect(template.Commands).To(Equal([]string{
+				"rosa link user-role --role-arn arn:aws:iam::123456789012:role/rosa/prefix-Installer-Role",
+			}))
+		})
+
+		It("Converts OIDC configuration buckets and secrets", func() {
+			template, err := iac.ToTerraform(awscb.JoinCommands([]string{
+				awscb.NewS3ApiCommandBuilder().
+					SetCommand(awscb.CreateBucket).
+					AddParam(awscb.Bucket, "oidc-bucket").
+					AddParam(awscb.Region, "us-east-2").
+					Build(),
+				awscb.NewS3ApiCommandBuilder().
+					SetCommand(awscb.PutBucketTagging).
+					AddParam(awscb.Bucket, "oidc-bucket").
+					AddParam(awscb.Tagging, "'TagSet=[{Key=red-hat-managed,Value=true}]'").
+					Build(),
+				awscb.NewS3ApiCommandBuilder().
+					SetCommand(awscb.PutPublicAccessBlock).
+					AddParam(awscb.Bucket, "oidc-bucket").
+					AddParam(awscb.PublicAccessBlockConfiguration, "BlockPublicAcls=true,BlockPublicPolicy=false").
+					Build(),
+				awscb.NewS3ApiCommandBuilder().
+					SetCommand(awscb.PutObject).
+					AddParam(awscb.Body, "./keys.json").
+					AddParam(awscb.Bucket, "oidc-bucket").
+					AddParam(awscb.Key, "keys.json").
+					AddParam(awscb.Tagging, "'red-hat-managed=true'").
+					Build(),
+				"rm keys.json",
+				awscb.NewSecretsManagerCommandBuilder().
+					SetCommand(awscb.CreateSecret).
+					AddParam(awscb.Name, "oidc-secret").
+					AddParam(awscb.SecretString, "file://private.key").
+					AddParam(awscb.Description, "\"Secret for oidc-bucket\"").
+					Build(),
+			}))
+			Expect(err).To(BeNil())
+			Expect(template.Region).To(Equal("us-east-2"))
+			Expect(template.Commands).To(BeEmpty())
+			Expect(template.Body).To(ContainSubstring(`provider "aws" {
+  region = "us-east-2"
+}
+`))
+			Expect(template.Body).To(ContainSubstring(`resource "aws_s3_bucket" "oidc-bucket" {
+  bucket = "oidc-bucket"
+
+  tags = {
+    "red-hat-managed" = "true"
+  }
+}
+`))
+			Expect(template.Body).To(ContainSubstring(`resource "aws_s3_bucket_public_access_block" "oidc-bucket" {
+  bucket              = aws_s3_bucket.oidc-bucket.id
+  block_public_acls   = true
+  block_public_policy = false
+}
+`))
+			Expect(template.Body).To(ContainSubstring(`resource "aws_s3_object" "oidc-bucket-keys_json" {
+  bucket = aws_s3_bucket.oidc-bucket.id
+  key    = "keys.json"
+  source = "${path.module}/keys.json"
+`))
+			Expect(template.Body).To(ContainSubstring(`resource "aws_secretsmanager_secret_version" "oidc-secret" {
+  secret_id     = aws_secretsmanager_secret.oidc-secret.id
+  secret_string = file("${path.module}/private.key")
+}
+`))
+		})
+	})
+
+	Context("CloudFormation", func() {
+		var dir string
+
+		BeforeEach(func() {
+			current, err := os.Getwd()
+			Expect(err).To(BeNil())
+			dir = GinkgoT().TempDir()
+			Expect(os.Chdir(dir)).To(Succeed())
+			DeferCleanup(os.Chdir, current)
+			Expect(os.WriteFile(filepath.Join(dir, "trust.json"), []byte(trustPolicy), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "permissions.json"), []byte(trustPolicy), 0600)).To(Succeed())
+		})
+
+		It("Converts roles, policies and attachments", func() {
+			template, err := iac.ToCloudFormation(roleCommands)
+			Expect(err).To(BeNil())
+
+			var body map[string]interface{}
+			Expect(json.Unmarshal([]byte(template.Body), &body)).To(Succeed())
+			resources := body["Resources"].(map[string]interface{})
+			Expect(resources).To(HaveLen(2))
+
+			role := resources["RoleprefixInstallerRole"].(map[string]interface{})
+			Expect(role["Type"]).To(Equal("AWS::IAM::Role"))
+			properties := role["Properties"].(map[string]interface{})
+			Expect(properties["RoleName"]).To(Equal("prefix-Installer-Role"))
+			Expect(properties["Path"]).To(Equal("/rosa/"))
+			Expect(properties["PermissionsBoundary"]).To(Equal("arn:aws:iam::123456789012:policy/boundary"))
+			Expect(properties["AssumeRolePolicyDocument"]).To(HaveKeyWithValue("Version", "2012-10-17"))
+			Expect(properties["ManagedPolicyArns"]).To(Equal([]interface{}{
+				map[string]interface{}{"Ref": "PolicyprefixInstallerRolePolicy"},
+				"arn:aws:iam::aws:policy/service-role/ROSAInstallerPolicy",
+			}))
+			Expect(properties["Tags"]).To(Equal([]interface{}{
+				map[string]interface{}{"Key": "red-hat-managed", "Value": "true"},
+			}))
+
+			policy := resources["PolicyprefixInstallerRolePolicy"].(map[string]interface{})
+			Expect(policy["Type"]).To(Equal("AWS::IAM::ManagedPolicy"))
+			Expect(policy["Properties"]).ToNot(HaveKey("Tags"))
+
+			Expect(template.Commands).To(Equal([]string{
+				"rosa link user-role --role-arn arn:aws:iam::123456789012:role/rosa/prefix-Installer-Role",
+				"aws iam tag-policy \\\n" +
+					"\t--policy-arn arn:aws:iam::123456789012:policy/rosa/prefix-Installer-Role-Policy \\\n" +
+					"\t--tags Key=red-hat-managed,Value=true",
+			}))
+		})
+
+		It("Passes secrets as parameters and leaves objects to commands", func() {
+			putObject := awscb.NewS3ApiCommandBuilder().
+				SetCommand(awscb.PutObject).
+				AddParam(awscb.Body, "./keys.json").
+				AddParam(awscb.Bucket, "oidc-bucket").
+				AddParam(awscb.Key, "keys.json").
+				Build()
+			template, err := iac.ToCloudFormation(awscb.JoinCommands([]string{
+				awscb.NewS3ApiCommandBuilder().
+					SetCommand(awscb.CreateBucket).
+					AddParam(awscb.Bucket, "oidc-bucket").
+					Build(),
+				putObject,
+				awscb.NewSecretsManagerCommandBuilder().
+					SetCommand(awscb.CreateSecret).
+					AddParam(awscb.Name, "oidc-secret").
+					AddParam(awscb.SecretString, "file://private.key").
+					AddParam(awscb.Region, "us-east-2").
+					Build(),
+			}))
+			Expect(err).To(BeNil())
+			Expect(template.Region).To(Equal("us-east-2"))
+			Expect(template.Parameters).To(Equal(map[string]string{
+				"SecretoidcsecretString": "private.key",
+			}))
+			Expect(template.Commands).To(Equal([]string{putObject}))
+
+			var body map[string]interface{}
+			Expect(json.Unmarshal([]byte(template.Body), &body)).To(Succeed())
+			Expect(body["Parameters"]).To(HaveKeyWithValue("SecretoidcsecretString", HaveKeyWithValue("NoEcho", true)))
+			secret := body["Resources"].(map[string]interface{})["Secretoidcsecret"].(map[string]interface{})
+			Expect(secret["Properties"]).To(HaveKeyWithValue("SecretString",
+				map[string]interface{}{"Ref": "SecretoidcsecretString"}))
+		})
+
+		It("Fails if a policy document is missing", func() {
+			Expect(os.Remove(filepath.Join(dir, "trust.json"))).To(Succeed())
+			_, err := iac.ToCloudFormation(roleCommands)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("Failed to read document 'trust.json'"))
+		})
+	})
+
+	Context("Commands of the builder", func() {
+		createRole := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.CreateRole).
+			AddParam(awscb.RoleName, "role").
+			AddParam(awscb.AssumeRolePolicyDocument, "file://trust.json").
+			Build()
+		createPolicy := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.CreatePolicy).
+			AddParam(awscb.PolicyName, "policy").
+			AddParam(awscb.PolicyDocument, "file://permissions.json").
+			Build()
+		attachRolePolicy := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.AttachRolePolicy).
+			AddParam(awscb.RoleName, "role").
+			AddParam(awscb.PolicyArn, "arn:aws:iam::123456789012:policy/policy").
+			Build()
+		createOidcProvider := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.CreateOpenIdConnectProvider).
+			AddParam(awscb.Url, "https://oidc.example.com").
+			AddParam(awscb.ClientIdList, "openshift sts.amazonaws.com").
+			AddParam(awscb.ThumbprintList, "0123456789abcdef").
+			Build()
+		createBucket := awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.CreateBucket).
+			AddParam(awscb.Bucket, "bucket").
+			Build()
+		putBucketTagging := awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.PutBucketTagging).
+			AddParam(awscb.Bucket, "bucket").
+			AddParam(awscb.Tagging, "'TagSet=[{Key=red-hat-managed,Value=true}]'").
+			Build()
+		putPublicAccessBlock := awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.PutPublicAccessBlock).
+			AddParam(awscb.Bucket, "bucket").
+			AddParam(awscb.PublicAccessBlockConfiguration, "BlockPublicAcls=true").
+			Build()
+		putBucketPolicy := awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.PutBucketPolicy).
+			AddParam(awscb.Bucket, "bucket").
+			AddParam(awscb.Policy, "file://permissions.json").
+			Build()
+		putObject := awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.PutObject).
+			AddParam(awscb.Body, "./keys.json").
+			AddParam(awscb.Bucket, "bucket").
+			AddParam(awscb.Key, "keys.json").
+			Build()
+		createSecret := awscb.NewSecretsManagerCommandBuilder().
+			SetCommand(awscb.CreateSecret).
+			AddParam(awscb.Name, "secret").
+			AddParam(awscb.SecretString, "file://private.key").
+			Build()
+		tagRole := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.TagRole).
+			AddParam(awscb.RoleName, "role").
+			AddTags(map[string]string{"red-hat-managed": "true"}).
+			Build()
+		createPolicyVersion := awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.CreatePolicyVersion).
+			AddParam(awscb.PolicyArn, "arn:aws:iam::123456789012:policy/policy").
+			AddParam(awscb.PolicyDocument, "file://permissions.json").
+			AddParamNoValue(awscb.SetAsDefault).
+			Build()
+
+		BeforeEach(func() {
+			current, err := os.Getwd()
+			Expect(err).To(BeNil())
+			dir := GinkgoT().TempDir()
+			Expect(os.Chdir(dir)).To(Succeed())
+			DeferCleanup(os.Chdir, current)
+			Expect(os.WriteFile(filepath.Join(dir, "trust.json"), []byte(trustPolicy), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "permissions.json"), []byte(trustPolicy), 0600)).To(Succeed())
+		})
+
+		DescribeTable("Convert into Terraform resources",
+			func(expected []string, commands ...string) {
+				template, err := iac.ToTerraform(awscb.JoinCommands(commands))
+				Expect(err).To(BeNil())
+				Expect(template.Commands).To(BeEmpty())
+				for _, value := range expected {
+					Expect(template.Body).To(ContainSubstring(value))
+				}
+			},
+			Entry("create-role",
+				[]string{`resource "aws_iam_role" "role"`, `file("${path.module}/trust.json")`},
+				createRole),
+			Entry("create-policy",
+				[]string{`resource "aws_iam_policy" "policy"`, `file("${path.module}/permissions.json")`},
+				createPolicy),
+			Entry("attach-role-policy",
+				[]string{`resource "aws_iam_role_policy_attachment" "role-policy"`,
+					`"arn:aws:iam::123456789012:policy/policy"`},
+				attachRolePolicy),
+			Entry("create-open-id-connect-provider",
+				[]string{`resource "aws_iam_openid_connect_provider"`, `"https://oidc.example.com"`,
+					`["openshift", "sts.amazonaws.com"]`, `["0123456789abcdef"]`},
+				createOidcProvider),
+			Entry("create-bucket",
+				[]string{`resource "aws_s3_bucket" "bucket"`},
+				createBucket),
+			Entry("put-bucket-tagging",
+				[]string{`"red-hat-managed" = "true"`},
+				createBucket, putBucketTagging),
+			Entry("put-public-access-block",
+				[]string{`resource "aws_s3_bucket_public_access_block" "bucket"`, `block_public_acls = true`},
+				createBucket, putPublicAccessBlock),
+			Entry("put-bucket-policy",
+				[]string{`resource "aws_s3_bucket_policy" "bucket"`, `file("${path.module}/permissions.json")`},
+				createBucket, putBucketPolicy),
+			Entry("put-object",
+				[]string{`resource "aws_s3_object" "bucket-keys_json"`, `"${path.module}/keys.json"`},
+				createBucket, putObject),
+			Entry("create-secret",
+				[]string{`resource "aws_secretsmanager_secret" "secret"`, `file("${path.module}/private.key")`},
+				createSecret),
+		)
+
+		DescribeTable("Convert into CloudFormation resources",
+			func(kind string, properties map[string]interface{}, commands ...string) {
+				template, err := iac.ToCloudFormation(awscb.JoinCommands(commands))
+				Expect(err).To(BeNil())
+				Expect(template.Commands).To(BeEmpty())
+				var body struct {
+					Resources map[string]struct {
+						Type       string                 `json:"Type"`
+						Properties map[string]interface{} `json:"Properties"`
+					} `json:"Resources"`
+				}
+				Expect(json.Unmarshal([]byte(template.Body), &body)).To(Succeed())
+				found := false
+				for _, resource := range body.Resources {
+					if resource.Type != kind {
+						continue
+					}
+					found = true
+					for name, value := range properties {
+						Expect(resource.Properties).To(HaveKeyWithValue(name, value))
+					}
+				}
+				Expect(found).To(BeTrue(), "resource of type '%s' not found", kind)
+			},
+			Entry("create-role", "AWS::IAM::Role",
+				map[string]interface{}{"RoleName": "role"},
+				createRole),
+			Entry("create-policy", "AWS::IAM::ManagedPolicy",
+				map[string]interface{}{"ManagedPolicyName": "policy"},
+				createPolicy),
+			Entry("attach-role-policy", "AWS::IAM::Role",
+				map[string]interface{}{"ManagedPolicyArns": []interface{}{"arn:aws:iam::123456789012:policy/policy"}},
+				createRole, attachRolePolicy),
+			Entry("create-open-id-connect-provider", "AWS::IAM::OIDCProvider",
+				map[string]interface{}{
+					"Url":            "https://oidc.example.com",
+					"ClientIdList":   []interface{}{"openshift", "sts.amazonaws.com"},
+					"ThumbprintList": []interface{}{"0123456789abcdef"},
+				},
+				createOidcProvider),
+			Entry("create-bucket", "AWS::S3::Bucket",
+				map[string]interface{}{"BucketName": "bucket"},
+				createBucket),
+			Entry("put-bucket-tagging", "AWS::S3::Bucket",
+				map[string]interface{}{"Tags": []interface{}{
+					map[string]interface{}{"Key": "red-hat-managed", "Value": "true"},
+				}},
+				createBucket, putBucketTagging),
+			Entry("put-public-access-block", "AWS::S3::Bucket",
+				map[string]interface{}{"PublicAccessBlockConfiguration": map[string]interface{}{
+					"BlockPublicAcls": true,
+				}},
+				createBucket, putPublicAccessBlock),
+			Entry("put-bucket-policy", "AWS::S3::BucketPolicy",
+				map[string]interface{}{"Bucket": map[string]interface{}{"Ref": "Bucketbucket"}},
+				createBucket, putBucketPolicy),
+			Entry("create-secret", "AWS::SecretsManager::Secret",
+				map[string]interface{}{"Name": "secret"},
+				createSecret),
+		)
+
+		DescribeTable("Leave changes of existing resources to commands",
+			func(format string, command string) {
+				template, err := iac.Convert(format, command)
+				Expect(err).To(BeNil())
+				Expect(template.Commands).To(Equal([]string{command}))
+			},
+			Entry("tag-role to Terraform", iac.Terraform, tagRole),
+			Entry("tag-role to CloudFormation", iac.CloudFormation, tagRole),
+			Entry("create-policy-version to Terraform", iac.Terraform, createPolicyVersion),
+			Entry("create-policy-version to CloudFormation", iac.CloudFormation, createPolicyVersion),
+		)
+
+		DescribeTable("Reject commands that templates can't express",
+			func(command string, message string) {
+				for _, format := range []string{iac.Terraform, iac.CloudFormation} {
+					_, err := iac.Convert(format, awscb.JoinCommands([]string{createRole, command}))
+					Expect(err).ToNot(BeNil())
+					Expect(err.Error()).To(ContainSubstring(message))
+				}
+			},
+			Entry("Unknown command",
+				awscb.NewIAMCommandBuilder().
+					SetCommand(awscb.PutRolePolicy).
+					AddParam(awscb.RoleName, "role").
+					AddParam(awscb.PolicyDocument, "file://permissions.json").
+					Build(),
+				"Command 'aws iam put-role-policy' can't be converted into a template"),
+			Entry("Unknown service",
+				awscb.NewCloudFormationCommandBuilder().
+					SetCommand(awscb.CreateStack).
+					AddParam(awscb.StackName, "stack").
+					Build(),
+				"Command 'aws cloudformation create-stack' can't be converted into a template"),
+			Entry("Positional argument",
+				awscb.NewS3CommandBuilder().
+					SetCommand(awscb.RemoveBucket).
+					AddValueNoParam("s3://bucket").
+					Build(),
+				"unexpected argument 's3://bucket'"),
+			Entry("Missing command", "aws iam", "expected 'aws <service> <command>'"),
+		)
+	})
+
+	It("Rejects unknown formats", func() {
+		_, err := iac.Convert("pulumi", roleCommands)
+		Expect(err).ToNot(BeNil())
+	})
+})
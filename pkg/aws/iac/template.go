@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package iac converts the AWS CLI commands printed by the manual mode into infrastructure as code,
+// either Terraform configurations or CloudFormation templates.
+package iac
+
+import (
+	"fmt"
+	"strings"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/reporter"
+)
+
+// Template is the infrastructure as code equivalent to a list of AWS CLI commands.
+type Template struct {
+	// Body is the Terraform configuration or the CloudFormation template.
+	Body string
+
+	// Region is the region where the resources must be created, when the commands select one.
+	Region string
+
+	// Parameters are the parameters of a CloudFormation template, and the names of the local files
+	// with their values.
+	Parameters map[string]string
+
+	// Commands are the commands that can't be expressed in the template. They have to be run once
+	// the template has been applied.
+	Commands []string
+}
+
+// Convert converts the given AWS CLI commands into a template in the given format.
+func Convert(format string, commands string) (*Template, error) {
+	switch format {
+	case Terraform:
+		return ToTerraform(commands)
+	case CloudFormation:
+		return ToCloudFormation(commands)
+	default:
+		return nil, fmt.Errorf("Invalid manual format. Allowed values are %s", Formats)
+	}
+}
+
+// Print writes the given AWS CLI commands to the standard output, in the format selected with the
+// '--manual-format' flag. The description says what the commands create, for example 'the
+// operator roles'.
+func Print(r *reporter.Object, description string, commands string) error {
+	format, err := Format()
+	if err != nil {
+		return err
+	}
+	if format == AWSCLI {
+		if r.IsTerminal() {
+			r.Infof("Run the following commands to create %s:\n", description)
+		}
+		fmt.Println(commands)
+		return nil
+	}
+	template, err := Convert(format, commands)
+	if err != nil {
+		return err
+	}
+	if r.IsTerminal() {
+		if format == Terraform {
+			r.Infof("Save the following configuration to a '.tf' file in the current directory "+
+				"and run 'terraform apply' to create %s:\n", description)
+		} else {
+			r.Infof("Save the following template to a file in the current directory and run "+
+				"'%s' to create %s:\n", deployCommand(template), description)
+		}
+	}
+	fmt.Println(template.Body)
+	if len(template.Commands) > 0 {
+		if r.IsTerminal() {
+			r.Infof("Then run the following commands:\n")
+		}
+		fmt.Println(awscb.JoinCommands(template.Commands))
+	}
+	return nil
+}
+
+// deployCommand returns the command that creates a stack from a CloudFormation template.
+func deployCommand(template *Template) string {
+	command := []string{
+		"aws cloudformation deploy",
+		"--template-file <file>",
+		"--stack-name <name>",
+		"--capabilities CAPABILITY_NAMED_IAM",
+	}
+	if template.Region != "" {
+		command = append(command, "--region "+template.Region)
+	}
+	if len(template.Parameters) > 0 {
+		command = append(command, "--parameter-overrides")
+		for _, parameter := range sortedKeys(template.Parameters) {
+			command = append(command, fmt.Sprintf("%s=\"$(cat %s)\"", parameter, template.Parameters[parameter]))
+		}
+	}
+	return strings.Join(command, " ")
+}
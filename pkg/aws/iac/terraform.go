@@ -0,0 +1,319 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that convert AWS CLI commands into Terraform configurations.
+
+package iac
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+// terraformHeader declares the provider used by the generated resources.
+const terraformHeader = `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 4.0"
+    }
+  }
+}
+`
+
+// ToTerraform converts the given AWS CLI commands, as returned by the JoinCommands function of the
+// command builder, into a Terraform configuration. The documents referenced by the commands are
+// read from the directory of the configuration with the 'file' function. The commands that change
+// resources that may already exist are returned to run once the configuration has been applied, and
+// other AWS CLI commands are rejected.
+func ToTerraform(commands string) (*Template, error) {
+	t := &terraform{
+		names:    newNames(terraformName),
+		roles:    map[string]*hclBlock{},
+		policies: map[string]*hclBlock{},
+		buckets:  map[string]*hclBlock{},
+		result:   &Template{},
+	}
+	parsed, err := parseCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+	for _, command := range parsed {
+		err = t.add(command)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.result.Body = t.render()
+	return t.result, nil
+}
+
+type terraform struct {
+	names    *names
+	blocks   []*hclBlock
+	roles    map[string]*hclBlock
+	policies map[string]*hclBlock
+	buckets  map[string]*hclBlock
+	result   *Template
+}
+
+func (t *terraform) add(c *command) error {
+	switch {
+	case c.is(awscb.IAM, awscb.CreateRole):
+		file, err := c.file(awscb.AssumeRolePolicyDocument)
+		if err != nil {
+			return err
+		}
+		name := c.param(awscb.RoleName)
+		role := t.resource("aws_iam_role", name)
+		role.setString("name", name)
+		role.setString("path", c.param(awscb.Path))
+		role.set("assume_role_policy", hclFile(file))
+		role.setString("permissions_boundary", c.param(awscb.PermissionsBoundary))
+		role.tags = c.tags()
+		t.roles[name] = role
+	case c.is(awscb.IAM, awscb.CreatePolicy):
+		file, err := c.file(awscb.PolicyDocument)
+		if err != nil {
+			return err
+		}
+		policy := t.resource("aws_iam_policy", c.param(awscb.PolicyName))
+		policy.setString("name", c.param(awscb.PolicyName))
+		policy.setString("path", c.param(awscb.Path))
+		policy.set("policy", hclFile(file))
+		policy.tags = c.tags()
+		t.policies[policyPathOf(c)] = policy
+	case c.is(awscb.IAM, awscb.AttachRolePolicy):
+		roleName := c.param(awscb.RoleName)
+		policyARN := c.param(awscb.PolicyArn)
+		attachment := t.resource("aws_iam_role_policy_attachment",
+			roleName+"-"+policyARN[strings.LastIndex(policyARN, "/")+1:])
+		if role, ok := t.roles[roleName]; ok {
+			attachment.set("role", role.ref("name"))
+		} else {
+			attachment.setString("role", roleName)
+		}
+		if policy, ok := t.policies[policyPath(policyARN)]; ok {
+			attachment.set("policy_arn", policy.ref("arn"))
+		} else {
+			attachment.setString("policy_arn", policyARN)
+		}
+	case c.is(awscb.IAM, awscb.CreateOpenIdConnectProvider):
+		url := c.param(awscb.Url)
+		provider := t.resource("aws_iam_openid_connect_provider", strings.TrimPrefix(url, "https://"))
+		provider.setString("url", url)
+		provider.set("client_id_list", hclList(strings.Fields(c.param(awscb.ClientIdList))))
+		provider.set("thumbprint_list", hclList(strings.Fields(c.param(awscb.ThumbprintList))))
+		provider.tags = c.tags()
+	case c.is(awscb.S3Api, awscb.CreateBucket):
+		name := c.param(awscb.Bucket)
+		bucket := t.resource("aws_s3_bucket", name)
+		bucket.setString("bucket", name)
+		bucket.tags = map[string]string{}
+		t.buckets[name] = bucket
+		t.setRegion(c)
+	case c.is(awscb.S3Api, awscb.PutBucketTagging):
+		bucket, ok := t.buckets[c.param(awscb.Bucket)]
+		if !ok {
+			t.result.Commands = append(t.result.Commands, c.raw)
+			break
+		}
+		for key, value := range c.tagSet() {
+			bucket.tags[key] = value
+		}
+	case c.is(awscb.S3Api, awscb.PutPublicAccessBlock):
+		block := t.resource("aws_s3_bucket_public_access_block", c.param(awscb.Bucket))
+		block.set("bucket", t.bucketRef(c.param(awscb.Bucket)))
+		settings := c.settings(awscb.PublicAccessBlockConfiguration)
+		for _, key := range sortedKeys(settings) {
+			block.set(snakeCase(key), strings.ToLower(settings[key]))
+		}
+	case c.is(awscb.S3Api, awscb.PutBucketPolicy):
+		file, err := c.file(awscb.Policy)
+		if err != nil {
+			return err
+		}
+		policy := t.resource("aws_s3_bucket_policy", c.param(awscb.Bucket))
+		policy.set("bucket", t.bucketRef(c.param(awscb.Bucket)))
+		policy.set("policy", hclFile(file))
+	case c.is(awscb.S3Api, awscb.PutObject):
+		file, err := c.file(awscb.Body)
+		if err != nil {
+			return err
+		}
+		objectTags, err := c.objectTags()
+		if err != nil {
+			return err
+		}
+		object := t.resource("aws_s3_object", c.param(awscb.Bucket)+"-"+c.param(awscb.Key))
+		object.set("bucket", t.bucketRef(c.param(awscb.Bucket)))
+		object.setString("key", c.param(awscb.Key))
+		object.set("source", hclPath(file))
+		object.tags = objectTags
+	case c.is(awscb.SM, awscb.CreateSecret):
+		file, err := c.file(awscb.SecretString)
+		if err != nil {
+			return err
+		}
+		name := c.param(awscb.Name)
+		secret := t.resource("aws_secretsmanager_secret", name)
+		secret.setString("name", name)
+		secret.setString("description", c.param(awscb.Description))
+		secret.tags = c.tags()
+		version := t.resource("aws_secretsmanager_secret_version", name)
+		version.set("secret_id", secret.ref("id"))
+		version.set("secret_string", hclFile(file))
+		t.setRegion(c)
+	case c.isRemove():
+		// The configuration reads the files, so they need to be kept.
+	case c.service == "" || c.isFollowUp():
+		t.result.Commands = append(t.result.Commands, c.raw)
+	default:
+		return c.unsupported()
+	}
+	return nil
+}
+
+func (t *terraform) resource(kind string, name string) *hclBlock {
+	block := &hclBlock{
+		kind: kind,
+		name: t.names.next(kind, name),
+	}
+	t.blocks = append(t.blocks, block)
+	return block
+}
+
+func (t *terraform) bucketRef(name string) string {
+	if bucket, ok := t.buckets[name]; ok {
+		return bucket.ref("id")
+	}
+	return hclString(name)
+}
+
+func (t *terraform) setRegion(c *command) {
+	if region := c.param(awscb.Region); region != "" {
+		t.result.Region = region
+	}
+}
+
+func (t *terraform) render() string {
+	var builder strings.Builder
+	builder.WriteString(terraformHeader)
+	if t.result.Region != "" {
+		fmt.Fprintf(&builder, "\nprovider \"aws\" {\n  region = %s\n}\n", hclString(t.result.Region))
+	}
+	for _, block := range t.blocks {
+		builder.WriteString("\n")
+		block.render(&builder)
+	}
+	return builder.String()
+}
+
+// hclBlock is a Terraform resource.
+type hclBlock struct {
+	kind  string
+	name  string
+	attrs []hclAttr
+	tags  map[string]string
+}
+
+// hclAttr is an attribute of a resource. The value is an HCL expression.
+type hclAttr struct {
+	name  string
+	value string
+}
+
+func (b *hclBlock) set(name string, value string) {
+	b.attrs = append(b.attrs, hclAttr{name: name, value: value})
+}
+
+func (b *hclBlock) setString(name string, value string) {
+	if value != "" {
+		b.set(name, hclString(value))
+	}
+}
+
+func (b *hclBlock) ref(attr string) string {
+	return fmt.Sprintf("%s.%s.%s", b.kind, b.name, attr)
+}
+
+func (b *hclBlock) render(builder *strings.Builder) {
+	fmt.Fprintf(builder, "resource %s %s {\n", hclString(b.kind), hclString(b.name))
+	width := 0
+	for _, attr := range b.attrs {
+		if len(attr.name) > width {
+			width = len(attr.name)
+		}
+	}
+	for _, attr := range b.attrs {
+		fmt.Fprintf(builder, "  %-*s = %s\n", width, attr.name, attr.value)
+	}
+	if len(b.tags) > 0 {
+		if len(b.attrs) > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("  tags = {\n")
+		for _, key := range sortedKeys(b.tags) {
+			fmt.Fprintf(builder, "    %s = %s\n", hclString(key), hclString(b.tags[key]))
+		}
+		builder.WriteString("  }\n")
+	}
+	builder.WriteString("}\n")
+}
+
+func hclString(value string) string {
+	value = strconv.Quote(value)
+	value = strings.ReplaceAll(value, "${", "$${")
+	return strings.ReplaceAll(value, "%{", "%%{")
+}
+
+func hclList(values []string) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = hclString(value)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func hclPath(file string) string {
+	return fmt.Sprintf("\"${path.module}/%s\"", file)
+}
+
+func hclFile(file string) string {
+	return fmt.Sprintf("file(%s)", hclPath(file))
+}
+
+var invalidTerraformNameRE = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// terraformName converts the name of an AWS resource into a valid Terraform identifier.
+func terraformName(value string) string {
+	name := invalidTerraformNameRE.ReplaceAllString(value, "_")
+	if name == "" || !(name[0] == '_' || unicode.IsLetter(rune(name[0]))) {
+		name = "_" + name
+	}
+	return name
+}
+
+var capitalRE = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+func snakeCase(value string) string {
+	return strings.ToLower(capitalRE.ReplaceAllString(value, "${1}_${2}"))
+}
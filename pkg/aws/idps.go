@@ -88,6 +88,30 @@ func (c *awsClient) HasOpenIDConnectProvider(issuerURL string, accountID string)
 	return true, nil
 }
 
+// OidcProvider is the configuration of an OIDC provider: the URL of the issuer without the
+// protocol, the audiences allowed to use it and the thumbprints of the certificate of the issuer.
+type OidcProvider struct {
+	ARN         string
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+}
+
+func (c *awsClient) GetOpenIDConnectProvider(oidcProviderARN string) (*OidcProvider, error) {
+	output, err := c.iamClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(oidcProviderARN),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OidcProvider{
+		ARN:         oidcProviderARN,
+		URL:         aws.StringValue(output.Url),
+		ClientIDs:   aws.StringValueSlice(output.ClientIDList),
+		Thumbprints: aws.StringValueSlice(output.ThumbprintList),
+	}, nil
+}
+
 func (c *awsClient) DeleteOpenIDConnectProvider(oidcProviderARN string) error {
 	_, err := c.iamClient.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
 		OpenIDConnectProviderArn: aws.String(oidcProviderARN),
@@ -0,0 +1,553 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the local preflight of the subnets of a cluster installed in an existing VPC.
+
+package aws
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Checks done by the network preflight.
+const (
+	NetworkCheckSubnets      = "subnets"
+	NetworkCheckMachineCIDR  = "machine-cidr"
+	NetworkCheckCIDROverlap  = "cidr-overlap"
+	NetworkCheckSubnetLayout = "subnet-layout"
+	NetworkCheckEgress       = "egress"
+	NetworkCheckDNS          = "dns"
+	NetworkCheckTags         = "tags"
+	NetworkCheckFreeIPs      = "free-ips"
+)
+
+// NetworkSeverity tells if a finding of the network preflight prevents the cluster from being
+// installed, or only may cause problems.
+type NetworkSeverity string
+
+const (
+	NetworkSeverityError   NetworkSeverity = "error"
+	NetworkSeverityWarning NetworkSeverity = "warning"
+)
+
+// Tags used by the load balancers of the cluster to select the subnets.
+const (
+	ElbRoleTag         = "kubernetes.io/role/elb"
+	InternalElbRoleTag = "kubernetes.io/role/internal-elb"
+)
+
+// Nodes and addresses that a cluster places in the subnets besides the compute nodes.
+const (
+	networkControlPlaneNodes   = 3
+	networkSingleAZInfraNodes  = 2
+	networkMultiAZInfraNodes   = 3
+	loadBalancerFreeIPs        = 8
+	multiAZAvailabilityZones   = 3
+	defaultRouteDestinationIPs = "0.0.0.0/0"
+)
+
+// NetworkPreflightOptions describe the network of the cluster whose subnets are checked.
+type NetworkPreflightOptions struct {
+	SubnetIDs []string
+
+	// The CIDRs aren't checked when they are nil.
+	MachineCIDR *net.IPNet
+	ServiceCIDR *net.IPNet
+	PodCIDR     *net.IPNet
+
+	PrivateLink bool
+	Hypershift  bool
+
+	// ComputeNodes is the number of compute nodes. The control plane and infra nodes of classic
+	// clusters are added to it to check the free IPs of the private subnets.
+	ComputeNodes int
+}
+
+// NetworkFinding is a problem found by the network preflight, with the way to fix it.
+type NetworkFinding struct {
+	Severity    NetworkSeverity `json:"severity"`
+	Check       string          `json:"check"`
+	SubnetID    string          `json:"subnet_id,omitempty"`
+	Message     string          `json:"message"`
+	Remediation string          `json:"remediation"`
+}
+
+// HasNetworkErrors checks if any of the findings is an error.
+func HasNetworkErrors(findings []*NetworkFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == NetworkSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// networkSubnet is a subnet with the information that the checks need.
+type networkSubnet struct {
+	subnet     *ec2.Subnet
+	id         string
+	zone       string
+	public     bool
+	routeTable *ec2.RouteTable
+}
+
+// ValidateNetwork checks the subnets of a cluster installed in an existing VPC with the EC2 API,
+// before the cluster is created. It returns the problems found, an error is only returned when
+// the subnets or the route tables can't be described.
+func (c *awsClient) ValidateNetwork(options NetworkPreflightOptions) ([]*NetworkFinding, error) {
+	findings := []*NetworkFinding{}
+	if len(options.SubnetIDs) == 0 {
+		return findings, nil
+	}
+	subnets, err := c.getSubnetIDs(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(options.SubnetIDs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	found := map[string]bool{}
+	vpcs := map[string]bool{}
+	for _, subnet := range subnets {
+		found[aws.StringValue(subnet.SubnetId)] = true
+		vpcs[aws.StringValue(subnet.VpcId)] = true
+	}
+	for _, subnetID := range options.SubnetIDs {
+		if !found[subnetID] {
+			findings = append(findings, &NetworkFinding{
+				Severity:    NetworkSeverityError,
+				Check:       NetworkCheckSubnets,
+				SubnetID:    subnetID,
+				Message:     fmt.Sprintf("Subnet '%s' doesn't exist in the region", subnetID),
+				Remediation: "Check the subnet IDs and the region of the cluster",
+			})
+		}
+	}
+	if len(vpcs) != 1 {
+		if len(vpcs) > 1 {
+			findings = append(findings, &NetworkFinding{
+				Severity:    NetworkSeverityError,
+				Check:       NetworkCheckSubnets,
+				Message:     fmt.Sprintf("The subnets belong to %d different VPCs", len(vpcs)),
+				Remediation: "Choose subnets of a single VPC",
+			})
+		}
+		return findings, nil
+	}
+	vpcID := aws.StringValue(subnets[0].VpcId)
+
+	routeTables, err := c.ec2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(vpcID)},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe route tables of VPC '%s': %v", vpcID, err)
+	}
+	networkSubnets := make([]*networkSubnet, 0, len(subnets))
+	for _, subnet := range subnets {
+		routeTable, err := c.getSubnetRouteTable(subnet.SubnetId, routeTables.RouteTables)
+		if err != nil {
+			return nil, err
+		}
+		public, err := c.isPublicSubnet(subnet.SubnetId, routeTables.RouteTables)
+		if err != nil {
+			return nil, err
+		}
+		networkSubnets = append(networkSubnets, &networkSubnet{
+			subnet:     subnet,
+			id:         aws.StringValue(subnet.SubnetId),
+			zone:       aws.StringValue(subnet.AvailabilityZone),
+			public:     public,
+			routeTable: routeTable,
+		})
+	}
+
+	findings = append(findings, checkSubnetCIDRs(networkSubnets, options)...)
+	findings = append(findings, c.checkVpcCIDRs(vpcID, options)...)
+	findings = append(findings, checkSubnetLayout(networkSubnets, options)...)
+	findings = append(findings, checkEgress(networkSubnets)...)
+	findings = append(findings, c.checkVpcDNS(vpcID)...)
+	findings = append(findings, checkLoadBalancerTags(networkSubnets, options)...)
+	findings = append(findings, checkFreeIPs(networkSubnets, options)...)
+	return findings, nil
+}
+
+// GetVPCCIDR returns the primary CIDR of a VPC. When the VPC identifier is empty the VPC is the one
+// of the given subnets, which must all belong to the same VPC.
+func (c *awsClient) GetVPCCIDR(vpcID string, subnetIDs []string) (*net.IPNet, error) {
+	if vpcID == "" {
+		if len(subnetIDs) == 0 {
+			return nil, fmt.Errorf("Either a VPC or a subnet is required")
+		}
+		subnets, err := c.getSubnetIDs(&ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(subnetIDs),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to describe subnets: %v", err)
+		}
+		for _, subnet := range subnets {
+			if vpcID != "" && aws.StringValue(subnet.VpcId) != vpcID {
+				return nil, fmt.Errorf("The subnets belong to different VPCs '%s' and '%s'",
+					vpcID, aws.StringValue(subnet.VpcId))
+			}
+			vpcID = aws.StringValue(subnet.VpcId)
+		}
+	}
+	output, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{VpcIds: []*string{aws.String(vpcID)}})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe VPC '%s': %v", vpcID, err)
+	}
+	if len(output.Vpcs) == 0 {
+		return nil, fmt.Errorf("VPC '%s' doesn't exist", vpcID)
+	}
+	_, network, err := net.ParseCIDR(aws.StringValue(output.Vpcs[0].CidrBlock))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse CIDR of VPC '%s': %v", vpcID, err)
+	}
+	return network, nil
+}
+
+// checkSubnetCIDRs checks that the machine CIDR contains the subnets.
+func checkSubnetCIDRs(subnets []*networkSubnet, options NetworkPreflightOptions) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	if options.MachineCIDR == nil {
+		return findings
+	}
+	for _, subnet := range subnets {
+		_, subnetNetwork, err := net.ParseCIDR(aws.StringValue(subnet.subnet.CidrBlock))
+		if err != nil {
+			continue
+		}
+		if !containsNetwork(options.MachineCIDR, subnetNetwork) {
+			findings = append(findings, &NetworkFinding{
+				Severity: NetworkSeverityError,
+				Check:    NetworkCheckMachineCIDR,
+				SubnetID: subnet.id,
+				Message: fmt.Sprintf("The CIDR '%s' of subnet '%s' isn't part of the machine CIDR '%s'",
+					subnetNetwork, subnet.id, options.MachineCIDR),
+				Remediation: "Use a machine CIDR that contains the CIDRs of all the subnets, usually the " +
+					"CIDR of the VPC",
+			})
+		}
+	}
+	return findings
+}
+
+// checkVpcCIDRs checks that the service and pod CIDRs don't overlap the CIDR of the VPC.
+func (c *awsClient) checkVpcCIDRs(vpcID string, options NetworkPreflightOptions) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	if options.ServiceCIDR == nil && options.PodCIDR == nil {
+		return findings
+	}
+	output, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{VpcIds: []*string{aws.String(vpcID)}})
+	if err != nil || len(output.Vpcs) == 0 {
+		return append(findings, &NetworkFinding{
+			Severity:    NetworkSeverityWarning,
+			Check:       NetworkCheckCIDROverlap,
+			Message:     fmt.Sprintf("Unable to describe VPC '%s' to check its CIDR: %v", vpcID, err),
+			Remediation: "Check that the service and pod CIDRs don't overlap the CIDR of the VPC",
+		})
+	}
+	_, vpcNetwork, err := net.ParseCIDR(aws.StringValue(output.Vpcs[0].CidrBlock))
+	if err != nil {
+		return findings
+	}
+	for _, cidr := range []struct {
+		name    string
+		network *net.IPNet
+	}{
+		{"service", options.ServiceCIDR},
+		{"pod", options.PodCIDR},
+	} {
+		if cidr.network != nil && overlaps(cidr.network, vpcNetwork) {
+			findings = append(findings, &NetworkFinding{
+				Severity: NetworkSeverityError,
+				Check:    NetworkCheckCIDROverlap,
+				Message: fmt.Sprintf("The %s CIDR '%s' overlaps the CIDR '%s' of VPC '%s'",
+					cidr.name, cidr.network, vpcNetwork, vpcID),
+				Remediation: fmt.Sprintf("Choose a %s CIDR outside of the VPC", cidr.name),
+			})
+		}
+	}
+	return findings
+}
+
+// checkSubnetLayout checks that there are public and private subnets in each availability zone,
+// or only private subnets for PrivateLink clusters.
+func checkSubnetLayout(subnets []*networkSubnet, options NetworkPreflightOptions) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	if options.PrivateLink {
+		for _, subnet := range subnets {
+			if subnet.public {
+				findings = append(findings, &NetworkFinding{
+					Severity: NetworkSeverityError,
+					Check:    NetworkCheckSubnetLayout,
+					SubnetID: subnet.id,
+					Message: fmt.Sprintf("Subnet '%s' is public, but PrivateLink clusters only use private "+
+						"subnets", subnet.id),
+					Remediation: "Remove the public subnets from the subnet IDs",
+				})
+			}
+		}
+		return findings
+	}
+
+	zones := []string{}
+	public := map[string]bool{}
+	private := map[string]bool{}
+	for _, subnet := range subnets {
+		if !public[subnet.zone] && !private[subnet.zone] {
+			zones = append(zones, subnet.zone)
+		}
+		if subnet.public {
+			public[subnet.zone] = true
+		} else {
+			private[subnet.zone] = true
+		}
+	}
+	if options.Hypershift {
+		// The nodes of hosted clusters only use private subnets, a public subnet is needed for
+		// the load balancers
+		if len(public) == 0 {
+			findings = append(findings, &NetworkFinding{
+				Severity:    NetworkSeverityError,
+				Check:       NetworkCheckSubnetLayout,
+				Message:     "Public hosted clusters need at least one public subnet",
+				Remediation: "Add a public subnet to the subnet IDs, or create a private cluster",
+			})
+		}
+		if len(private) == 0 {
+			findings = append(findings, &NetworkFinding{
+				Severity:    NetworkSeverityError,
+				Check:       NetworkCheckSubnetLayout,
+				Message:     "Hosted clusters need at least one private subnet for the nodes",
+				Remediation: "Add a private subnet to the subnet IDs",
+			})
+		}
+		return findings
+	}
+	for _, zone := range zones {
+		missing := ""
+		if !public[zone] {
+			missing = "public"
+		} else if !private[zone] {
+			missing = "private"
+		}
+		if missing != "" {
+			findings = append(findings, &NetworkFinding{
+				Severity: NetworkSeverityError,
+				Check:    NetworkCheckSubnetLayout,
+				Message:  fmt.Sprintf("There is no %s subnet in availability zone '%s'", missing, zone),
+				Remediation: fmt.Sprintf("Add a %s subnet of availability zone '%s' to the subnet IDs. "+
+					"Clusters that aren't PrivateLink need a public and a private subnet in each "+
+					"availability zone", missing, zone),
+			})
+		}
+	}
+	return findings
+}
+
+// checkEgress checks that the route tables of the private subnets have a default route, usually to
+// a NAT gateway.
+func checkEgress(subnets []*networkSubnet) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	for _, subnet := range subnets {
+		if subnet.public || hasDefaultRoute(subnet.routeTable) {
+			continue
+		}
+		findings = append(findings, &NetworkFinding{
+			Severity: NetworkSeverityWarning,
+			Check:    NetworkCheckEgress,
+			SubnetID: subnet.id,
+			Message: fmt.Sprintf("Route table '%s' of private subnet '%s' has no default route",
+				aws.StringValue(subnet.routeTable.RouteTableId), subnet.id),
+			Remediation: fmt.Sprintf("Add a route to %s through a NAT gateway or a transit gateway, "+
+				"unless the egress traffic of the cluster goes through a proxy", defaultRouteDestinationIPs),
+		})
+	}
+	return findings
+}
+
+func hasDefaultRoute(routeTable *ec2.RouteTable) bool {
+	for _, route := range routeTable.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) != defaultRouteDestinationIPs ||
+			aws.StringValue(route.State) == "blackhole" {
+			continue
+		}
+		if route.NatGatewayId != nil || route.TransitGatewayId != nil || route.InstanceId != nil ||
+			route.NetworkInterfaceId != nil || route.VpcPeeringConnectionId != nil || route.GatewayId != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVpcDNS checks that the DNS support and DNS hostnames attributes of the VPC are enabled.
+func (c *awsClient) checkVpcDNS(vpcID string) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	for _, attribute := range []struct {
+		name   string
+		option string
+	}{
+		{ec2.VpcAttributeNameEnableDnsSupport, "--enable-dns-support"},
+		{ec2.VpcAttributeNameEnableDnsHostnames, "--enable-dns-hostnames"},
+	} {
+		output, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			Attribute: aws.String(attribute.name),
+			VpcId:     aws.String(vpcID),
+		})
+		if err != nil {
+			findings = append(findings, &NetworkFinding{
+				Severity:    NetworkSeverityWarning,
+				Check:       NetworkCheckDNS,
+				Message:     fmt.Sprintf("Unable to check attribute '%s' of VPC '%s': %v", attribute.name, vpcID, err),
+				Remediation: fmt.Sprintf("Check that attribute '%s' of the VPC is enabled", attribute.name),
+			})
+			continue
+		}
+		value := output.EnableDnsSupport
+		if attribute.name == ec2.VpcAttributeNameEnableDnsHostnames {
+			value = output.EnableDnsHostnames
+		}
+		if value == nil || !aws.BoolValue(value.Value) {
+			findings = append(findings, &NetworkFinding{
+				Severity: NetworkSeverityError,
+				Check:    NetworkCheckDNS,
+				Message:  fmt.Sprintf("Attribute '%s' of VPC '%s' isn't enabled", attribute.name, vpcID),
+				Remediation: fmt.Sprintf("aws ec2 modify-vpc-attribute --vpc-id %s %s '{\"Value\":true}'",
+					vpcID, attribute.option),
+			})
+		}
+	}
+	return findings
+}
+
+// checkLoadBalancerTags checks that the subnets have the tags used to place the public and the
+// internal load balancers. They are mandatory for the public subnets of hosted clusters.
+func checkLoadBalancerTags(subnets []*networkSubnet, options NetworkPreflightOptions) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	for _, subnet := range subnets {
+		tag := InternalElbRoleTag
+		severity := NetworkSeverityWarning
+		if subnet.public {
+			tag = ElbRoleTag
+			if options.Hypershift {
+				severity = NetworkSeverityError
+			}
+		}
+		if hasTag(subnet.subnet.Tags, tag) {
+			continue
+		}
+		findings = append(findings, &NetworkFinding{
+			Severity: severity,
+			Check:    NetworkCheckTags,
+			SubnetID: subnet.id,
+			Message:  fmt.Sprintf("Subnet '%s' doesn't have tag '%s'", subnet.id, tag),
+			Remediation: fmt.Sprintf("aws ec2 create-tags --resources %s --tags Key=%s,Value=1",
+				subnet.id, tag),
+		})
+	}
+	return findings
+}
+
+func hasTag(tags []*ec2.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFreeIPs checks that the private subnets have enough free IPs for the nodes, which are
+// spread across them, and that every subnet can hold load balancers.
+func checkFreeIPs(subnets []*networkSubnet, options NetworkPreflightOptions) []*NetworkFinding {
+	findings := []*NetworkFinding{}
+	privateSubnets := 0
+	zones := map[string]bool{}
+	for _, subnet := range subnets {
+		if !subnet.public {
+			privateSubnets++
+			zones[subnet.zone] = true
+		}
+	}
+	nodes := options.ComputeNodes
+	if !options.Hypershift {
+		nodes += networkControlPlaneNodes + networkSingleAZInfraNodes
+		if len(zones) >= multiAZAvailabilityZones {
+			nodes += networkMultiAZInfraNodes - networkSingleAZInfraNodes
+		}
+	}
+	nodesPerSubnet := 0
+	if privateSubnets > 0 {
+		nodesPerSubnet = int(math.Ceil(float64(nodes) / float64(privateSubnets)))
+	}
+
+	for _, subnet := range subnets {
+		required := loadBalancerFreeIPs
+		if !subnet.public {
+			required += nodesPerSubnet
+		}
+		free := int(aws.Int64Value(subnet.subnet.AvailableIpAddressCount))
+		if free >= required {
+			continue
+		}
+		findings = append(findings, &NetworkFinding{
+			Severity: NetworkSeverityError,
+			Check:    NetworkCheckFreeIPs,
+			SubnetID: subnet.id,
+			Message: fmt.Sprintf("Subnet '%s' has %d free IPs, but %d are needed for %s", subnet.id, free,
+				required, freeIPsUse(subnet.public, nodesPerSubnet)),
+			Remediation: "Free IPs of the subnet, use a larger subnet, or spread the nodes across more subnets",
+		})
+	}
+	return findings
+}
+
+func freeIPsUse(public bool, nodes int) string {
+	if public {
+		return "the load balancers"
+	}
+	return fmt.Sprintf("%d nodes and the load balancers", nodes)
+}
+
+// containsNetwork checks if the outer network contains all the addresses of the inner network.
+func containsNetwork(outer *net.IPNet, inner *net.IPNet) bool {
+	outerSize, _ := outer.Mask.Size()
+	innerSize, _ := inner.Mask.Size()
+	return outer.Contains(inner.IP) && innerSize >= outerSize
+}
+
+// overlaps checks if two networks have addresses in common.
+func overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// DescribeNetworkFindings returns a line for each finding, with its severity and remediation.
+func DescribeNetworkFindings(findings []*NetworkFinding) string {
+	lines := make([]string, len(findings))
+	for i, finding := range findings {
+		lines[i] = fmt.Sprintf("- %s: %s\n  To fix it: %s", finding.Severity, finding.Message, finding.Remediation)
+	}
+	return strings.Join(lines, "\n")
+}
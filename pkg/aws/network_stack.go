@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that manage the VPCs created from the network template.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/helper"
+)
+
+// NetworkTemplatePath is the path of the embedded template used to create networks.
+const NetworkTemplatePath = "templates/cloudformation/rosa_network.json"
+
+// Names of the outputs of the network template.
+const (
+	networkOutputVpcID            = "VpcId"
+	networkOutputPrivateSubnetIDs = "PrivateSubnetIds"
+	networkOutputPublicSubnetIDs  = "PublicSubnetIds"
+)
+
+// Limits of the prefix of the VPC CIDR, the VPC is split in 6 subnets of 1/8 of its size, and
+// AWS doesn't accept subnets smaller than /28.
+const (
+	NetworkPrefixMin = 16
+	NetworkPrefixMax = 25
+)
+
+// NetworkStackOptions contains the options used to create a network from the template.
+type NetworkStackOptions struct {
+	Name        string
+	CIDR        *net.IPNet
+	MultiAZ     bool
+	PrivateLink bool
+	Egress      bool
+}
+
+// NetworkStack is a network created from the template.
+type NetworkStack struct {
+	Name             string   `json:"name"`
+	StackName        string   `json:"stack_name"`
+	Status           string   `json:"status"`
+	VpcID            string   `json:"vpc_id"`
+	PrivateSubnetIDs []string `json:"private_subnet_ids"`
+	PublicSubnetIDs  []string `json:"public_subnet_ids"`
+}
+
+// SubnetIDs returns the subnets to use with the '--subnet-ids' option when creating a cluster.
+// Clusters that use PrivateLink are only installed into the private subnets.
+func (s *NetworkStack) SubnetIDs(privateLink bool) []string {
+	if privateLink {
+		return s.PrivateSubnetIDs
+	}
+	return append(append([]string{}, s.PrivateSubnetIDs...), s.PublicSubnetIDs...)
+}
+
+// NetworkStackName returns the name of the cloudformation stack of a network.
+func NetworkStackName(name string) string {
+	return fmt.Sprintf("rosa-network-%s", name)
+}
+
+// NetworkTemplate returns the body of the embedded network template.
+func NetworkTemplate() (string, error) {
+	return readCloudFormationTemplate(NetworkTemplatePath)
+}
+
+// Parameters returns the values of the parameters of the network template.
+func (o *NetworkStackOptions) Parameters() (map[string]string, error) {
+	if o.Name == "" {
+		return nil, fmt.Errorf("A name is required for the network")
+	}
+	if o.CIDR == nil || o.CIDR.IP.To4() == nil {
+		return nil, fmt.Errorf("An IPv4 CIDR is required for the network")
+	}
+	prefix, _ := o.CIDR.Mask.Size()
+	if prefix < NetworkPrefixMin || prefix > NetworkPrefixMax {
+		return nil, fmt.Errorf("Invalid network CIDR '%s': the prefix length should be between %d and %d",
+			o.CIDR, NetworkPrefixMin, NetworkPrefixMax)
+	}
+	if !o.Egress && !o.PrivateLink {
+		return nil, fmt.Errorf("Networks without egress have no public subnets, they can only be used " +
+			"by clusters that use PrivateLink")
+	}
+	availabilityZones := "1"
+	if o.MultiAZ {
+		availabilityZones = "3"
+	}
+	return map[string]string{
+		"Name":              o.Name,
+		"VpcCidr":           o.CIDR.String(),
+		"SubnetBits":        fmt.Sprintf("%d", 32-prefix-3),
+		"AvailabilityZones": availabilityZones,
+		"PrivateLink":       fmt.Sprintf("%t", o.PrivateLink),
+		"Egress":            fmt.Sprintf("%t", o.Egress),
+	}, nil
+}
+
+// CreateNetworkStack creates a network from the template and waits until it is ready.
+func (c *awsClient) CreateNetworkStack(options NetworkStackOptions) (*NetworkStack, error) {
+	parameters, err := options.Parameters()
+	if err != nil {
+		return nil, err
+	}
+	stackName := NetworkStackName(options.Name)
+	existing, err := c.GetNetworkStack(options.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("Network '%s' already exists with status %s", options.Name, existing.Status)
+	}
+	body, err := NetworkTemplate()
+	if err != nil {
+		return nil, err
+	}
+	input := &cloudformation.CreateStackInput{
+		StackName:    aws.String(stackName),
+		TemplateBody: aws.String(body),
+		Tags: []*cloudformation.Tag{
+			{Key: aws.String(tags.NetworkName), Value: aws.String(options.Name)},
+			{Key: aws.String(tags.RedHatManaged), Value: aws.String("true")},
+		},
+	}
+	keys := helper.MapKeys(parameters)
+	sort.Strings(keys)
+	for _, key := range keys {
+		input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+			ParameterKey:   aws.String(key),
+			ParameterValue: aws.String(parameters[key]),
+		})
+	}
+	_, err = c.createStack(input)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create stack '%s': %v", stackName, err)
+	}
+	return c.GetNetworkStack(options.Name)
+}
+
+// GetNetworkStack returns the network with the given name, or nil if it doesn't exist.
+func (c *awsClient) GetNetworkStack(name string) (*NetworkStack, error) {
+	stackName := NetworkStackName(name)
+	output, err := c.cfClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ValidationError" &&
+			strings.Contains(aerr.Message(), "does not exist") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to describe stack '%s': %v", stackName, err)
+	}
+	if len(output.Stacks) == 0 ||
+		aws.StringValue(output.Stacks[0].StackStatus) == cloudformation.StackStatusDeleteComplete {
+		return nil, nil
+	}
+	stack := output.Stacks[0]
+	network := &NetworkStack{
+		Name:      name,
+		StackName: stackName,
+		Status:    aws.StringValue(stack.StackStatus),
+	}
+	for _, stackOutput := range stack.Outputs {
+		value := aws.StringValue(stackOutput.OutputValue)
+		switch aws.StringValue(stackOutput.OutputKey) {
+		case networkOutputVpcID:
+			network.VpcID = value
+		case networkOutputPrivateSubnetIDs:
+			network.PrivateSubnetIDs = splitOutput(value)
+		case networkOutputPublicSubnetIDs:
+			network.PublicSubnetIDs = splitOutput(value)
+		}
+	}
+	return network, nil
+}
+
+func splitOutput(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,134 @@
+package aws_test
+
+import (
+	"encoding/json"
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Network stack", func() {
+	var (
+		client    aws.Client
+		mockCtrl  *gomock.Controller
+		mockCFAPI *mocks.MockCloudFormationAPI
+		options   aws.NetworkStackOptions
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCFAPI = mocks.NewMockCloudFormationAPI(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mocks.NewMockIAMAPI(mockCtrl),
+			mocks.NewMockEC2API(mockCtrl),
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockS3API(mockCtrl),
+			mocks.NewMockSecretsManagerAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mockCFAPI,
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			&session.Session{},
+			&aws.AccessKey{},
+			false,
+		)
+		_, cidr, err := net.ParseCIDR("10.0.0.0/16")
+		Expect(err).ToNot(HaveOccurred())
+		options = aws.NetworkStackOptions{Name: "mynetwork", CIDR: cidr, MultiAZ: true, Egress: true}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Embeds a valid template with the expected parameters and outputs", func() {
+		body, err := aws.NetworkTemplate()
+		Expect(err).ToNot(HaveOccurred())
+		template := struct {
+			Parameters map[string]interface{}
+			Outputs    map[string]interface{}
+		}{}
+		Expect(json.Unmarshal([]byte(body), &template)).To(Succeed())
+		parameters, err := options.Parameters()
+		Expect(err).ToNot(HaveOccurred())
+		for name := range parameters {
+			Expect(template.Parameters).To(HaveKey(name))
+		}
+		Expect(template.Outputs).To(HaveKey("VpcId"))
+		Expect(template.Outputs).To(HaveKey("PrivateSubnetIds"))
+		Expect(template.Outputs).To(HaveKey("PublicSubnetIds"))
+	})
+
+	It("Splits the VPC in subnets of an eighth of its size", func() {
+		parameters, err := options.Parameters()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameters).To(Equal(map[string]string{
+			"Name":              "mynetwork",
+			"VpcCidr":           "10.0.0.0/16",
+			"SubnetBits":        "13",
+			"AvailabilityZones": "3",
+			"PrivateLink":       "false",
+			"Egress":            "true",
+		}))
+	})
+
+	It("Rejects networks without egress for clusters without PrivateLink", func() {
+		options.Egress = false
+		_, err := options.Parameters()
+		Expect(err).To(MatchError(ContainSubstring("can only be used by clusters that use PrivateLink")))
+	})
+
+	It("Rejects VPC CIDRs that are too small for the subnets", func() {
+		_, options.CIDR, _ = net.ParseCIDR("10.0.0.0/26")
+		_, err := options.Parameters()
+		Expect(err).To(MatchError(ContainSubstring("should be between 16 and 25")))
+	})
+
+	It("Reads the subnets from the outputs of the stack", func() {
+		mockCFAPI.EXPECT().DescribeStacks(&cloudformation.DescribeStacksInput{
+			StackName: awssdk.String("rosa-network-mynetwork"),
+		}).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{{
+				StackStatus: awssdk.String(cloudformation.StackStatusCreateComplete),
+				Outputs: []*cloudformation.Output{
+					{OutputKey: awssdk.String("VpcId"), OutputValue: awssdk.String("vpc-1")},
+					{OutputKey: awssdk.String("PrivateSubnetIds"), OutputValue: awssdk.String("subnet-1,subnet-2")},
+					{OutputKey: awssdk.String("PublicSubnetIds"), OutputValue: awssdk.String("subnet-3,subnet-4")},
+				},
+			}},
+		}, nil)
+		network, err := client.GetNetworkStack("mynetwork")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(network.VpcID).To(Equal("vpc-1"))
+		Expect(network.SubnetIDs(false)).To(Equal([]string{"subnet-1", "subnet-2", "subnet-3", "subnet-4"}))
+		Expect(network.SubnetIDs(true)).To(Equal([]string{"subnet-1", "subnet-2"}))
+	})
+
+	It("Refuses to create a network that already exists", func() {
+		mockCFAPI.EXPECT().DescribeStacks(gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{{
+				StackStatus: awssdk.String(cloudformation.StackStatusRollbackComplete),
+			}},
+		}, nil)
+		_, err := client.CreateNetworkStack(options)
+		Expect(err).To(MatchError("Network 'mynetwork' already exists with status ROLLBACK_COMPLETE"))
+	})
+
+	It("Returns nothing for networks that don't exist", func() {
+		mockCFAPI.EXPECT().DescribeStacks(gomock.Any()).Return(nil,
+			awserr.New("ValidationError", "Stack with id rosa-network-mynetwork does not exist", nil))
+		network, err := client.GetNetworkStack("mynetwork")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(network).To(BeNil())
+	})
+})
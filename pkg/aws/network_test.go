@@ -0,0 +1,199 @@
+package aws_test
+
+import (
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Network preflight", func() {
+	var (
+		client     aws.Client
+		mockCtrl   *gomock.Controller
+		mockEC2API *mocks.MockEC2API
+	)
+
+	mustParseCIDR := func(value string) *net.IPNet {
+		_, network, err := net.ParseCIDR(value)
+		Expect(err).ToNot(HaveOccurred())
+		return network
+	}
+	subnet := func(id string, zone string, cidr string, free int64, tag string) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:                awssdk.String(id),
+			VpcId:                   awssdk.String("vpc-1"),
+			AvailabilityZone:        awssdk.String(zone),
+			CidrBlock:               awssdk.String(cidr),
+			AvailableIpAddressCount: awssdk.Int64(free),
+			Tags:                    []*ec2.Tag{{Key: awssdk.String(tag), Value: awssdk.String("1")}},
+		}
+	}
+	routeTable := func(id string, subnetID string, route *ec2.Route) *ec2.RouteTable {
+		return &ec2.RouteTable{
+			RouteTableId: awssdk.String(id),
+			Associations: []*ec2.RouteTableAssociation{{SubnetId: awssdk.String(subnetID)}},
+			Routes:       []*ec2.Route{route},
+		}
+	}
+	expectDNS := func(support bool, hostnames bool) {
+		mockEC2API.EXPECT().DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			Attribute: awssdk.String(ec2.VpcAttributeNameEnableDnsSupport),
+			VpcId:     awssdk.String("vpc-1"),
+		}).Return(&ec2.DescribeVpcAttributeOutput{
+			EnableDnsSupport: &ec2.AttributeBooleanValue{Value: awssdk.Bool(support)},
+		}, nil)
+		mockEC2API.EXPECT().DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			Attribute: awssdk.String(ec2.VpcAttributeNameEnableDnsHostnames),
+			VpcId:     awssdk.String("vpc-1"),
+		}).Return(&ec2.DescribeVpcAttributeOutput{
+			EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: awssdk.Bool(hostnames)},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEC2API = mocks.NewMockEC2API(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mocks.NewMockIAMAPI(mockCtrl),
+			mockEC2API,
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockS3API(mockCtrl),
+			mocks.NewMockSecretsManagerAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mocks.NewMockCloudFormationAPI(mockCtrl),
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			&session.Session{},
+			&aws.AccessKey{},
+			false,
+		)
+		mockEC2API.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: awssdk.String("vpc-1"), CidrBlock: awssdk.String("10.0.0.0/16")}},
+		}, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Doesn't report findings for a correct network", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				subnet("subnet-public", "us-east-1a", "10.0.0.0/24", 200, aws.ElbRoleTag),
+				subnet("subnet-private", "us-east-1a", "10.0.1.0/24", 200, aws.InternalElbRoleTag),
+			},
+		}, nil)
+		mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{
+			RouteTables: []*ec2.RouteTable{
+				routeTable("rtb-public", "subnet-public", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+					GatewayId:            awssdk.String("igw-1"),
+				}),
+				routeTable("rtb-private", "subnet-private", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+					NatGatewayId:         awssdk.String("nat-1"),
+				}),
+			},
+		}, nil)
+		expectDNS(true, true)
+
+		findings, err := client.ValidateNetwork(aws.NetworkPreflightOptions{
+			SubnetIDs:    []string{"subnet-public", "subnet-private"},
+			MachineCIDR:  mustParseCIDR("10.0.0.0/16"),
+			ServiceCIDR:  mustParseCIDR("172.30.0.0/16"),
+			PodCIDR:      mustParseCIDR("10.128.0.0/14"),
+			ComputeNodes: 2,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("Reports the problems of the subnets and the VPC", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				subnet("subnet-public", "us-east-1a", "10.0.0.0/24", 200, "Name"),
+				subnet("subnet-private-a", "us-east-1a", "10.0.1.0/24", 10, aws.InternalElbRoleTag),
+				subnet("subnet-private-b", "us-east-1b", "10.1.2.0/24", 200, aws.InternalElbRoleTag),
+			},
+		}, nil)
+		mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{
+			RouteTables: []*ec2.RouteTable{
+				routeTable("rtb-public", "subnet-public", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+					GatewayId:            awssdk.String("igw-1"),
+				}),
+				routeTable("rtb-private-a", "subnet-private-a", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+					NatGatewayId:         awssdk.String("nat-1"),
+				}),
+				routeTable("rtb-private-b", "subnet-private-b", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("10.0.0.0/16"),
+					GatewayId:            awssdk.String("local"),
+				}),
+			},
+		}, nil)
+		expectDNS(true, false)
+
+		findings, err := client.ValidateNetwork(aws.NetworkPreflightOptions{
+			SubnetIDs:    []string{"subnet-public", "subnet-private-a", "subnet-private-b"},
+			MachineCIDR:  mustParseCIDR("10.0.0.0/16"),
+			ServiceCIDR:  mustParseCIDR("172.30.0.0/16"),
+			PodCIDR:      mustParseCIDR("10.0.0.0/14"),
+			ComputeNodes: 6,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.HasNetworkErrors(findings)).To(BeTrue())
+
+		checks := []string{}
+		for _, finding := range findings {
+			checks = append(checks, finding.Check+"/"+string(finding.Severity)+"/"+finding.SubnetID)
+		}
+		Expect(checks).To(Equal([]string{
+			"machine-cidr/error/subnet-private-b",
+			"cidr-overlap/error/",
+			"subnet-layout/error/",
+			"egress/warning/subnet-private-b",
+			"dns/error/",
+			"tags/warning/subnet-public",
+			"free-ips/error/subnet-private-a",
+		}))
+		Expect(findings[2].Message).To(ContainSubstring("no public subnet in availability zone 'us-east-1b'"))
+		Expect(findings[4].Remediation).To(ContainSubstring("--enable-dns-hostnames"))
+		Expect(findings[6].Message).To(ContainSubstring("has 10 free IPs, but 14 are needed for 6 nodes"))
+	})
+
+	It("Reports the public subnets of PrivateLink clusters", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				subnet("subnet-public", "us-east-1a", "10.0.0.0/24", 200, aws.ElbRoleTag),
+			},
+		}, nil)
+		mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{
+			RouteTables: []*ec2.RouteTable{
+				routeTable("rtb-public", "subnet-public", &ec2.Route{
+					DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+					GatewayId:            awssdk.String("igw-1"),
+				}),
+			},
+		}, nil)
+		expectDNS(true, true)
+
+		findings, err := client.ValidateNetwork(aws.NetworkPreflightOptions{
+			SubnetIDs:   []string{"subnet-public"},
+			PrivateLink: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Check).To(Equal(aws.NetworkCheckSubnetLayout))
+		Expect(findings[0].SubnetID).To(Equal("subnet-public"))
+	})
+})
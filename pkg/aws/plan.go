@@ -0,0 +1,544 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the client used by the plan mode, which records the changes to IAM resources
+// instead of applying them.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+)
+
+// Plan is the list of changes to IAM resources that a command would make.
+type Plan struct {
+	AccountID  string           `json:"account_id"`
+	Operations []*PlanOperation `json:"operations"`
+
+	// PolicyDiffs are the changes to the permissions of the policies that are upgraded.
+	PolicyDiffs []*PolicyDiff `json:"policy_diffs,omitempty"`
+}
+
+// PlanOperation is a change to an IAM resource. The action is the name of the equivalent AWS CLI
+// command, for example 'create-role'.
+type PlanOperation struct {
+	Action              awscb.Command     `json:"action"`
+	RoleName            string            `json:"role_name,omitempty"`
+	PolicyName          string            `json:"policy_name,omitempty"`
+	PolicyARN           string            `json:"policy_arn,omitempty"`
+	VersionID           string            `json:"version_id,omitempty"`
+	ProviderARN         string            `json:"provider_arn,omitempty"`
+	URL                 string            `json:"url,omitempty"`
+	UserName            string            `json:"user_name,omitempty"`
+	Path                string            `json:"path,omitempty"`
+	PermissionsBoundary string            `json:"permissions_boundary,omitempty"`
+	SetAsDefault        bool              `json:"set_as_default,omitempty"`
+	Document            json.RawMessage   `json:"document,omitempty"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+// NewPlanClient returns a copy of the given client that records the changes to IAM resources in
+// the returned plan instead of applying them. The same methods, like EnsureRole or
+// DeleteOperatorRole, are used to compute the plan, and the IAM resources are still read from AWS,
+// taking into account the changes already planned.
+func NewPlanClient(client Client, creator *Creator) (Client, *Plan, error) {
+	c, ok := client.(*awsClient)
+	if !ok {
+		return nil, nil, fmt.Errorf("Plan mode isn't supported by this AWS client")
+	}
+	plan := &Plan{
+		AccountID:  creator.AccountID,
+		Operations: []*PlanOperation{},
+	}
+	copy := *c
+	copy.iamClient = &planIAMClient{
+		reader:    c.iamClient,
+		plan:      plan,
+		roles:     map[string]*iam.Role{},
+		policies:  map[string]*iam.Policy{},
+		deleted:   map[string]bool{},
+		attached:  map[string][]*iam.AttachedPolicy{},
+		removed:   map[string]map[string]bool{},
+		detached:  map[string]int{},
+		accountID: creator.AccountID,
+	}
+	return &copy, plan, nil
+}
+
+// planIAMClient records the calls that change IAM resources and passes an explicit list of calls
+// that only read them to the real client. It keeps track of the roles and policies created or
+// deleted by the plan, so that the calls that read them return what they would after applying it.
+type planIAMClient struct {
+	// IAMAPI is always nil. It is only embedded to implement the interface, so that calling any
+	// other method panics instead of changing IAM resources.
+	iamiface.IAMAPI
+
+	// reader is the real client, only used by the methods that read IAM resources:
+	reader iamiface.IAMAPI
+
+	plan      *Plan
+	accountID string
+
+	// roles and policies created by the plan, by name and by ARN:
+	roles    map[string]*iam.Role
+	policies map[string]*iam.Policy
+
+	// deleted contains the names of the deleted roles and the ARNs of the deleted policies:
+	deleted map[string]bool
+
+	// attached and removed contain the policies attached to and detached from each role by the
+	// plan, and detached the number of roles that each existing policy has been detached from:
+	attached map[string][]*iam.AttachedPolicy
+	removed  map[string]map[string]bool
+	detached map[string]int
+}
+
+func (c *planIAMClient) record(operation *PlanOperation) {
+	c.plan.Operations = append(c.plan.Operations, operation)
+}
+
+func noSuchEntity(kind string, name string) error {
+	return awserr.New(iam.ErrCodeNoSuchEntityException,
+		fmt.Sprintf("The %s with name %s cannot be found (deleted by the plan).", kind, name), nil)
+}
+
+func (c *planIAMClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	if c.deleted[name] {
+		return nil, noSuchEntity("role", name)
+	}
+	if role, ok := c.roles[name]; ok {
+		return &iam.GetRoleOutput{Role: role}, nil
+	}
+	return c.reader.GetRole(input)
+}
+
+func (c *planIAMClient) ListRoleTags(input *iam.ListRoleTagsInput) (*iam.ListRoleTagsOutput, error) {
+	if role, ok := c.roles[aws.StringValue(input.RoleName)]; ok {
+		return &iam.ListRoleTagsOutput{Tags: role.Tags}, nil
+	}
+	return c.reader.ListRoleTags(input)
+}
+
+func (c *planIAMClient) ListAttachedRolePolicies(
+	input *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	if c.deleted[name] {
+		return nil, noSuchEntity("role", name)
+	}
+	if _, ok := c.roles[name]; ok {
+		return &iam.ListAttachedRolePoliciesOutput{AttachedPolicies: c.attached[name]}, nil
+	}
+	output, err := c.reader.ListAttachedRolePolicies(input)
+	if err != nil {
+		return output, err
+	}
+	result := []*iam.AttachedPolicy{}
+	for _, policy := range output.AttachedPolicies {
+		if !c.removed[name][aws.StringValue(policy.PolicyArn)] {
+			result = append(result, policy)
+		}
+	}
+	result = append(result, c.attached[name]...)
+	return &iam.ListAttachedRolePoliciesOutput{AttachedPolicies: result}, nil
+}
+
+func (c *planIAMClient) GetPolicy(input *iam.GetPolicyInput) (*iam.GetPolicyOutput, error) {
+	arn := aws.StringValue(input.PolicyArn)
+	if c.deleted[arn] {
+		return nil, noSuchEntity("policy", arn)
+	}
+	if policy, ok := c.policies[arn]; ok {
+		return &iam.GetPolicyOutput{Policy: policy}, nil
+	}
+	output, err := c.reader.GetPolicy(input)
+	if err != nil || output.Policy == nil || c.detached[arn] == 0 {
+		return output, err
+	}
+	policy := *output.Policy
+	policy.AttachmentCount = aws.Int64(aws.Int64Value(policy.AttachmentCount) - int64(c.detached[arn]))
+	return &iam.GetPolicyOutput{Policy: &policy}, nil
+}
+
+func (c *planIAMClient) ListPolicyTags(input *iam.ListPolicyTagsInput) (*iam.ListPolicyTagsOutput, error) {
+	if policy, ok := c.policies[aws.StringValue(input.PolicyArn)]; ok {
+		return &iam.ListPolicyTagsOutput{Tags: policy.Tags}, nil
+	}
+	return c.reader.ListPolicyTags(input)
+}
+
+// The rest of the calls that read IAM resources aren't affected by the plan:
+
+func (c *planIAMClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, error) {
+	return c.reader.GetUser(input)
+}
+
+func (c *planIAMClient) ListUsers(input *iam.ListUsersInput) (*iam.ListUsersOutput, error) {
+	return c.reader.ListUsers(input)
+}
+
+func (c *planIAMClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
+	return c.reader.ListAccessKeys(input)
+}
+
+func (c *planIAMClient) ListRolesPages(input *iam.ListRolesInput,
+	fn func(*iam.ListRolesOutput, bool) bool) error {
+	return c.reader.ListRolesPages(input, fn)
+}
+
+func (c *planIAMClient) ListRolePolicies(input *iam.ListRolePoliciesInput) (*iam.ListRolePoliciesOutput, error) {
+	return c.reader.ListRolePolicies(input)
+}
+
+func (c *planIAMClient) GetRolePolicy(input *iam.GetRolePolicyInput) (*iam.GetRolePolicyOutput, error) {
+	return c.reader.GetRolePolicy(input)
+}
+
+func (c *planIAMClient) ListInstanceProfilesForRole(
+	input *iam.ListInstanceProfilesForRoleInput) (*iam.ListInstanceProfilesForRoleOutput, error) {
+	return c.reader.ListInstanceProfilesForRole(input)
+}
+
+func (c *planIAMClient) ListPoliciesPages(input *iam.ListPoliciesInput,
+	fn func(*iam.ListPoliciesOutput, bool) bool) error {
+	return c.reader.ListPoliciesPages(input, fn)
+}
+
+func (c *planIAMClient) ListPolicyVersions(
+	input *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error) {
+	return c.reader.ListPolicyVersions(input)
+}
+
+func (c *planIAMClient) GetPolicyVersion(input *iam.GetPolicyVersionInput) (*iam.GetPolicyVersionOutput, error) {
+	return c.reader.GetPolicyVersion(input)
+}
+
+func (c *planIAMClient) SimulatePrincipalPolicyPages(input *iam.SimulatePrincipalPolicyInput,
+	fn func(*iam.SimulatePolicyResponse, bool) bool) error {
+	return c.reader.SimulatePrincipalPolicyPages(input, fn)
+}
+
+func (c *planIAMClient) ListOpenIDConnectProviders(
+	input *iam.ListOpenIDConnectProvidersInput) (*iam.ListOpenIDConnectProvidersOutput, error) {
+	return c.reader.ListOpenIDConnectProviders(input)
+}
+
+func (c *planIAMClient) GetOpenIDConnectProvider(
+	input *iam.GetOpenIDConnectProviderInput) (*iam.GetOpenIDConnectProviderOutput, error) {
+	return c.reader.GetOpenIDConnectProvider(input)
+}
+
+func (c *planIAMClient) ListOpenIDConnectProviderTags(
+	input *iam.ListOpenIDConnectProviderTagsInput) (*iam.ListOpenIDConnectProviderTagsOutput, error) {
+	return c.reader.ListOpenIDConnectProviderTags(input)
+}
+
+func (c *planIAMClient) CreateRole(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	path := aws.StringValue(input.Path)
+	if path == "" {
+		path = "/"
+	}
+	role := &iam.Role{
+		RoleName:                 input.RoleName,
+		Path:                     aws.String(path),
+		Arn:                      aws.String(fmt.Sprintf("arn:%s:iam::%s:role%s%s", GetPartition(), c.accountID, path, name)),
+		AssumeRolePolicyDocument: input.AssumeRolePolicyDocument,
+		Tags:                     input.Tags,
+	}
+	if input.PermissionsBoundary != nil {
+		role.PermissionsBoundary = &iam.AttachedPermissionsBoundary{
+			PermissionsBoundaryArn:  input.PermissionsBoundary,
+			PermissionsBoundaryType: aws.String(iam.PermissionsBoundaryAttachmentTypePermissionsBoundaryPolicy),
+		}
+	}
+	c.roles[name] = role
+	delete(c.deleted, name)
+	c.record(&PlanOperation{
+		Action:              awscb.CreateRole,
+		RoleName:            name,
+		Path:                path,
+		PermissionsBoundary: aws.StringValue(input.PermissionsBoundary),
+		Document:            planDocument(aws.StringValue(input.AssumeRolePolicyDocument)),
+		Tags:                planTags(input.Tags),
+	})
+	return &iam.CreateRoleOutput{Role: role}, nil
+}
+
+func (c *planIAMClient) DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	_, err := c.GetRole(&iam.GetRoleInput{RoleName: input.RoleName})
+	if err != nil {
+		return nil, err
+	}
+	delete(c.roles, name)
+	c.deleted[name] = true
+	c.record(&PlanOperation{
+		Action:   awscb.DeleteRole,
+		RoleName: name,
+	})
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+func (c *planIAMClient) UpdateAssumeRolePolicy(
+	input *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	c.record(&PlanOperation{
+		Action:   awscb.UpdateAssumeRolePolicy,
+		RoleName: aws.StringValue(input.RoleName),
+		Document: planDocument(aws.StringValue(input.PolicyDocument)),
+	})
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) PutRolePermissionsBoundary(
+	input *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	c.record(&PlanOperation{
+		Action:              awscb.PutRolePermissionsBoundary,
+		RoleName:            aws.StringValue(input.RoleName),
+		PermissionsBoundary: aws.StringValue(input.PermissionsBoundary),
+	})
+	return &iam.PutRolePermissionsBoundaryOutput{}, nil
+}
+
+func (c *planIAMClient) DeleteRolePermissionsBoundary(
+	input *iam.DeleteRolePermissionsBoundaryInput) (*iam.DeleteRolePermissionsBoundaryOutput, error) {
+	c.record(&PlanOperation{
+		Action:   awscb.DeleteRolePermissionsBoundary,
+		RoleName: aws.StringValue(input.RoleName),
+	})
+	return &iam.DeleteRolePermissionsBoundaryOutput{}, nil
+}
+
+func (c *planIAMClient) TagRole(input *iam.TagRoleInput) (*iam.TagRoleOutput, error) {
+	c.record(&PlanOperation{
+		Action:   awscb.TagRole,
+		RoleName: aws.StringValue(input.RoleName),
+		Tags:     planTags(input.Tags),
+	})
+	return &iam.TagRoleOutput{}, nil
+}
+
+func (c *planIAMClient) PutRolePolicy(input *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+	c.record(&PlanOperation{
+		Action:     awscb.PutRolePolicy,
+		RoleName:   aws.StringValue(input.RoleName),
+		PolicyName: aws.StringValue(input.PolicyName),
+		Document:   planDocument(aws.StringValue(input.PolicyDocument)),
+	})
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) DeleteRolePolicy(input *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error) {
+	c.record(&PlanOperation{
+		Action:     awscb.DeleteRolePolicy,
+		RoleName:   aws.StringValue(input.RoleName),
+		PolicyName: aws.StringValue(input.PolicyName),
+	})
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) AttachRolePolicy(input *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	arn := aws.StringValue(input.PolicyArn)
+	c.attached[name] = append(c.attached[name], &iam.AttachedPolicy{
+		PolicyArn:  input.PolicyArn,
+		PolicyName: aws.String(arn[strings.LastIndex(arn, "/")+1:]),
+	})
+	if policy, ok := c.policies[arn]; ok {
+		policy.AttachmentCount = aws.Int64(aws.Int64Value(policy.AttachmentCount) + 1)
+	}
+	c.record(&PlanOperation{
+		Action:    awscb.AttachRolePolicy,
+		RoleName:  name,
+		PolicyARN: arn,
+	})
+	return &iam.AttachRolePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) DetachRolePolicy(input *iam.DetachRolePolicyInput) (*iam.DetachRolePolicyOutput, error) {
+	name := aws.StringValue(input.RoleName)
+	arn := aws.StringValue(input.PolicyArn)
+	if policy, ok := c.policies[arn]; ok {
+		policy.AttachmentCount = aws.Int64(aws.Int64Value(policy.AttachmentCount) - 1)
+	} else {
+		c.detached[arn]++
+	}
+	attached := []*iam.AttachedPolicy{}
+	for _, policy := range c.attached[name] {
+		if aws.StringValue(policy.PolicyArn) != arn {
+			attached = append(attached, policy)
+		}
+	}
+	c.attached[name] = attached
+	if c.removed[name] == nil {
+		c.removed[name] = map[string]bool{}
+	}
+	c.removed[name][arn] = true
+	c.record(&PlanOperation{
+		Action:    awscb.DetachRolePolicy,
+		RoleName:  name,
+		PolicyARN: arn,
+	})
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) CreatePolicy(input *iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+	name := aws.StringValue(input.PolicyName)
+	path := aws.StringValue(input.Path)
+	if path == "" {
+		path = "/"
+	}
+	arn := fmt.Sprintf("arn:%s:iam::%s:policy%s%s", GetPartition(), c.accountID, path, name)
+	policy := &iam.Policy{
+		PolicyName:       input.PolicyName,
+		Path:             aws.String(path),
+		Arn:              aws.String(arn),
+		DefaultVersionId: aws.String("v1"),
+		AttachmentCount:  aws.Int64(0),
+		Tags:             input.Tags,
+	}
+	c.policies[arn] = policy
+	delete(c.deleted, arn)
+	c.record(&PlanOperation{
+		Action:     awscb.CreatePolicy,
+		PolicyName: name,
+		PolicyARN:  arn,
+		Path:       path,
+		Document:   planDocument(aws.StringValue(input.PolicyDocument)),
+		Tags:       planTags(input.Tags),
+	})
+	return &iam.CreatePolicyOutput{Policy: policy}, nil
+}
+
+func (c *planIAMClient) DeletePolicy(input *iam.DeletePolicyInput) (*iam.DeletePolicyOutput, error) {
+	arn := aws.StringValue(input.PolicyArn)
+	delete(c.policies, arn)
+	c.deleted[arn] = true
+	c.record(&PlanOperation{
+		Action:    awscb.DeletePolicy,
+		PolicyARN: arn,
+	})
+	return &iam.DeletePolicyOutput{}, nil
+}
+
+func (c *planIAMClient) CreatePolicyVersion(
+	input *iam.CreatePolicyVersionInput) (*iam.CreatePolicyVersionOutput, error) {
+	c.record(&PlanOperation{
+		Action:       awscb.CreatePolicyVersion,
+		PolicyARN:    aws.StringValue(input.PolicyArn),
+		SetAsDefault: aws.BoolValue(input.SetAsDefault),
+		Document:     planDocument(aws.StringValue(input.PolicyDocument)),
+	})
+	return &iam.CreatePolicyVersionOutput{
+		PolicyVersion: &iam.PolicyVersion{
+			IsDefaultVersion: input.SetAsDefault,
+		},
+	}, nil
+}
+
+func (c *planIAMClient) DeletePolicyVersion(
+	input *iam.DeletePolicyVersionInput) (*iam.DeletePolicyVersionOutput, error) {
+	c.record(&PlanOperation{
+		Action:    awscb.DeletePolicyVersion,
+		PolicyARN: aws.StringValue(input.PolicyArn),
+		VersionID: aws.StringValue(input.VersionId),
+	})
+	return &iam.DeletePolicyVersionOutput{}, nil
+}
+
+func (c *planIAMClient) TagPolicy(input *iam.TagPolicyInput) (*iam.TagPolicyOutput, error) {
+	c.record(&PlanOperation{
+		Action:    awscb.TagPolicy,
+		PolicyARN: aws.StringValue(input.PolicyArn),
+		Tags:      planTags(input.Tags),
+	})
+	return &iam.TagPolicyOutput{}, nil
+}
+
+func (c *planIAMClient) CreateOpenIDConnectProvider(
+	input *iam.CreateOpenIDConnectProviderInput) (*iam.CreateOpenIDConnectProviderOutput, error) {
+	url := aws.StringValue(input.Url)
+	arn := fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", GetPartition(), c.accountID,
+		strings.TrimPrefix(url, "https://"))
+	c.record(&PlanOperation{
+		Action:      awscb.CreateOpenIdConnectProvider,
+		ProviderARN: arn,
+		URL:         url,
+		Tags:        planTags(input.Tags),
+	})
+	return &iam.CreateOpenIDConnectProviderOutput{OpenIDConnectProviderArn: aws.String(arn)}, nil
+}
+
+func (c *planIAMClient) DeleteOpenIDConnectProvider(
+	input *iam.DeleteOpenIDConnectProviderInput) (*iam.DeleteOpenIDConnectProviderOutput, error) {
+	c.record(&PlanOperation{
+		Action:      awscb.DeleteOpenIdConnectProvider,
+		ProviderARN: aws.StringValue(input.OpenIDConnectProviderArn),
+	})
+	return &iam.DeleteOpenIDConnectProviderOutput{}, nil
+}
+
+func (c *planIAMClient) TagUser(input *iam.TagUserInput) (*iam.TagUserOutput, error) {
+	c.record(&PlanOperation{
+		Action:   awscb.TagUser,
+		UserName: aws.StringValue(input.UserName),
+		Tags:     planTags(input.Tags),
+	})
+	return &iam.TagUserOutput{}, nil
+}
+
+// Access keys can't be planned because the secrets that would be returned don't exist:
+
+func (c *planIAMClient) CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
+	return nil, fmt.Errorf("Creating access keys isn't supported in plan mode")
+}
+
+func (c *planIAMClient) DeleteAccessKey(*iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
+	return nil, fmt.Errorf("Deleting access keys isn't supported in plan mode")
+}
+
+// planDocument returns the given policy document as JSON, so that it is embedded in the plan
+// instead of quoted. Documents that aren't valid JSON are kept as strings.
+func planDocument(document string) json.RawMessage {
+	if document == "" {
+		return nil
+	}
+	if json.Valid([]byte(document)) {
+		return json.RawMessage(document)
+	}
+	data, _ := json.Marshal(document)
+	return data
+}
+
+func planTags(iamTags []*iam.Tag) map[string]string {
+	if len(iamTags) == 0 {
+		return nil
+	}
+	result := map[string]string{}
+	for _, tag := range iamTags {
+		result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Plan IAM client", func() {
+	It("Doesn't pass calls that aren't explicitly allowed to the real client", func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+		client := &planIAMClient{
+			reader: mocks.NewMockIAMAPI(mockCtrl),
+			plan:   &Plan{},
+		}
+
+		Expect(func() {
+			_, _ = client.DeleteUser(&iam.DeleteUserInput{})
+		}).To(Panic())
+		Expect(func() {
+			_, _ = client.CreatePolicyWithContext(context.Background(), &iam.CreatePolicyInput{})
+		}).To(Panic())
+	})
+})
@@ -0,0 +1,126 @@
+package aws_test
+
+import (
+	"encoding/json"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Plan client", func() {
+	var (
+		client     aws.Client
+		plan       *aws.Plan
+		mockCtrl   *gomock.Controller
+		mockIamAPI *mocks.MockIAMAPI
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
+		real := aws.New(
+			logrus.New(),
+			mockIamAPI,
+			mocks.NewMockEC2API(mockCtrl),
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockS3API(mockCtrl),
+			mocks.NewMockSecretsManagerAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mocks.NewMockCloudFormationAPI(mockCtrl),
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			&session.Session{},
+			&aws.AccessKey{},
+			false,
+		)
+		var err error
+		client, plan, err = aws.NewPlanClient(real, &aws.Creator{AccountID: "123456789012"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Records the creation of missing roles", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(nil,
+			awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+
+		arn, err := client.EnsureRole("my-role", `{"Version":"2012-10-17"}`, "", "4.13",
+			map[string]string{"red-hat-managed": "true"}, "/test/", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arn).To(HaveSuffix(":iam::123456789012:role/test/my-role"))
+
+		Expect(plan.Operations).To(HaveLen(1))
+		operation := plan.Operations[0]
+		Expect(operation.Action).To(Equal(awscb.CreateRole))
+		Expect(operation.RoleName).To(Equal("my-role"))
+		Expect(operation.Path).To(Equal("/test/"))
+		Expect(operation.Tags).To(Equal(map[string]string{"red-hat-managed": "true"}))
+
+		data, err := json.Marshal(plan)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"document":{"Version":"2012-10-17"}`))
+	})
+
+	It("Returns the roles created by the plan", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(nil,
+			awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+
+		_, err := client.EnsureRole("my-role", "{}", "", "4.13", nil, "", false)
+		Expect(err).ToNot(HaveOccurred())
+		exists, _, err := client.CheckRoleExists("my-role")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("Records the deletion of operator roles without deleting them", func() {
+		policyARN := "arn:aws:iam::123456789012:policy/my-policy"
+		mockIamAPI.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{{
+				PolicyArn:  awssdk.String(policyARN),
+				PolicyName: awssdk.String("my-policy"),
+			}},
+		}, nil).Times(2)
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{RoleName: awssdk.String("my-role")},
+		}, nil)
+		mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(&iam.GetPolicyOutput{
+			Policy: &iam.Policy{Arn: awssdk.String(policyARN), AttachmentCount: awssdk.Int64(1)},
+		}, nil)
+		mockIamAPI.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+			Versions: []*iam.PolicyVersion{
+				{VersionId: awssdk.String("v1"), IsDefaultVersion: awssdk.Bool(false)},
+				{VersionId: awssdk.String("v2"), IsDefaultVersion: awssdk.Bool(true)},
+			},
+		}, nil)
+
+		Expect(client.DeleteOperatorRole("my-role", false)).To(Succeed())
+
+		actions := []awscb.Command{}
+		for _, operation := range plan.Operations {
+			actions = append(actions, operation.Action)
+		}
+		Expect(actions).To(Equal([]awscb.Command{
+			awscb.DetachRolePolicy,
+			awscb.DeleteRole,
+			awscb.DeletePolicyVersion,
+			awscb.DeletePolicy,
+		}))
+		Expect(plan.Operations[2].VersionID).To(Equal("v1"))
+		Expect(plan.Operations[3].PolicyARN).To(Equal(policyARN))
+
+		exists, _, err := client.CheckRoleExists("my-role")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -47,28 +48,48 @@ type AccountRole struct {
 	Flag string
 }
 
+// Role is an IAM role created for ROSA. Its JSON representation is the schema used by the
+// '--output' flag of the commands that list roles, so all the fields are always present.
 type Role struct {
-	RoleType      string   `json:"RoleType,omitempty"`
-	Version       string   `json:"Version,omitempty"`
-	RolePrefix    string   `json:"RolePrefix,omitempty"`
-	RoleName      string   `json:"RoleName,omitempty"`
-	RoleARN       string   `json:"RoleARN,omitempty"`
-	Linked        string   `json:"Linked,omitempty"`
-	Admin         string   `json:"Admin,omitempty"`
-	Policy        []Policy `json:"Policy,omitempty"`
-	ManagedPolicy bool     `json:"ManagedPolicy,omitempty"`
-	ClusterID     string   `json:"ClusterID,omitempty"`
+	RoleType      string   `json:"RoleType"`
+	Version       string   `json:"Version"`
+	RolePrefix    string   `json:"RolePrefix"`
+	RoleName      string   `json:"RoleName"`
+	RoleARN       string   `json:"RoleARN"`
+	Linked        string   `json:"Linked"`
+	Admin         string   `json:"Admin"`
+	Policy        []Policy `json:"Policy"`
+	ManagedPolicy bool     `json:"ManagedPolicy"`
+	ClusterID     string   `json:"ClusterID"`
 }
 
+func (r Role) MarshalJSON() ([]byte, error) {
+	type role Role
+	if r.Policy == nil {
+		r.Policy = []Policy{}
+	}
+	return json.Marshal(role(r))
+}
+
+// OperatorRoleDetail is an operator role created for ROSA. Like Role, its JSON representation is
+// the schema used by the '--output' flag and all the fields are always present.
 type OperatorRoleDetail struct {
-	OperatorName      string   `json:"Name,omitempty"`
-	OperatorNamespace string   `json:"Namespace,omitempty"`
-	Version           string   `json:"Version,omitempty"`
-	RoleName          string   `json:"RoleName,omitempty"`
-	RoleARN           string   `json:"RoleARN,omitempty"`
-	ClusterID         string   `json:"ClusterID,omitempty"`
-	AttachedPolicies  []string `json:"Policy,omitempty"`
-	ManagedPolicy     bool     `json:"ManagedPolicy,omitempty"`
+	OperatorName      string   `json:"Name"`
+	OperatorNamespace string   `json:"Namespace"`
+	Version           string   `json:"Version"`
+	RoleName          string   `json:"RoleName"`
+	RoleARN           string   `json:"RoleARN"`
+	ClusterID         string   `json:"ClusterID"`
+	AttachedPolicies  []string `json:"Policy"`
+	ManagedPolicy     bool     `json:"ManagedPolicy"`
+}
+
+func (o OperatorRoleDetail) MarshalJSON() ([]byte, error) {
+	type operatorRoleDetail OperatorRoleDetail
+	if o.AttachedPolicies == nil {
+		o.AttachedPolicies = []string{}
+	}
+	return json.Marshal(operatorRoleDetail(o))
 }
 
 type PolicyDetail struct {
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output schema", func() {
+	It("Marshals all the fields of roles", func() {
+		var b bytes.Buffer
+		Expect(MarshalRoles([]Role{{RoleName: "ManagedOpenShift-Installer-Role"}}, &b)).To(Succeed())
+		Expect(b.String()).To(MatchJSON(`[{
+			"RoleType": "",
+			"Version": "",
+			"RolePrefix": "",
+			"RoleName": "ManagedOpenShift-Installer-Role",
+			"RoleARN": "",
+			"Linked": "",
+			"Admin": "",
+			"Policy": [],
+			"ManagedPolicy": false,
+			"ClusterID": ""
+		}]`))
+	})
+
+	It("Marshals all the fields of operator roles", func() {
+		data, err := json.Marshal(OperatorRoleDetail{OperatorName: "ebs-cloud-credentials"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"Name": "ebs-cloud-credentials",
+			"Namespace": "",
+			"Version": "",
+			"RoleName": "",
+			"RoleARN": "",
+			"ClusterID": "",
+			"Policy": [],
+			"ManagedPolicy": false
+		}`))
+	})
+
+	It("Marshals all the fields of OIDC providers", func() {
+		data, err := json.Marshal(OidcProviderOutput{Arn: "arn:aws:iam::123:oidc-provider/example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"arn": "arn:aws:iam::123:oidc-provider/example.com",
+			"cluster_id": "",
+			"in_use": false
+		}`))
+	})
+})
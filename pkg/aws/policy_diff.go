@@ -0,0 +1,327 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that compare the permissions granted by two versions of a
+// policy document.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// PolicyDiff contains the permissions that are added and removed when the document of a policy is
+// replaced.
+type PolicyDiff struct {
+	RoleName  string `json:"role_name,omitempty"`
+	PolicyARN string `json:"policy_arn"`
+
+	// Created is set when the policy doesn't exist yet, so all its permissions are added.
+	Created bool `json:"created,omitempty"`
+
+	Added   []PolicyPermission `json:"added,omitempty"`
+	Removed []PolicyPermission `json:"removed,omitempty"`
+
+	// Expands is set when the new document may allow more than the current one.
+	Expands bool `json:"expands_permissions,omitempty"`
+}
+
+// PolicyPermission is a set of actions that a statement allows or denies on a resource under the
+// same conditions. Statements that use 'NotAction' or 'NotResource' keep the listed values instead
+// of the actions or the resource. Conditions are written as the operator followed by the key and
+// its values, for example 'StringEquals aws:ResourceTag/red-hat-managed = true'.
+type PolicyPermission struct {
+	Effect       string   `json:"effect"`
+	Actions      []string `json:"actions,omitempty"`
+	NotActions   []string `json:"not_actions,omitempty"`
+	Resource     string   `json:"resource,omitempty"`
+	NotResources []string `json:"not_resources,omitempty"`
+	Conditions   []string `json:"conditions,omitempty"`
+}
+
+// HasChanges returns true if the new document adds or removes any permission.
+func (d *PolicyDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// ExpandsPermissions returns true if the new document may allow more than the current one: it
+// allows an action on a resource, or under fewer conditions, than any statement of the current
+// document, it stops denying an action, or it changes a statement that uses 'NotAction' or
+// 'NotResource'.
+func (d *PolicyDiff) ExpandsPermissions() bool {
+	return d.Expands
+}
+
+// DiffPolicyDocuments compares the permissions of the current and the target policy documents.
+// Statements are expanded into one grant per effect, action and resource, with the conditions of
+// the statement, so that moving an action to another resource or removing a condition from a
+// single statement is detected. An empty current document means that the policy doesn't exist
+// yet.
+func DiffPolicyDocuments(current string, target string) (*PolicyDiff, error) {
+	diff := &PolicyDiff{}
+	currentGrants := grantSet{}
+	if current == "" {
+		diff.Created = true
+	} else {
+		document, err := ParsePolicyDocument(InterpolatePolicyDocument(current, nil))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse current policy document: %v", err)
+		}
+		currentGrants = getGrants(document)
+	}
+	document, err := ParsePolicyDocument(InterpolatePolicyDocument(target, nil))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse target policy document: %v", err)
+	}
+	targetGrants := getGrants(document)
+
+	added := targetGrants.subtract(currentGrants)
+	removed := currentGrants.subtract(targetGrants)
+	diff.Added = groupGrants(added)
+	diff.Removed = groupGrants(removed)
+	for _, grant := range added {
+		if grant.isNegated() || grant.effect == "Allow" && !currentGrants.covers(grant) {
+			diff.Expands = true
+		}
+	}
+	for _, grant := range removed {
+		if grant.isNegated() || grant.effect == "Deny" && !targetGrants.covers(grant) {
+			diff.Expands = true
+		}
+	}
+	return diff, nil
+}
+
+// DiffPolicy compares the default version of the policy with the target document. The policy
+// doesn't need to exist.
+func (c *awsClient) DiffPolicy(policyARN string, target string) (*PolicyDiff, error) {
+	current, err := c.GetDefaultPolicyDocument(policyARN)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return nil, err
+		}
+		current = ""
+	}
+	diff, err := DiffPolicyDocuments(current, target)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compare policy '%s': %v", policyARN, err)
+	}
+	diff.PolicyARN = policyARN
+	return diff, nil
+}
+
+// grant is the effect of a statement on a single action and resource. When the statement uses
+// 'NotAction' or 'NotResource' the sorted values are kept instead of the action or the resource.
+type grant struct {
+	effect       string
+	action       string
+	notActions   []string
+	resource     string
+	notResources []string
+	conditions   []string
+}
+
+func (g *grant) isNegated() bool {
+	return len(g.notActions) > 0 || len(g.notResources) > 0
+}
+
+func (g *grant) key() string {
+	return strings.Join([]string{
+		g.effect,
+		g.action,
+		strings.Join(g.notActions, ","),
+		g.resource,
+		strings.Join(g.notResources, ","),
+		strings.Join(g.conditions, "\n"),
+	}, "|")
+}
+
+// groupKey identifies the grants that are shown together, as they only differ in the action.
+func (g *grant) groupKey() string {
+	return strings.Join([]string{
+		g.effect,
+		strings.Join(g.notActions, ","),
+		g.resource,
+		strings.Join(g.notResources, ","),
+		strings.Join(g.conditions, "\n"),
+	}, "|")
+}
+
+// grantSet contains grants by key.
+type grantSet map[string]*grant
+
+func getGrants(document *PolicyDocument) grantSet {
+	grants := grantSet{}
+	for _, statement := range document.Statement {
+		var conditions []string
+		for operator, keys := range statement.Condition {
+			for key, values := range keys {
+				sorted := GetStringList(values)
+				sort.Strings(sorted)
+				conditions = append(conditions, fmt.Sprintf("%s %s = %s", operator, key,
+					strings.Join(sorted, ", ")))
+			}
+		}
+		sort.Strings(conditions)
+		notActions := sortedStringList(statement.NotAction)
+		notResources := sortedStringList(statement.NotResource)
+		actions := GetStringList(statement.Action)
+		if len(actions) == 0 {
+			actions = []string{""}
+		}
+		resources := GetStringList(statement.Resource)
+		if len(resources) == 0 {
+			resources = []string{""}
+		}
+		for _, action := range actions {
+			for _, resource := range resources {
+				g := &grant{
+					effect:       statement.Effect,
+					action:       action,
+					notActions:   notActions,
+					resource:     resource,
+					notResources: notResources,
+					conditions:   conditions,
+				}
+				grants[g.key()] = g
+			}
+		}
+	}
+	return grants
+}
+
+// subtract returns the grants that are in this set but not in the other one, sorted by key.
+func (s grantSet) subtract(other grantSet) []*grant {
+	keys := []string{}
+	for key := range s {
+		if _, ok := other[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	result := make([]*grant, len(keys))
+	for i, key := range keys {
+		result[i] = s[key]
+	}
+	return result
+}
+
+// covers checks if the set contains a grant with the same effect that applies to at least the
+// action and resource of the given one, under a subset of its conditions.
+func (s grantSet) covers(g *grant) bool {
+	for _, other := range s {
+		if other.effect == g.effect && !other.isNegated() &&
+			matchesPattern(other.action, g.action, true) &&
+			matchesPattern(other.resource, g.resource, false) &&
+			isSubset(other.conditions, g.conditions) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupGrants converts the grants into permissions, with the actions of grants that only differ
+// in the action grouped together.
+func groupGrants(grants []*grant) []PolicyPermission {
+	var result []PolicyPermission
+	index := map[string]int{}
+	for _, g := range grants {
+		key := g.groupKey()
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, PolicyPermission{
+				Effect:       g.effect,
+				NotActions:   g.notActions,
+				Resource:     g.resource,
+				NotResources: g.notResources,
+				Conditions:   g.conditions,
+			})
+		}
+		if g.action != "" {
+			result[i].Actions = append(result[i].Actions, g.action)
+		}
+	}
+	for i := range result {
+		sort.Strings(result[i].Actions)
+	}
+	return result
+}
+
+// matchesPattern checks if a value of a statement, that can contain the '*' and '?' wildcards,
+// matches another value. Actions are case insensitive.
+func matchesPattern(pattern string, value string, ignoreCase bool) bool {
+	if pattern == value {
+		return true
+	}
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	if ignoreCase {
+		expression = "(?i)" + expression
+	}
+	matched, err := regexp.MatchString("^"+expression+"$", value)
+	return err == nil && matched
+}
+
+func isSubset(a []string, b []string) bool {
+	set := map[string]bool{}
+	for _, value := range b {
+		set[value] = true
+	}
+	for _, value := range a {
+		if !set[value] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedStringList(value interface{}) []string {
+	result := GetStringList(value)
+	sort.Strings(result)
+	return result
+}
+
+// GetStringList returns the values of a policy element that can be a single value or a list.
+// Values that aren't strings, like booleans in conditions, are converted to JSON.
+func GetStringList(value interface{}) []string {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{typed}
+	case []string:
+		return typed
+	case []interface{}:
+		result := []string{}
+		for _, item := range typed {
+			result = append(result, GetStringList(item)...)
+		}
+		return result
+	default:
+		data, _ := json.Marshal(typed)
+		return []string{string(data)}
+	}
+}
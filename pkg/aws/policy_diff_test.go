@@ -0,0 +1,224 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Policy diff", func() {
+	Context("DiffPolicyDocuments", func() {
+		It("Returns the added and removed permissions of each statement", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"arn:aws:s3:::a/*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject","s3:DeleteObject"],"Resource":"*"}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Created).To(BeFalse())
+			Expect(diff.Added).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Allow",
+				Actions:  []string{"s3:DeleteObject", "s3:GetObject", "s3:PutObject"},
+				Resource: "*",
+			}}))
+			Expect(diff.Removed).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Allow",
+				Actions:  []string{"s3:GetObject", "s3:PutObject"},
+				Resource: "arn:aws:s3:::a/*",
+			}}))
+			Expect(diff.HasChanges()).To(BeTrue())
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Detects an action moved to a resource already used by another statement", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"iam:PassRole","Resource":"arn:aws:iam::123:role/installer"},
+				{"Effect":"Allow","Action":"ec2:DescribeInstances","Resource":"*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"iam:PassRole","Resource":"*"},
+				{"Effect":"Allow","Action":"ec2:DescribeInstances","Resource":"*"}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Added).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Allow",
+				Actions:  []string{"iam:PassRole"},
+				Resource: "*",
+			}}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Detects a condition removed from one of the statements that use it", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*",
+				 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}},
+				{"Effect":"Allow","Action":"ec2:DeleteVolume","Resource":"*",
+				 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*"},
+				{"Effect":"Allow","Action":"ec2:DeleteVolume","Resource":"*",
+				 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Added).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Allow",
+				Actions:  []string{"ec2:TerminateInstances"},
+				Resource: "*",
+			}}))
+			Expect(diff.Removed).To(Equal([]aws.PolicyPermission{{
+				Effect:     "Allow",
+				Actions:    []string{"ec2:TerminateInstances"},
+				Resource:   "*",
+				Conditions: []string{"StringEquals aws:ResourceTag/red-hat-managed = true"},
+			}}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Doesn't report an expansion when a condition is added or a resource narrowed", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*"},
+				{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*",
+				 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}},
+				{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::a/*"}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.HasChanges()).To(BeTrue())
+			Expect(diff.ExpandsPermissions()).To(BeFalse())
+		})
+
+		It("Reports removed denied actions as an expansion", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*"},
+				{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:TerminateInstances","Resource":"*"}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Added).To(BeEmpty())
+			Expect(diff.Removed).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Deny",
+				Actions:  []string{"iam:*"},
+				Resource: "*",
+			}}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Reports changes of NotAction and NotResource as an expansion", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","NotAction":["iam:*","organizations:*"],"Resource":"*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Added).To(Equal([]aws.PolicyPermission{{
+				Effect:     "Allow",
+				NotActions: []string{"iam:*"},
+				Resource:   "*",
+			}}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+
+			current = `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"s3:GetObject","NotResource":"arn:aws:s3:::secret/*"}]}`
+			target = `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"s3:GetObject","NotResource":"arn:aws:s3:::other/*"}]}`
+			diff, err = aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Added[0].NotResources).To(Equal([]string{"arn:aws:s3:::other/*"}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Doesn't report changes when only the order changes", func() {
+			current := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"}]}`
+			target := `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject"],"Resource":["*"]}]}`
+			diff, err := aws.DiffPolicyDocuments(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.HasChanges()).To(BeFalse())
+		})
+
+		It("Marks the policy as created when there is no current document", func() {
+			target := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+			diff, err := aws.DiffPolicyDocuments("", target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Created).To(BeTrue())
+			Expect(diff.Added).To(Equal([]aws.PolicyPermission{{
+				Effect:   "Allow",
+				Actions:  []string{"s3:*"},
+				Resource: "*",
+			}}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+	})
+
+	Context("DiffPolicy", func() {
+		var (
+			client     aws.Client
+			mockCtrl   *gomock.Controller
+			mockIamAPI *mocks.MockIAMAPI
+		)
+
+		const (
+			policyArn = "arn:aws:iam::123456789012:policy/test-Installer-Role-Policy"
+			target    = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockS3API(mockCtrl),
+				mocks.NewMockSecretsManagerAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				&session.Session{},
+				&aws.AccessKey{},
+				false,
+			)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("Compares the default version of the policy", func() {
+			mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(&iam.GetPolicyOutput{
+				Policy: &iam.Policy{DefaultVersionId: awssdk.String("v1")},
+			}, nil)
+			mockIamAPI.EXPECT().GetPolicyVersion(gomock.Any()).Return(&iam.GetPolicyVersionOutput{
+				PolicyVersion: &iam.PolicyVersion{
+					Document: awssdk.String(`{"Version":"2012-10-17","Statement":[` +
+						`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+				},
+			}, nil)
+			diff, err := client.DiffPolicy(policyArn, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.PolicyARN).To(Equal(policyArn))
+			Expect(diff.Added[0].Actions).To(Equal([]string{"s3:*"}))
+			Expect(diff.Removed[0].Actions).To(Equal([]string{"s3:GetObject"}))
+			Expect(diff.ExpandsPermissions()).To(BeTrue())
+		})
+
+		It("Marks missing policies as created", func() {
+			mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(nil,
+				awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+			diff, err := client.DiffPolicy(policyArn, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.Created).To(BeTrue())
+		})
+	})
+})
@@ -37,11 +37,18 @@ type PolicyStatement struct {
 	// Include a list of actions that the policy allows or denies.
 	// (i.e. ec2:StartInstances, iam:ChangePassword)
 	Action interface{} `json:"Action,omitempty"`
+	// NotAction matches every action except the listed ones.
+	NotAction interface{} `json:"NotAction,omitempty"`
 	// If you create an IAM permissions policy, you must specify a list of resources to which
 	// the actions apply. If you create a resource-based policy, this element is optional. If
 	// you do not include this element, then the resource to which the action applies is the
 	// resource to which the policy is attached.
 	Resource interface{} `json:"Resource,omitempty"`
+	// NotResource matches every resource except the listed ones.
+	NotResource interface{} `json:"NotResource,omitempty"`
+	// Use conditions to restrict when the statement applies. The keys are the condition operators
+	// (i.e. StringEquals) and the values map the condition keys to their expected values.
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"`
 }
 
 type PolicyStatementPrincipal struct {
@@ -0,0 +1,394 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a local evaluator of IAM policies, used to check permissions without calling
+// the IAM policy simulator.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PolicyDecision is the result of evaluating a request. The values are the same used by the IAM
+// policy simulator.
+type PolicyDecision string
+
+const (
+	PolicyDecisionAllowed      PolicyDecision = "allowed"
+	PolicyDecisionExplicitDeny PolicyDecision = "explicitDeny"
+	PolicyDecisionImplicitDeny PolicyDecision = "implicitDeny"
+)
+
+// Kinds of policies that can deny a request.
+const (
+	PolicyKindIdentity            = "identity"
+	PolicyKindPermissionsBoundary = "permissions-boundary"
+	PolicyKindSCP                 = "scp"
+)
+
+// PolicyRequest is a request evaluated against policies. The resource is matched as '*' when
+// it is empty. The context contains the values of the condition keys, like 'aws:RequestedRegion'.
+type PolicyRequest struct {
+	Action   string
+	Resource string
+	Context  map[string][]string
+}
+
+// PolicySet contains the policies that apply to a principal. A request is allowed when it is
+// allowed by the identity policies, the permissions boundary and every SCP, and no policy denies
+// it explicitly. The permissions boundary isn't evaluated when it is nil.
+type PolicySet struct {
+	Identity            []*PolicyDocument
+	PermissionsBoundary *PolicyDocument
+	SCPs                []*PolicyDocument
+}
+
+// PolicyEvaluation is the decision for a request, with the kind of policy that denied it.
+type PolicyEvaluation struct {
+	Decision PolicyDecision
+	DeniedBy string
+}
+
+// Evaluate returns the decision of the policy document for the request: an explicit deny if a
+// statement denies it, allowed if a statement allows it, and an implicit deny otherwise.
+func (p *PolicyDocument) Evaluate(request PolicyRequest) (PolicyDecision, error) {
+	allowed := false
+	for i := range p.Statement {
+		statement := &p.Statement[i]
+		matches, err := statement.matches(request)
+		if err != nil {
+			return "", err
+		}
+		if !matches {
+			continue
+		}
+		if strings.EqualFold(statement.Effect, "Deny") {
+			return PolicyDecisionExplicitDeny, nil
+		}
+		if strings.EqualFold(statement.Effect, "Allow") {
+			allowed = true
+		}
+	}
+	if allowed {
+		return PolicyDecisionAllowed, nil
+	}
+	return PolicyDecisionImplicitDeny, nil
+}
+
+// Evaluate returns the decision of the policy set for the request, following the IAM policy
+// evaluation logic for a principal of the same account.
+func (s *PolicySet) Evaluate(request PolicyRequest) (*PolicyEvaluation, error) {
+	type evaluated struct {
+		kind     string
+		decision PolicyDecision
+	}
+	results := []evaluated{}
+	for _, scp := range s.SCPs {
+		decision, err := scp.Evaluate(request)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, evaluated{PolicyKindSCP, decision})
+	}
+	if s.PermissionsBoundary != nil {
+		decision, err := s.PermissionsBoundary.Evaluate(request)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, evaluated{PolicyKindPermissionsBoundary, decision})
+	}
+	identity := PolicyDecisionImplicitDeny
+	for _, policy := range s.Identity {
+		decision, err := policy.Evaluate(request)
+		if err != nil {
+			return nil, err
+		}
+		if decision != PolicyDecisionImplicitDeny {
+			identity = decision
+		}
+		if decision == PolicyDecisionExplicitDeny {
+			break
+		}
+	}
+	results = append(results, evaluated{PolicyKindIdentity, identity})
+
+	for _, result := range results {
+		if result.decision == PolicyDecisionExplicitDeny {
+			return &PolicyEvaluation{Decision: PolicyDecisionExplicitDeny, DeniedBy: result.kind}, nil
+		}
+	}
+	for _, result := range results {
+		if result.decision == PolicyDecisionImplicitDeny {
+			return &PolicyEvaluation{Decision: PolicyDecisionImplicitDeny, DeniedBy: result.kind}, nil
+		}
+	}
+	return &PolicyEvaluation{Decision: PolicyDecisionAllowed}, nil
+}
+
+func (p *PolicyStatement) matches(request PolicyRequest) (bool, error) {
+	if p.NotAction != nil {
+		if matchesAny(GetStringList(p.NotAction), request.Action, true) {
+			return false, nil
+		}
+	} else if !matchesAny(GetStringList(p.Action), request.Action, true) {
+		return false, nil
+	}
+
+	resource := request.Resource
+	if resource == "" {
+		resource = "*"
+	}
+	if p.NotResource != nil {
+		if matchesAny(GetStringList(p.NotResource), resource, false) {
+			return false, nil
+		}
+	} else if p.Resource != nil && !matchesAny(GetStringList(p.Resource), resource, false) {
+		return false, nil
+	}
+
+	for operator, keys := range p.Condition {
+		for key, values := range keys {
+			matches, err := evaluateCondition(operator, GetStringList(values),
+				request.Context[strings.ToLower(key)], hasKey(request.Context, key))
+			if err != nil {
+				return false, fmt.Errorf("Failed to evaluate condition '%s' on key '%s': %v", operator, key, err)
+			}
+			if !matches {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// hasKey checks if the context contains the condition key. Condition keys aren't case sensitive,
+// so the keys of the context are expected to be lower case.
+func hasKey(context map[string][]string, key string) bool {
+	_, ok := context[strings.ToLower(key)]
+	return ok
+}
+
+// NewPolicyRequestContext returns a request context with the keys in lower case, as expected by
+// the evaluator.
+func NewPolicyRequestContext(values map[string][]string) map[string][]string {
+	context := map[string][]string{}
+	for key, value := range values {
+		context[strings.ToLower(key)] = append(context[strings.ToLower(key)], value...)
+	}
+	return context
+}
+
+func matchesAny(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, value, ignoreCase) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches a value with a pattern where '*' matches any sequence of characters and
+// '?' matches a single character.
+func wildcardMatch(pattern string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		pattern = strings.ToLower(pattern)
+		value = strings.ToLower(value)
+	}
+	p, v := 0, 0
+	star, match := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star = p
+			match = v
+			p++
+		case star != -1:
+			p = star + 1
+			match++
+			v = match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// evaluateCondition evaluates a condition operator, including the 'ForAnyValue:' and
+// 'ForAllValues:' set operators and the 'IfExists' suffix, on the values of a condition key.
+func evaluateCondition(operator string, expected []string, actual []string, exists bool) (bool, error) {
+	if operator == "Null" {
+		if len(expected) != 1 {
+			return false, fmt.Errorf("expected a single value")
+		}
+		isNull, err := strconv.ParseBool(expected[0])
+		if err != nil {
+			return false, err
+		}
+		return isNull != exists, nil
+	}
+
+	forAll := false
+	forAny := false
+	if strings.HasPrefix(operator, "ForAllValues:") {
+		forAll = true
+		operator = strings.TrimPrefix(operator, "ForAllValues:")
+	} else if strings.HasPrefix(operator, "ForAnyValue:") {
+		forAny = true
+		operator = strings.TrimPrefix(operator, "ForAnyValue:")
+	}
+	ifExists := strings.HasSuffix(operator, "IfExists")
+	operator = strings.TrimSuffix(operator, "IfExists")
+	match, negated, err := conditionMatcher(operator)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists || len(actual) == 0 {
+		switch {
+		case forAll, ifExists:
+			return true, nil
+		case forAny:
+			return false, nil
+		default:
+			return negated, nil
+		}
+	}
+
+	// The operator is evaluated for each value of the key: all of them have to match for the
+	// 'ForAllValues:' operator, and any of them otherwise.
+	for _, value := range actual {
+		matches := false
+		for _, expectedValue := range expected {
+			matches, err = match(expectedValue, value)
+			if err != nil {
+				return false, err
+			}
+			if matches {
+				break
+			}
+		}
+		if matches != negated && !forAll {
+			return true, nil
+		}
+		if matches == negated && forAll {
+			return false, nil
+		}
+	}
+	return forAll, nil
+}
+
+// conditionMatcher returns the function that compares an expected value with a value of the
+// request for the operator, and whether the result of the operator is negated.
+func conditionMatcher(operator string) (func(expected string, actual string) (bool, error), bool, error) {
+	switch operator {
+	case "StringEquals", "ArnEquals", "BinaryEquals":
+		return equalMatcher, false, nil
+	case "StringNotEquals", "ArnNotEquals":
+		return equalMatcher, true, nil
+	case "StringEqualsIgnoreCase":
+		return ignoreCaseMatcher, false, nil
+	case "StringNotEqualsIgnoreCase":
+		return ignoreCaseMatcher, true, nil
+	case "StringLike", "ArnLike":
+		return likeMatcher, false, nil
+	case "StringNotLike", "ArnNotLike":
+		return likeMatcher, true, nil
+	case "Bool":
+		return boolMatcher, false, nil
+	case "IpAddress":
+		return ipMatcher, false, nil
+	case "NotIpAddress":
+		return ipMatcher, true, nil
+	case "NumericEquals":
+		return numericMatcher(func(e, a float64) bool { return a == e }), false, nil
+	case "NumericNotEquals":
+		return numericMatcher(func(e, a float64) bool { return a == e }), true, nil
+	case "NumericLessThan":
+		return numericMatcher(func(e, a float64) bool { return a < e }), false, nil
+	case "NumericLessThanEquals":
+		return numericMatcher(func(e, a float64) bool { return a <= e }), false, nil
+	case "NumericGreaterThan":
+		return numericMatcher(func(e, a float64) bool { return a > e }), false, nil
+	case "NumericGreaterThanEquals":
+		return numericMatcher(func(e, a float64) bool { return a >= e }), false, nil
+	default:
+		return nil, false, fmt.Errorf("operator is not supported")
+	}
+}
+
+func equalMatcher(expected string, actual string) (bool, error) {
+	return expected == actual, nil
+}
+
+func ignoreCaseMatcher(expected string, actual string) (bool, error) {
+	return strings.EqualFold(expected, actual), nil
+}
+
+func likeMatcher(expected string, actual string) (bool, error) {
+	return wildcardMatch(expected, actual, false), nil
+}
+
+func boolMatcher(expected string, actual string) (bool, error) {
+	expectedBool, err := strconv.ParseBool(expected)
+	if err != nil {
+		return false, err
+	}
+	actualBool, err := strconv.ParseBool(actual)
+	if err != nil {
+		return false, nil
+	}
+	return expectedBool == actualBool, nil
+}
+
+func ipMatcher(expected string, actual string) (bool, error) {
+	if !strings.Contains(expected, "/") {
+		if strings.Contains(expected, ":") {
+			expected += "/128"
+		} else {
+			expected += "/32"
+		}
+	}
+	_, network, err := net.ParseCIDR(expected)
+	if err != nil {
+		return false, err
+	}
+	ip := net.ParseIP(actual)
+	return ip != nil && network.Contains(ip), nil
+}
+
+func numericMatcher(compare func(expected float64, actual float64) bool) func(string, string) (bool, error) {
+	return func(expected string, actual string) (bool, error) {
+		expectedNumber, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return false, err
+		}
+		actualNumber, err := strconv.ParseFloat(actual, 64)
+		if err != nil {
+			return false, nil
+		}
+		return compare(expectedNumber, actualNumber), nil
+	}
+}
@@ -0,0 +1,172 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+func mustParsePolicy(document string) *aws.PolicyDocument {
+	policy, err := aws.ParsePolicyDocument(document)
+	Expect(err).ToNot(HaveOccurred())
+	return policy
+}
+
+var _ = Describe("Policy evaluator", func() {
+	Context("PolicyDocument.Evaluate", func() {
+		It("Allows matching actions with wildcards, ignoring the case", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:Describe*","Resource":"*"}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{Action: "EC2:DescribeInstances"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionAllowed))
+			decision, err = policy.Evaluate(aws.PolicyRequest{Action: "ec2:RunInstances"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+		})
+
+		It("Gives precedence to explicit denies", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"*","Resource":"*"},
+				{"Effect":"Deny","Action":"iam:Create?ser","Resource":"*"}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{Action: "iam:CreateUser"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionExplicitDeny))
+		})
+
+		It("Evaluates NotAction and NotResource", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","NotAction":"iam:*","NotResource":"arn:aws:s3:::private/*"}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{
+				Action:   "s3:GetObject",
+				Resource: "arn:aws:s3:::public/key",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionAllowed))
+			decision, err = policy.Evaluate(aws.PolicyRequest{
+				Action:   "s3:GetObject",
+				Resource: "arn:aws:s3:::private/key",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+			decision, err = policy.Evaluate(aws.PolicyRequest{Action: "iam:GetRole"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+		})
+
+		It("Matches resources with case sensitivity", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::bucket/*"}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{
+				Action:   "s3:GetObject",
+				Resource: "arn:aws:s3:::Bucket/key",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+		})
+
+		It("Evaluates conditions against the request context", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Deny","Action":"*","Resource":"*",
+				 "Condition":{"StringNotEquals":{"aws:RequestedRegion":["us-east-1","us-west-2"]}}},
+				{"Effect":"Allow","Action":"*","Resource":"*"}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{
+				Action:  "ec2:RunInstances",
+				Context: aws.NewPolicyRequestContext(map[string][]string{"aws:RequestedRegion": {"us-east-1"}}),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionAllowed))
+			decision, err = policy.Evaluate(aws.PolicyRequest{
+				Action:  "ec2:RunInstances",
+				Context: aws.NewPolicyRequestContext(map[string][]string{"aws:RequestedRegion": {"eu-west-1"}}),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionExplicitDeny))
+		})
+
+		It("Evaluates IfExists, Null and ForAllValues conditions", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"ec2:CreateTags","Resource":"*","Condition":{
+				 "StringEqualsIfExists":{"aws:ResourceTag/owner":"rosa"},
+				 "Null":{"aws:RequestTag/red-hat-managed":"false"},
+				 "ForAllValues:StringLike":{"aws:TagKeys":["red-hat-*","owner"]}}}]}`)
+			decision, err := policy.Evaluate(aws.PolicyRequest{
+				Action: "ec2:CreateTags",
+				Context: aws.NewPolicyRequestContext(map[string][]string{
+					"aws:RequestTag/red-hat-managed": {"true"},
+					"aws:TagKeys":                    {"red-hat-managed", "owner"},
+				}),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionAllowed))
+			decision, err = policy.Evaluate(aws.PolicyRequest{
+				Action: "ec2:CreateTags",
+				Context: aws.NewPolicyRequestContext(map[string][]string{
+					"aws:RequestTag/red-hat-managed": {"true"},
+					"aws:TagKeys":                    {"red-hat-managed", "cost-center"},
+				}),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+			decision, err = policy.Evaluate(aws.PolicyRequest{
+				Action:  "ec2:CreateTags",
+				Context: aws.NewPolicyRequestContext(map[string][]string{"aws:TagKeys": {"owner"}}),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+		})
+
+		It("Fails with unsupported condition operators", func() {
+			policy := mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":"*","Resource":"*",
+				 "Condition":{"DateGreaterThan":{"aws:CurrentTime":"2020-01-01T00:00:00Z"}}}]}`)
+			_, err := policy.Evaluate(aws.PolicyRequest{Action: "ec2:RunInstances"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("PolicySet.Evaluate", func() {
+		var set *aws.PolicySet
+
+		BeforeEach(func() {
+			set = &aws.PolicySet{
+				Identity: []*aws.PolicyDocument{mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":["ec2:*","iam:GetRole","s3:GetObject"],"Resource":"*"}]}`)},
+				PermissionsBoundary: mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":["ec2:*","s3:*"],"Resource":"*"}]}`),
+				SCPs: []*aws.PolicyDocument{mustParsePolicy(`{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"*","Resource":"*"},
+					{"Effect":"Deny","Action":"ec2:TerminateInstances","Resource":"*"}]}`)},
+			}
+		})
+
+		It("Allows requests allowed by every policy", func() {
+			evaluation, err := set.Evaluate(aws.PolicyRequest{Action: "ec2:RunInstances"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evaluation.Decision).To(Equal(aws.PolicyDecisionAllowed))
+			Expect(evaluation.DeniedBy).To(BeEmpty())
+		})
+
+		It("Reports the explicit deny of an SCP", func() {
+			evaluation, err := set.Evaluate(aws.PolicyRequest{Action: "ec2:TerminateInstances"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evaluation.Decision).To(Equal(aws.PolicyDecisionExplicitDeny))
+			Expect(evaluation.DeniedBy).To(Equal(aws.PolicyKindSCP))
+		})
+
+		It("Reports the implicit deny of the permissions boundary", func() {
+			evaluation, err := set.Evaluate(aws.PolicyRequest{Action: "iam:GetRole"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evaluation.Decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+			Expect(evaluation.DeniedBy).To(Equal(aws.PolicyKindPermissionsBoundary))
+		})
+
+		It("Reports the implicit deny of the identity policies", func() {
+			evaluation, err := set.Evaluate(aws.PolicyRequest{Action: "s3:PutObject"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evaluation.Decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+			Expect(evaluation.DeniedBy).To(Equal(aws.PolicyKindIdentity))
+		})
+	})
+})
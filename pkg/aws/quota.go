@@ -2,10 +2,16 @@ package aws
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper"
 )
 
 type quota struct {
@@ -111,13 +117,414 @@ func (c *awsClient) ValidateQuota() (bool, error) {
 	}
 
 	if len(invalidQuotas) > 0 {
-		return false, fmt.Errorf("Service quota is insufficient for the following service quota codes:\n%s",
+		return false, exitcode.InsufficientQuota.Errorf(
+			"Service quota is insufficient for the following service quota codes:\n%s",
 			strings.Join(invalidQuotas, "\n"))
 	}
 
 	return true, nil
 }
 
+// Volume types of the root disks of the nodes.
+const (
+	VolumeTypeGP2 = "gp2"
+	VolumeTypeGP3 = "gp3"
+	VolumeTypeIO1 = "io1"
+)
+
+// Quotas checked for a cluster or machine pool specification.
+var (
+	standardInstancesQuota = quota{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-1216C47A",
+		QuotaName:   "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances",
+	}
+	elasticIPsQuota = quota{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-0263D0A3",
+		QuotaName:   "EC2-VPC Elastic IPs",
+	}
+	vpcsQuota = quota{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-F678F1CE",
+		QuotaName:   "VPCs per Region",
+	}
+	internetGatewaysQuota = quota{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-A4707A72",
+		QuotaName:   "Internet gateways per Region",
+	}
+	natGatewaysQuota = quota{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-FE5A380F",
+		QuotaName:   "NAT gateways per Availability Zone",
+	}
+	networkLoadBalancersQuota = quota{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-69A177A2",
+		QuotaName:   "Network Load Balancers per Region",
+	}
+	classicLoadBalancersQuota = quota{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-E9E9831D",
+		QuotaName:   "Classic Load Balancers per Region",
+	}
+	io1IOPSQuota = quota{
+		ServiceCode: "ebs",
+		QuotaCode:   "L-B3A130E6",
+		QuotaName:   "IOPS for Provisioned IOPS SSD (io1) volumes",
+	}
+)
+
+// instanceQuotas are the quotas of running on-demand vCPUs of the instance families that don't
+// count towards the quota of standard instances, indexed by family.
+var instanceQuotas = map[string]quota{
+	"dl":  {ServiceCode: "ec2", QuotaCode: "L-6E869C2A", QuotaName: "Running On-Demand DL instances"},
+	"f":   {ServiceCode: "ec2", QuotaCode: "L-74FC7D96", QuotaName: "Running On-Demand F instances"},
+	"g":   {ServiceCode: "ec2", QuotaCode: "L-DB2E81BA", QuotaName: "Running On-Demand G and VT instances"},
+	"vt":  {ServiceCode: "ec2", QuotaCode: "L-DB2E81BA", QuotaName: "Running On-Demand G and VT instances"},
+	"hpc": {ServiceCode: "ec2", QuotaCode: "L-F7808C92", QuotaName: "Running On-Demand HPC instances"},
+	"inf": {ServiceCode: "ec2", QuotaCode: "L-1945791B", QuotaName: "Running On-Demand Inf instances"},
+	"p":   {ServiceCode: "ec2", QuotaCode: "L-417A185B", QuotaName: "Running On-Demand P instances"},
+	"trn": {ServiceCode: "ec2", QuotaCode: "L-2C3B7624", QuotaName: "Running On-Demand Trn instances"},
+	"u":   {ServiceCode: "ec2", QuotaCode: "L-43DA4232", QuotaName: "Running On-Demand High Memory instances"},
+	"x":   {ServiceCode: "ec2", QuotaCode: "L-7295265B", QuotaName: "Running On-Demand X instances"},
+}
+
+// storageQuotas are the quotas of storage, in TiB, indexed by volume type.
+var storageQuotas = map[string]quota{
+	VolumeTypeGP2: {
+		ServiceCode: "ebs",
+		QuotaCode:   "L-D18FCD1D",
+		QuotaName:   "Storage for General Purpose SSD (gp2) volumes",
+	},
+	VolumeTypeGP3: {
+		ServiceCode: "ebs",
+		QuotaCode:   "L-7A658B76",
+		QuotaName:   "Storage for General Purpose SSD (gp3) volumes",
+	},
+	VolumeTypeIO1: {
+		ServiceCode: "ebs",
+		QuotaCode:   "L-FD252861",
+		QuotaName:   "Storage for Provisioned IOPS SSD (io1) volumes",
+	},
+}
+
+// QuotaNodes are nodes of the same instance type, like the control plane nodes of a cluster or
+// the nodes of a machine pool.
+type QuotaNodes struct {
+	InstanceType string
+	VCPUs        int
+	Count        int
+
+	// VolumeType, VolumeSize in GiB and VolumeIOPS describe the root disk of each node. The IOPS
+	// are only used for io1 volumes.
+	VolumeType string
+	VolumeSize int
+	VolumeIOPS int
+}
+
+// QuotaSpec contains the resources that a cluster or machine pool will create in the account.
+type QuotaSpec struct {
+	Nodes []QuotaNodes
+
+	VPCs             int
+	InternetGateways int
+
+	// NatGateways is the number of NAT gateways, one per availability zone, each using an
+	// elastic IP.
+	NatGateways int
+
+	NetworkLoadBalancers int
+	ClassicLoadBalancers int
+}
+
+// QuotaCheck compares the amount of a quota required by a specification with the current usage
+// and the limit of the quota.
+type QuotaCheck struct {
+	ServiceCode string  `json:"service_code"`
+	QuotaCode   string  `json:"quota_code"`
+	QuotaName   string  `json:"quota_name"`
+	Required    float64 `json:"required"`
+	Limit       float64 `json:"limit"`
+
+	// Usage is nil when it can't be measured, like for load balancers. The quota is then only
+	// compared with the required amount.
+	Usage *float64 `json:"usage,omitempty"`
+}
+
+// Available returns the amount of the quota that isn't used yet.
+func (q *QuotaCheck) Available() float64 {
+	return q.Limit - aws.Float64Value(q.Usage)
+}
+
+// Sufficient checks if the available amount of the quota covers the required amount.
+func (q *QuotaCheck) Sufficient() bool {
+	return q.Available() >= q.Required
+}
+
+// InstanceQuota returns the quota of running on-demand vCPUs that applies to the instance type,
+// based on its family. For example 'p3.2xlarge' counts towards the quota of P instances and
+// 'm5.xlarge' towards the quota of standard instances.
+func InstanceQuota(instanceType string) (serviceCode string, quotaCode string, quotaName string) {
+	family := strings.ToLower(instanceType)
+	family = family[:strings.IndexFunc(family+"0", func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})]
+	q, ok := instanceQuotas[family]
+	if !ok {
+		q = standardInstancesQuota
+	}
+	return q.ServiceCode, q.QuotaCode, q.QuotaName
+}
+
+// requiredQuotas returns the checks of the quotas needed by the specification, with the required
+// amounts, in a stable order.
+func (s *QuotaSpec) requiredQuotas() []*QuotaCheck {
+	checks := map[string]*QuotaCheck{}
+	add := func(q quota, amount float64) {
+		if amount <= 0 {
+			return
+		}
+		check, ok := checks[q.QuotaCode]
+		if !ok {
+			check = &QuotaCheck{
+				ServiceCode: q.ServiceCode,
+				QuotaCode:   q.QuotaCode,
+				QuotaName:   q.QuotaName,
+			}
+			checks[q.QuotaCode] = check
+		}
+		check.Required += amount
+	}
+
+	for _, nodes := range s.Nodes {
+		serviceCode, quotaCode, quotaName := InstanceQuota(nodes.InstanceType)
+		add(quota{ServiceCode: serviceCode, QuotaCode: quotaCode, QuotaName: quotaName},
+			float64(nodes.VCPUs*nodes.Count))
+		if storageQuota, ok := storageQuotas[nodes.VolumeType]; ok {
+			add(storageQuota, float64(nodes.VolumeSize*nodes.Count)/1024)
+		}
+		if nodes.VolumeType == VolumeTypeIO1 {
+			add(io1IOPSQuota, float64(nodes.VolumeIOPS*nodes.Count))
+		}
+	}
+	add(vpcsQuota, float64(s.VPCs))
+	add(internetGatewaysQuota, float64(s.InternetGateways))
+	add(elasticIPsQuota, float64(s.NatGateways))
+	if s.NatGateways > 0 {
+		add(natGatewaysQuota, 1)
+	}
+	add(networkLoadBalancersQuota, float64(s.NetworkLoadBalancers))
+	add(classicLoadBalancersQuota, float64(s.ClassicLoadBalancers))
+
+	result := make([]*QuotaCheck, 0, len(checks))
+	for _, check := range checks {
+		result = append(result, check)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceCode != result[j].ServiceCode {
+			return result[i].ServiceCode < result[j].ServiceCode
+		}
+		return result[i].QuotaName < result[j].QuotaName
+	})
+	return result
+}
+
+// ValidateQuotaForSpec checks the quotas needed by the resources of the specification against
+// their current usage in the region and their limits. The checks are returned whether the quotas
+// are sufficient or not, use the Sufficient method to tell.
+func (c *awsClient) ValidateQuotaForSpec(spec QuotaSpec) ([]*QuotaCheck, error) {
+	checks := spec.requiredQuotas()
+	serviceQuotas := map[string][]*servicequotas.ServiceQuota{}
+	var usage map[string]float64
+	for _, check := range checks {
+		quotas, ok := serviceQuotas[check.ServiceCode]
+		if !ok {
+			var err error
+			quotas, err = ListServiceQuotas(c, check.ServiceCode)
+			if err != nil {
+				return nil, fmt.Errorf("Error listing AWS service quotas: %s %v", check.ServiceCode, err)
+			}
+			serviceQuotas[check.ServiceCode] = quotas
+		}
+		serviceQuota, err := GetServiceQuota(quotas, check.QuotaCode)
+		if err != nil || serviceQuota.Value == nil {
+			return nil, fmt.Errorf("Error getting AWS service quota: %s %v", check.ServiceCode, err)
+		}
+		check.Limit = *serviceQuota.Value
+
+		if usage == nil {
+			usage, err = c.getQuotaUsage()
+			if err != nil {
+				return nil, fmt.Errorf("Error getting AWS resource usage: %v", err)
+			}
+		}
+		if value, ok := usage[check.QuotaCode]; ok {
+			check.Usage = aws.Float64(value)
+		}
+	}
+	return checks, nil
+}
+
+// getQuotaUsage returns the current usage of the quotas that can be measured with the EC2 API,
+// indexed by quota code.
+func (c *awsClient) getQuotaUsage() (map[string]float64, error) {
+	usage := map[string]float64{
+		standardInstancesQuota.QuotaCode: 0,
+	}
+	for _, q := range instanceQuotas {
+		usage[q.QuotaCode] = 0
+	}
+
+	instancesInput := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: aws.StringSlice([]string{"pending", "running"}),
+		}},
+	}
+	for {
+		output, err := c.ec2Client.DescribeInstances(instancesInput)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.CpuOptions == nil {
+					continue
+				}
+				_, quotaCode, _ := InstanceQuota(aws.StringValue(instance.InstanceType))
+				usage[quotaCode] += float64(aws.Int64Value(instance.CpuOptions.CoreCount) *
+					aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+			}
+		}
+		if output.NextToken == nil {
+			break
+		}
+		instancesInput.NextToken = output.NextToken
+	}
+
+	addresses, err := c.ec2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("domain"),
+			Values: aws.StringSlice([]string{"vpc"}),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage[elasticIPsQuota.QuotaCode] = float64(len(addresses.Addresses))
+
+	vpcsInput := &ec2.DescribeVpcsInput{}
+	usage[vpcsQuota.QuotaCode] = 0
+	for {
+		output, err := c.ec2Client.DescribeVpcs(vpcsInput)
+		if err != nil {
+			return nil, err
+		}
+		usage[vpcsQuota.QuotaCode] += float64(len(output.Vpcs))
+		if output.NextToken == nil {
+			break
+		}
+		vpcsInput.NextToken = output.NextToken
+	}
+
+	gatewaysInput := &ec2.DescribeInternetGatewaysInput{}
+	usage[internetGatewaysQuota.QuotaCode] = 0
+	for {
+		output, err := c.ec2Client.DescribeInternetGateways(gatewaysInput)
+		if err != nil {
+			return nil, err
+		}
+		usage[internetGatewaysQuota.QuotaCode] += float64(len(output.InternetGateways))
+		if output.NextToken == nil {
+			break
+		}
+		gatewaysInput.NextToken = output.NextToken
+	}
+
+	natGatewaysPerAZ, err := c.getNatGatewaysPerAZ()
+	if err != nil {
+		return nil, err
+	}
+	// The new NAT gateways can be in any availability zone, so the busiest one is used
+	usage[natGatewaysQuota.QuotaCode] = 0
+	for _, count := range natGatewaysPerAZ {
+		usage[natGatewaysQuota.QuotaCode] = math.Max(usage[natGatewaysQuota.QuotaCode], float64(count))
+	}
+
+	for _, q := range storageQuotas {
+		usage[q.QuotaCode] = 0
+	}
+	usage[io1IOPSQuota.QuotaCode] = 0
+	volumesInput := &ec2.DescribeVolumesInput{}
+	for {
+		output, err := c.ec2Client.DescribeVolumes(volumesInput)
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range output.Volumes {
+			volumeType := aws.StringValue(volume.VolumeType)
+			if storageQuota, ok := storageQuotas[volumeType]; ok {
+				usage[storageQuota.QuotaCode] += float64(aws.Int64Value(volume.Size)) / 1024
+			}
+			if volumeType == VolumeTypeIO1 {
+				usage[io1IOPSQuota.QuotaCode] += float64(aws.Int64Value(volume.Iops))
+			}
+		}
+		if output.NextToken == nil {
+			break
+		}
+		volumesInput.NextToken = output.NextToken
+	}
+
+	return usage, nil
+}
+
+// getNatGatewaysPerAZ returns the number of NAT gateways that are pending or available in each
+// availability zone.
+func (c *awsClient) getNatGatewaysPerAZ() (map[string]int, error) {
+	subnetIDs := []string{}
+	input := &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{{
+			Name:   aws.String("state"),
+			Values: aws.StringSlice([]string{"pending", "available"}),
+		}},
+	}
+	for {
+		output, err := c.ec2Client.DescribeNatGateways(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, gateway := range output.NatGateways {
+			subnetIDs = append(subnetIDs, aws.StringValue(gateway.SubnetId))
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	result := map[string]int{}
+	if len(subnetIDs) == 0 {
+		return result, nil
+	}
+	subnets, err := c.getSubnetIDs(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(helper.MapKeys(helper.SliceToMap(subnetIDs))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	availabilityZones := map[string]string{}
+	for _, subnet := range subnets {
+		availabilityZones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+	}
+	for _, subnetID := range subnetIDs {
+		result[availabilityZones[subnetID]]++
+	}
+	return result, nil
+}
+
 // ListServiceQuotas list available quotas for service
 func ListServiceQuotas(client *awsClient, serviceCode string) ([]*servicequotas.ServiceQuota, error) {
 	var serviceQuotas []*servicequotas.ServiceQuota
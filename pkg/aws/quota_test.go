@@ -0,0 +1,173 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Quota", func() {
+	Context("InstanceQuota", func() {
+		It("Returns the quota of the instance family", func() {
+			_, quotaCode, _ := aws.InstanceQuota("m5.xlarge")
+			Expect(quotaCode).To(Equal("L-1216C47A"))
+			_, quotaCode, _ = aws.InstanceQuota("p3.2xlarge")
+			Expect(quotaCode).To(Equal("L-417A185B"))
+			_, quotaCode, _ = aws.InstanceQuota("g4dn.xlarge")
+			Expect(quotaCode).To(Equal("L-DB2E81BA"))
+			_, quotaCode, _ = aws.InstanceQuota("inf1.xlarge")
+			Expect(quotaCode).To(Equal("L-1945791B"))
+			_, quotaCode, _ = aws.InstanceQuota("u-6tb1.metal")
+			Expect(quotaCode).To(Equal("L-43DA4232"))
+		})
+	})
+
+	Context("ValidateQuotaForSpec", func() {
+		var (
+			client            aws.Client
+			mockCtrl          *gomock.Controller
+			mockEC2API        *mocks.MockEC2API
+			mockServiceQuotas *mocks.MockServiceQuotasAPI
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockEC2API = mocks.NewMockEC2API(mockCtrl)
+			mockServiceQuotas = mocks.NewMockServiceQuotasAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mocks.NewMockIAMAPI(mockCtrl),
+				mockEC2API,
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockS3API(mockCtrl),
+				mocks.NewMockSecretsManagerAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mockServiceQuotas,
+				&session.Session{},
+				&aws.AccessKey{},
+				false,
+			)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("Compares the required quotas with the usage and the limits", func() {
+			limits := map[string]map[string]float64{
+				"ec2":                  {"L-1216C47A": 32, "L-417A185B": 8, "L-0263D0A3": 5},
+				"vpc":                  {"L-F678F1CE": 5, "L-A4707A72": 5, "L-FE5A380F": 5},
+				"ebs":                  {"L-7A658B76": 50},
+				"elasticloadbalancing": {"L-69A177A2": 50},
+			}
+			mockServiceQuotas.EXPECT().ListServiceQuotasPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *servicequotas.ListServiceQuotasInput,
+					fn func(*servicequotas.ListServiceQuotasOutput, bool) bool) error {
+					page := &servicequotas.ListServiceQuotasOutput{}
+					for quotaCode, value := range limits[*input.ServiceCode] {
+						page.Quotas = append(page.Quotas, &servicequotas.ServiceQuota{
+							QuotaCode: awssdk.String(quotaCode),
+							Value:     awssdk.Float64(value),
+						})
+					}
+					fn(page, true)
+					return nil
+				}).Times(4)
+			mockEC2API.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{{
+					Instances: []*ec2.Instance{{
+						InstanceType: awssdk.String("m5.large"),
+						CpuOptions:   &ec2.CpuOptions{CoreCount: awssdk.Int64(1), ThreadsPerCore: awssdk.Int64(2)},
+					}, {
+						InstanceType: awssdk.String("p3.2xlarge"),
+						CpuOptions:   &ec2.CpuOptions{CoreCount: awssdk.Int64(4), ThreadsPerCore: awssdk.Int64(2)},
+					}},
+				}},
+			}, nil)
+			mockEC2API.EXPECT().DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+				Addresses: []*ec2.Address{{}, {}, {}, {}},
+			}, nil)
+			mockEC2API.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+				Vpcs: []*ec2.Vpc{{}, {}, {}, {}, {}},
+			}, nil)
+			mockEC2API.EXPECT().DescribeInternetGateways(gomock.Any()).Return(&ec2.DescribeInternetGatewaysOutput{
+				InternetGateways: []*ec2.InternetGateway{{}},
+			}, nil)
+			mockEC2API.EXPECT().DescribeNatGateways(gomock.Any()).Return(&ec2.DescribeNatGatewaysOutput{
+				NatGateways: []*ec2.NatGateway{
+					{SubnetId: awssdk.String("subnet-a")},
+					{SubnetId: awssdk.String("subnet-a")},
+					{SubnetId: awssdk.String("subnet-b")},
+				},
+			}, nil)
+			mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+				Subnets: []*ec2.Subnet{
+					{SubnetId: awssdk.String("subnet-a"), AvailabilityZone: awssdk.String("us-east-1a")},
+					{SubnetId: awssdk.String("subnet-b"), AvailabilityZone: awssdk.String("us-east-1b")},
+				},
+			}, nil)
+			mockEC2API.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+				Volumes: []*ec2.Volume{{VolumeType: awssdk.String("gp3"), Size: awssdk.Int64(1024)}},
+			}, nil)
+
+			checks, err := client.ValidateQuotaForSpec(aws.QuotaSpec{
+				Nodes: []aws.QuotaNodes{{
+					InstanceType: "m5.xlarge",
+					VCPUs:        4,
+					Count:        3,
+					VolumeType:   aws.VolumeTypeGP3,
+					VolumeSize:   512,
+				}, {
+					InstanceType: "p3.2xlarge",
+					VCPUs:        8,
+					Count:        1,
+					VolumeType:   aws.VolumeTypeGP3,
+					VolumeSize:   512,
+				}},
+				VPCs:                 1,
+				InternetGateways:     1,
+				NatGateways:          1,
+				NetworkLoadBalancers: 2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			byCode := map[string]*aws.QuotaCheck{}
+			for _, check := range checks {
+				byCode[check.QuotaCode] = check
+			}
+			Expect(byCode).To(HaveLen(8))
+
+			standard := byCode["L-1216C47A"]
+			Expect(standard.Required).To(Equal(12.0))
+			Expect(*standard.Usage).To(Equal(2.0))
+			Expect(standard.Sufficient()).To(BeTrue())
+
+			p := byCode["L-417A185B"]
+			Expect(p.Required).To(Equal(8.0))
+			Expect(*p.Usage).To(Equal(8.0))
+			Expect(p.Sufficient()).To(BeFalse())
+
+			Expect(byCode["L-0263D0A3"].Required).To(Equal(1.0))
+			Expect(byCode["L-0263D0A3"].Sufficient()).To(BeTrue())
+			Expect(byCode["L-F678F1CE"].Sufficient()).To(BeFalse())
+			Expect(*byCode["L-FE5A380F"].Usage).To(Equal(2.0))
+
+			storage := byCode["L-7A658B76"]
+			Expect(storage.Required).To(Equal(2.0))
+			Expect(*storage.Usage).To(Equal(1.0))
+
+			loadBalancers := byCode["L-69A177A2"]
+			Expect(loadBalancers.Required).To(Equal(2.0))
+			Expect(loadBalancers.Usage).To(BeNil())
+			Expect(loadBalancers.Sufficient()).To(BeTrue())
+		})
+	})
+})
@@ -209,9 +209,13 @@ func BuildOperatorRoleCommands(prefix string, accountID string, awsClient Client
 	return commands
 }
 
+// OidcProviderOutput is an OIDC provider created for ROSA. Its JSON representation is the schema
+// used by the '--output' flag of 'rosa list oidc-providers'. The InUse field isn't set by the AWS
+// client, as it depends on the clusters known to OCM.
 type OidcProviderOutput struct {
-	Arn       string
-	ClusterId string
+	Arn       string `json:"arn"`
+	ClusterId string `json:"cluster_id"`
+	InUse     bool   `json:"in_use"`
 }
 
 func (c *awsClient) ListOidcProviders(targetClusterId string) ([]OidcProviderOutput, error) {
@@ -61,3 +61,6 @@ const OperatorName = "operator_name"
 const InUse = "in_use"
 
 const True = "true"
+
+// NetworkName is the name of the tag that will contain the name of a network created with 'rosa create network'
+const NetworkName = prefix + "network_name"
@@ -0,0 +1,621 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the command line flags and interactive questions used to configure the
+// cluster autoscaler, shared by 'rosa create cluster' and the autoscaler commands.
+
+package clusterautoscaler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+const (
+	BalanceSimilarNodeGroupsFlag      = "balance-similar-node-groups"
+	SkipNodesWithLocalStorageFlag     = "skip-nodes-with-local-storage"
+	LogVerbosityFlag                  = "log-verbosity"
+	MaxPodGracePeriodFlag             = "max-pod-grace-period"
+	PodPriorityThresholdFlag          = "pod-priority-threshold"
+	IgnoreDaemonsetsUtilizationFlag   = "ignore-daemonsets-utilization"
+	MaxNodeProvisionTimeFlag          = "max-node-provision-time"
+	BalancingIgnoredLabelsFlag        = "balancing-ignored-labels"
+	MaxNodesTotalFlag                 = "max-nodes-total"
+	MinCoresFlag                      = "min-cores"
+	MaxCoresFlag                      = "max-cores"
+	MinMemoryFlag                     = "min-memory"
+	MaxMemoryFlag                     = "max-memory"
+	ScaleDownEnabledFlag              = "scale-down-enabled"
+	ScaleDownUnneededTimeFlag         = "scale-down-unneeded-time"
+	ScaleDownUtilizationThresholdFlag = "scale-down-utilization-threshold"
+	ScaleDownDelayAfterAddFlag        = "scale-down-delay-after-add"
+	ScaleDownDelayAfterDeleteFlag     = "scale-down-delay-after-delete"
+	ScaleDownDelayAfterFailureFlag    = "scale-down-delay-after-failure"
+)
+
+var flagNames = []string{
+	BalanceSimilarNodeGroupsFlag,
+	SkipNodesWithLocalStorageFlag,
+	LogVerbosityFlag,
+	MaxPodGracePeriodFlag,
+	PodPriorityThresholdFlag,
+	IgnoreDaemonsetsUtilizationFlag,
+	MaxNodeProvisionTimeFlag,
+	BalancingIgnoredLabelsFlag,
+	MaxNodesTotalFlag,
+	MinCoresFlag,
+	MaxCoresFlag,
+	MinMemoryFlag,
+	MaxMemoryFlag,
+	ScaleDownEnabledFlag,
+	ScaleDownUnneededTimeFlag,
+	ScaleDownUtilizationThresholdFlag,
+	ScaleDownDelayAfterAddFlag,
+	ScaleDownDelayAfterDeleteFlag,
+	ScaleDownDelayAfterFailureFlag,
+}
+
+// AddFlags adds the autoscaler flags to the flag set, with the given prefix prepended to the
+// name of every flag. The values of the flags are stored in the returned configuration.
+func AddFlags(flags *pflag.FlagSet, prefix string) *ocm.AutoscalerConfig {
+	config := &ocm.AutoscalerConfig{}
+
+	flags.BoolVar(
+		&config.BalanceSimilarNodeGroups,
+		prefix+BalanceSimilarNodeGroupsFlag,
+		false,
+		"Identify node groups with the same instance type and label set, "+
+			"and aim to balance respective sizes of those node groups.",
+	)
+
+	flags.BoolVar(
+		&config.SkipNodesWithLocalStorage,
+		prefix+SkipNodesWithLocalStorageFlag,
+		false,
+		"If true cluster autoscaler will never delete nodes with pods with local storage, e.g. EmptyDir or HostPath.",
+	)
+
+	flags.IntVar(
+		&config.LogVerbosity,
+		prefix+LogVerbosityFlag,
+		1,
+		"Autoscaler log level. Default is 1, 4 is a good option when trying to debug the autoscaler.",
+	)
+
+	flags.IntVar(
+		&config.MaxPodGracePeriod,
+		prefix+MaxPodGracePeriodFlag,
+		0,
+		"Gives pods graceful termination time before scaling down, measured in seconds.",
+	)
+
+	flags.IntVar(
+		&config.PodPriorityThreshold,
+		prefix+PodPriorityThresholdFlag,
+		0,
+		"The priority that a pod must exceed to cause the cluster autoscaler to deploy additional nodes. "+
+			"Expects an integer, can be negative.",
+	)
+
+	flags.BoolVar(
+		&config.IgnoreDaemonsetsUtilization,
+		prefix+IgnoreDaemonsetsUtilizationFlag,
+		false,
+		"Should cluster-autoscaler ignore DaemonSet pods when calculating resource utilization for scaling down.",
+	)
+
+	flags.StringVar(
+		&config.MaxNodeProvisionTime,
+		prefix+MaxNodeProvisionTimeFlag,
+		"",
+		"Maximum time cluster-autoscaler waits for node to be provisioned. "+
+			"Expects string comprised of an integer and time unit (ns|us|µs|ms|s|m|h), examples: 20m, 1h.",
+	)
+
+	flags.StringSliceVar(
+		&config.BalancingIgnoredLabels,
+		prefix+BalancingIgnoredLabelsFlag,
+		nil,
+		"A comma-separated list of label keys that cluster autoscaler should ignore when considering node group similarity.",
+	)
+
+	// Resource Limits
+	flags.IntVar(
+		&config.ResourceLimits.MaxNodesTotal,
+		prefix+MaxNodesTotalFlag,
+		1000,
+		"Total amount of nodes that can exist in the cluster, including non-scaled nodes.",
+	)
+
+	flags.IntVar(
+		&config.ResourceLimits.Cores.Min,
+		prefix+MinCoresFlag,
+		0,
+		"Minimum limit for the amount of cores to deploy in the cluster.",
+	)
+
+	flags.IntVar(
+		&config.ResourceLimits.Cores.Max,
+		prefix+MaxCoresFlag,
+		100,
+		"Maximum limit for the amount of cores to deploy in the cluster.",
+	)
+
+	flags.IntVar(
+		&config.ResourceLimits.Memory.Min,
+		prefix+MinMemoryFlag,
+		0,
+		"Minimum limit for the amount of memory, in GiB, in the cluster.",
+	)
+
+	flags.IntVar(
+		&config.ResourceLimits.Memory.Max,
+		prefix+MaxMemoryFlag,
+		4096,
+		"Maximum limit for the amount of memory, in GiB, in the cluster.",
+	)
+
+	// TODO: handle GPU limitations
+
+	// Scale down Configuration
+
+	flags.BoolVar(
+		&config.ScaleDown.Enabled,
+		prefix+ScaleDownEnabledFlag,
+		false,
+		"Should cluster-autoscaler be able to scale down the cluster.",
+	)
+
+	flags.StringVar(
+		&config.ScaleDown.UnneededTime,
+		prefix+ScaleDownUnneededTimeFlag,
+		"",
+		"Increasing value will make nodes stay up longer, waiting for pods to be scheduled "+
+			"while decreasing value will make nodes be deleted sooner.",
+	)
+
+	flags.Float64Var(
+		&config.ScaleDown.UtilizationThreshold,
+		prefix+ScaleDownUtilizationThresholdFlag,
+		0.5,
+		"Node utilization level, defined as sum of requested resources divided by capacity, "+
+			"below which a node can be considered for scale down. Value should be between 0 and 1.",
+	)
+
+	flags.StringVar(
+		&config.ScaleDown.DelayAfterAdd,
+		prefix+ScaleDownDelayAfterAddFlag,
+		"",
+		"After a scale-up, consider scaling down only after this amount of time.",
+	)
+
+	flags.StringVar(
+		&config.ScaleDown.DelayAfterDelete,
+		prefix+ScaleDownDelayAfterDeleteFlag,
+		"",
+		"After a scale-down, consider scaling down again only after this amount of time.",
+	)
+
+	flags.StringVar(
+		&config.ScaleDown.DelayAfterFailure,
+		prefix+ScaleDownDelayAfterFailureFlag,
+		"",
+		"After a failing scale-down, consider scaling down again only after this amount of time.",
+	)
+
+	return config
+}
+
+// IsSetViaCLI returns true if any of the autoscaler flags has been set in the command line.
+func IsSetViaCLI(flags *pflag.FlagSet, prefix string) bool {
+	for _, name := range flagNames {
+		if flags.Changed(prefix + name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUnchanged copies the values of the current configuration into the fields of config whose
+// flags haven't been set in the command line, so that only the flags that are set change an
+// existing autoscaler.
+func SetUnchanged(flags *pflag.FlagSet, prefix string, config *ocm.AutoscalerConfig,
+	current *ocm.AutoscalerConfig) {
+	changed := func(name string) bool {
+		return flags.Changed(prefix + name)
+	}
+	if !changed(BalanceSimilarNodeGroupsFlag) {
+		config.BalanceSimilarNodeGroups = current.BalanceSimilarNodeGroups
+	}
+	if !changed(SkipNodesWithLocalStorageFlag) {
+		config.SkipNodesWithLocalStorage = current.SkipNodesWithLocalStorage
+	}
+	if !changed(LogVerbosityFlag) {
+		config.LogVerbosity = current.LogVerbosity
+	}
+	if !changed(MaxPodGracePeriodFlag) {
+		config.MaxPodGracePeriod = current.MaxPodGracePeriod
+	}
+	if !changed(PodPriorityThresholdFlag) {
+		config.PodPriorityThreshold = current.PodPriorityThreshold
+	}
+	if !changed(IgnoreDaemonsetsUtilizationFlag) {
+		config.IgnoreDaemonsetsUtilization = current.IgnoreDaemonsetsUtilization
+	}
+	if !changed(MaxNodeProvisionTimeFlag) {
+		config.MaxNodeProvisionTime = current.MaxNodeProvisionTime
+	}
+	if !changed(BalancingIgnoredLabelsFlag) {
+		config.BalancingIgnoredLabels = current.BalancingIgnoredLabels
+	}
+	if !changed(MaxNodesTotalFlag) {
+		config.ResourceLimits.MaxNodesTotal = current.ResourceLimits.MaxNodesTotal
+	}
+	if !changed(MinCoresFlag) {
+		config.ResourceLimits.Cores.Min = current.ResourceLimits.Cores.Min
+	}
+	if !changed(MaxCoresFlag) {
+		config.ResourceLimits.Cores.Max = current.ResourceLimits.Cores.Max
+	}
+	if !changed(MinMemoryFlag) {
+		config.ResourceLimits.Memory.Min = current.ResourceLimits.Memory.Min
+	}
+	if !changed(MaxMemoryFlag) {
+		config.ResourceLimits.Memory.Max = current.ResourceLimits.Memory.Max
+	}
+	if !changed(ScaleDownEnabledFlag) {
+		config.ScaleDown.Enabled = current.ScaleDown.Enabled
+	}
+	if !changed(ScaleDownUnneededTimeFlag) {
+		config.ScaleDown.UnneededTime = current.ScaleDown.UnneededTime
+	}
+	if !changed(ScaleDownUtilizationThresholdFlag) {
+		config.ScaleDown.UtilizationThreshold = current.ScaleDown.UtilizationThreshold
+	}
+	if !changed(ScaleDownDelayAfterAddFlag) {
+		config.ScaleDown.DelayAfterAdd = current.ScaleDown.DelayAfterAdd
+	}
+	if !changed(ScaleDownDelayAfterDeleteFlag) {
+		config.ScaleDown.DelayAfterDelete = current.ScaleDown.DelayAfterDelete
+	}
+	if !changed(ScaleDownDelayAfterFailureFlag) {
+		config.ScaleDown.DelayAfterFailure = current.ScaleDown.DelayAfterFailure
+	}
+}
+
+// GetAutoscalerOptions asks interactively for the values of the flags that haven't been set in
+// the command line and validates the result. When confirmBeforeAllArgs is set and no flag has
+// been set, the user is first asked whether to configure the autoscaler at all, and nil is
+// returned if not.
+func GetAutoscalerOptions(flags *pflag.FlagSet, prefix string, confirmBeforeAllArgs bool,
+	args *ocm.AutoscalerConfig) (*ocm.AutoscalerConfig, error) {
+	var err error
+	config := *args
+	isSet := func(name string) bool {
+		return !interactive.Enabled() || flags.Changed(prefix+name)
+	}
+	usage := func(name string) string {
+		return flags.Lookup(prefix + name).Usage
+	}
+
+	if confirmBeforeAllArgs && !IsSetViaCLI(flags, prefix) {
+		configure := false
+		if interactive.Enabled() {
+			configure, err = interactive.GetBool(interactive.Input{
+				Question: "Configure cluster-autoscaler",
+				Help:     "Set cluster-wide autoscaling configurations",
+				Default:  false,
+				Required: false,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Expected a valid value for configure-cluster-autoscaler: %s", err)
+			}
+		}
+		if !configure {
+			return nil, nil
+		}
+	}
+
+	if !isSet(BalanceSimilarNodeGroupsFlag) {
+		config.BalanceSimilarNodeGroups, err = interactive.GetBool(interactive.Input{
+			Question: "Balance similar node groups",
+			Help:     usage(BalanceSimilarNodeGroupsFlag),
+			Default:  config.BalanceSimilarNodeGroups,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+BalanceSimilarNodeGroupsFlag, err)
+		}
+	}
+
+	if !isSet(SkipNodesWithLocalStorageFlag) {
+		config.SkipNodesWithLocalStorage, err = interactive.GetBool(interactive.Input{
+			Question: "Skip nodes with local storage",
+			Help:     usage(SkipNodesWithLocalStorageFlag),
+			Default:  config.SkipNodesWithLocalStorage,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+SkipNodesWithLocalStorageFlag, err)
+		}
+	}
+
+	if !isSet(LogVerbosityFlag) {
+		config.LogVerbosity, err = interactive.GetInt(interactive.Input{
+			Question: "Log verbosity",
+			Help:     usage(LogVerbosityFlag),
+			Default:  config.LogVerbosity,
+			Required: false,
+			Validators: []interactive.Validator{
+				intValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+LogVerbosityFlag, err)
+		}
+	}
+
+	if !isSet(BalancingIgnoredLabelsFlag) {
+		labels, err := interactive.GetString(interactive.Input{
+			Question: "Labels that cluster autoscaler should ignore when considering node group similarity",
+			Help:     usage(BalancingIgnoredLabelsFlag),
+			Default:  strings.Join(config.BalancingIgnoredLabels, ","),
+			Required: false,
+			Validators: []interactive.Validator{
+				ocm.ValidateBalancingIgnoredLabels,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+BalancingIgnoredLabelsFlag, err)
+		}
+		config.BalancingIgnoredLabels = nil
+		if labels != "" {
+			config.BalancingIgnoredLabels = strings.Split(labels, ",")
+		}
+	}
+
+	if !isSet(IgnoreDaemonsetsUtilizationFlag) {
+		config.IgnoreDaemonsetsUtilization, err = interactive.GetBool(interactive.Input{
+			Question: "Ignore daemonsets utilization",
+			Help:     usage(IgnoreDaemonsetsUtilizationFlag),
+			Default:  config.IgnoreDaemonsetsUtilization,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+IgnoreDaemonsetsUtilizationFlag, err)
+		}
+	}
+
+	if !isSet(MaxNodeProvisionTimeFlag) {
+		config.MaxNodeProvisionTime, err = interactive.GetString(interactive.Input{
+			Question: "Maximum node provision time",
+			Help:     usage(MaxNodeProvisionTimeFlag),
+			Default:  config.MaxNodeProvisionTime,
+			Required: false,
+			Validators: []interactive.Validator{
+				durationStringValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MaxNodeProvisionTimeFlag, err)
+		}
+	}
+
+	if !isSet(MaxPodGracePeriodFlag) {
+		config.MaxPodGracePeriod, err = interactive.GetInt(interactive.Input{
+			Question: "Maximum pod grace period",
+			Help:     usage(MaxPodGracePeriodFlag),
+			Default:  config.MaxPodGracePeriod,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MaxPodGracePeriodFlag, err)
+		}
+	}
+
+	if !isSet(PodPriorityThresholdFlag) {
+		config.PodPriorityThreshold, err = interactive.GetInt(interactive.Input{
+			Question: "Pod priority threshold",
+			Help:     usage(PodPriorityThresholdFlag),
+			Default:  config.PodPriorityThreshold,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+PodPriorityThresholdFlag, err)
+		}
+	}
+
+	if !isSet(MaxNodesTotalFlag) {
+		config.ResourceLimits.MaxNodesTotal, err = interactive.GetInt(interactive.Input{
+			Question: "Maximum amount of nodes in the cluster",
+			Help:     usage(MaxNodesTotalFlag),
+			Default:  config.ResourceLimits.MaxNodesTotal,
+			Required: false,
+			Validators: []interactive.Validator{
+				nonNegativeIntValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MaxNodesTotalFlag, err)
+		}
+	}
+
+	if !isSet(MinCoresFlag) {
+		config.ResourceLimits.Cores.Min, err = interactive.GetInt(interactive.Input{
+			Question: "Minimum number of cores to deploy in cluster",
+			Help:     usage(MinCoresFlag),
+			Default:  config.ResourceLimits.Cores.Min,
+			Required: false,
+			Validators: []interactive.Validator{
+				nonNegativeIntValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MinCoresFlag, err)
+		}
+	}
+
+	if !isSet(MaxCoresFlag) {
+		config.ResourceLimits.Cores.Max, err = interactive.GetInt(interactive.Input{
+			Question: "Maximum number of cores to deploy in cluster",
+			Help:     usage(MaxCoresFlag),
+			Default:  config.ResourceLimits.Cores.Max,
+			Required: false,
+			Validators: []interactive.Validator{
+				nonNegativeIntValidator,
+				getValidMaxRangeValidator(config.ResourceLimits.Cores.Min),
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MaxCoresFlag, err)
+		}
+	}
+
+	if !isSet(MinMemoryFlag) {
+		config.ResourceLimits.Memory.Min, err = interactive.GetInt(interactive.Input{
+			Question: "Minimum amount of memory, in GiB, in the cluster",
+			Help:     usage(MinMemoryFlag),
+			Default:  config.ResourceLimits.Memory.Min,
+			Required: false,
+			Validators: []interactive.Validator{
+				nonNegativeIntValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MinMemoryFlag, err)
+		}
+	}
+
+	if !isSet(MaxMemoryFlag) {
+		config.ResourceLimits.Memory.Max, err = interactive.GetInt(interactive.Input{
+			Question: "Maximum amount of memory, in GiB, in the cluster",
+			Help:     usage(MaxMemoryFlag),
+			Default:  config.ResourceLimits.Memory.Max,
+			Required: false,
+			Validators: []interactive.Validator{
+				nonNegativeIntValidator,
+				getValidMaxRangeValidator(config.ResourceLimits.Memory.Min),
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+MaxMemoryFlag, err)
+		}
+	}
+
+	// scale-down configs
+
+	if !isSet(ScaleDownEnabledFlag) {
+		config.ScaleDown.Enabled, err = interactive.GetBool(interactive.Input{
+			Question: "Should scale-down be enabled",
+			Help:     usage(ScaleDownEnabledFlag),
+			Default:  config.ScaleDown.Enabled,
+			Required: false,
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownEnabledFlag, err)
+		}
+	}
+
+	if !isSet(ScaleDownUnneededTimeFlag) {
+		config.ScaleDown.UnneededTime, err = interactive.GetString(interactive.Input{
+			Question: "How long a node should be unneeded before it is eligible for scale down",
+			Help:     usage(ScaleDownUnneededTimeFlag),
+			Default:  config.ScaleDown.UnneededTime,
+			Required: false,
+			Validators: []interactive.Validator{
+				durationStringValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownUnneededTimeFlag, err)
+		}
+	}
+
+	if !isSet(ScaleDownUtilizationThresholdFlag) {
+		config.ScaleDown.UtilizationThreshold, err = interactive.GetFloat(interactive.Input{
+			Question: "Node utilization threshold",
+			Help:     usage(ScaleDownUtilizationThresholdFlag),
+			Default:  config.ScaleDown.UtilizationThreshold,
+			Required: false,
+			Validators: []interactive.Validator{
+				zeroToOneFloatValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownUtilizationThresholdFlag, err)
+		}
+	}
+
+	if !isSet(ScaleDownDelayAfterAddFlag) {
+		config.ScaleDown.DelayAfterAdd, err = interactive.GetString(interactive.Input{
+			Question: "How long after scale up should scale down evaluation resume",
+			Help:     usage(ScaleDownDelayAfterAddFlag),
+			Default:  config.ScaleDown.DelayAfterAdd,
+			Required: false,
+			Validators: []interactive.Validator{
+				durationStringValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownDelayAfterAddFlag, err)
+		}
+	}
+
+	if !isSet(ScaleDownDelayAfterDeleteFlag) {
+		config.ScaleDown.DelayAfterDelete, err = interactive.GetString(interactive.Input{
+			Question: "How long after node deletion should scale down evaluation resume",
+			Help:     usage(ScaleDownDelayAfterDeleteFlag),
+			Default:  config.ScaleDown.DelayAfterDelete,
+			Required: false,
+			Validators: []interactive.Validator{
+				durationStringValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownDelayAfterDeleteFlag, err)
+		}
+	}
+
+	if !isSet(ScaleDownDelayAfterFailureFlag) {
+		config.ScaleDown.DelayAfterFailure, err = interactive.GetString(interactive.Input{
+			Question: "How long after node deletion failure should scale down evaluation resume.",
+			Help:     usage(ScaleDownDelayAfterFailureFlag),
+			Default:  config.ScaleDown.DelayAfterFailure,
+			Required: false,
+			Validators: []interactive.Validator{
+				durationStringValidator,
+			},
+		})
+		if err != nil {
+			return nil, invalidValue(prefix+ScaleDownDelayAfterFailureFlag, err)
+		}
+	}
+
+	err = Validate(&config, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func invalidValue(flag string, err error) error {
+	return fmt.Errorf("Expected a valid value for %s: %s", flag, err)
+}
@@ -0,0 +1,83 @@
+package clusterautoscaler
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("Autoscaler flags", func() {
+	var flags *pflag.FlagSet
+	var args *ocm.AutoscalerConfig
+
+	BeforeEach(func() {
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		args = AddFlags(flags, "autoscaler-")
+	})
+
+	It("Adds the flags with the prefix and their defaults", func() {
+		Expect(flags.Lookup("autoscaler-max-nodes-total")).ToNot(BeNil())
+		Expect(args.LogVerbosity).To(Equal(1))
+		Expect(args.ResourceLimits.MaxNodesTotal).To(Equal(1000))
+		Expect(args.ScaleDown.UtilizationThreshold).To(Equal(0.5))
+		Expect(IsSetViaCLI(flags, "autoscaler-")).To(BeFalse())
+	})
+
+	It("Detects flags set in the command line", func() {
+		Expect(flags.Parse([]string{"--autoscaler-min-cores=2"})).To(Succeed())
+		Expect(IsSetViaCLI(flags, "autoscaler-")).To(BeTrue())
+		Expect(args.ResourceLimits.Cores.Min).To(Equal(2))
+	})
+
+	It("Keeps the current values of the flags that aren't set", func() {
+		Expect(flags.Parse([]string{"--autoscaler-max-nodes-total=50"})).To(Succeed())
+		current := &ocm.AutoscalerConfig{
+			LogVerbosity:           4,
+			BalancingIgnoredLabels: []string{"topology.kubernetes.io/zone"},
+			ResourceLimits:         ocm.ResourceLimits{MaxNodesTotal: 10},
+		}
+		SetUnchanged(flags, "autoscaler-", args, current)
+		Expect(args.LogVerbosity).To(Equal(4))
+		Expect(args.BalancingIgnoredLabels).To(Equal([]string{"topology.kubernetes.io/zone"}))
+		Expect(args.ResourceLimits.MaxNodesTotal).To(Equal(50))
+	})
+
+	It("Returns nil when the autoscaler isn't configured", func() {
+		config, err := GetAutoscalerOptions(flags, "autoscaler-", true, args)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(BeNil())
+	})
+
+	It("Validates the options", func() {
+		Expect(flags.Parse([]string{"--autoscaler-min-cores=20", "--autoscaler-max-cores=10"})).To(Succeed())
+		_, err := GetAutoscalerOptions(flags, "autoscaler-", true, args)
+		Expect(err).To(MatchError(
+			"Expected a valid value for autoscaler-max-cores: max value must be greater or equal than min value 20."))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	It("Accepts the defaults", func() {
+		config := AddFlags(pflag.NewFlagSet("test", pflag.ContinueOnError), "")
+		Expect(Validate(config, "")).To(Succeed())
+	})
+
+	It("Rejects invalid durations", func() {
+		config := &ocm.AutoscalerConfig{ScaleDown: ocm.ScaleDownConfig{DelayAfterAdd: "10 minutes"}}
+		Expect(Validate(config, "")).To(MatchError(ContainSubstring(
+			"Expected a valid value for scale-down-delay-after-add")))
+	})
+
+	It("Rejects invalid labels", func() {
+		config := &ocm.AutoscalerConfig{BalancingIgnoredLabels: []string{"-invalid"}}
+		Expect(Validate(config, "")).To(MatchError(ContainSubstring(
+			"Expected a valid value for balancing-ignored-labels")))
+	})
+
+	It("Rejects utilization thresholds out of range", func() {
+		config := &ocm.AutoscalerConfig{ScaleDown: ocm.ScaleDownConfig{UtilizationThreshold: 1.5}}
+		Expect(Validate(config, "")).To(MatchError(ContainSubstring("between 0 and 1")))
+	})
+})
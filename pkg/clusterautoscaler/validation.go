@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterautoscaler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Validate checks the values of the autoscaler configuration. The prefix is the one used for
+// the flags, so that errors refer to the flags that the user has set.
+func Validate(config *ocm.AutoscalerConfig, prefix string) error {
+	validations := []struct {
+		flag      string
+		value     interface{}
+		validator func(interface{}) error
+	}{
+		{LogVerbosityFlag, config.LogVerbosity, intValidator},
+		{BalancingIgnoredLabelsFlag, strings.Join(config.BalancingIgnoredLabels, ","),
+			ocm.ValidateBalancingIgnoredLabels},
+		{MaxNodeProvisionTimeFlag, config.MaxNodeProvisionTime, durationStringValidator},
+		{MaxNodesTotalFlag, config.ResourceLimits.MaxNodesTotal, nonNegativeIntValidator},
+		{MinCoresFlag, config.ResourceLimits.Cores.Min, nonNegativeIntValidator},
+		{MaxCoresFlag, config.ResourceLimits.Cores.Max, nonNegativeIntValidator},
+		{MaxCoresFlag, config.ResourceLimits.Cores.Max,
+			getValidMaxRangeValidator(config.ResourceLimits.Cores.Min)},
+		{MinMemoryFlag, config.ResourceLimits.Memory.Min, nonNegativeIntValidator},
+		{MaxMemoryFlag, config.ResourceLimits.Memory.Max, nonNegativeIntValidator},
+		{MaxMemoryFlag, config.ResourceLimits.Memory.Max,
+			getValidMaxRangeValidator(config.ResourceLimits.Memory.Min)},
+		{ScaleDownUnneededTimeFlag, config.ScaleDown.UnneededTime, durationStringValidator},
+		{ScaleDownUtilizationThresholdFlag, config.ScaleDown.UtilizationThreshold, zeroToOneFloatValidator},
+		{ScaleDownDelayAfterAddFlag, config.ScaleDown.DelayAfterAdd, durationStringValidator},
+		{ScaleDownDelayAfterDeleteFlag, config.ScaleDown.DelayAfterDelete, durationStringValidator},
+		{ScaleDownDelayAfterFailureFlag, config.ScaleDown.DelayAfterFailure, durationStringValidator},
+	}
+	for _, validation := range validations {
+		err := validation.validator(validation.value)
+		if err != nil {
+			return invalidValue(prefix+validation.flag, err)
+		}
+	}
+	return nil
+}
+
+func zeroToOneFloatValidator(val interface{}) error {
+	if val == "" {
+		return nil
+	}
+	number, err := strconv.ParseFloat(fmt.Sprintf("%v", val), 64)
+	if err != nil {
+		return fmt.Errorf("Failed parsing '%v' into a floating-point number.", val)
+	}
+	if number > 1 || number < 0 {
+		return fmt.Errorf("Expecting a floating-point number between 0 and 1.")
+	}
+	return nil
+}
+
+func durationStringValidator(val interface{}) error {
+	if val == "" {
+		return nil
+	}
+	input, ok := val.(string)
+
+	if !ok {
+		return fmt.Errorf("Can only validate strings, got %v", val)
+	}
+
+	if input == "" {
+		return nil
+	}
+
+	re := regexp.MustCompile("^([0-9]+(.[0-9]+)?(ns|us|µs|ms|s|m|h))+$")
+	regexPass := re.MatchString(input)
+	if !regexPass {
+		return fmt.Errorf("Expecting an integer plus unit of time (without spaces). " +
+			"Options for time units include: ns, us, µs, ms, s, m, h. Examples: 2000000ns, 180s, 2m, etc.")
+	}
+	return nil
+
+}
+
+func nonNegativeIntValidator(val interface{}) error {
+	if val == "" { // if a value is not passed it should not throw an error (optional value)
+		return nil
+	}
+	number, err := strconv.Atoi(fmt.Sprintf("%v", val))
+	if err != nil {
+		return fmt.Errorf("Failed parsing '%v' to an integer number.", val)
+	}
+
+	if number < 0 {
+		return fmt.Errorf("Number must be greater or equal to zero.")
+	}
+
+	return nil
+}
+
+// getValidMaxRangeValidator returns a validator function that asserts a given
+// number is greater than or equal to a fixed minimal number.
+func getValidMaxRangeValidator(min int) func(interface{}) error {
+	return func(val interface{}) error {
+		if val == "" { // Allowing optional inputs
+			return nil
+		}
+
+		max, err := strconv.Atoi(fmt.Sprintf("%v", val))
+		if err != nil {
+			return fmt.Errorf("Failed parsing '%v' to an integer number.", val)
+		}
+
+		if max < min {
+			return fmt.Errorf("max value must be greater or equal than min value %d.", min)
+		}
+
+		return nil
+	}
+}
+
+func intValidator(val interface{}) error {
+	if val == "" { // if a value is not passed it should not throw an error (optional value)
+		return nil
+	}
+	_, err := strconv.Atoi(fmt.Sprintf("%v", val))
+	return err
+}
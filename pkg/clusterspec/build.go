@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that build the OCM objects of the day-2 resources of a
+// cluster definition. They are the reverse of the functions in export.go.
+
+package clusterspec
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// MachinePoolBuilder returns the builder of the machine pool of a classic cluster. When update
+// is set only the fields that can be changed on an existing machine pool are added.
+func (m *MachinePool) MachinePoolBuilder(update bool) (*cmv1.MachinePoolBuilder, error) {
+	builder := cmv1.NewMachinePool().ID(m.Name)
+	if m.Autoscaling != nil {
+		builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+			MinReplicas(m.Autoscaling.MinReplicas).
+			MaxReplicas(m.Autoscaling.MaxReplicas))
+	} else if m.Replicas != nil {
+		builder.Replicas(*m.Replicas)
+	}
+	if m.Labels != nil {
+		builder.Labels(m.Labels)
+	}
+	if m.Taints != nil {
+		builder.Taints(m.taintBuilders()...)
+	}
+	if update {
+		return builder, nil
+	}
+
+	builder.InstanceType(m.InstanceType)
+	if m.AvailabilityZone != "" {
+		builder.AvailabilityZones(m.AvailabilityZone)
+	}
+	if m.Subnet != "" {
+		builder.Subnets(m.Subnet)
+	}
+	if m.DiskSize != "" {
+		size, err := ocm.ParseDiskSizeToGigibyte(m.DiskSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disk size for machine pool '%s': %v", m.Name, err)
+		}
+		builder.RootVolume(cmv1.NewRootVolume().AWS(cmv1.NewAWSVolume().Size(size)))
+	}
+	if m.SpotInstances != nil {
+		spot := cmv1.NewAWSSpotMarketOptions()
+		if m.SpotInstances.MaxPrice != nil {
+			spot.MaxPrice(*m.SpotInstances.MaxPrice)
+		}
+		builder.AWS(cmv1.NewAWSMachinePool().SpotMarketOptions(spot))
+	}
+	return builder, nil
+}
+
+// NodePoolBuilder returns the builder of the node pool of a hosted control plane cluster. When
+// update is set only the fields that can be changed on an existing node pool are added.
+func (m *MachinePool) NodePoolBuilder(update bool) *cmv1.NodePoolBuilder {
+	builder := cmv1.NewNodePool().ID(m.Name)
+	if m.Autoscaling != nil {
+		builder.Autoscaling(cmv1.NewNodePoolAutoscaling().
+			MinReplica(m.Autoscaling.MinReplicas).
+			MaxReplica(m.Autoscaling.MaxReplicas))
+	} else if m.Replicas != nil {
+		builder.Replicas(*m.Replicas)
+	}
+	if m.Labels != nil {
+		builder.Labels(m.Labels)
+	}
+	if m.Taints != nil {
+		builder.Taints(m.taintBuilders()...)
+	}
+	if m.AutoRepair != nil {
+		builder.AutoRepair(*m.AutoRepair)
+	}
+	if m.TuningConfigs != nil {
+		builder.TuningConfigs(m.TuningConfigs...)
+	}
+	if update {
+		return builder
+	}
+
+	builder.AWSNodePool(cmv1.NewAWSNodePool().InstanceType(m.InstanceType))
+	if m.Subnet != "" {
+		builder.Subnet(m.Subnet)
+	}
+	if m.Version != "" {
+		builder.Version(cmv1.NewVersion().ID(m.Version))
+	}
+	return builder
+}
+
+func (m *MachinePool) taintBuilders() []*cmv1.TaintBuilder {
+	builders := make([]*cmv1.TaintBuilder, 0, len(m.Taints))
+	for _, taint := range m.Taints {
+		builders = append(builders, cmv1.NewTaint().Key(taint.Key).Value(taint.Value).Effect(taint.Effect))
+	}
+	return builders
+}
+
+// Builder returns the builder of the identity provider. Secrets are only added if they are set,
+// so that an update doesn't overwrite them.
+func (i *IdentityProvider) Builder() (*cmv1.IdentityProviderBuilder, error) {
+	builder := cmv1.NewIdentityProvider().Name(i.Name)
+	if i.MappingMethod != "" {
+		builder.MappingMethod(cmv1.IdentityProviderMappingMethod(i.MappingMethod))
+	}
+	switch i.Type {
+	case "github":
+		github := cmv1.NewGithubIdentityProvider().
+			ClientID(i.ClientID).
+			Hostname(i.Hostname).
+			Organizations(i.Organizations...).
+			Teams(i.Teams...)
+		if i.ClientSecret != "" {
+			github.ClientSecret(i.ClientSecret)
+		}
+		builder.Type(cmv1.IdentityProviderTypeGithub).Github(github)
+	case "gitlab":
+		gitlab := cmv1.NewGitlabIdentityProvider().ClientID(i.ClientID).URL(i.URL)
+		if i.ClientSecret != "" {
+			gitlab.ClientSecret(i.ClientSecret)
+		}
+		builder.Type(cmv1.IdentityProviderTypeGitlab).Gitlab(gitlab)
+	case "google":
+		google := cmv1.NewGoogleIdentityProvider().ClientID(i.ClientID).HostedDomain(i.HostedDomain)
+		if i.ClientSecret != "" {
+			google.ClientSecret(i.ClientSecret)
+		}
+		builder.Type(cmv1.IdentityProviderTypeGoogle).Google(google)
+	case "ldap":
+		ldap := cmv1.NewLDAPIdentityProvider().
+			URL(i.URL).
+			BindDN(i.BindDN).
+			Insecure(i.Insecure != nil && *i.Insecure).
+			Attributes(cmv1.NewLDAPAttributes().
+				ID(i.IDAttributes...).
+				PreferredUsername(i.UsernameAttributes...).
+				Name(i.NameAttributes...).
+				Email(i.EmailAttributes...))
+		if i.BindPassword != "" {
+			ldap.BindPassword(i.BindPassword)
+		}
+		builder.Type(cmv1.IdentityProviderTypeLDAP).LDAP(ldap)
+	case "openid":
+		openID := cmv1.NewOpenIDIdentityProvider().
+			ClientID(i.ClientID).
+			Issuer(i.IssuerURL).
+			ExtraScopes(i.ExtraScopes...).
+			Claims(cmv1.NewOpenIDClaims().
+				Email(i.EmailClaims...).
+				Name(i.NameClaims...).
+				PreferredUsername(i.UsernameClaims...).
+				Groups(i.GroupsClaims...))
+		if i.ClientSecret != "" {
+			openID.ClientSecret(i.ClientSecret)
+		}
+		builder.Type(cmv1.IdentityProviderTypeOpenID).OpenID(openID)
+	case "htpasswd":
+		users := make([]*cmv1.HTPasswdUserBuilder, 0, len(i.Users))
+		for _, user := range i.Users {
+			users = append(users, cmv1.NewHTPasswdUser().Username(user.Username).Password(user.Password))
+		}
+		builder.Type(cmv1.IdentityProviderTypeHtpasswd).
+			Htpasswd(cmv1.NewHTPasswdIdentityProvider().Users(cmv1.NewHTPasswdUserList().Items(users...)))
+	default:
+		return nil, fmt.Errorf("unsupported type '%s' for identity provider '%s'", i.Type, i.Name)
+	}
+	return builder, nil
+}
+
+// Builder returns the builder of the ingress. Only the fields that are set are added.
+func (i *Ingress) Builder() *cmv1.IngressBuilder {
+	builder := cmv1.NewIngress()
+	if i.Private != nil {
+		if *i.Private {
+			builder.Listening(cmv1.ListeningMethodInternal)
+		} else {
+			builder.Listening(cmv1.ListeningMethodExternal)
+		}
+	}
+	if i.LabelMatch != nil {
+		builder.RouteSelectors(i.LabelMatch)
+	}
+	if i.LBType != "" {
+		builder.LoadBalancerType(cmv1.LoadBalancerFlavor(i.LBType))
+	}
+	if i.ExcludedNamespaces != nil {
+		builder.ExcludedNamespaces(i.ExcludedNamespaces...)
+	}
+	if i.WildcardPolicy != "" {
+		builder.RouteWildcardPolicy(cmv1.WildcardPolicy(i.WildcardPolicy))
+	}
+	if i.NamespaceOwnershipPolicy != "" {
+		builder.RouteNamespaceOwnershipPolicy(cmv1.NamespaceOwnershipPolicy(i.NamespaceOwnershipPolicy))
+	}
+	return builder
+}
+
+func (t *TuningConfig) Builder() *cmv1.TuningConfigBuilder {
+	return cmv1.NewTuningConfig().Name(t.Name).Spec(t.Spec)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types used to describe a cluster declaratively, so that the same
+// definition can be passed to 'rosa create cluster --from-file' any number of times.
+
+package clusterspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	APIVersion = "rosa.openshift.io/v1"
+	Kind       = "Cluster"
+)
+
+// Cluster is the declarative definition of a cluster. Its sections follow the groups of
+// fields in ocm.Spec. Optional scalars are pointers so that an explicit zero value in the
+// file can be told apart from a value that was not set at all.
+type Cluster struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Basic configs
+	Name                      string            `json:"name"`
+	Region                    string            `json:"region,omitempty"`
+	Version                   string            `json:"version,omitempty"`
+	ChannelGroup              string            `json:"channelGroup,omitempty"`
+	MultiAZ                   *bool             `json:"multiAZ,omitempty"`
+	HostedCP                  *bool             `json:"hostedCP,omitempty"`
+	AvailabilityZones         []string          `json:"availabilityZones,omitempty"`
+	DisableWorkloadMonitoring *bool             `json:"disableWorkloadMonitoring,omitempty"`
+	DisableSCPChecks          *bool             `json:"disableSCPChecks,omitempty"`
+	Ec2MetadataHttpTokens     string            `json:"ec2MetadataHttpTokens,omitempty"`
+	BillingAccount            string            `json:"billingAccount,omitempty"`
+	AuditLogRoleARN           string            `json:"auditLogRoleARN,omitempty"`
+	Tags                      map[string]string `json:"tags,omitempty"`
+
+	Encryption     *Encryption     `json:"encryption,omitempty"`
+	Compute        *Compute        `json:"compute,omitempty"`
+	Autoscaler     *Autoscaler     `json:"autoscaler,omitempty"`
+	Network        *Network        `json:"network,omitempty"`
+	STS            *STS            `json:"sts,omitempty"`
+	Proxy          *Proxy          `json:"proxy,omitempty"`
+	DefaultIngress *DefaultIngress `json:"defaultIngress,omitempty"`
+	SharedVPC      *SharedVPC      `json:"sharedVPC,omitempty"`
+	ClusterAdmin   *ClusterAdmin   `json:"clusterAdmin,omitempty"`
+
+	Resources *Resources `json:"resources,omitempty"`
+}
+
+type Encryption struct {
+	FIPS                 *bool  `json:"fips,omitempty"`
+	EtcdEncryption       *bool  `json:"etcdEncryption,omitempty"`
+	KMSKeyARN            string `json:"kmsKeyARN,omitempty"`
+	EtcdEncryptionKMSARN string `json:"etcdEncryptionKMSARN,omitempty"`
+}
+
+type Compute struct {
+	MachineType       string            `json:"machineType,omitempty"`
+	Replicas          *int              `json:"replicas,omitempty"`
+	EnableAutoscaling *bool             `json:"enableAutoscaling,omitempty"`
+	MinReplicas       *int              `json:"minReplicas,omitempty"`
+	MaxReplicas       *int              `json:"maxReplicas,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	RootDiskSize      string            `json:"rootDiskSize,omitempty"`
+}
+
+type Autoscaler struct {
+	BalanceSimilarNodeGroups    *bool           `json:"balanceSimilarNodeGroups,omitempty"`
+	SkipNodesWithLocalStorage   *bool           `json:"skipNodesWithLocalStorage,omitempty"`
+	LogVerbosity                *int            `json:"logVerbosity,omitempty"`
+	MaxPodGracePeriod           *int            `json:"maxPodGracePeriod,omitempty"`
+	PodPriorityThreshold        *int            `json:"podPriorityThreshold,omitempty"`
+	IgnoreDaemonsetsUtilization *bool           `json:"ignoreDaemonsetsUtilization,omitempty"`
+	MaxNodeProvisionTime        string          `json:"maxNodeProvisionTime,omitempty"`
+	BalancingIgnoredLabels      []string        `json:"balancingIgnoredLabels,omitempty"`
+	ResourceLimits              *ResourceLimits `json:"resourceLimits,omitempty"`
+	ScaleDown                   *ScaleDown      `json:"scaleDown,omitempty"`
+}
+
+type ResourceLimits struct {
+	MaxNodesTotal *int           `json:"maxNodesTotal,omitempty"`
+	Cores         *ResourceRange `json:"cores,omitempty"`
+	Memory        *ResourceRange `json:"memory,omitempty"`
+}
+
+type ResourceRange struct {
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+}
+
+type ScaleDown struct {
+	Enabled              *bool    `json:"enabled,omitempty"`
+	UnneededTime         string   `json:"unneededTime,omitempty"`
+	UtilizationThreshold *float64 `json:"utilizationThreshold,omitempty"`
+	DelayAfterAdd        string   `json:"delayAfterAdd,omitempty"`
+	DelayAfterDelete     string   `json:"delayAfterDelete,omitempty"`
+	DelayAfterFailure    string   `json:"delayAfterFailure,omitempty"`
+}
+
+type Network struct {
+	NetworkType string   `json:"networkType,omitempty"`
+	MachineCIDR string   `json:"machineCIDR,omitempty"`
+	ServiceCIDR string   `json:"serviceCIDR,omitempty"`
+	PodCIDR     string   `json:"podCIDR,omitempty"`
+	HostPrefix  *int     `json:"hostPrefix,omitempty"`
+	SubnetIDs   []string `json:"subnetIDs,omitempty"`
+	Private     *bool    `json:"private,omitempty"`
+	PrivateLink *bool    `json:"privateLink,omitempty"`
+}
+
+type STS struct {
+	Enabled             *bool  `json:"enabled,omitempty"`
+	Mode                string `json:"mode,omitempty"`
+	RoleARN             string `json:"roleARN,omitempty"`
+	ExternalID          string `json:"externalID,omitempty"`
+	SupportRoleARN      string `json:"supportRoleARN,omitempty"`
+	ControlPlaneRoleARN string `json:"controlPlaneRoleARN,omitempty"`
+	WorkerRoleARN       string `json:"workerRoleARN,omitempty"`
+	OperatorRolesPrefix string `json:"operatorRolesPrefix,omitempty"`
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
+	OidcConfigID        string `json:"oidcConfigID,omitempty"`
+}
+
+type Proxy struct {
+	HTTPProxy                 string   `json:"httpProxy,omitempty"`
+	HTTPSProxy                string   `json:"httpsProxy,omitempty"`
+	NoProxy                   []string `json:"noProxy,omitempty"`
+	AdditionalTrustBundleFile string   `json:"additionalTrustBundleFile,omitempty"`
+}
+
+type DefaultIngress struct {
+	RouteSelectors           map[string]string `json:"routeSelectors,omitempty"`
+	ExcludedNamespaces       []string          `json:"excludedNamespaces,omitempty"`
+	WildcardPolicy           string            `json:"wildcardPolicy,omitempty"`
+	NamespaceOwnershipPolicy string            `json:"namespaceOwnershipPolicy,omitempty"`
+}
+
+type SharedVPC struct {
+	PrivateHostedZoneID string `json:"privateHostedZoneID,omitempty"`
+	RoleARN             string `json:"roleARN,omitempty"`
+	BaseDomain          string `json:"baseDomain,omitempty"`
+}
+
+type ClusterAdmin struct {
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// New returns an empty cluster definition with the current API version and kind set.
+func New() *Cluster {
+	return &Cluster{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+	}
+}
+
+// Parse reads a cluster definition from YAML or JSON. Unknown fields are rejected so
+// that typos in the file don't silently fall back to the defaults.
+func Parse(data []byte) (*Cluster, error) {
+	spec := &Cluster{}
+	err := decode(data, spec, true)
+	if err != nil {
+		return nil, err
+	}
+	err = validateVersion(spec.APIVersion, spec.Kind, Kind)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Load reads a cluster definition from the given file. Relative paths inside the definition,
+// such as the additional trust bundle file, are resolved against the directory of the file.
+func Load(path string) (*Cluster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read cluster definition file '%s': %v", path, err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if spec.Proxy != nil && spec.Proxy.AdditionalTrustBundleFile != "" &&
+		!filepath.IsAbs(spec.Proxy.AdditionalTrustBundleFile) {
+		spec.Proxy.AdditionalTrustBundleFile = filepath.Join(filepath.Dir(path),
+			spec.Proxy.AdditionalTrustBundleFile)
+	}
+	return spec, nil
+}
+
+// Marshal returns the YAML representation of the cluster definition.
+func (c *Cluster) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func decode(data []byte, into interface{}, strict bool) error {
+	body, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("Failed to parse cluster definition: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	err = decoder.Decode(into)
+	if err != nil {
+		return fmt.Errorf("Failed to parse cluster definition: %v", err)
+	}
+	return nil
+}
+
+func validateVersion(apiVersion string, kind string, expectedKind string) error {
+	if apiVersion == "" {
+		return fmt.Errorf("Missing 'apiVersion', expected '%s'", APIVersion)
+	}
+	if apiVersion != APIVersion {
+		return fmt.Errorf("Unsupported apiVersion '%s', expected '%s'", apiVersion, APIVersion)
+	}
+	if kind != expectedKind {
+		return fmt.Errorf("Unsupported kind '%s', expected '%s'", kind, expectedKind)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package clusterspec
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster definition", func() {
+	Context("Parse", func() {
+		It("Parses a YAML definition", func() {
+			spec, err := Parse([]byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: mycluster
+region: us-east-1
+multiAZ: true
+network:
+  machineCIDR: 10.0.0.0/16
+  hostPrefix: 23
+  subnetIDs:
+  - subnet-1
+  - subnet-2
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Name).To(Equal("mycluster"))
+			Expect(*spec.MultiAZ).To(BeTrue())
+			Expect(*spec.Network.HostPrefix).To(Equal(23))
+			Expect(spec.Network.SubnetIDs).To(Equal([]string{"subnet-1", "subnet-2"}))
+		})
+		It("Parses a JSON definition", func() {
+			spec, err := Parse([]byte(`{"apiVersion": "rosa.openshift.io/v1", "kind": "Cluster", "name": "json"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Name).To(Equal("json"))
+		})
+		It("Rejects unknown fields", func() {
+			_, err := Parse([]byte("apiVersion: rosa.openshift.io/v1\nkind: Cluster\nname: a\nmultiZone: true\n"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown field \"multiZone\""))
+		})
+		It("Requires the API version", func() {
+			_, err := Parse([]byte("kind: Cluster\nname: a\n"))
+			Expect(err).To(MatchError("Missing 'apiVersion', expected 'rosa.openshift.io/v1'"))
+		})
+		It("Rejects unsupported API versions", func() {
+			_, err := Parse([]byte("apiVersion: rosa.openshift.io/v2\nkind: Cluster\nname: a\n"))
+			Expect(err).To(MatchError(
+				"Unsupported apiVersion 'rosa.openshift.io/v2', expected 'rosa.openshift.io/v1'"))
+		})
+		It("Round trips through Marshal", func() {
+			replicas := 3
+			spec := New()
+			spec.Name = "mycluster"
+			spec.Compute = &Compute{Replicas: &replicas}
+			data, err := spec.Marshal()
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := Parse(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(spec))
+		})
+	})
+
+	Context("Load", func() {
+		It("Resolves the trust bundle relative to the definition file", func() {
+			dir := GinkgoT().TempDir()
+			path := filepath.Join(dir, "cluster.yaml")
+			err := os.WriteFile(path, []byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: mycluster
+proxy:
+  additionalTrustBundleFile: ca.pem
+`), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			spec, err := Load(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Proxy.AdditionalTrustBundleFile).To(Equal(filepath.Join(dir, "ca.pem")))
+		})
+	})
+
+	Context("FlagValues", func() {
+		It("Only returns the values that are set", func() {
+			disabled := false
+			zero := 0
+			threshold := 0.25
+			spec := New()
+			spec.Name = "mycluster"
+			spec.HostedCP = &disabled
+			spec.Tags = map[string]string{"b": "2", "a": "1"}
+			spec.Compute = &Compute{
+				Labels: map[string]string{"foo": "bar"},
+			}
+			spec.Autoscaler = &Autoscaler{
+				ResourceLimits: &ResourceLimits{Cores: &ResourceRange{Min: &zero}},
+				ScaleDown:      &ScaleDown{UtilizationThreshold: &threshold},
+			}
+			Expect(spec.FlagValues()).To(Equal([]FlagValue{
+				{Name: "cluster-name", Value: "mycluster"},
+				{Name: "hosted-cp", Value: "false", Bool: true},
+				{Name: "tags", Value: "a:1,b:2"},
+				{Name: "default-mp-labels", Value: "foo=bar"},
+				{Name: "autoscaler-min-cores", Value: "0"},
+				{Name: "autoscaler-scale-down-utilization-threshold", Value: "0.25"},
+			}))
+		})
+		It("Uses a space to delimit tags containing a colon", func() {
+			spec := New()
+			spec.Tags = map[string]string{"key:1": "value"}
+			Expect(spec.FlagValues()).To(Equal([]FlagValue{
+				{Name: "tags", Value: "key:1 value"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that render a cluster definition as 'rosa' commands.
+
+package clusterspec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Commands returns the 'rosa' commands that create the cluster described by the definition,
+// followed by the commands that create each of its day-2 resources, in an order that can be
+// replayed. Secrets that are not part of the definition are replaced by placeholders in angle
+// brackets.
+func (c *Cluster) Commands() []string {
+	commands := []string{commandLine("rosa create cluster", c.FlagValues())}
+	if c.Resources == nil {
+		return commands
+	}
+	cluster := FlagValue{Name: "cluster", Value: c.Name}
+	// Tuning configurations go first because machine pools reference them:
+	for _, tuningConfig := range c.Resources.TuningConfigs {
+		flags := []FlagValue{
+			cluster,
+			{Name: "name", Value: tuningConfig.Name},
+			{Name: "spec-path", Value: fmt.Sprintf("<path to the spec of '%s'>", tuningConfig.Name)},
+		}
+		commands = append(commands, commandLine("rosa create tuning-configs", flags))
+	}
+	for _, machinePool := range c.Resources.MachinePools {
+		flags := append([]FlagValue{cluster}, machinePool.FlagValues()...)
+		commands = append(commands, commandLine("rosa create machinepool", flags))
+	}
+	for _, idp := range c.Resources.IdentityProviders {
+		flags := append([]FlagValue{cluster}, idp.FlagValues()...)
+		commands = append(commands, commandLine("rosa create idp", flags))
+	}
+	for _, ingress := range c.Resources.Ingresses {
+		flags := append([]FlagValue{cluster}, ingress.FlagValues()...)
+		commands = append(commands, commandLine("rosa create ingress", flags))
+	}
+	if c.Resources.UpgradePolicy != nil {
+		l := &flagList{cluster}
+		if c.HostedCP != nil && *c.HostedCP {
+			l.addBool("control-plane", c.HostedCP)
+		}
+		l.addString("schedule", c.Resources.UpgradePolicy.Schedule)
+		l.addBool("allow-minor-version-updates", c.Resources.UpgradePolicy.AllowMinorVersionUpdates)
+		commands = append(commands, commandLine("rosa upgrade cluster", *l))
+	}
+	return commands
+}
+
+// FlagValues translates the machine pool into the equivalent 'rosa create machinepool' options.
+func (m *MachinePool) FlagValues() []FlagValue {
+	l := &flagList{}
+	l.addString("name", m.Name)
+	l.addString("instance-type", m.InstanceType)
+	l.addInt("replicas", m.Replicas)
+	if m.Autoscaling != nil {
+		enabled := true
+		l.addBool("enable-autoscaling", &enabled)
+		l.addInt("min-replicas", &m.Autoscaling.MinReplicas)
+		l.addInt("max-replicas", &m.Autoscaling.MaxReplicas)
+	}
+	l.addMap("labels", m.Labels, "=")
+	taints := make([]string, 0, len(m.Taints))
+	for _, taint := range m.Taints {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+	}
+	l.addList("taints", taints)
+	l.addString("availability-zone", m.AvailabilityZone)
+	l.addString("subnet", m.Subnet)
+	l.addString("disk-size", m.DiskSize)
+	if m.SpotInstances != nil {
+		enabled := true
+		l.addBool("use-spot-instances", &enabled)
+		l.addFloat("spot-max-price", m.SpotInstances.MaxPrice)
+	}
+	l.addString("version", m.Version)
+	l.addBool("autorepair", m.AutoRepair)
+	l.addList("tuning-configs", m.TuningConfigs)
+	return *l
+}
+
+// FlagValues translates the identity provider into the equivalent 'rosa create idp' options.
+func (i *IdentityProvider) FlagValues() []FlagValue {
+	l := &flagList{}
+	l.addString("type", i.Type)
+	l.addString("name", i.Name)
+	l.addString("mapping-method", i.MappingMethod)
+	l.addString("client-id", i.ClientID)
+	if i.ClientID != "" {
+		l.addString("client-secret", secretOrPlaceholder(i.ClientSecret, "client-secret"))
+	}
+	l.addString("hostname", i.Hostname)
+	l.addList("organizations", i.Organizations)
+	l.addList("teams", i.Teams)
+	switch i.Type {
+	case "gitlab":
+		l.addString("host-url", i.URL)
+	default:
+		l.addString("url", i.URL)
+	}
+	l.addString("hosted-domain", i.HostedDomain)
+	l.addString("bind-dn", i.BindDN)
+	if i.BindDN != "" {
+		l.addString("bind-password", secretOrPlaceholder(i.BindPassword, "bind-password"))
+	}
+	l.addBool("insecure", i.Insecure)
+	l.addList("id-attributes", i.IDAttributes)
+	l.addList("username-attributes", i.UsernameAttributes)
+	l.addList("name-attributes", i.NameAttributes)
+	l.addList("email-attributes", i.EmailAttributes)
+	l.addString("issuer-url", i.IssuerURL)
+	l.addList("email-claims", i.EmailClaims)
+	l.addList("name-claims", i.NameClaims)
+	l.addList("username-claims", i.UsernameClaims)
+	l.addList("groups-claims", i.GroupsClaims)
+	l.addList("extra-scopes", i.ExtraScopes)
+	users := make([]string, 0, len(i.Users))
+	for _, user := range i.Users {
+		users = append(users, fmt.Sprintf("%s:%s", user.Username, secretOrPlaceholder(user.Password, "password")))
+	}
+	l.addList("users", users)
+	return *l
+}
+
+// FlagValues translates the ingress into the equivalent 'rosa create ingress' options. The
+// remaining settings can only be changed with 'rosa edit ingress' once the ingress exists.
+func (i *Ingress) FlagValues() []FlagValue {
+	l := &flagList{}
+	l.addBool("private", i.Private)
+	l.addMap("label-match", i.LabelMatch, "=")
+	l.addString("lb-type", i.LBType)
+	return *l
+}
+
+func secretOrPlaceholder(value string, name string) string {
+	if value != "" {
+		return value
+	}
+	return fmt.Sprintf("<%s>", name)
+}
+
+var safeArgRE = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// commandLine renders a command followed by its options. Values that the shell would split or
+// expand are single quoted.
+func commandLine(command string, flags []FlagValue) string {
+	var b strings.Builder
+	b.WriteString(command)
+	for _, flag := range flags {
+		if flag.Bool {
+			if flag.Value == "true" {
+				fmt.Fprintf(&b, " --%s", flag.Name)
+			} else {
+				fmt.Fprintf(&b, " --%s=%s", flag.Name, flag.Value)
+			}
+			continue
+		}
+		fmt.Fprintf(&b, " --%s %s", flag.Name, quoteArg(flag.Value))
+	}
+	return b.String()
+}
+
+func quoteArg(value string) string {
+	if safeArgRE.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to compare the definitions of two clusters.
+
+package clusterspec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Difference is a field that has a different value in two documents. A value is nil when the
+// field is only set in one of them.
+type Difference struct {
+	Field string      `json:"field"`
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// Compare returns the fields that differ between two documents, sorted by field. Nested fields
+// are joined with dots, and the items of lists are identified by their name or, for ingresses,
+// by their label match, like in 'resources.machinePools[db].replicas'. Lists of items without
+// a name are compared as a whole.
+func Compare(left interface{}, right interface{}) ([]Difference, error) {
+	leftFields, err := toFields(left)
+	if err != nil {
+		return nil, err
+	}
+	rightFields, err := toFields(right)
+	if err != nil {
+		return nil, err
+	}
+	leftFlat := map[string]interface{}{}
+	flatten("", leftFields, leftFlat)
+	rightFlat := map[string]interface{}{}
+	flatten("", rightFields, rightFlat)
+
+	names := map[string]bool{}
+	for name := range leftFlat {
+		names[name] = true
+	}
+	for name := range rightFlat {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var differences []Difference
+	for _, name := range sorted {
+		if reflect.DeepEqual(leftFlat[name], rightFlat[name]) {
+			continue
+		}
+		differences = append(differences, Difference{
+			Field: name,
+			Left:  leftFlat[name],
+			Right: rightFlat[name],
+		})
+	}
+	return differences, nil
+}
+
+func flatten(path string, value interface{}, result map[string]interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typed {
+			if path == "" {
+				flatten(k, v, result)
+			} else {
+				flatten(path+"."+k, v, result)
+			}
+		}
+	case []interface{}:
+		keys := make([]string, 0, len(typed))
+		for _, item := range typed {
+			key, ok := itemKey(item)
+			if !ok {
+				result[path] = value
+				return
+			}
+			keys = append(keys, key)
+		}
+		for i, item := range typed {
+			flatten(fmt.Sprintf("%s[%s]", path, keys[i]), item, result)
+		}
+	default:
+		result[path] = value
+	}
+}
+
+func itemKey(item interface{}) (string, bool) {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	if name, ok := fields["name"].(string); ok && name != "" {
+		return name, true
+	}
+	if labelMatch, ok := fields["labelMatch"].(map[string]interface{}); ok {
+		selectors := map[string]string{}
+		for k, v := range labelMatch {
+			selectors[k] = fmt.Sprintf("%v", v)
+		}
+		return ingressName(selectors), true
+	}
+	return "", false
+}
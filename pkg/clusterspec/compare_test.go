@@ -0,0 +1,48 @@
+package clusterspec
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compare", func() {
+	It("Returns no differences for identical definitions", func() {
+		spec := &Cluster{Name: "a", Region: "us-east-1"}
+		differences, err := Compare(spec, spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("Identifies list items by name and label match", func() {
+		three := 3
+		five := 5
+		private := true
+		left := &Cluster{Resources: &Resources{
+			MachinePools: []MachinePool{{Name: "db", Replicas: &three}, {Name: "old"}},
+			Ingresses:    []Ingress{{LabelMatch: map[string]string{"route": "internal"}}},
+		}}
+		right := &Cluster{Resources: &Resources{
+			MachinePools: []MachinePool{{Name: "db", Replicas: &five}},
+			Ingresses:    []Ingress{{LabelMatch: map[string]string{"route": "internal"}, Private: &private}},
+		}}
+		differences, err := Compare(left, right)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(Equal([]Difference{
+			{Field: "resources.ingresses[route=internal].private", Left: nil, Right: true},
+			{Field: "resources.machinePools[db].replicas", Left: float64(3), Right: float64(5)},
+			{Field: "resources.machinePools[old].name", Left: "old", Right: nil},
+		}))
+	})
+
+	It("Compares lists without names as a whole", func() {
+		left := &Cluster{Resources: &Resources{Users: &Users{ClusterAdmins: []string{"alice"}}}}
+		right := &Cluster{Resources: &Resources{Users: &Users{ClusterAdmins: []string{"bob"}}}}
+		differences, err := Compare(left, right)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(Equal([]Difference{{
+			Field: "resources.users.clusterAdmins",
+			Left:  []interface{}{"alice"},
+			Right: []interface{}{"bob"},
+		}}))
+	})
+})
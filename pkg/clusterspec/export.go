@@ -0,0 +1,554 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that build a cluster definition from an existing cluster.
+
+package clusterspec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+const (
+	redHatTagPrefix        = "red-hat-"
+	defaultChannelGroup    = "stable"
+	classicDefaultPoolName = "worker"
+)
+
+// hostedDefaultPoolRE matches the names of the node pools created together with a hosted control
+// plane cluster: 'workers' for a single pool, and 'workers-0', 'workers-1'... for one pool per
+// subnet.
+var hostedDefaultPoolRE = regexp.MustCompile(`^workers(-[0-9]+)?$`)
+
+// Export loads the definition of an existing cluster, including its day-2 resources.
+func Export(client *ocm.Client, cluster *cmv1.Cluster) (*Cluster, error) {
+	autoscaler, err := client.GetClusterAutoscaler(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get autoscaler: %v", err)
+	}
+	ingresses, err := client.GetIngresses(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingresses: %v", err)
+	}
+	spec := FromCluster(cluster, autoscaler, ingresses)
+	resources := &Resources{}
+
+	if ocm.IsHyperShiftCluster(cluster) {
+		nodePools, err := client.GetNodePools(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get machine pools: %v", err)
+		}
+		for _, nodePool := range nodePools {
+			if machinePool := NodePoolFromOCM(nodePool); machinePool != nil {
+				resources.MachinePools = append(resources.MachinePools, *machinePool)
+			}
+		}
+		tuningConfigs, err := client.GetTuningConfigs(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tuning configs: %v", err)
+		}
+		for _, tuningConfig := range tuningConfigs {
+			resources.TuningConfigs = append(resources.TuningConfigs,
+				*TuningConfigFromOCM(tuningConfig))
+		}
+		policies, err := client.GetControlPlaneUpgradePolicies(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get upgrade policies: %v", err)
+		}
+		resources.UpgradePolicy = ControlPlaneUpgradePolicyFromOCM(policies)
+	} else {
+		machinePools, err := client.GetMachinePools(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get machine pools: %v", err)
+		}
+		for _, machinePool := range machinePools {
+			if pool := MachinePoolFromOCM(cluster, machinePool); pool != nil {
+				resources.MachinePools = append(resources.MachinePools, *pool)
+			}
+		}
+		policies, err := client.GetUpgradePolicies(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get upgrade policies: %v", err)
+		}
+		resources.UpgradePolicy = UpgradePolicyFromOCM(policies)
+	}
+
+	idps, err := client.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity providers: %v", err)
+	}
+	for _, idp := range idps {
+		var usernames []string
+		if idp.Type() == cmv1.IdentityProviderTypeHtpasswd {
+			users, err := client.GetHTPasswdUserList(cluster.ID(), idp.ID())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get users of identity provider '%s': %v", idp.Name(), err)
+			}
+			users.Each(func(user *cmv1.HTPasswdUser) bool {
+				usernames = append(usernames, user.Username())
+				return true
+			})
+		}
+		resources.IdentityProviders = append(resources.IdentityProviders,
+			*IdentityProviderFromOCM(idp, usernames))
+	}
+
+	for _, ingress := range ingresses {
+		if additional := IngressFromOCM(ingress); additional != nil {
+			resources.Ingresses = append(resources.Ingresses, *additional)
+		}
+	}
+
+	if len(resources.MachinePools) > 0 || len(resources.IdentityProviders) > 0 ||
+		len(resources.Ingresses) > 0 || len(resources.TuningConfigs) > 0 || resources.UpgradePolicy != nil {
+		spec.Resources = resources
+	}
+	return spec, nil
+}
+
+// FromCluster builds the definition of an existing cluster. The autoscaler may be nil if the
+// cluster doesn't have one. Values that are generated by the service, like the tags managed by
+// Red Hat, are left out so that the definition can be used to create a new cluster.
+func FromCluster(cluster *cmv1.Cluster, autoscaler *cmv1.ClusterAutoscaler,
+	ingresses []*cmv1.Ingress) *Cluster {
+	spec := New()
+	spec.Name = cluster.Name()
+	spec.Region = cluster.Region().ID()
+	spec.Version = cluster.Version().RawID()
+	if channelGroup := cluster.Version().ChannelGroup(); channelGroup != defaultChannelGroup {
+		spec.ChannelGroup = channelGroup
+	}
+	hostedCP := cluster.Hypershift().Enabled()
+	spec.HostedCP = trueOrNil(hostedCP)
+	if !hostedCP {
+		spec.MultiAZ = trueOrNil(cluster.MultiAZ())
+	}
+	if len(cluster.AWS().SubnetIDs()) == 0 {
+		spec.AvailabilityZones = cluster.Nodes().AvailabilityZones()
+	}
+	spec.DisableWorkloadMonitoring = trueOrNil(cluster.DisableUserWorkloadMonitoring())
+	spec.Ec2MetadataHttpTokens = string(cluster.AWS().Ec2MetadataHttpTokens())
+	spec.BillingAccount = cluster.AWS().BillingAccountID()
+	spec.AuditLogRoleARN = cluster.AWS().AuditLog().RoleArn()
+	for k, v := range cluster.AWS().Tags() {
+		if strings.HasPrefix(k, redHatTagPrefix) {
+			continue
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[k] = v
+	}
+
+	spec.Encryption = encryptionFromCluster(cluster)
+	spec.Compute = computeFromCluster(cluster)
+	spec.Autoscaler = autoscalerFromOCM(autoscaler)
+	spec.Network = networkFromCluster(cluster)
+	spec.STS = stsFromCluster(cluster)
+	spec.Proxy = proxyFromCluster(cluster)
+	if !hostedCP {
+		for _, ingress := range ingresses {
+			if ingress.Default() {
+				spec.DefaultIngress = defaultIngressFromOCM(ingress)
+			}
+		}
+	}
+	if cluster.AWS().PrivateHostedZoneID() != "" {
+		spec.SharedVPC = &SharedVPC{
+			PrivateHostedZoneID: cluster.AWS().PrivateHostedZoneID(),
+			RoleARN:             cluster.AWS().PrivateHostedZoneRoleARN(),
+			BaseDomain:          cluster.DNS().BaseDomain(),
+		}
+	}
+	return spec
+}
+
+func encryptionFromCluster(cluster *cmv1.Cluster) *Encryption {
+	encryption := &Encryption{
+		FIPS:                 trueOrNil(cluster.FIPS()),
+		EtcdEncryption:       trueOrNil(cluster.EtcdEncryption()),
+		KMSKeyARN:            cluster.AWS().KMSKeyArn(),
+		EtcdEncryptionKMSARN: cluster.AWS().EtcdEncryption().KMSKeyARN(),
+	}
+	if *encryption == (Encryption{}) {
+		return nil
+	}
+	return encryption
+}
+
+func computeFromCluster(cluster *cmv1.Cluster) *Compute {
+	nodes := cluster.Nodes()
+	compute := &Compute{
+		MachineType: nodes.ComputeMachineType().ID(),
+		Labels:      nodes.ComputeLabels(),
+	}
+	if autoscaling, ok := nodes.GetAutoscaleCompute(); ok {
+		compute.EnableAutoscaling = trueOrNil(true)
+		compute.MinReplicas = intPtr(autoscaling.MinReplicas())
+		compute.MaxReplicas = intPtr(autoscaling.MaxReplicas())
+	} else if replicas, ok := nodes.GetCompute(); ok {
+		compute.Replicas = intPtr(replicas)
+	}
+	if size, ok := nodes.ComputeRootVolume().AWS().GetSize(); ok {
+		compute.RootDiskSize = fmt.Sprintf("%dGiB", size)
+	}
+	return compute
+}
+
+func autoscalerFromOCM(autoscaler *cmv1.ClusterAutoscaler) *Autoscaler {
+	if autoscaler == nil {
+		return nil
+	}
+	spec := &Autoscaler{
+		BalanceSimilarNodeGroups:    boolPtr(autoscaler.GetBalanceSimilarNodeGroups()),
+		SkipNodesWithLocalStorage:   boolPtr(autoscaler.GetSkipNodesWithLocalStorage()),
+		LogVerbosity:                intPtrIf(autoscaler.GetLogVerbosity()),
+		MaxPodGracePeriod:           intPtrIf(autoscaler.GetMaxPodGracePeriod()),
+		PodPriorityThreshold:        intPtrIf(autoscaler.GetPodPriorityThreshold()),
+		IgnoreDaemonsetsUtilization: boolPtr(autoscaler.GetIgnoreDaemonsetsUtilization()),
+		MaxNodeProvisionTime:        autoscaler.MaxNodeProvisionTime(),
+		BalancingIgnoredLabels:      autoscaler.BalancingIgnoredLabels(),
+	}
+	if limits, ok := autoscaler.GetResourceLimits(); ok {
+		spec.ResourceLimits = &ResourceLimits{
+			MaxNodesTotal: intPtrIf(limits.GetMaxNodesTotal()),
+			Cores:         resourceRangeFromOCM(limits.Cores()),
+			Memory:        resourceRangeFromOCM(limits.Memory()),
+		}
+	}
+	if scaleDown, ok := autoscaler.GetScaleDown(); ok {
+		spec.ScaleDown = &ScaleDown{
+			Enabled:           boolPtr(scaleDown.GetEnabled()),
+			UnneededTime:      scaleDown.UnneededTime(),
+			DelayAfterAdd:     scaleDown.DelayAfterAdd(),
+			DelayAfterDelete:  scaleDown.DelayAfterDelete(),
+			DelayAfterFailure: scaleDown.DelayAfterFailure(),
+		}
+		threshold, err := strconv.ParseFloat(scaleDown.UtilizationThreshold(), 64)
+		if err == nil {
+			spec.ScaleDown.UtilizationThreshold = &threshold
+		}
+	}
+	return spec
+}
+
+func resourceRangeFromOCM(resourceRange *cmv1.ResourceRange) *ResourceRange {
+	if resourceRange == nil {
+		return nil
+	}
+	return &ResourceRange{
+		Min: intPtrIf(resourceRange.GetMin()),
+		Max: intPtrIf(resourceRange.GetMax()),
+	}
+}
+
+func networkFromCluster(cluster *cmv1.Cluster) *Network {
+	network := cluster.Network()
+	return &Network{
+		NetworkType: network.Type(),
+		MachineCIDR: network.MachineCIDR(),
+		ServiceCIDR: network.ServiceCIDR(),
+		PodCIDR:     network.PodCIDR(),
+		HostPrefix:  intPtrIf(network.GetHostPrefix()),
+		SubnetIDs:   cluster.AWS().SubnetIDs(),
+		Private:     trueOrNil(cluster.API().Listening() == cmv1.ListeningMethodInternal),
+		PrivateLink: trueOrNil(cluster.AWS().PrivateLink()),
+	}
+}
+
+func stsFromCluster(cluster *cmv1.Cluster) *STS {
+	sts, ok := cluster.AWS().GetSTS()
+	if !ok || sts.RoleARN() == "" {
+		return nil
+	}
+	spec := &STS{
+		Enabled:             trueOrNil(true),
+		RoleARN:             sts.RoleARN(),
+		ExternalID:          sts.ExternalID(),
+		SupportRoleARN:      sts.SupportRoleARN(),
+		WorkerRoleARN:       sts.InstanceIAMRoles().WorkerRoleARN(),
+		OperatorRolesPrefix: sts.OperatorRolePrefix(),
+		PermissionsBoundary: sts.PermissionBoundary(),
+		OidcConfigID:        sts.OidcConfig().ID(),
+	}
+	if !cluster.Hypershift().Enabled() {
+		spec.ControlPlaneRoleARN = sts.InstanceIAMRoles().MasterRoleARN()
+	}
+	return spec
+}
+
+// proxyFromCluster returns the proxy settings of the cluster. The additional trust bundle is
+// not returned by the service, so it always has to be added to the definition by hand.
+func proxyFromCluster(cluster *cmv1.Cluster) *Proxy {
+	proxy, ok := cluster.GetProxy()
+	if !ok {
+		return nil
+	}
+	spec := &Proxy{
+		HTTPProxy:  proxy.HTTPProxy(),
+		HTTPSProxy: proxy.HTTPSProxy(),
+	}
+	if proxy.NoProxy() != "" {
+		spec.NoProxy = strings.Split(proxy.NoProxy(), ",")
+	}
+	if spec.HTTPProxy == "" && spec.HTTPSProxy == "" && len(spec.NoProxy) == 0 {
+		return nil
+	}
+	return spec
+}
+
+func defaultIngressFromOCM(ingress *cmv1.Ingress) *DefaultIngress {
+	return &DefaultIngress{
+		RouteSelectors:           ingress.RouteSelectors(),
+		ExcludedNamespaces:       ingress.ExcludedNamespaces(),
+		WildcardPolicy:           string(ingress.RouteWildcardPolicy()),
+		NamespaceOwnershipPolicy: string(ingress.RouteNamespaceOwnershipPolicy()),
+	}
+}
+
+// MachinePoolFromOCM converts a machine pool of a classic cluster. It returns nil for the
+// default machine pool, which is part of the compute section of the cluster definition.
+func MachinePoolFromOCM(cluster *cmv1.Cluster, machinePool *cmv1.MachinePool) *MachinePool {
+	if isDefaultMachinePool(machinePool.ID(), false) {
+		return nil
+	}
+	return machinePoolFromOCM(cluster, machinePool)
+}
+
+func machinePoolFromOCM(cluster *cmv1.Cluster, machinePool *cmv1.MachinePool) *MachinePool {
+	spec := &MachinePool{
+		Name:         machinePool.ID(),
+		InstanceType: machinePool.InstanceType(),
+		Labels:       machinePool.Labels(),
+		Taints:       taintsFromOCM(machinePool.Taints()),
+	}
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		spec.Autoscaling = &MachinePoolAutoscaling{
+			MinReplicas: autoscaling.MinReplicas(),
+			MaxReplicas: autoscaling.MaxReplicas(),
+		}
+	} else {
+		spec.Replicas = intPtr(machinePool.Replicas())
+	}
+	// Machine pools of multi-AZ clusters span all the zones unless a single zone was requested
+	if cluster.MultiAZ() && len(machinePool.AvailabilityZones()) == 1 {
+		if len(machinePool.Subnets()) == 1 {
+			spec.Subnet = machinePool.Subnets()[0]
+		} else {
+			spec.AvailabilityZone = machinePool.AvailabilityZones()[0]
+		}
+	}
+	if size, ok := machinePool.RootVolume().AWS().GetSize(); ok {
+		spec.DiskSize = fmt.Sprintf("%dGiB", size)
+	}
+	if spot, ok := machinePool.AWS().GetSpotMarketOptions(); ok {
+		spec.SpotInstances = &SpotInstances{}
+		if maxPrice, ok := spot.GetMaxPrice(); ok {
+			spec.SpotInstances.MaxPrice = &maxPrice
+		}
+	}
+	return spec
+}
+
+// NodePoolFromOCM converts a node pool of a hosted control plane cluster. It returns nil for
+// the node pools created together with the cluster.
+func NodePoolFromOCM(nodePool *cmv1.NodePool) *MachinePool {
+	if isDefaultMachinePool(nodePool.ID(), true) {
+		return nil
+	}
+	return nodePoolFromOCM(nodePool)
+}
+
+func nodePoolFromOCM(nodePool *cmv1.NodePool) *MachinePool {
+	spec := &MachinePool{
+		Name:          nodePool.ID(),
+		InstanceType:  nodePool.AWSNodePool().InstanceType(),
+		Labels:        nodePool.Labels(),
+		Taints:        taintsFromOCM(nodePool.Taints()),
+		Subnet:        nodePool.Subnet(),
+		Version:       nodePool.Version().RawID(),
+		AutoRepair:    boolPtr(nodePool.GetAutoRepair()),
+		TuningConfigs: nodePool.TuningConfigs(),
+	}
+	if autoscaling, ok := nodePool.GetAutoscaling(); ok {
+		spec.Autoscaling = &MachinePoolAutoscaling{
+			MinReplicas: autoscaling.MinReplica(),
+			MaxReplicas: autoscaling.MaxReplica(),
+		}
+	} else {
+		spec.Replicas = intPtr(nodePool.Replicas())
+	}
+	return spec
+}
+
+// isDefaultMachinePool checks if the machine pool was created together with the cluster.
+func isDefaultMachinePool(name string, hostedCP bool) bool {
+	if hostedCP {
+		return hostedDefaultPoolRE.MatchString(name)
+	}
+	return name == classicDefaultPoolName
+}
+
+func taintsFromOCM(taints []*cmv1.Taint) []Taint {
+	var result []Taint
+	for _, taint := range taints {
+		result = append(result, Taint{
+			Key:    taint.Key(),
+			Value:  taint.Value(),
+			Effect: taint.Effect(),
+		})
+	}
+	return result
+}
+
+// IdentityProviderFromOCM converts an identity provider, leaving out all of its secrets. For
+// HTPasswd identity providers only the names of the given users are kept.
+func IdentityProviderFromOCM(idp *cmv1.IdentityProvider, htpasswdUsers []string) *IdentityProvider {
+	spec := &IdentityProvider{
+		Name:          idp.Name(),
+		MappingMethod: string(idp.MappingMethod()),
+	}
+	switch idp.Type() {
+	case cmv1.IdentityProviderTypeGithub:
+		spec.Type = "github"
+		spec.ClientID = idp.Github().ClientID()
+		spec.Hostname = idp.Github().Hostname()
+		spec.Organizations = idp.Github().Organizations()
+		spec.Teams = idp.Github().Teams()
+	case cmv1.IdentityProviderTypeGitlab:
+		spec.Type = "gitlab"
+		spec.ClientID = idp.Gitlab().ClientID()
+		spec.URL = idp.Gitlab().URL()
+	case cmv1.IdentityProviderTypeGoogle:
+		spec.Type = "google"
+		spec.ClientID = idp.Google().ClientID()
+		spec.HostedDomain = idp.Google().HostedDomain()
+	case cmv1.IdentityProviderTypeLDAP:
+		spec.Type = "ldap"
+		spec.URL = idp.LDAP().URL()
+		spec.BindDN = idp.LDAP().BindDN()
+		spec.Insecure = trueOrNil(idp.LDAP().Insecure())
+		spec.IDAttributes = idp.LDAP().Attributes().ID()
+		spec.UsernameAttributes = idp.LDAP().Attributes().PreferredUsername()
+		spec.NameAttributes = idp.LDAP().Attributes().Name()
+		spec.EmailAttributes = idp.LDAP().Attributes().Email()
+	case cmv1.IdentityProviderTypeOpenID:
+		spec.Type = "openid"
+		spec.ClientID = idp.OpenID().ClientID()
+		spec.IssuerURL = idp.OpenID().Issuer()
+		spec.EmailClaims = idp.OpenID().Claims().Email()
+		spec.NameClaims = idp.OpenID().Claims().Name()
+		spec.UsernameClaims = idp.OpenID().Claims().PreferredUsername()
+		spec.GroupsClaims = idp.OpenID().Claims().Groups()
+		spec.ExtraScopes = idp.OpenID().ExtraScopes()
+	case cmv1.IdentityProviderTypeHtpasswd:
+		spec.Type = "htpasswd"
+		for _, username := range htpasswdUsers {
+			spec.Users = append(spec.Users, HTPasswdUser{Username: username})
+		}
+	}
+	return spec
+}
+
+// IngressFromOCM converts an additional ingress. It returns nil for the default ingress, which
+// is part of the cluster definition.
+func IngressFromOCM(ingress *cmv1.Ingress) *Ingress {
+	if ingress.Default() {
+		return nil
+	}
+	return ingressFromOCM(ingress)
+}
+
+func ingressFromOCM(ingress *cmv1.Ingress) *Ingress {
+	return &Ingress{
+		ID:                       ingress.ID(),
+		Private:                  trueOrNil(ingress.Listening() == cmv1.ListeningMethodInternal),
+		LabelMatch:               ingress.RouteSelectors(),
+		LBType:                   string(ingress.LoadBalancerType()),
+		ExcludedNamespaces:       ingress.ExcludedNamespaces(),
+		WildcardPolicy:           string(ingress.RouteWildcardPolicy()),
+		NamespaceOwnershipPolicy: string(ingress.RouteNamespaceOwnershipPolicy()),
+	}
+}
+
+func TuningConfigFromOCM(tuningConfig *cmv1.TuningConfig) *TuningConfig {
+	return &TuningConfig{
+		Name: tuningConfig.Name(),
+		Spec: tuningConfig.Spec(),
+	}
+}
+
+// UpgradePolicyFromOCM returns the recurring upgrade schedule from the given policies, or nil
+// if the cluster is only upgraded manually.
+func UpgradePolicyFromOCM(policies []*cmv1.UpgradePolicy) *UpgradePolicy {
+	for _, policy := range policies {
+		if policy.ScheduleType() == cmv1.ScheduleTypeAutomatic && policy.Schedule() != "" {
+			return &UpgradePolicy{
+				Schedule:                 policy.Schedule(),
+				AllowMinorVersionUpdates: trueOrNil(policy.EnableMinorVersionUpgrades()),
+			}
+		}
+	}
+	return nil
+}
+
+// ControlPlaneUpgradePolicyFromOCM is the equivalent of UpgradePolicyFromOCM for hosted
+// control plane clusters.
+func ControlPlaneUpgradePolicyFromOCM(policies []*cmv1.ControlPlaneUpgradePolicy) *UpgradePolicy {
+	for _, policy := range policies {
+		if policy.ScheduleType() == cmv1.ScheduleTypeAutomatic && policy.Schedule() != "" {
+			return &UpgradePolicy{
+				Schedule:                 policy.Schedule(),
+				AllowMinorVersionUpdates: trueOrNil(policy.EnableMinorVersionUpgrades()),
+			}
+		}
+	}
+	return nil
+}
+
+// trueOrNil leaves out flags that are disabled, so that the definition only contains the
+// settings that differ from the defaults.
+func trueOrNil(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
+
+func boolPtr(value bool, ok bool) *bool {
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func intPtrIf(value int, ok bool) *int {
+	if !ok {
+		return nil
+	}
+	return &value
+}
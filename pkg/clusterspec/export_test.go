@@ -0,0 +1,183 @@
+package clusterspec
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	Context("FromCluster", func() {
+		It("Builds the definition of a classic STS cluster", func() {
+			cluster, err := cmv1.NewCluster().
+				Name("mycluster").
+				Region(cmv1.NewCloudRegion().ID("us-east-1")).
+				Version(cmv1.NewVersion().RawID("4.13.4").ChannelGroup("stable")).
+				MultiAZ(true).
+				FIPS(true).
+				Nodes(cmv1.NewClusterNodes().
+					ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge")).
+					Compute(3).
+					AvailabilityZones("us-east-1a", "us-east-1b", "us-east-1c")).
+				Network(cmv1.NewNetwork().Type("OVNKubernetes").MachineCIDR("10.0.0.0/16").HostPrefix(23)).
+				API(cmv1.NewClusterAPI().Listening(cmv1.ListeningMethodExternal)).
+				AWS(cmv1.NewAWS().
+					Tags(map[string]string{"owner": "me", "red-hat-managed": "true"}).
+					STS(cmv1.NewSTS().
+						RoleARN("arn:aws:iam::123:role/Installer").
+						SupportRoleARN("arn:aws:iam::123:role/Support").
+						OperatorRolePrefix("mycluster-a1b2").
+						InstanceIAMRoles(cmv1.NewInstanceIAMRoles().
+							MasterRoleARN("arn:aws:iam::123:role/ControlPlane").
+							WorkerRoleARN("arn:aws:iam::123:role/Worker")))).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			autoscaler, err := cmv1.NewClusterAutoscaler().
+				BalanceSimilarNodeGroups(true).
+				ScaleDown(cmv1.NewAutoscalerScaleDownConfig().UtilizationThreshold("0.5")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+
+			spec := FromCluster(cluster, autoscaler, nil)
+			Expect(spec.Name).To(Equal("mycluster"))
+			Expect(spec.ChannelGroup).To(BeEmpty())
+			Expect(*spec.MultiAZ).To(BeTrue())
+			Expect(spec.HostedCP).To(BeNil())
+			Expect(spec.AvailabilityZones).To(HaveLen(3))
+			Expect(spec.Tags).To(Equal(map[string]string{"owner": "me"}))
+			Expect(*spec.Encryption.FIPS).To(BeTrue())
+			Expect(*spec.Compute.Replicas).To(Equal(3))
+			Expect(spec.Network.Private).To(BeNil())
+			Expect(*spec.Autoscaler.BalanceSimilarNodeGroups).To(BeTrue())
+			Expect(spec.Autoscaler.SkipNodesWithLocalStorage).To(BeNil())
+			Expect(*spec.Autoscaler.ScaleDown.UtilizationThreshold).To(Equal(0.5))
+			Expect(spec.STS.ControlPlaneRoleARN).To(Equal("arn:aws:iam::123:role/ControlPlane"))
+		})
+	})
+
+	Context("Resources", func() {
+		It("Skips the default machine pool", func() {
+			cluster, err := cmv1.NewCluster().Build()
+			Expect(err).ToNot(HaveOccurred())
+			machinePool, err := cmv1.NewMachinePool().ID("worker").Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(MachinePoolFromOCM(cluster, machinePool)).To(BeNil())
+		})
+		It("Skips only the default node pools of hosted control plane clusters", func() {
+			for _, name := range []string{"workers", "workers-0", "workers-12"} {
+				nodePool, err := cmv1.NewNodePool().ID(name).Build()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(NodePoolFromOCM(nodePool)).To(BeNil(), name)
+			}
+			for _, name := range []string{"workers-gpu", "workersx", "my-workers"} {
+				nodePool, err := cmv1.NewNodePool().ID(name).Build()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(NodePoolFromOCM(nodePool)).ToNot(BeNil(), name)
+			}
+		})
+		It("Keeps the single availability zone of a machine pool in a multi-AZ cluster", func() {
+			cluster, err := cmv1.NewCluster().MultiAZ(true).Build()
+			Expect(err).ToNot(HaveOccurred())
+			machinePool, err := cmv1.NewMachinePool().
+				ID("mp-1").
+				InstanceType("m5.xlarge").
+				Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(1).MaxReplicas(3)).
+				AvailabilityZones("us-east-1a").
+				Taints(cmv1.NewTaint().Key("k").Value("v").Effect("NoSchedule")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			spec := MachinePoolFromOCM(cluster, machinePool)
+			Expect(spec.AvailabilityZone).To(Equal("us-east-1a"))
+			Expect(spec.Replicas).To(BeNil())
+			Expect(spec.Autoscaling).To(Equal(&MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 3}))
+			Expect(spec.Taints).To(Equal([]Taint{{Key: "k", Value: "v", Effect: "NoSchedule"}}))
+		})
+		It("Leaves out the secrets of identity providers", func() {
+			idp, err := cmv1.NewIdentityProvider().
+				Name("github-1").
+				Type(cmv1.IdentityProviderTypeGithub).
+				Github(cmv1.NewGithubIdentityProvider().
+					ClientID("id").
+					ClientSecret("secret").
+					Organizations("myorg")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			spec := IdentityProviderFromOCM(idp, nil)
+			Expect(spec.Type).To(Equal("github"))
+			Expect(spec.ClientID).To(Equal("id"))
+			Expect(spec.ClientSecret).To(BeEmpty())
+		})
+		It("Only keeps recurring upgrade policies", func() {
+			manual, err := cmv1.NewUpgradePolicy().ScheduleType(cmv1.ScheduleTypeManual).Version("4.13.5").Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(UpgradePolicyFromOCM([]*cmv1.UpgradePolicy{manual})).To(BeNil())
+			automatic, err := cmv1.NewUpgradePolicy().
+				ScheduleType(cmv1.ScheduleTypeAutomatic).
+				Schedule("0 0 * * 0").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(UpgradePolicyFromOCM([]*cmv1.UpgradePolicy{manual, automatic})).To(Equal(
+				&UpgradePolicy{Schedule: "0 0 * * 0"}))
+		})
+	})
+
+	Context("Commands", func() {
+		It("Renders the cluster and its resources", func() {
+			enabled := true
+			replicas := 2
+			spec := New()
+			spec.Name = "mycluster"
+			spec.MultiAZ = &enabled
+			spec.Tags = map[string]string{"team": "my team"}
+			spec.Resources = &Resources{
+				MachinePools: []MachinePool{{
+					Name:         "mp-1",
+					InstanceType: "m5.xlarge",
+					Replicas:     &replicas,
+					Labels:       map[string]string{"app": "db"},
+				}},
+				IdentityProviders: []IdentityProvider{{
+					Name:     "github-1",
+					Type:     "github",
+					ClientID: "id",
+				}},
+				UpgradePolicy: &UpgradePolicy{Schedule: "0 0 * * 0"},
+			}
+			Expect(spec.Commands()).To(Equal([]string{
+				"rosa create cluster --cluster-name mycluster --multi-az --tags 'team:my team'",
+				"rosa create machinepool --cluster mycluster --name mp-1 --instance-type m5.xlarge " +
+					"--replicas 2 --labels app=db",
+				"rosa create idp --cluster mycluster --type github --name github-1 --client-id id " +
+					"--client-secret '<client-secret>'",
+				"rosa upgrade cluster --cluster mycluster --schedule '0 0 * * 0'",
+			}))
+		})
+		It("Creates the tuning configurations before the machine pools that use them", func() {
+			spec := New()
+			spec.Name = "mycluster"
+			spec.Resources = &Resources{
+				MachinePools: []MachinePool{{
+					Name:          "mp-1",
+					TuningConfigs: []string{"tuned-1"},
+				}},
+				TuningConfigs: []TuningConfig{{Name: "tuned-1"}},
+			}
+			Expect(spec.Commands()).To(Equal([]string{
+				"rosa create cluster --cluster-name mycluster",
+				"rosa create tuning-configs --cluster mycluster --name tuned-1 " +
+					"--spec-path '<path to the spec of '\\''tuned-1'\\''>'",
+				"rosa create machinepool --cluster mycluster --name mp-1 --tuning-configs tuned-1",
+			}))
+		})
+		It("Writes disabled options with a value", func() {
+			disabled := false
+			spec := New()
+			spec.Name = "mycluster"
+			spec.Network = &Network{Private: &disabled}
+			Expect(spec.Commands()).To(Equal([]string{
+				"rosa create cluster --cluster-name mycluster --private=false",
+			}))
+		})
+	})
+})
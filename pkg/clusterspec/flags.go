@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlagValue is a single 'rosa create cluster' command line option derived from a cluster
+// definition. Value is formatted the same way a user would type it on the command line.
+type FlagValue struct {
+	Name  string
+	Value string
+	// Bool is set for boolean options, which are written without a value when enabled.
+	Bool bool
+}
+
+// FlagValues translates the cluster definition into the equivalent 'rosa create cluster'
+// options. Only the fields that are set in the definition are returned.
+func (c *Cluster) FlagValues() []FlagValue {
+	l := &flagList{}
+
+	l.addString("cluster-name", c.Name)
+	l.addString("region", c.Region)
+	l.addString("version", c.Version)
+	l.addString("channel-group", c.ChannelGroup)
+	l.addBool("multi-az", c.MultiAZ)
+	l.addBool("hosted-cp", c.HostedCP)
+	l.addList("availability-zones", c.AvailabilityZones)
+	l.addBool("disable-workload-monitoring", c.DisableWorkloadMonitoring)
+	l.addBool("disable-scp-checks", c.DisableSCPChecks)
+	l.addString("ec2-metadata-http-tokens", c.Ec2MetadataHttpTokens)
+	l.addString("billing-account", c.BillingAccount)
+	l.addString("audit-log-arn", c.AuditLogRoleARN)
+	l.addMap("tags", c.Tags, tagsDelimiter(c.Tags))
+
+	if c.Encryption != nil {
+		l.addBool("fips", c.Encryption.FIPS)
+		l.addBool("etcd-encryption", c.Encryption.EtcdEncryption)
+		l.addString("kms-key-arn", c.Encryption.KMSKeyARN)
+		l.addString("etcd-encryption-kms-arn", c.Encryption.EtcdEncryptionKMSARN)
+	}
+
+	if c.Compute != nil {
+		l.addString("compute-machine-type", c.Compute.MachineType)
+		l.addInt("replicas", c.Compute.Replicas)
+		l.addBool("enable-autoscaling", c.Compute.EnableAutoscaling)
+		l.addInt("min-replicas", c.Compute.MinReplicas)
+		l.addInt("max-replicas", c.Compute.MaxReplicas)
+		l.addMap("default-mp-labels", c.Compute.Labels, "=")
+		l.addString("worker-disk-size", c.Compute.RootDiskSize)
+	}
+
+	if c.Autoscaler != nil {
+		a := c.Autoscaler
+		l.addBool("autoscaler-balance-similar-node-groups", a.BalanceSimilarNodeGroups)
+		l.addBool("autoscaler-skip-nodes-with-local-storage", a.SkipNodesWithLocalStorage)
+		l.addInt("autoscaler-log-verbosity", a.LogVerbosity)
+		l.addInt("autoscaler-max-pod-grace-period", a.MaxPodGracePeriod)
+		l.addInt("autoscaler-pod-priority-threshold", a.PodPriorityThreshold)
+		l.addBool("autoscaler-ignore-daemonsets-utilization", a.IgnoreDaemonsetsUtilization)
+		l.addString("autoscaler-max-node-provision-time", a.MaxNodeProvisionTime)
+		l.addList("autoscaler-balancing-ignored-labels", a.BalancingIgnoredLabels)
+		if a.ResourceLimits != nil {
+			l.addInt("autoscaler-max-nodes-total", a.ResourceLimits.MaxNodesTotal)
+			if a.ResourceLimits.Cores != nil {
+				l.addInt("autoscaler-min-cores", a.ResourceLimits.Cores.Min)
+				l.addInt("autoscaler-max-cores", a.ResourceLimits.Cores.Max)
+			}
+			if a.ResourceLimits.Memory != nil {
+				l.addInt("autoscaler-min-memory", a.ResourceLimits.Memory.Min)
+				l.addInt("autoscaler-max-memory", a.ResourceLimits.Memory.Max)
+			}
+		}
+		if a.ScaleDown != nil {
+			l.addBool("autoscaler-scale-down-enabled", a.ScaleDown.Enabled)
+			l.addString("autoscaler-scale-down-unneeded-time", a.ScaleDown.UnneededTime)
+			l.addFloat("autoscaler-scale-down-utilization-threshold", a.ScaleDown.UtilizationThreshold)
+			l.addString("autoscaler-scale-down-delay-after-add", a.ScaleDown.DelayAfterAdd)
+			l.addString("autoscaler-scale-down-delay-after-delete", a.ScaleDown.DelayAfterDelete)
+			l.addString("autoscaler-scale-down-delay-after-failure", a.ScaleDown.DelayAfterFailure)
+		}
+	}
+
+	if c.Network != nil {
+		l.addString("network-type", c.Network.NetworkType)
+		l.addString("machine-cidr", c.Network.MachineCIDR)
+		l.addString("service-cidr", c.Network.ServiceCIDR)
+		l.addString("pod-cidr", c.Network.PodCIDR)
+		l.addInt("host-prefix", c.Network.HostPrefix)
+		l.addList("subnet-ids", c.Network.SubnetIDs)
+		l.addBool("private", c.Network.Private)
+		l.addBool("private-link", c.Network.PrivateLink)
+	}
+
+	if c.STS != nil {
+		l.addBool("sts", c.STS.Enabled)
+		l.addString("mode", c.STS.Mode)
+		l.addString("role-arn", c.STS.RoleARN)
+		l.addString("external-id", c.STS.ExternalID)
+		l.addString("support-role-arn", c.STS.SupportRoleARN)
+		l.addString("controlplane-iam-role", c.STS.ControlPlaneRoleARN)
+		l.addString("worker-iam-role", c.STS.WorkerRoleARN)
+		l.addString("operator-roles-prefix", c.STS.OperatorRolesPrefix)
+		l.addString("permissions-boundary", c.STS.PermissionsBoundary)
+		l.addString("oidc-config-id", c.STS.OidcConfigID)
+	}
+
+	if c.Proxy != nil {
+		l.addString("http-proxy", c.Proxy.HTTPProxy)
+		l.addString("https-proxy", c.Proxy.HTTPSProxy)
+		l.addList("no-proxy", c.Proxy.NoProxy)
+		l.addString("additional-trust-bundle-file", c.Proxy.AdditionalTrustBundleFile)
+	}
+
+	if c.DefaultIngress != nil {
+		l.addMap("default-ingress-route-selector", c.DefaultIngress.RouteSelectors, "=")
+		l.addString("default-ingress-excluded-namespaces", strings.Join(c.DefaultIngress.ExcludedNamespaces, ","))
+		l.addString("default-ingress-wildcard-policy", c.DefaultIngress.WildcardPolicy)
+		l.addString("default-ingress-namespace-ownership-policy", c.DefaultIngress.NamespaceOwnershipPolicy)
+	}
+
+	if c.SharedVPC != nil {
+		l.addString("private-hosted-zone-id", c.SharedVPC.PrivateHostedZoneID)
+		l.addString("shared-vpc-role-arn", c.SharedVPC.RoleARN)
+		l.addString("base-domain", c.SharedVPC.BaseDomain)
+	}
+
+	if c.ClusterAdmin != nil {
+		l.addString("cluster-admin-user", c.ClusterAdmin.User)
+		l.addString("cluster-admin-password", c.ClusterAdmin.Password)
+	}
+
+	return *l
+}
+
+type flagList []FlagValue
+
+func (l *flagList) addString(name string, value string) {
+	if value == "" {
+		return
+	}
+	*l = append(*l, FlagValue{Name: name, Value: value})
+}
+
+func (l *flagList) addBool(name string, value *bool) {
+	if value == nil {
+		return
+	}
+	*l = append(*l, FlagValue{Name: name, Value: strconv.FormatBool(*value), Bool: true})
+}
+
+func (l *flagList) addInt(name string, value *int) {
+	if value == nil {
+		return
+	}
+	l.addString(name, strconv.Itoa(*value))
+}
+
+func (l *flagList) addFloat(name string, value *float64) {
+	if value == nil {
+		return
+	}
+	l.addString(name, strconv.FormatFloat(*value, 'f', -1, 64))
+}
+
+func (l *flagList) addList(name string, values []string) {
+	l.addString(name, strings.Join(values, ","))
+}
+
+// addMap adds a map as a comma-separated list of key/value pairs sorted by key, so that the
+// resulting command line is stable between runs.
+func (l *flagList) addMap(name string, values map[string]string, delim string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s%s%s", k, delim, values[k]))
+	}
+	l.addList(name, pairs)
+}
+
+// tagsDelimiter mirrors the delimiter detection used by the '--tags' option: if a key or a
+// value contains ':' the pairs must be separated with a space instead.
+func tagsDelimiter(tags map[string]string) string {
+	for k, v := range tags {
+		if strings.Contains(k, ":") || strings.Contains(v, ":") {
+			return " "
+		}
+	}
+	return ":"
+}
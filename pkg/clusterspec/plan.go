@@ -0,0 +1,558 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that compare the desired day-2 resources of a cluster with
+// the existing ones and calculate the changes needed to reconcile them.
+
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+const (
+	KindMachinePool      = "machine pool"
+	KindIdentityProvider = "identity provider"
+	KindIngress          = "ingress"
+	KindTuningConfig     = "tuning config"
+	KindUser             = "user"
+)
+
+const (
+	ClusterAdminsGroup   = "cluster-admins"
+	DedicatedAdminsGroup = "dedicated-admins"
+)
+
+// Change is a single step of the plan that brings the cluster to the desired state.
+type Change struct {
+	Action Action
+	Kind   string
+	// Name identifies the resource in the plan, ID is the identifier of an existing resource.
+	Name string
+	ID   string
+	// Group is only set for users.
+	Group string
+	// Fields are the differences that cause an update.
+	Fields []FieldDiff
+	// Desired is the definition of the resource to create or update, one of *MachinePool,
+	// *IdentityProvider, *Ingress or *TuningConfig.
+	Desired interface{}
+}
+
+type FieldDiff struct {
+	Field string
+	From  string
+	To    string
+}
+
+// State is the current state of the day-2 resources of a cluster. The users are indexed by
+// group.
+type State struct {
+	Cluster           *cmv1.Cluster
+	MachinePools      []*cmv1.MachinePool
+	NodePools         []*cmv1.NodePool
+	IdentityProviders []*cmv1.IdentityProvider
+	Ingresses         []*cmv1.Ingress
+	TuningConfigs     []*cmv1.TuningConfig
+	Users             map[string][]string
+}
+
+// Plan calculates the changes that bring the current state to the desired one. Only the types
+// of resources that are listed in the desired state are managed, and only the fields that are
+// set are compared. Resources that aren't in the desired state are deleted only when prune is
+// set, with the exception of the default machine pools and ingress. The users of existing
+// HTPasswd identity providers are not reconciled.
+//
+// Resources are created in dependency order, so that tuning configs exist before the machine
+// pools that use them, and deleted in the reverse order.
+func Plan(desired *Resources, current *State, prune bool) ([]Change, error) {
+	p := &planner{prune: prune}
+	steps := []func() error{
+		func() error { return p.tuningConfigs(desired.TuningConfigs, current) },
+		func() error { return p.machinePools(desired.MachinePools, current) },
+		func() error { return p.identityProviders(desired.IdentityProviders, current) },
+		func() error { return p.ingresses(desired.Ingresses, current) },
+		func() error { return p.users(desired.Users, current) },
+	}
+	for _, step := range steps {
+		err := step()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(p.changes, p.deletions...), nil
+}
+
+type planner struct {
+	prune     bool
+	changes   []Change
+	deletions []Change
+	// pending collects the deletions of the resource type being planned
+	pending []Change
+}
+
+func (p *planner) add(change Change) {
+	if change.Action == ActionDelete {
+		p.pending = append(p.pending, change)
+		return
+	}
+	p.changes = append(p.changes, change)
+}
+
+// done moves the deletions of the current type of resource in front of the ones of the types
+// that were planned before it.
+func (p *planner) done() {
+	p.deletions = append(p.pending, p.deletions...)
+	p.pending = nil
+}
+
+func (p *planner) tuningConfigs(desired []TuningConfig, current *State) error {
+	if desired == nil {
+		return nil
+	}
+	defer p.done()
+	existing := map[string]*cmv1.TuningConfig{}
+	for _, tuningConfig := range current.TuningConfigs {
+		existing[tuningConfig.Name()] = tuningConfig
+	}
+	seen := map[string]bool{}
+	for i := range desired {
+		tuningConfig := &desired[i]
+		err := checkName(KindTuningConfig, tuningConfig.Name, seen)
+		if err != nil {
+			return err
+		}
+		cur, ok := existing[tuningConfig.Name]
+		if !ok {
+			p.add(Change{Action: ActionCreate, Kind: KindTuningConfig, Name: tuningConfig.Name,
+				Desired: tuningConfig})
+			continue
+		}
+		fields, err := diffFields(tuningConfig, TuningConfigFromOCM(cur))
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			p.add(Change{Action: ActionUpdate, Kind: KindTuningConfig, Name: tuningConfig.Name, ID: cur.ID(),
+				Fields: fields, Desired: tuningConfig})
+		}
+	}
+	if p.prune {
+		for _, tuningConfig := range current.TuningConfigs {
+			if !seen[tuningConfig.Name()] {
+				p.add(Change{Action: ActionDelete, Kind: KindTuningConfig, Name: tuningConfig.Name(),
+					ID: tuningConfig.ID()})
+			}
+		}
+	}
+	return nil
+}
+
+var immutableMachinePoolFields = []string{
+	"instanceType", "availabilityZone", "subnet", "diskSize", "spotInstances", "version",
+}
+
+func (p *planner) machinePools(desired []MachinePool, current *State) error {
+	if desired == nil {
+		return nil
+	}
+	defer p.done()
+	hostedCP := ocm.IsHyperShiftCluster(current.Cluster)
+	var names []string
+	existing := map[string]*MachinePool{}
+	if hostedCP {
+		for _, nodePool := range current.NodePools {
+			names = append(names, nodePool.ID())
+			existing[nodePool.ID()] = nodePoolFromOCM(nodePool)
+		}
+	} else {
+		for _, machinePool := range current.MachinePools {
+			names = append(names, machinePool.ID())
+			existing[machinePool.ID()] = machinePoolFromOCM(current.Cluster, machinePool)
+		}
+	}
+	seen := map[string]bool{}
+	for i := range desired {
+		machinePool := &desired[i]
+		err := checkName(KindMachinePool, machinePool.Name, seen)
+		if err != nil {
+			return err
+		}
+		cur, ok := existing[machinePool.Name]
+		if !ok {
+			if machinePool.InstanceType == "" {
+				return fmt.Errorf("Machine pool '%s' doesn't exist and has no instance type", machinePool.Name)
+			}
+			p.add(Change{Action: ActionCreate, Kind: KindMachinePool, Name: machinePool.Name,
+				Desired: machinePool})
+			continue
+		}
+		fields, err := machinePoolDiff(machinePool, cur)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			p.add(Change{Action: ActionUpdate, Kind: KindMachinePool, Name: machinePool.Name,
+				ID: machinePool.Name, Fields: fields, Desired: machinePool})
+		}
+	}
+	if p.prune {
+		for _, name := range names {
+			if !seen[name] && !isDefaultMachinePool(name, hostedCP) {
+				p.add(Change{Action: ActionDelete, Kind: KindMachinePool, Name: name, ID: name})
+			}
+		}
+	}
+	return nil
+}
+
+func machinePoolDiff(desired *MachinePool, current *MachinePool) ([]FieldDiff, error) {
+	normalized := *desired
+	if normalized.DiskSize != "" {
+		size, err := ocm.ParseDiskSizeToGigibyte(normalized.DiskSize)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid disk size for machine pool '%s': %v", desired.Name, err)
+		}
+		normalized.DiskSize = fmt.Sprintf("%dGiB", size)
+	}
+	fields, err := diffFields(&normalized, current)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		for _, immutable := range immutableMachinePoolFields {
+			if field.Field == immutable {
+				return nil, fmt.Errorf("Field '%s' of machine pool '%s' can't be changed from %s to %s, "+
+					"the machine pool needs to be recreated", field.Field, desired.Name, field.From, field.To)
+			}
+		}
+	}
+	if desired.Replicas != nil && current.Autoscaling != nil {
+		fields = append(fields, FieldDiff{Field: "autoscaling", From: formatValue(current.Autoscaling),
+			To: formatValue(nil)})
+	}
+	fields = append(fields, clearedField("labels", desired.Labels, current.Labels)...)
+	fields = append(fields, clearedField("taints", desired.Taints, current.Taints)...)
+	return fields, nil
+}
+
+func (p *planner) identityProviders(desired []IdentityProvider, current *State) error {
+	if desired == nil {
+		return nil
+	}
+	defer p.done()
+	existing := map[string]*cmv1.IdentityProvider{}
+	for _, idp := range current.IdentityProviders {
+		existing[idp.Name()] = idp
+	}
+	seen := map[string]bool{}
+	for i := range desired {
+		idp := &desired[i]
+		err := checkName(KindIdentityProvider, idp.Name, seen)
+		if err != nil {
+			return err
+		}
+		cur, ok := existing[idp.Name]
+		if !ok {
+			err = idp.checkSecrets()
+			if err != nil {
+				return err
+			}
+			p.add(Change{Action: ActionCreate, Kind: KindIdentityProvider, Name: idp.Name, Desired: idp})
+			continue
+		}
+		converted := IdentityProviderFromOCM(cur, nil)
+		if converted.Type != idp.Type {
+			return fmt.Errorf("Identity provider '%s' is of type '%s' and can't be changed to '%s'",
+				idp.Name, converted.Type, idp.Type)
+		}
+		if cur.Type() == cmv1.IdentityProviderTypeLDAP {
+			insecure := cur.LDAP().Insecure()
+			converted.Insecure = &insecure
+		}
+		// Secrets are never returned, so they can't be compared
+		compared := *idp
+		compared.ClientSecret = ""
+		compared.BindPassword = ""
+		compared.Users = nil
+		fields, err := diffFields(&compared, converted)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			p.add(Change{Action: ActionUpdate, Kind: KindIdentityProvider, Name: idp.Name, ID: cur.ID(),
+				Fields: fields, Desired: idp})
+		}
+	}
+	if p.prune {
+		for _, idp := range current.IdentityProviders {
+			if !seen[idp.Name()] {
+				p.add(Change{Action: ActionDelete, Kind: KindIdentityProvider, Name: idp.Name(), ID: idp.ID()})
+			}
+		}
+	}
+	return nil
+}
+
+// checkSecrets checks that the secrets needed to create the identity provider are set, as they
+// are never part of an exported definition.
+func (i *IdentityProvider) checkSecrets() error {
+	switch i.Type {
+	case "github", "gitlab", "google", "openid":
+		if i.ClientSecret == "" {
+			return fmt.Errorf("Identity provider '%s' doesn't exist and has no client secret", i.Name)
+		}
+	case "ldap":
+		if i.BindDN != "" && i.BindPassword == "" {
+			return fmt.Errorf("Identity provider '%s' doesn't exist and has no bind password", i.Name)
+		}
+	case "htpasswd":
+		if len(i.Users) == 0 {
+			return fmt.Errorf("Identity provider '%s' doesn't exist and has no users", i.Name)
+		}
+		for _, user := range i.Users {
+			if user.Password == "" {
+				return fmt.Errorf("User '%s' of identity provider '%s' has no password", user.Username, i.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *planner) ingresses(desired []Ingress, current *State) error {
+	if desired == nil {
+		return nil
+	}
+	defer p.done()
+	matched := map[string]bool{}
+	for i := range desired {
+		ingress := &desired[i]
+		cur, err := matchIngress(ingress, current.Ingresses, matched)
+		if err != nil {
+			return err
+		}
+		if cur == nil {
+			p.add(Change{Action: ActionCreate, Kind: KindIngress, Name: ingressName(ingress.LabelMatch),
+				Desired: ingress})
+			continue
+		}
+		matched[cur.ID()] = true
+		converted := ingressFromOCM(cur)
+		private := cur.Listening() == cmv1.ListeningMethodInternal
+		converted.Private = &private
+		compared := *ingress
+		compared.ID = cur.ID()
+		fields, err := diffFields(&compared, converted)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if field.Field == "lbType" {
+				return fmt.Errorf("Load balancer type of ingress '%s' can't be changed from %s to %s",
+					cur.ID(), field.From, field.To)
+			}
+		}
+		fields = append(fields, clearedField("excludedNamespaces", ingress.ExcludedNamespaces,
+			converted.ExcludedNamespaces)...)
+		if len(fields) > 0 {
+			p.add(Change{Action: ActionUpdate, Kind: KindIngress, Name: cur.ID(), ID: cur.ID(),
+				Fields: fields, Desired: ingress})
+		}
+	}
+	if p.prune {
+		for _, ingress := range current.Ingresses {
+			if !ingress.Default() && !matched[ingress.ID()] {
+				p.add(Change{Action: ActionDelete, Kind: KindIngress, Name: ingress.ID(), ID: ingress.ID()})
+			}
+		}
+	}
+	return nil
+}
+
+// matchIngress finds the existing ingress for the desired one. Ingresses get a generated
+// identifier, so unless the identifier is given they are matched by their label selector.
+func matchIngress(desired *Ingress, ingresses []*cmv1.Ingress, matched map[string]bool) (*cmv1.Ingress, error) {
+	if desired.ID != "" {
+		for _, ingress := range ingresses {
+			if ingress.ID() == desired.ID {
+				if matched[ingress.ID()] {
+					return nil, fmt.Errorf("Ingress '%s' is defined more than once", desired.ID)
+				}
+				return ingress, nil
+			}
+		}
+		return nil, fmt.Errorf("Ingress '%s' doesn't exist", desired.ID)
+	}
+	for _, ingress := range ingresses {
+		if ingress.Default() || matched[ingress.ID()] {
+			continue
+		}
+		if len(ingress.RouteSelectors()) == 0 && len(desired.LabelMatch) == 0 ||
+			reflect.DeepEqual(ingress.RouteSelectors(), desired.LabelMatch) {
+			return ingress, nil
+		}
+	}
+	return nil, nil
+}
+
+func ingressName(labelMatch map[string]string) string {
+	if len(labelMatch) == 0 {
+		return "without label match"
+	}
+	pairs := make([]string, 0, len(labelMatch))
+	for k, v := range labelMatch {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p *planner) users(desired *Users, current *State) error {
+	if desired == nil {
+		return nil
+	}
+	defer p.done()
+	groups := []struct {
+		name  string
+		users []string
+	}{
+		{ClusterAdminsGroup, desired.ClusterAdmins},
+		{DedicatedAdminsGroup, desired.DedicatedAdmins},
+	}
+	for _, group := range groups {
+		if group.users == nil {
+			continue
+		}
+		existing := map[string]bool{}
+		for _, username := range current.Users[group.name] {
+			existing[username] = true
+		}
+		seen := map[string]bool{}
+		for _, username := range group.users {
+			err := checkName(KindUser, username, seen)
+			if err != nil {
+				return err
+			}
+			if !existing[username] {
+				p.add(Change{Action: ActionCreate, Kind: KindUser, Name: username, ID: username,
+					Group: group.name})
+			}
+		}
+		if p.prune {
+			for _, username := range current.Users[group.name] {
+				if !seen[username] {
+					p.add(Change{Action: ActionDelete, Kind: KindUser, Name: username, ID: username,
+						Group: group.name})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkName(kind string, name string, seen map[string]bool) error {
+	if name == "" {
+		return fmt.Errorf("Found a %s without a name", kind)
+	}
+	if seen[name] {
+		return fmt.Errorf("The %s '%s' is defined more than once", kind, name)
+	}
+	seen[name] = true
+	return nil
+}
+
+// diffFields compares the fields that are set in the desired object with the current one. The
+// objects are compared through their JSON representation, which leaves out the unset fields.
+func diffFields(desired interface{}, current interface{}) ([]FieldDiff, error) {
+	desiredFields, err := toFields(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentFields, err := toFields(current)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(desiredFields))
+	for name := range desiredFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var fields []FieldDiff
+	for _, name := range names {
+		if reflect.DeepEqual(desiredFields[name], currentFields[name]) {
+			continue
+		}
+		fields = append(fields, FieldDiff{
+			Field: name,
+			From:  formatValue(currentFields[name]),
+			To:    formatValue(desiredFields[name]),
+		})
+	}
+	return fields, nil
+}
+
+// clearedField reports a field that is explicitly set to an empty list or map, which diffFields
+// can't tell apart from a field that isn't set.
+func clearedField(name string, desired interface{}, current interface{}) []FieldDiff {
+	value := reflect.ValueOf(desired)
+	if value.IsNil() || value.Len() > 0 || reflect.ValueOf(current).Len() == 0 {
+		return nil
+	}
+	return []FieldDiff{{Field: name, From: formatValue(current), To: formatValue(nil)}}
+}
+
+func toFields(object interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func formatValue(value interface{}) string {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil() {
+		return "<none>"
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("'%s'", s)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,199 @@
+package clusterspec
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+	var cluster *cmv1.Cluster
+	var current *State
+
+	BeforeEach(func() {
+		var err error
+		cluster, err = cmv1.NewCluster().ID("123").Build()
+		Expect(err).ToNot(HaveOccurred())
+		worker, err := cmv1.NewMachinePool().ID("worker").InstanceType("m5.xlarge").Replicas(2).Build()
+		Expect(err).ToNot(HaveOccurred())
+		db, err := cmv1.NewMachinePool().ID("db").InstanceType("r5.xlarge").Replicas(2).
+			Labels(map[string]string{"app": "db"}).Build()
+		Expect(err).ToNot(HaveOccurred())
+		old, err := cmv1.NewMachinePool().ID("old").InstanceType("m5.xlarge").Replicas(1).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defaultIngress, err := cmv1.NewIngress().ID("a1b2").Default(true).Build()
+		Expect(err).ToNot(HaveOccurred())
+		internalIngress, err := cmv1.NewIngress().ID("c3d4").
+			Listening(cmv1.ListeningMethodExternal).
+			RouteSelectors(map[string]string{"route": "internal"}).Build()
+		Expect(err).ToNot(HaveOccurred())
+		tuningConfig, err := cmv1.NewTuningConfig().ID("t1").Name("tuned").
+			Spec(map[string]interface{}{"profile": "a"}).Build()
+		Expect(err).ToNot(HaveOccurred())
+		current = &State{
+			Cluster:       cluster,
+			MachinePools:  []*cmv1.MachinePool{worker, db, old},
+			Ingresses:     []*cmv1.Ingress{defaultIngress, internalIngress},
+			TuningConfigs: []*cmv1.TuningConfig{tuningConfig},
+			Users: map[string][]string{
+				ClusterAdminsGroup: {"alice"},
+			},
+		}
+	})
+
+	It("Returns no changes when the cluster is up to date", func() {
+		replicas := 2
+		desired := &Resources{
+			MachinePools: []MachinePool{{Name: "db", Replicas: &replicas}, {Name: "old"}},
+			Users:        &Users{ClusterAdmins: []string{"alice"}},
+		}
+		changes, err := Plan(desired, current, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("Creates, updates and prunes machine pools", func() {
+		replicas := 3
+		desired := &Resources{
+			MachinePools: []MachinePool{
+				{Name: "db", Replicas: &replicas},
+				{Name: "new", InstanceType: "m5.xlarge", Replicas: &replicas},
+			},
+		}
+		changes, err := Plan(desired, current, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(3))
+		Expect(changes[0].Action).To(Equal(ActionUpdate))
+		Expect(changes[0].Name).To(Equal("db"))
+		Expect(changes[0].Fields).To(Equal([]FieldDiff{{Field: "replicas", From: "2", To: "3"}}))
+		Expect(changes[1].Action).To(Equal(ActionCreate))
+		Expect(changes[1].Name).To(Equal("new"))
+		Expect(changes[2].Action).To(Equal(ActionDelete))
+		Expect(changes[2].Name).To(Equal("old"))
+	})
+
+	It("Doesn't delete anything without prune", func() {
+		desired := &Resources{MachinePools: []MachinePool{}}
+		changes, err := Plan(desired, current, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("Never deletes the default machine pool and ingress", func() {
+		desired := &Resources{MachinePools: []MachinePool{}, Ingresses: []Ingress{}}
+		changes, err := Plan(desired, current, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(3))
+		Expect(changes[0].Kind).To(Equal(KindIngress))
+		Expect(changes[0].Name).To(Equal("c3d4"))
+		Expect(changes[1].Name).To(Equal("db"))
+		Expect(changes[2].Name).To(Equal("old"))
+	})
+
+	It("Rejects changes to immutable fields", func() {
+		desired := &Resources{MachinePools: []MachinePool{{Name: "db", InstanceType: "m5.xlarge"}}}
+		_, err := Plan(desired, current, false)
+		Expect(err).To(MatchError(ContainSubstring(
+			"Field 'instanceType' of machine pool 'db' can't be changed from 'r5.xlarge' to 'm5.xlarge'")))
+	})
+
+	It("Clears labels set to an empty map", func() {
+		desired := &Resources{MachinePools: []MachinePool{{Name: "db", Labels: map[string]string{}}}}
+		changes, err := Plan(desired, current, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Fields).To(Equal([]FieldDiff{{Field: "labels", From: `{"app":"db"}`, To: "<none>"}}))
+	})
+
+	It("Matches ingresses by their label selector", func() {
+		private := true
+		desired := &Resources{Ingresses: []Ingress{
+			{LabelMatch: map[string]string{"route": "internal"}, Private: &private},
+			{LabelMatch: map[string]string{"route": "public"}},
+		}}
+		changes, err := Plan(desired, current, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(2))
+		Expect(changes[0].Action).To(Equal(ActionUpdate))
+		Expect(changes[0].ID).To(Equal("c3d4"))
+		Expect(changes[0].Fields).To(Equal([]FieldDiff{{Field: "private", From: "false", To: "true"}}))
+		Expect(changes[1].Action).To(Equal(ActionCreate))
+		Expect(changes[1].Name).To(Equal("route=public"))
+	})
+
+	It("Updates tuning configs when the spec changes", func() {
+		desired := &Resources{TuningConfigs: []TuningConfig{
+			{Name: "tuned", Spec: map[string]interface{}{"profile": "b"}},
+		}}
+		changes, err := Plan(desired, current, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].ID).To(Equal("t1"))
+		Expect(changes[0].Fields[0].Field).To(Equal("spec"))
+	})
+
+	It("Requires the secrets of new identity providers", func() {
+		desired := &Resources{IdentityProviders: []IdentityProvider{
+			{Name: "github-1", Type: "github", ClientID: "id"},
+		}}
+		_, err := Plan(desired, current, false)
+		Expect(err).To(MatchError("Identity provider 'github-1' doesn't exist and has no client secret"))
+	})
+
+	It("Reconciles users per group", func() {
+		desired := &Resources{Users: &Users{
+			ClusterAdmins:   []string{"bob"},
+			DedicatedAdmins: []string{"carol"},
+		}}
+		changes, err := Plan(desired, current, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]Change{
+			{Action: ActionCreate, Kind: KindUser, Name: "bob", ID: "bob", Group: ClusterAdminsGroup},
+			{Action: ActionCreate, Kind: KindUser, Name: "carol", ID: "carol", Group: DedicatedAdminsGroup},
+			{Action: ActionDelete, Kind: KindUser, Name: "alice", ID: "alice", Group: ClusterAdminsGroup},
+		}))
+	})
+
+	It("Rejects duplicated names", func() {
+		desired := &Resources{Users: &Users{ClusterAdmins: []string{"bob", "bob"}}}
+		_, err := Plan(desired, current, false)
+		Expect(err).To(MatchError("The user 'bob' is defined more than once"))
+	})
+})
+
+var _ = Describe("ParseResources", func() {
+	It("Parses a resources file", func() {
+		resources, err := ParseResources([]byte(`
+apiVersion: rosa.openshift.io/v1
+kind: ClusterResources
+machinePools:
+- name: db
+  replicas: 3
+users:
+  clusterAdmins: []
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.MachinePools).To(HaveLen(1))
+		Expect(resources.IdentityProviders).To(BeNil())
+		Expect(resources.Users.ClusterAdmins).ToNot(BeNil())
+		Expect(resources.Users.DedicatedAdmins).To(BeNil())
+	})
+	It("Uses the resources of a cluster definition", func() {
+		resources, err := ParseResources([]byte(`
+apiVersion: rosa.openshift.io/v1
+kind: Cluster
+name: mycluster
+resources:
+  tuningConfigs:
+  - name: tuned
+    spec: {}
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.TuningConfigs).To(HaveLen(1))
+	})
+	It("Rejects unknown kinds", func() {
+		_, err := ParseResources([]byte("apiVersion: rosa.openshift.io/v1\nkind: MachinePool\n"))
+		Expect(err).To(MatchError("Unsupported kind 'MachinePool', expected 'ClusterResources'"))
+	})
+})
@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types used to describe the resources that are added to a cluster
+// after it has been created, like machine pools or identity providers.
+
+package clusterspec
+
+import (
+	"fmt"
+	"os"
+)
+
+// ResourcesKind is the kind of a file that only contains the day-2 resources of a cluster.
+const ResourcesKind = "ClusterResources"
+
+// Resources are the day-2 resources of a cluster. They aren't used by 'rosa create cluster',
+// which only creates the cluster itself. A list that is left out is not managed by 'rosa apply',
+// while an empty list means that there should be no resources of that type.
+type Resources struct {
+	MachinePools      []MachinePool      `json:"machinePools,omitempty"`
+	IdentityProviders []IdentityProvider `json:"identityProviders,omitempty"`
+	Ingresses         []Ingress          `json:"ingresses,omitempty"`
+	TuningConfigs     []TuningConfig     `json:"tuningConfigs,omitempty"`
+	UpgradePolicy     *UpgradePolicy     `json:"upgradePolicy,omitempty"`
+	Users             *Users             `json:"users,omitempty"`
+}
+
+// ClusterResources is a file that only contains the day-2 resources of a cluster.
+type ClusterResources struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Resources
+}
+
+// MachinePool describes either a machine pool of a classic cluster or a node pool of a
+// hosted control plane cluster. Fields that only apply to one of them are ignored for the other.
+type MachinePool struct {
+	Name             string                  `json:"name"`
+	InstanceType     string                  `json:"instanceType,omitempty"`
+	Replicas         *int                    `json:"replicas,omitempty"`
+	Autoscaling      *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+	Labels           map[string]string       `json:"labels,omitempty"`
+	Taints           []Taint                 `json:"taints,omitempty"`
+	AvailabilityZone string                  `json:"availabilityZone,omitempty"`
+	Subnet           string                  `json:"subnet,omitempty"`
+
+	// Classic clusters only
+	DiskSize      string         `json:"diskSize,omitempty"`
+	SpotInstances *SpotInstances `json:"spotInstances,omitempty"`
+
+	// Hosted control plane clusters only
+	Version       string   `json:"version,omitempty"`
+	AutoRepair    *bool    `json:"autoRepair,omitempty"`
+	TuningConfigs []string `json:"tuningConfigs,omitempty"`
+}
+
+type MachinePoolAutoscaling struct {
+	MinReplicas int `json:"minReplicas"`
+	MaxReplicas int `json:"maxReplicas"`
+}
+
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// SpotInstances enables spot instances for a machine pool. Without a maximum price the
+// on-demand price is used.
+type SpotInstances struct {
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+}
+
+// IdentityProvider mirrors the options of 'rosa create idp'. Secrets such as the client secret
+// are never exported, so they have to be filled in before the definition can be applied.
+type IdentityProvider struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	MappingMethod string `json:"mappingMethod,omitempty"`
+
+	// GitHub, GitLab, Google and OpenID
+	ClientID     string `json:"clientID,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// GitHub
+	Hostname      string   `json:"hostname,omitempty"`
+	Organizations []string `json:"organizations,omitempty"`
+	Teams         []string `json:"teams,omitempty"`
+
+	// GitLab and LDAP
+	URL string `json:"url,omitempty"`
+
+	// Google
+	HostedDomain string `json:"hostedDomain,omitempty"`
+
+	// LDAP
+	BindDN             string   `json:"bindDN,omitempty"`
+	BindPassword       string   `json:"bindPassword,omitempty"`
+	Insecure           *bool    `json:"insecure,omitempty"`
+	IDAttributes       []string `json:"idAttributes,omitempty"`
+	UsernameAttributes []string `json:"usernameAttributes,omitempty"`
+	NameAttributes     []string `json:"nameAttributes,omitempty"`
+	EmailAttributes    []string `json:"emailAttributes,omitempty"`
+
+	// OpenID
+	IssuerURL      string   `json:"issuerURL,omitempty"`
+	EmailClaims    []string `json:"emailClaims,omitempty"`
+	NameClaims     []string `json:"nameClaims,omitempty"`
+	UsernameClaims []string `json:"usernameClaims,omitempty"`
+	GroupsClaims   []string `json:"groupsClaims,omitempty"`
+	ExtraScopes    []string `json:"extraScopes,omitempty"`
+
+	// HTPasswd
+	Users []HTPasswdUser `json:"users,omitempty"`
+}
+
+type HTPasswdUser struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+// Ingress describes an additional ingress. The default ingress of the cluster is part of the
+// cluster definition itself.
+type Ingress struct {
+	ID                       string            `json:"id,omitempty"`
+	Private                  *bool             `json:"private,omitempty"`
+	LabelMatch               map[string]string `json:"labelMatch,omitempty"`
+	LBType                   string            `json:"lbType,omitempty"`
+	ExcludedNamespaces       []string          `json:"excludedNamespaces,omitempty"`
+	WildcardPolicy           string            `json:"wildcardPolicy,omitempty"`
+	NamespaceOwnershipPolicy string            `json:"namespaceOwnershipPolicy,omitempty"`
+}
+
+type TuningConfig struct {
+	Name string      `json:"name"`
+	Spec interface{} `json:"spec"`
+}
+
+// UpgradePolicy is the recurring upgrade schedule of the cluster. One-off upgrades are not
+// part of the definition, as they don't outlive the upgrade itself.
+type UpgradePolicy struct {
+	Schedule                 string `json:"schedule"`
+	AllowMinorVersionUpdates *bool  `json:"allowMinorVersionUpdates,omitempty"`
+}
+
+// Users are the members of the groups that grant administrative access to the cluster.
+type Users struct {
+	ClusterAdmins   []string `json:"clusterAdmins,omitempty"`
+	DedicatedAdmins []string `json:"dedicatedAdmins,omitempty"`
+}
+
+// ParseResources reads the day-2 resources from YAML or JSON. Both a file of kind
+// ClusterResources and a complete cluster definition are accepted.
+func ParseResources(data []byte) (*Resources, error) {
+	header := &ClusterResources{}
+	err := decode(data, header, false)
+	if err != nil {
+		return nil, err
+	}
+	if header.Kind == Kind {
+		spec, err := Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		if spec.Resources == nil {
+			return &Resources{}, nil
+		}
+		return spec.Resources, nil
+	}
+	err = validateVersion(header.APIVersion, header.Kind, ResourcesKind)
+	if err != nil {
+		return nil, err
+	}
+	doc := &ClusterResources{}
+	err = decode(data, doc, true)
+	if err != nil {
+		return nil, err
+	}
+	return &doc.Resources, nil
+}
+
+// LoadResources reads the day-2 resources from the given file.
+func LoadResources(path string) (*Resources, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read resources file '%s': %v", path, err)
+	}
+	resources, err := ParseResources(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return resources, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exitcode contains the exit codes of the tool and the typed errors that select them.
+//
+// Commands return errors up to the main function, which reports them and exits with the code
+// returned by Of. Errors created with the Errorf and Wrap methods of a code select that code, and
+// errors that come from the OCM and AWS APIs are classified according to their status or error
+// code, so that callers only need to pick a code when the cause isn't already typed.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/zgalor/weberr"
+)
+
+// Code is the exit code of the tool.
+type Code int
+
+// Exit codes of the tool. They are part of the interface used by scripts, so existing values
+// must never change.
+const (
+	// Success means that the command completed.
+	Success Code = 0
+
+	// Failure is used for any error that doesn't have a more specific code.
+	Failure Code = 1
+
+	// InvalidInput means that the flags, arguments or files given to the command aren't valid.
+	InvalidInput Code = 2
+
+	// NotLoggedIn means that there is no valid OCM session, and 'rosa login' is required.
+	NotLoggedIn Code = 3
+
+	// NotFound means that a cluster or other resource doesn't exist.
+	NotFound Code = 4
+
+	// AWSCredentials means that the AWS credentials are missing, invalid or expired.
+	AWSCredentials Code = 5
+
+	// InsufficientQuota means that there isn't enough AWS or OCM quota for the operation.
+	InsufficientQuota Code = 6
+
+	// Forbidden means that the OCM user or the AWS identity lacks the required permissions.
+	Forbidden Code = 7
+
+	// VerificationFailed means that a 'rosa verify' command found resources that don't match what
+	// is expected.
+	VerificationFailed Code = 8
+
+	// Timeout means that 'rosa wait' reached its timeout before the condition was met.
+	Timeout Code = 9
+
+	// ConditionFailed means that the resource waited for by 'rosa wait' reached a state from which
+	// the condition can't be met, like a cluster in error state while waiting for it to be ready.
+	ConditionFailed Code = 10
+)
+
+// Error is an error that selects the exit code of the tool.
+type Error struct {
+	code Code
+	err  error
+}
+
+// Errorf creates an error with the given format and arguments that selects this exit code.
+func (c Code) Errorf(format string, args ...interface{}) error {
+	return &Error{code: c, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap adds this exit code to the given error, keeping its message. It returns nil if the error
+// is nil.
+func (c Code) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{code: c, err: err}
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Code returns the exit code selected by the error.
+func (e *Error) Code() Code {
+	return e.code
+}
+
+// causer is implemented by the errors of the weberr package.
+type causer interface {
+	Cause() error
+}
+
+// Of returns the exit code for the given error. The outermost error that can be classified
+// decides the code, and errors that can't be classified at all result in Failure.
+func Of(err error) Code {
+	if err == nil {
+		return Success
+	}
+	for err != nil {
+		code, ok := classify(err)
+		if ok {
+			return code
+		}
+		next := errors.Unwrap(err)
+		if next == nil {
+			if cause, ok := err.(causer); ok {
+				next = cause.Cause()
+			}
+		}
+		err = next
+	}
+	return Failure
+}
+
+func classify(err error) (Code, bool) {
+	if typed, ok := err.(*Error); ok {
+		return typed.code, true
+	}
+	if errorType := weberr.GetType(err); errorType != weberr.NoType {
+		return fromStatus(int(errorType))
+	}
+	if ocmErr, ok := err.(*ocmerrors.Error); ok {
+		return fromStatus(ocmErr.Status())
+	}
+	if awsErr, ok := err.(awserr.Error); ok {
+		return fromAWSCode(awsErr.Code())
+	}
+	return Failure, false
+}
+
+func fromStatus(status int) (Code, bool) {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return InvalidInput, true
+	case http.StatusUnauthorized:
+		return NotLoggedIn, true
+	case http.StatusForbidden:
+		return Forbidden, true
+	case http.StatusNotFound:
+		return NotFound, true
+	}
+	return Failure, false
+}
+
+func fromAWSCode(code string) (Code, bool) {
+	switch code {
+	case "NoCredentialProviders", "InvalidClientTokenId", "ExpiredToken", "ExpiredTokenException",
+		"SignatureDoesNotMatch", "UnrecognizedClientException", "InvalidAccessKeyId":
+		return AWSCredentials, true
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation":
+		return Forbidden, true
+	case "NoSuchEntity", "NoSuchBucket":
+		return NotFound, true
+	}
+	return Failure, false
+}
@@ -0,0 +1,78 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/zgalor/weberr"
+)
+
+var _ = Describe("Of", func() {
+	It("Returns success without an error", func() {
+		Expect(Of(nil)).To(Equal(Success))
+	})
+
+	It("Returns failure for errors that can't be classified", func() {
+		Expect(Of(errors.New("boom"))).To(Equal(Failure))
+	})
+
+	It("Uses the code of typed errors", func() {
+		err := NotLoggedIn.Errorf("Not logged in, run the '%s' command", "rosa login")
+		Expect(err).To(MatchError("Not logged in, run the 'rosa login' command"))
+		Expect(Of(err)).To(Equal(NotLoggedIn))
+	})
+
+	It("Finds typed errors wrapped with fmt.Errorf", func() {
+		err := fmt.Errorf("Insufficient AWS quotas: %w", InsufficientQuota.Errorf("not enough"))
+		Expect(Of(err)).To(Equal(InsufficientQuota))
+	})
+
+	It("Keeps the message of wrapped errors", func() {
+		err := InvalidInput.Wrap(errors.New("bad flag"))
+		Expect(err).To(MatchError("bad flag"))
+		Expect(Of(err)).To(Equal(InvalidInput))
+		Expect(InvalidInput.Wrap(nil)).To(BeNil())
+	})
+
+	It("Prefers the outermost code", func() {
+		err := AWSCredentials.Wrap(fmt.Errorf("wrapped: %w", NotFound.Errorf("missing")))
+		Expect(Of(err)).To(Equal(AWSCredentials))
+	})
+
+	DescribeTable("Classifies weberr errors",
+		func(errorType weberr.ErrorType, expected Code) {
+			Expect(Of(errorType.Errorf("failed"))).To(Equal(expected))
+		},
+		Entry("bad request", weberr.BadRequest, InvalidInput),
+		Entry("unauthorized", weberr.Unauthorized, NotLoggedIn),
+		Entry("forbidden", weberr.Forbidden, Forbidden),
+		Entry("not found", weberr.NotFound, NotFound),
+		Entry("internal error", weberr.InternalServerError, Failure),
+	)
+
+	It("Follows the cause of weberr errors", func() {
+		err := weberr.Wrapf(NotFound.Errorf("missing"), "failed")
+		Expect(Of(err)).To(Equal(NotFound))
+	})
+
+	It("Classifies OCM errors by status", func() {
+		err, buildErr := ocmerrors.NewError().Status(404).Reason("Cluster not found").Build()
+		Expect(buildErr).ToNot(HaveOccurred())
+		Expect(Of(fmt.Errorf("Failed to get cluster: %w", err))).To(Equal(NotFound))
+	})
+
+	DescribeTable("Classifies AWS errors",
+		func(code string, expected Code) {
+			Expect(Of(awserr.New(code, "failed", nil))).To(Equal(expected))
+		},
+		Entry("expired token", "ExpiredToken", AWSCredentials),
+		Entry("invalid client token", "InvalidClientTokenId", AWSCredentials),
+		Entry("access denied", "AccessDenied", Forbidden),
+		Entry("no such entity", "NoSuchEntity", NotFound),
+		Entry("throttling", "Throttling", Failure),
+	)
+})
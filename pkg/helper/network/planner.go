@@ -0,0 +1,304 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"net"
+	"os"
+)
+
+// Limits of the host prefix accepted for the cluster network.
+const (
+	HostPrefixMin = 23
+	HostPrefixMax = 26
+)
+
+// Size of the service CIDR proposed by the planner, the same used by default by OpenShift.
+const servicePrefix = 16
+
+// awsReservedAddresses is the number of addresses of each subnet that AWS reserves: the first four and
+// the last one.
+const awsReservedAddresses = 5
+
+// ovnRanges are the join and transit subnets used internally by OVN-Kubernetes, that none of the CIDRs
+// of the cluster may overlap.
+var ovnRanges = []string{
+	"100.64.0.0/16",
+	"100.88.0.0/16",
+}
+
+// privateRanges are the ranges searched when the preferred CIDRs aren't available, in order of preference.
+var privateRanges = []string{
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"100.64.0.0/10",
+}
+
+// PlanOptions contains the constraints used to plan the CIDRs of a cluster.
+type PlanOptions struct {
+	// MachineCIDR is the CIDR of the VPC the cluster is installed into. When it is empty a free range
+	// is proposed, starting with DefaultMachineCIDR.
+	MachineCIDR *net.IPNet
+
+	// Reserved are ranges used elsewhere, for example in the corporate network, that none of the
+	// proposed CIDRs may overlap.
+	Reserved []*net.IPNet
+
+	MaxNodes    int
+	PodsPerNode int
+
+	DefaultMachineCIDR *net.IPNet
+	DefaultServiceCIDR *net.IPNet
+	DefaultPodCIDR     *net.IPNet
+}
+
+// Plan is a set of non-overlapping CIDRs for a cluster, with the capacity they provide.
+type Plan struct {
+	MachineCIDR string `json:"machine_cidr"`
+	ServiceCIDR string `json:"service_cidr"`
+	PodCIDR     string `json:"pod_cidr"`
+	HostPrefix  int    `json:"host_prefix"`
+
+	// MaxNodes is the number of nodes that can get a block of the pod CIDR and an address of the
+	// machine CIDR.
+	MaxNodes int `json:"max_nodes"`
+
+	// MaxPodsPerNode is the number of pod addresses in the block assigned to each node.
+	MaxPodsPerNode int `json:"max_pods_per_node"`
+
+	// MaxServices is the number of addresses in the service CIDR.
+	MaxServices int `json:"max_services"`
+
+	// MachineAddresses is the number of addresses in the machine CIDR.
+	MachineAddresses int `json:"machine_addresses"`
+}
+
+// Describe explains the capacity of the plan.
+func (p *Plan) Describe() string {
+	return fmt.Sprintf("Host prefix /%d supports up to %d nodes with up to %d pods each, "+
+		"%d services and %d machine addresses",
+		p.HostPrefix, p.MaxNodes, p.MaxPodsPerNode, p.MaxServices, p.MachineAddresses)
+}
+
+// CreateFlags returns the flags of 'rosa create cluster' that use the CIDRs of the plan.
+func (p *Plan) CreateFlags() string {
+	return fmt.Sprintf("--machine-cidr %s --service-cidr %s --pod-cidr %s --host-prefix %d",
+		p.MachineCIDR, p.ServiceCIDR, p.PodCIDR, p.HostPrefix)
+}
+
+// Parse returns the CIDRs of the plan, checking that they are valid.
+func (p *Plan) Parse() (machineCIDR, serviceCIDR, podCIDR *net.IPNet, err error) {
+	_, machineCIDR, err = net.ParseCIDR(p.MachineCIDR)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Invalid machine CIDR '%s' in network plan: %v", p.MachineCIDR, err)
+	}
+	_, serviceCIDR, err = net.ParseCIDR(p.ServiceCIDR)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Invalid service CIDR '%s' in network plan: %v", p.ServiceCIDR, err)
+	}
+	_, podCIDR, err = net.ParseCIDR(p.PodCIDR)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Invalid pod CIDR '%s' in network plan: %v", p.PodCIDR, err)
+	}
+	if p.HostPrefix < HostPrefixMin || p.HostPrefix > HostPrefixMax {
+		return nil, nil, nil, fmt.Errorf("Invalid host prefix /%d in network plan: it should be between %d and %d",
+			p.HostPrefix, HostPrefixMin, HostPrefixMax)
+	}
+	return machineCIDR, serviceCIDR, podCIDR, nil
+}
+
+// LoadPlan reads a plan saved by 'rosa plan network'.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read network plan: %v", err)
+	}
+	plan := &Plan{}
+	err = json.Unmarshal(data, plan)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse network plan '%s': %v", path, err)
+	}
+	return plan, nil
+}
+
+// PlanCIDRs proposes machine, service and pod CIDRs that don't overlap each other, the reserved ranges
+// nor the ranges used internally by OVN-Kubernetes. There is a plan for each host prefix that fits the
+// pods per node, the first one being the one that uses the smallest pod CIDR. The number of nodes is
+// also limited by the usable addresses of the machine CIDR.
+func PlanCIDRs(options PlanOptions) ([]*Plan, error) {
+	if options.MaxNodes < 1 {
+		return nil, fmt.Errorf("The maximum number of nodes must be at least 1")
+	}
+	if options.PodsPerNode < 1 {
+		return nil, fmt.Errorf("The number of pods per node must be at least 1")
+	}
+	maxHostPrefix := HostPrefixForPods(options.PodsPerNode)
+	if maxHostPrefix < HostPrefixMin {
+		return nil, fmt.Errorf("A host prefix of /%d supports up to %d pods per node, %d were requested",
+			HostPrefixMin, podsPerHostPrefix(HostPrefixMin), options.PodsPerNode)
+	}
+	for _, network := range append(options.Reserved, options.MachineCIDR) {
+		if network != nil && network.IP.To4() == nil {
+			return nil, fmt.Errorf("Only IPv4 CIDRs are supported, got '%s'", network)
+		}
+	}
+
+	ovnNetworks := make([]*net.IPNet, len(ovnRanges))
+	for i, cidr := range ovnRanges {
+		_, ovnNetworks[i], _ = net.ParseCIDR(cidr)
+	}
+	unavailable := append(ovnNetworks, options.Reserved...)
+
+	machineCIDR := options.MachineCIDR
+	if machineCIDR != nil {
+		for _, ovnNetwork := range ovnNetworks {
+			if overlaps(machineCIDR, ovnNetwork) {
+				return nil, fmt.Errorf("The machine CIDR '%s' overlaps the range '%s' used internally by "+
+					"OVN-Kubernetes", machineCIDR, ovnNetwork)
+			}
+		}
+		for _, reserved := range options.Reserved {
+			if overlaps(machineCIDR, reserved) {
+				return nil, fmt.Errorf("The machine CIDR '%s' overlaps the reserved range '%s'",
+					machineCIDR, reserved)
+			}
+		}
+	} else {
+		size := 16
+		if options.DefaultMachineCIDR != nil {
+			size, _ = options.DefaultMachineCIDR.Mask.Size()
+		}
+		machineCIDR = findFree(size, options.DefaultMachineCIDR, unavailable)
+		if machineCIDR == nil {
+			return nil, fmt.Errorf("Unable to find a free /%d machine CIDR outside of the reserved ranges", size)
+		}
+	}
+
+	machinePrefix, _ := machineCIDR.Mask.Size()
+	machineAddresses := 1 << (32 - machinePrefix)
+	usableMachineAddresses := 0
+	if machineAddresses > awsReservedAddresses {
+		usableMachineAddresses = machineAddresses - awsReservedAddresses
+	}
+	if usableMachineAddresses < options.MaxNodes {
+		return nil, fmt.Errorf("The machine CIDR '%s' is too small for %d nodes, it only has %d usable addresses",
+			machineCIDR, options.MaxNodes, usableMachineAddresses)
+	}
+
+	nodeBits := bits.Len(uint(options.MaxNodes - 1))
+	plans := []*Plan{}
+	for hostPrefix := maxHostPrefix; hostPrefix >= HostPrefixMin; hostPrefix-- {
+		podPrefix := hostPrefix - nodeBits
+		if podPrefix < 8 {
+			continue
+		}
+		taken := append([]*net.IPNet{machineCIDR}, unavailable...)
+		podCIDR := findFree(podPrefix, options.DefaultPodCIDR, taken)
+		if podCIDR == nil {
+			continue
+		}
+		serviceCIDR := findFree(servicePrefix, options.DefaultServiceCIDR, append(taken, podCIDR))
+		if serviceCIDR == nil {
+			continue
+		}
+		maxNodes := 1 << (hostPrefix - podPrefix)
+		if maxNodes > usableMachineAddresses {
+			maxNodes = usableMachineAddresses
+		}
+		plans = append(plans, &Plan{
+			MachineCIDR:      machineCIDR.String(),
+			ServiceCIDR:      serviceCIDR.String(),
+			PodCIDR:          podCIDR.String(),
+			HostPrefix:       hostPrefix,
+			MaxNodes:         maxNodes,
+			MaxPodsPerNode:   podsPerHostPrefix(hostPrefix),
+			MaxServices:      1 << (32 - servicePrefix),
+			MachineAddresses: machineAddresses,
+		})
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("Unable to find free service and pod CIDRs for %d nodes with %d pods each "+
+			"outside of the machine CIDR and the reserved ranges", options.MaxNodes, options.PodsPerNode)
+	}
+	return plans, nil
+}
+
+// HostPrefixForPods returns the longest host prefix that gives each node enough addresses for the
+// given number of pods. The result may be shorter than HostPrefixMin if no valid host prefix fits.
+func HostPrefixForPods(pods int) int {
+	hostPrefix := HostPrefixMax
+	for hostPrefix >= HostPrefixMin && podsPerHostPrefix(hostPrefix) < pods {
+		hostPrefix--
+	}
+	return hostPrefix
+}
+
+// podsPerHostPrefix returns the number of pod addresses of a node block, excluding the network and
+// broadcast addresses.
+func podsPerHostPrefix(hostPrefix int) int {
+	return 1<<(32-hostPrefix) - 2
+}
+
+// findFree returns a block of the given size that doesn't overlap any of the taken networks. The block
+// aligned with the preferred network is tried first, then the private ranges are searched in order.
+func findFree(size int, preferred *net.IPNet, taken []*net.IPNet) *net.IPNet {
+	if preferred != nil && preferred.IP.To4() != nil {
+		candidate := &net.IPNet{IP: preferred.IP.Mask(net.CIDRMask(size, 32)), Mask: net.CIDRMask(size, 32)}
+		if isFree(candidate, taken) {
+			return candidate
+		}
+	}
+	for _, cidr := range privateRanges {
+		_, privateRange, _ := net.ParseCIDR(cidr)
+		rangeSize, _ := privateRange.Mask.Size()
+		if rangeSize > size {
+			continue
+		}
+		start := binary.BigEndian.Uint32(privateRange.IP.To4())
+		step := uint64(1) << (32 - size)
+		end := uint64(start) + uint64(1)<<(32-rangeSize)
+		for address := uint64(start); address < end && address <= math.MaxUint32; address += step {
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, uint32(address))
+			candidate := &net.IPNet{IP: ip, Mask: net.CIDRMask(size, 32)}
+			if isFree(candidate, taken) {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+func isFree(candidate *net.IPNet, taken []*net.IPNet) bool {
+	for _, network := range taken {
+		if network != nil && overlaps(candidate, network) {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps checks if two networks have addresses in common.
+func overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,141 @@
+package network
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func cidr(value string) *net.IPNet {
+	_, network, err := net.ParseCIDR(value)
+	Expect(err).NotTo(HaveOccurred())
+	return network
+}
+
+var _ = Describe("Network planner", func() {
+	var options PlanOptions
+
+	BeforeEach(func() {
+		options = PlanOptions{
+			MaxNodes:           180,
+			PodsPerNode:        250,
+			DefaultMachineCIDR: cidr("10.0.0.0/16"),
+			DefaultServiceCIDR: cidr("172.30.0.0/16"),
+			DefaultPodCIDR:     cidr("10.128.0.0/14"),
+		}
+	})
+
+	It("Proposes the defaults when there are no conflicts", func() {
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plans).To(HaveLen(2))
+		Expect(plans[0].MachineCIDR).To(Equal("10.0.0.0/16"))
+		Expect(plans[0].ServiceCIDR).To(Equal("172.30.0.0/16"))
+		Expect(plans[0].PodCIDR).To(Equal("10.128.0.0/16"))
+		Expect(plans[0].HostPrefix).To(Equal(24))
+		Expect(plans[0].MaxNodes).To(Equal(256))
+		Expect(plans[0].MaxPodsPerNode).To(Equal(254))
+		Expect(plans[1].PodCIDR).To(Equal("10.128.0.0/15"))
+		Expect(plans[1].HostPrefix).To(Equal(23))
+		Expect(plans[1].MaxPodsPerNode).To(Equal(510))
+	})
+
+	It("Sizes the pod CIDR for the maximum number of nodes", func() {
+		options.MaxNodes = 500
+		options.PodsPerNode = 500
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plans).To(HaveLen(1))
+		Expect(plans[0].PodCIDR).To(Equal("10.128.0.0/14"))
+		Expect(plans[0].HostPrefix).To(Equal(23))
+		Expect(plans[0].MaxNodes).To(Equal(512))
+	})
+
+	It("Avoids the VPC and the reserved ranges", func() {
+		options.MachineCIDR = cidr("10.128.0.0/16")
+		options.Reserved = []*net.IPNet{cidr("172.16.0.0/12"), cidr("10.129.0.0/16")}
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		for _, plan := range plans {
+			machineCIDR, serviceCIDR, podCIDR, err := plan.Parse()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(machineCIDR.String()).To(Equal("10.128.0.0/16"))
+			taken := append([]*net.IPNet{machineCIDR}, options.Reserved...)
+			Expect(isFree(serviceCIDR, taken)).To(BeTrue())
+			Expect(isFree(podCIDR, taken)).To(BeTrue())
+			Expect(overlaps(serviceCIDR, podCIDR)).To(BeFalse())
+		}
+		Expect(plans[0].PodCIDR).To(Equal("10.0.0.0/16"))
+		Expect(plans[0].ServiceCIDR).To(Equal("10.1.0.0/16"))
+	})
+
+	It("Proposes a machine CIDR outside of the reserved ranges", func() {
+		options.Reserved = []*net.IPNet{cidr("10.0.0.0/14")}
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plans[0].MachineCIDR).To(Equal("10.4.0.0/16"))
+	})
+
+	It("Fails if the VPC overlaps a reserved range", func() {
+		options.MachineCIDR = cidr("10.0.0.0/16")
+		options.Reserved = []*net.IPNet{cidr("10.0.0.0/8")}
+		_, err := PlanCIDRs(options)
+		Expect(err).To(MatchError(ContainSubstring("overlaps the reserved range '10.0.0.0/8'")))
+	})
+
+	It("Limits the number of nodes to the usable addresses of the machine CIDR", func() {
+		options.MachineCIDR = cidr("10.0.0.0/24")
+		options.MaxNodes = 200
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plans[0].PodCIDR).To(Equal("10.128.0.0/16"))
+		Expect(plans[0].MaxNodes).To(Equal(251))
+		Expect(plans[0].MachineAddresses).To(Equal(256))
+	})
+
+	It("Fails if the machine CIDR is too small for the nodes", func() {
+		options.MachineCIDR = cidr("10.0.0.0/26")
+		options.MaxNodes = 100
+		_, err := PlanCIDRs(options)
+		Expect(err).To(MatchError("The machine CIDR '10.0.0.0/26' is too small for 100 nodes, " +
+			"it only has 59 usable addresses"))
+	})
+
+	It("Never proposes the ranges used internally by OVN-Kubernetes", func() {
+		options.Reserved = []*net.IPNet{cidr("10.0.0.0/8"), cidr("172.16.0.0/12"), cidr("192.168.0.0/16")}
+		plans, err := PlanCIDRs(options)
+		Expect(err).NotTo(HaveOccurred())
+		ovnNetworks := []*net.IPNet{cidr("100.64.0.0/16"), cidr("100.88.0.0/16")}
+		for _, plan := range plans {
+			machineCIDR, serviceCIDR, podCIDR, err := plan.Parse()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isFree(machineCIDR, ovnNetworks)).To(BeTrue())
+			Expect(isFree(serviceCIDR, ovnNetworks)).To(BeTrue())
+			Expect(isFree(podCIDR, ovnNetworks)).To(BeTrue())
+		}
+		Expect(plans[0].MachineCIDR).To(Equal("100.65.0.0/16"))
+	})
+
+	It("Fails if the VPC overlaps a range used internally by OVN-Kubernetes", func() {
+		options.MachineCIDR = cidr("100.88.0.0/20")
+		_, err := PlanCIDRs(options)
+		Expect(err).To(MatchError(ContainSubstring("overlaps the range '100.88.0.0/16' used internally by " +
+			"OVN-Kubernetes")))
+	})
+
+	It("Fails if there are too many pods per node", func() {
+		options.PodsPerNode = 600
+		_, err := PlanCIDRs(options)
+		Expect(err).To(MatchError(ContainSubstring("supports up to 510 pods per node")))
+	})
+
+	It("Computes the host prefix for the pods per node", func() {
+		Expect(HostPrefixForPods(50)).To(Equal(26))
+		Expect(HostPrefixForPods(62)).To(Equal(26))
+		Expect(HostPrefixForPods(63)).To(Equal(25))
+		Expect(HostPrefixForPods(250)).To(Equal(24))
+		Expect(HostPrefixForPods(510)).To(Equal(23))
+		Expect(HostPrefixForPods(511)).To(Equal(22))
+	})
+})
@@ -16,6 +16,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/helper"
 )
 
@@ -65,6 +66,24 @@ func BuildOidcConfigInput(userPrefix, region string) (OidcConfigInput, error) {
 	}, nil
 }
 
+// GetBucketNameFromSecretArn returns the name of the S3 bucket of an OIDC configuration created by
+// ROSA, from the ARN of the secret that contains its private key.
+func GetBucketNameFromSecretArn(secretArn string) (string, error) {
+	secretResourceName, err := aws.GetResourceIdFromSecretArn(secretArn)
+	if err != nil {
+		return "", err
+	}
+	// The secret when creating from ROSA options has the following format
+	// rosa-private-key-<prefix>-oidc-<random-hash-length-4>-<random-aws-created-hash>
+	// The bucket is expected to be <prefix>-oidc-<random-hash-length-4>
+	bucketName := strings.TrimPrefix(secretResourceName, prefixForPrivateKeySecret+"-")
+	index := strings.LastIndex(bucketName, "-")
+	if index != -1 {
+		bucketName = bucketName[:index]
+	}
+	return bucketName, nil
+}
+
 func GenerateBucketName(userPrefix string) (string, error) {
 	randomLabel := helper.RandomLabel(defaultLengthRandomLabel)
 	bucketName := fmt.Sprintf("%s-%s", defaultPrefixForConfiguration, randomLabel)
@@ -162,14 +181,129 @@ type JSONWebKeySet struct {
 
 // BuildJSONWebKeySet builds JSON web key set from the public key
 func BuildJSONWebKeySet(publicKeyContent []byte) ([]byte, error) {
-	block, _ := pem.Decode(publicKeyContent)
+	key, err := buildJSONWebKey(publicKeyContent)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSONWebKeySet([]jose.JSONWebKey{key})
+}
+
+// AddJSONWebKey returns the JSON web key set with the given public key added before the keys that
+// it already contains, so that tokens signed with the new and the old keys can be verified.
+func AddJSONWebKey(jwks []byte, publicKeyContent []byte) ([]byte, error) {
+	keySet, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, err
+	}
+	key, err := buildJSONWebKey(publicKeyContent)
+	if err != nil {
+		return nil, err
+	}
+	keys := []jose.JSONWebKey{key}
+	for _, existing := range keySet.Keys {
+		if existing.KeyID != key.KeyID {
+			keys = append(keys, existing)
+		}
+	}
+	return marshalJSONWebKeySet(keys)
+}
+
+// RetainJSONWebKey returns the JSON web key set with only the key that matches the given public
+// key. It fails if the key set doesn't contain that key, as tokens signed with it couldn't be
+// verified anymore.
+func RetainJSONWebKey(jwks []byte, publicKeyContent []byte) ([]byte, error) {
+	keySet, err := ParseJSONWebKeySet(jwks)
+	if err != nil {
+		return nil, err
+	}
+	key, err := buildJSONWebKey(publicKeyContent)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range keySet.Keys {
+		if existing.KeyID == key.KeyID {
+			return marshalJSONWebKeySet([]jose.JSONWebKey{key})
+		}
+	}
+	return nil, errors.Errorf("JSON Web Key Set doesn't contain key '%s'", key.KeyID)
+}
+
+// ParseJSONWebKeySet parses a JSON web key set, like the one stored in the bucket of an OIDC
+// configuration.
+func ParseJSONWebKeySet(jwks []byte) (*JSONWebKeySet, error) {
+	keySet := &JSONWebKeySet{}
+	err := json.Unmarshal(jwks, keySet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse JSON Web Key Set")
+	}
+	return keySet, nil
+}
+
+// KeyIDs returns the identifiers of the keys of the set.
+func (s *JSONWebKeySet) KeyIDs() []string {
+	keyIDs := make([]string, 0, len(s.Keys))
+	for _, key := range s.Keys {
+		keyIDs = append(keyIDs, key.KeyID)
+	}
+	return keyIDs
+}
+
+// GetPublicKey returns the PEM encoded public key of a PEM encoded RSA private key, like the one
+// stored in the secret of an OIDC configuration.
+func GetPublicKey(privateKeyContent []byte) ([]byte, error) {
+	block, _ := pem.Decode(privateKeyContent)
 	if block == nil {
 		return nil, errors.Errorf("Failed to decode PEM file")
 	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse private key content")
+	}
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate public key from private")
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pubKeyBytes,
+	}), nil
+}
+
+// ValidateJSONWebKey checks that the key can be used to verify the service account tokens signed
+// by the cluster: it must be an RSA signing key for the RS256 algorithm, and its ID must be derived
+// from the public key like the cluster does when it signs the tokens.
+func ValidateJSONWebKey(key jose.JSONWebKey) error {
+	if key.KeyID == "" {
+		return errors.Errorf("Key doesn't have an ID")
+	}
+	if _, ok := key.Key.(*rsa.PublicKey); !ok {
+		return errors.Errorf("Key '%s' is not an RSA public key", key.KeyID)
+	}
+	if key.Algorithm != string(jose.RS256) {
+		return errors.Errorf("Key '%s' uses algorithm '%s' instead of '%s'", key.KeyID, key.Algorithm, jose.RS256)
+	}
+	if key.Use != "sig" {
+		return errors.Errorf("Key '%s' has use '%s' instead of 'sig'", key.KeyID, key.Use)
+	}
+	kid, err := keyIDFromPublicKey(key.Key)
+	if err != nil {
+		return err
+	}
+	if kid != key.KeyID {
+		return errors.Errorf("Key '%s' doesn't match the ID derived from its public key '%s'", key.KeyID, kid)
+	}
+	return nil
+}
+
+func buildJSONWebKey(publicKeyContent []byte) (jose.JSONWebKey, error) {
+	block, _ := pem.Decode(publicKeyContent)
+	if block == nil {
+		return jose.JSONWebKey{}, errors.Errorf("Failed to decode PEM file")
+	}
 
 	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to parse key content")
+		return jose.JSONWebKey{}, errors.Wrapf(err, "Failed to parse key content")
 	}
 
 	var alg jose.SignatureAlgorithm
@@ -177,22 +311,23 @@ func BuildJSONWebKeySet(publicKeyContent []byte) ([]byte, error) {
 	case *rsa.PublicKey:
 		alg = jose.RS256
 	default:
-		return nil, errors.Errorf("Public key is not of type RSA")
+		return jose.JSONWebKey{}, errors.Errorf("Public key is not of type RSA")
 	}
 
 	kid, err := keyIDFromPublicKey(publicKey)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to fetch key ID from public key")
+		return jose.JSONWebKey{}, errors.Wrapf(err, "Failed to fetch key ID from public key")
 	}
 
-	var keys []jose.JSONWebKey
-	keys = append(keys, jose.JSONWebKey{
+	return jose.JSONWebKey{
 		Key:       publicKey,
 		KeyID:     kid,
 		Algorithm: string(alg),
 		Use:       "sig",
-	})
+	}, nil
+}
 
+func marshalJSONWebKeySet(keys []jose.JSONWebKey) ([]byte, error) {
 	keySet, err := json.MarshalIndent(JSONWebKeySet{Keys: keys}, "", "    ")
 	if err != nil {
 		return nil, errors.Wrapf(err, "JSON encoding of web key set failed")
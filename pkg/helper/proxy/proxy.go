@@ -0,0 +1,554 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of the cluster-wide proxy and of the additional trust bundle that
+// are run before they are used by a cluster.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// Names of the checks:
+const (
+	CheckTrustBundle = "trust-bundle"
+	CheckNoProxy     = "no-proxy"
+	CheckConnect     = "connect"
+)
+
+// Severity indicates if a finding prevents the proxy from working or only deserves attention.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// DefaultExpiryWarningDays is the number of days before their expiration that certificates are
+// reported by default.
+const DefaultExpiryWarningDays = 30
+
+// DefaultConnectTimeout is the time given to each connection through the proxy.
+const DefaultConnectTimeout = 10 * time.Second
+
+// RequiredEndpoints are the endpoints that clusters need to reach through the proxy, used by default
+// to test the connections.
+var RequiredEndpoints = []string{
+	"api.openshift.com:443",
+	"sso.redhat.com:443",
+	"registry.redhat.io:443",
+	"quay.io:443",
+	"cdn.quay.io:443",
+}
+
+// Finding is a problem found by the proxy checks, with the way to fix it.
+type Finding struct {
+	Severity    Severity `json:"severity"`
+	Check       string   `json:"check"`
+	Subject     string   `json:"subject,omitempty"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation"`
+}
+
+// HasErrors checks if any of the findings is an error.
+func HasErrors(findings []*Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the findings that are errors.
+func Errors(findings []*Finding) []*Finding {
+	result := []*Finding{}
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+// DescribeFindings returns a line for each finding, with its severity and remediation.
+func DescribeFindings(findings []*Finding) string {
+	lines := make([]string, len(findings))
+	for i, finding := range findings {
+		lines[i] = fmt.Sprintf("- %s: %s\n  To fix it: %s", finding.Severity, finding.Message, finding.Remediation)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Preflight checks the no-proxy entries and the additional trust bundle file of a cluster before it
+// is created or edited. The findings are only reported as warnings: the syntax of the proxy settings
+// is already validated by the commands, and 'rosa verify proxy' is the command that fails when the
+// findings contain errors. An error is only returned when the trust bundle file can't be read.
+func Preflight(r *rosa.Runtime, noProxy []string, trustBundleFile string) error {
+	findings := ValidateNoProxy(noProxy)
+	if trustBundleFile != "" {
+		bundle, err := os.ReadFile(trustBundleFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read additional trust bundle file: %v", err)
+		}
+		findings = append(findings, ValidateTrustBundle(bundle, time.Now(), DefaultExpiryWarningDays)...)
+	}
+	for _, finding := range findings {
+		r.Reporter.Warnf("%s. To fix it: %s", finding.Message, finding.Remediation)
+	}
+	if HasErrors(findings) {
+		r.Reporter.Warnf("The proxy configuration may not work, run 'rosa verify proxy' to check it")
+	}
+	return nil
+}
+
+// ValidateTrustBundle parses all the certificates of an additional trust bundle. Blocks that aren't
+// certificates, certificates that are expired or not valid yet are errors. Certificates that expire
+// within the given number of days, that aren't certificate authorities, that are duplicated or whose
+// issuer isn't in the bundle are warnings.
+func ValidateTrustBundle(bundle []byte, now time.Time, warningDays int) []*Finding {
+	findings := []*Finding{}
+	certs := []*x509.Certificate{}
+	rest := bytes.TrimSpace(bundle)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckTrustBundle,
+				Message:     fmt.Sprintf("The trust bundle contains data that isn't PEM encoded after %d certificates", len(certs)),
+				Remediation: "Remove everything that isn't a PEM encoded certificate from the trust bundle",
+			})
+			break
+		}
+		rest = bytes.TrimSpace(rest)
+		if block.Type != "CERTIFICATE" {
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckTrustBundle,
+				Message:     fmt.Sprintf("The trust bundle contains a '%s' block", block.Type),
+				Remediation: "Only include certificates in the trust bundle, never private keys",
+			})
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckTrustBundle,
+				Message:     fmt.Sprintf("Certificate %d of the trust bundle can't be parsed: %v", len(certs)+1, err),
+				Remediation: "Replace the certificate with a valid X.509 certificate",
+			})
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return append(findings, &Finding{
+			Severity:    SeverityError,
+			Check:       CheckTrustBundle,
+			Message:     "The trust bundle doesn't contain any certificate",
+			Remediation: "Add the PEM encoded certificates of the certificate authorities to trust",
+		})
+	}
+
+	seen := map[[sha256.Size]byte]bool{}
+	for _, cert := range certs {
+		subject := cert.Subject.String()
+		fingerprint := sha256.Sum256(cert.Raw)
+		if seen[fingerprint] {
+			findings = append(findings, &Finding{
+				Severity:    SeverityWarning,
+				Check:       CheckTrustBundle,
+				Subject:     subject,
+				Message:     fmt.Sprintf("Certificate '%s' is included more than once in the trust bundle", subject),
+				Remediation: "Remove the duplicated certificate",
+			})
+			continue
+		}
+		seen[fingerprint] = true
+
+		switch {
+		case now.After(cert.NotAfter):
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckTrustBundle,
+				Subject:     subject,
+				Message:     fmt.Sprintf("Certificate '%s' expired on %s", subject, cert.NotAfter.Format(time.RFC3339)),
+				Remediation: "Replace the certificate with a renewed one",
+			})
+		case now.Before(cert.NotBefore):
+			findings = append(findings, &Finding{
+				Severity: SeverityError,
+				Check:    CheckTrustBundle,
+				Subject:  subject,
+				Message: fmt.Sprintf("Certificate '%s' isn't valid until %s", subject,
+					cert.NotBefore.Format(time.RFC3339)),
+				Remediation: "Use a certificate that is already valid, or check the clock of this machine",
+			})
+		case now.AddDate(0, 0, warningDays).After(cert.NotAfter):
+			findings = append(findings, &Finding{
+				Severity: SeverityWarning,
+				Check:    CheckTrustBundle,
+				Subject:  subject,
+				Message: fmt.Sprintf("Certificate '%s' expires on %s, in less than %d days", subject,
+					cert.NotAfter.Format(time.RFC3339), warningDays),
+				Remediation: "Renew the certificate and update the trust bundle of the cluster before it expires",
+			})
+		}
+
+		if !cert.BasicConstraintsValid || !cert.IsCA {
+			findings = append(findings, &Finding{
+				Severity:    SeverityWarning,
+				Check:       CheckTrustBundle,
+				Subject:     subject,
+				Message:     fmt.Sprintf("Certificate '%s' isn't a certificate authority", subject),
+				Remediation: "Include the certificate authority that issued the certificate instead",
+			})
+		}
+
+		if !issuerInBundle(cert, certs) {
+			findings = append(findings, &Finding{
+				Severity: SeverityWarning,
+				Check:    CheckTrustBundle,
+				Subject:  subject,
+				Message: fmt.Sprintf("The issuer '%s' of certificate '%s' isn't in the trust bundle",
+					cert.Issuer.String(), subject),
+				Remediation: "Add the rest of the chain to the trust bundle, unless the issuer is a public " +
+					"certificate authority",
+			})
+		}
+	}
+	return findings
+}
+
+// issuerInBundle checks if the certificate is self-signed or signed by another certificate of the bundle.
+func issuerInBundle(cert *x509.Certificate, certs []*x509.Certificate) bool {
+	for _, candidate := range certs {
+		if bytes.Equal(cert.RawIssuer, candidate.RawSubject) && cert.CheckSignatureFrom(candidate) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNoProxy checks that the no-proxy entries are IP addresses, CIDRs or domains, without
+// schemes, ports or wildcards. Duplicated entries and CIDRs with host bits set are warnings.
+func ValidateNoProxy(entries []string) []*Finding {
+	findings := []*Finding{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		// An empty entry removes the existing entries when editing a cluster:
+		if entry == "" {
+			continue
+		}
+		if seen[entry] {
+			findings = append(findings, &Finding{
+				Severity:    SeverityWarning,
+				Check:       CheckNoProxy,
+				Subject:     entry,
+				Message:     fmt.Sprintf("No-proxy entry '%s' is duplicated", entry),
+				Remediation: "Remove the duplicated entry",
+			})
+			continue
+		}
+		seen[entry] = true
+		switch {
+		case strings.Contains(entry, "://"):
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckNoProxy,
+				Subject:     entry,
+				Message:     fmt.Sprintf("No-proxy entry '%s' isn't an IP address, a CIDR or a domain", entry),
+				Remediation: "Remove the scheme and the path from the entry",
+			})
+		case strings.Contains(entry, "*"):
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckNoProxy,
+				Subject:     entry,
+				Message:     fmt.Sprintf("No-proxy entry '%s' contains a wildcard", entry),
+				Remediation: "Use a domain starting with a dot, like '.example.com', to match all its subdomains",
+			})
+		case strings.Contains(entry, ":"):
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckNoProxy,
+				Subject:     entry,
+				Message:     fmt.Sprintf("No-proxy entry '%s' contains a port", entry),
+				Remediation: "Remove the port, the entries apply to all the ports",
+			})
+		case strings.Contains(entry, "/"):
+			ip, network, err := net.ParseCIDR(entry)
+			if err != nil || !aws.UserNoProxyRE.MatchString(entry) {
+				findings = append(findings, &Finding{
+					Severity:    SeverityError,
+					Check:       CheckNoProxy,
+					Subject:     entry,
+					Message:     fmt.Sprintf("No-proxy entry '%s' isn't a valid IPv4 CIDR", entry),
+					Remediation: "Use a CIDR like '10.0.0.0/16'",
+				})
+			} else if !ip.Equal(network.IP) {
+				findings = append(findings, &Finding{
+					Severity:    SeverityWarning,
+					Check:       CheckNoProxy,
+					Subject:     entry,
+					Message:     fmt.Sprintf("No-proxy CIDR '%s' has host bits set, it means '%s'", entry, network),
+					Remediation: fmt.Sprintf("Use '%s' instead", network),
+				})
+			}
+		case !aws.UserNoProxyRE.MatchString(entry):
+			findings = append(findings, &Finding{
+				Severity:    SeverityError,
+				Check:       CheckNoProxy,
+				Subject:     entry,
+				Message:     fmt.Sprintf("No-proxy entry '%s' isn't a valid IP address or domain", entry),
+				Remediation: "Use lowercase domains like 'example.com' or '.example.com', or IP addresses",
+			})
+		}
+	}
+	return findings
+}
+
+// ConnectOptions describe the proxy used to test the connections to the endpoints.
+type ConnectOptions struct {
+	HTTPProxy   string
+	HTTPSProxy  string
+	TrustBundle []byte
+	Endpoints   []string
+	Timeout     time.Duration
+}
+
+// ConnectResult is the result of the connection to an endpoint through the proxy.
+type ConnectResult struct {
+	Endpoint string `json:"endpoint"`
+	Proxy    string `json:"proxy"`
+	Success  bool   `json:"success"`
+
+	// Intercepted indicates that the certificate of the endpoint was issued by a certificate authority
+	// of the trust bundle, which means that the proxy intercepts the TLS connections.
+	Intercepted bool   `json:"intercepted"`
+	Issuer      string `json:"issuer,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Connect opens a connection to each endpoint with a CONNECT request sent to the proxy, from the local
+// machine, and checks the certificate presented by the endpoint with the system certificate authorities
+// and the trust bundle. The HTTPS proxy is used when it is set, as the endpoints use TLS.
+func Connect(ctx context.Context, options ConnectOptions) ([]*ConnectResult, []*Finding, error) {
+	proxyURL := options.HTTPSProxy
+	if proxyURL == "" {
+		proxyURL = options.HTTPProxy
+	}
+	if proxyURL == "" {
+		return nil, nil, fmt.Errorf("An HTTP or HTTPS proxy is required to test the connections")
+	}
+	proxy, err := url.Parse(proxyURL)
+	if err != nil || proxy.Host == "" {
+		return nil, nil, fmt.Errorf("Invalid proxy URL '%s'", proxyURL)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	bundle := x509.NewCertPool()
+	if len(options.TrustBundle) > 0 {
+		roots.AppendCertsFromPEM(options.TrustBundle)
+		bundle.AppendCertsFromPEM(options.TrustBundle)
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultConnectTimeout
+	}
+	endpoints := options.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = RequiredEndpoints
+	}
+
+	results := []*ConnectResult{}
+	findings := []*Finding{}
+	for _, endpoint := range endpoints {
+		result := connect(ctx, proxy, endpoint, roots, bundle, timeout)
+		results = append(results, result)
+		if result.Success {
+			continue
+		}
+		finding := &Finding{
+			Severity:    SeverityError,
+			Check:       CheckConnect,
+			Subject:     endpoint,
+			Message:     fmt.Sprintf("Unable to connect to '%s' through the proxy: %s", endpoint, result.Error),
+			Remediation: "Allow the endpoint in the proxy",
+		}
+		if result.Issuer != "" {
+			finding.Message = fmt.Sprintf("The proxy intercepts the connections to '%s' with a certificate "+
+				"issued by '%s', which isn't trusted", endpoint, result.Issuer)
+			finding.Remediation = "Add the certificate authority of the proxy to the additional trust bundle"
+		}
+		findings = append(findings, finding)
+	}
+	return results, findings, nil
+}
+
+func connect(ctx context.Context, proxy *url.URL, endpoint string, roots *x509.CertPool,
+	bundle *x509.CertPool, timeout time.Duration) *ConnectResult {
+	result := &ConnectResult{
+		Endpoint: endpoint,
+		Proxy:    proxy.Redacted(),
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := dialProxy(ctx, proxy, endpoint, roots)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	// The certificate is verified after the handshake, so that the issuer of an untrusted certificate
+	// can be reported:
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true, // #nosec G402 -- verified below
+	})
+	err = tlsConn.HandshakeContext(ctx)
+	if err != nil {
+		result.Error = fmt.Sprintf("TLS handshake failed: %v", err)
+		return result
+	}
+	peers := tlsConn.ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		result.Error = "the endpoint didn't present a certificate"
+		return result
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range peers[1:] {
+		intermediates.AddCert(cert)
+	}
+	verifyOptions := x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	}
+	_, err = peers[0].Verify(verifyOptions)
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		if errors.As(err, &unknownAuthority) {
+			result.Issuer = peers[len(peers)-1].Issuer.String()
+		}
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	verifyOptions.Roots = bundle
+	if chains, err := peers[0].Verify(verifyOptions); err == nil && len(chains) > 0 {
+		result.Intercepted = true
+		result.Issuer = peers[0].Issuer.String()
+	}
+	return result
+}
+
+// dialProxy opens a connection to the proxy and sends the CONNECT request for the endpoint.
+func dialProxy(ctx context.Context, proxy *url.URL, endpoint string, roots *x509.CertPool) (net.Conn, error) {
+	address := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(proxy.Hostname(), port)
+	}
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the proxy: %v", err)
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: proxy.Hostname(),
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		})
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS handshake with the proxy failed: %v", err)
+		}
+		conn = tlsConn
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: endpoint},
+		Host:   endpoint,
+		Header: http.Header{},
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	err = request.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT request to the proxy: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read the response of the proxy: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("the proxy answered '%s'", response.Status)
+	}
+	if reader.Buffered() > 0 {
+		conn.Close()
+		return nil, fmt.Errorf("the proxy sent unexpected data after the CONNECT response")
+	}
+	return conn, nil
+}
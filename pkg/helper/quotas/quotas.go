@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+// Insufficient returns the checks whose quota doesn't cover the required amount.
+func Insufficient(checks []*aws.QuotaCheck) []*aws.QuotaCheck {
+	result := []*aws.QuotaCheck{}
+	for _, check := range checks {
+		if !check.Sufficient() {
+			result = append(result, check)
+		}
+	}
+	return result
+}
+
+// PrintChecks prints the quota checks as a table, or in the format given with the output flag.
+func PrintChecks(checks []*aws.QuotaCheck) error {
+	table := output.NewTable("SERVICE", "QUOTA CODE", "QUOTA NAME", "REQUIRED", "USAGE", "LIMIT", "STATUS")
+	for _, check := range checks {
+		usage := "unknown"
+		if check.Usage != nil {
+			usage = formatAmount(*check.Usage)
+		}
+		status := "ok"
+		if !check.Sufficient() {
+			status = "insufficient"
+		}
+		table.AddRow(check.ServiceCode, check.QuotaCode, check.QuotaName, formatAmount(check.Required), usage,
+			formatAmount(check.Limit), status)
+	}
+	return output.PrintTable(checks, table)
+}
+
+// Describe returns a line for each check, with the required amount and the amount available.
+func Describe(checks []*aws.QuotaCheck) string {
+	lines := make([]string, len(checks))
+	for i, check := range checks {
+		lines[i] = fmt.Sprintf("- Service %s quota code %s %s: %s required, %s available",
+			check.ServiceCode, check.QuotaCode, check.QuotaName, formatAmount(check.Required),
+			formatAmount(check.Available()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Preflight checks the AWS quotas needed by a new cluster or machine pool and warns about the
+// insufficient ones. It doesn't fail: the usage of some resources can't be measured and the
+// credentials may not allow listing the quotas, so creating the resources is left to decide.
+func Preflight(r *rosa.Runtime, options workflows.QuotaOptions) {
+	if r.Creator == nil {
+		creator, err := r.AWSClient.GetCreator()
+		if err != nil {
+			r.Reporter.Debugf("Failed to get AWS creator to check quotas: %v", err)
+			return
+		}
+		r.Creator = creator
+	}
+	checks, err := workflows.VerifyQuota(context.Background(), &workflows.Clients{
+		OCM:     r.OCMClient,
+		AWS:     r.AWSClient,
+		Creator: r.Creator,
+	}, options)
+	if err != nil {
+		r.Reporter.Warnf("Unable to check the AWS quotas: %v", err)
+		return
+	}
+	insufficient := Insufficient(checks)
+	if len(insufficient) > 0 {
+		r.Reporter.Warnf("The AWS quotas may be insufficient for the following service quota codes:\n%s\n"+
+			"Run 'rosa verify quota --for-spec' for details", Describe(insufficient))
+	}
+}
+
+// formatAmount formats an amount without decimals when it is a whole number, like vCPUs, and with
+// two decimals otherwise, like TiB of storage.
+func formatAmount(amount float64) string {
+	if amount == float64(int64(amount)) {
+		return strconv.FormatInt(int64(amount), 10)
+	}
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,212 @@
+package roles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/rosa"
+)
+
+// AccountRolePolicyDiffs returns the changes that upgrading the policies of the account roles
+// created with the prefix makes to their permissions.
+func AccountRolePolicyDiffs(awsClient aws.Client, prefix string, accountID string, policyPath string,
+	policies map[string]*cmv1.AWSSTSPolicy) ([]*aws.PolicyDiff, error) {
+	diffs := []*aws.PolicyDiff{}
+	for _, file := range sortedAccountRoleFiles() {
+		roleName := aws.GetRoleName(prefix, aws.AccountRoles[file].Name)
+		policyARN := aws.GetPolicyARN(accountID, roleName, policyPath)
+		diff, err := diffAccountRolePolicy(awsClient, roleName, policyARN, file, policies)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// AccountRolePolicyDiffsFromCluster returns the changes that upgrading the policies of the account
+// roles used by the cluster makes to their permissions.
+func AccountRolePolicyDiffsFromCluster(awsClient aws.Client, cluster *cmv1.Cluster, accountID string,
+	policies map[string]*cmv1.AWSSTSPolicy) ([]*aws.PolicyDiff, error) {
+	diffs := []*aws.PolicyDiff{}
+	for _, file := range sortedAccountRoleFiles() {
+		role := aws.AccountRoles[file]
+		roleName, err := aws.GetAccountRoleName(cluster, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		if roleName == "" {
+			continue
+		}
+		rolePath, err := aws.GetPathFromAccountRole(cluster, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		policyARN, err := attachedPolicyARN(awsClient, roleName, aws.GetPolicyARN(accountID, roleName, rolePath))
+		if err != nil {
+			return nil, err
+		}
+		diff, err := diffAccountRolePolicy(awsClient, roleName, policyARN, file, policies)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+func diffAccountRolePolicy(awsClient aws.Client, roleName string, policyARN string, file string,
+	policies map[string]*cmv1.AWSSTSPolicy) (*aws.PolicyDiff, error) {
+	filename := fmt.Sprintf("sts_%s_permission_policy", file)
+	diff, err := awsClient.DiffPolicy(policyARN, aws.GetPolicyDetails(policies, filename))
+	if err != nil {
+		return nil, err
+	}
+	diff.RoleName = roleName
+	return diff, nil
+}
+
+// OperatorRolePolicyDiffs returns the changes that upgrading the policies of the operator roles
+// of the cluster makes to their permissions.
+func OperatorRolePolicyDiffs(awsClient aws.Client, cluster *cmv1.Cluster, accountID string,
+	policies map[string]*cmv1.AWSSTSPolicy, credRequests map[string]*cmv1.STSOperator,
+	operatorRolePolicyPrefix string) ([]*aws.PolicyDiff, error) {
+	operatorRoles := cluster.AWS().STS().OperatorIAMRoles()
+	if len(operatorRoles) == 0 {
+		return nil, fmt.Errorf("Cluster '%s' doesn't have any operator roles", cluster.ID())
+	}
+	isSharedVpc := cluster.AWS().PrivateHostedZoneRoleARN() != ""
+	path, err := aws.GetPathFromARN(operatorRoles[0].RoleARN())
+	if err != nil {
+		return nil, err
+	}
+	credRequestNames := make([]string, 0, len(credRequests))
+	for credRequest := range credRequests {
+		credRequestNames = append(credRequestNames, credRequest)
+	}
+	sort.Strings(credRequestNames)
+
+	diffs := []*aws.PolicyDiff{}
+	for _, credRequest := range credRequestNames {
+		operator := credRequests[credRequest]
+		policyARN := aws.GetOperatorPolicyARN(accountID, operatorRolePolicyPrefix, operator.Namespace(),
+			operator.Name(), path)
+		roleName := ""
+		roleARN := aws.FindOperatorRoleBySTSOperator(operatorRoles, operator)
+		if roleARN != "" {
+			roleName, err = aws.GetResourceIdFromARN(roleARN)
+			if err != nil {
+				return nil, err
+			}
+			policyARN, err = attachedPolicyARN(awsClient, roleName, policyARN)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		filename := aws.GetOperatorPolicyKey(credRequest, cluster.Hypershift().Enabled(), isSharedVpc)
+		policyDetails := aws.GetPolicyDetails(policies, filename)
+		if isSharedVpc {
+			policyDetails = aws.InterpolatePolicyDocument(policyDetails, map[string]string{
+				"shared_vpc_role_arn": cluster.AWS().PrivateHostedZoneRoleARN(),
+			})
+		}
+		diff, err := awsClient.DiffPolicy(policyARN, policyDetails)
+		if err != nil {
+			return nil, err
+		}
+		diff.RoleName = roleName
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// attachedPolicyARN returns the policy attached to the role, which is the policy that the upgrade
+// replaces. The generated ARN is used when the role doesn't have exactly one attached policy, as
+// the upgrade then asks which policy to use.
+func attachedPolicyARN(awsClient aws.Client, roleName string, generatedPolicyARN string) (string, error) {
+	policiesDetails, err := awsClient.GetAttachedPolicy(&roleName)
+	if err != nil {
+		return "", err
+	}
+	attachedPoliciesDetails := aws.FindAllAttachedPolicyDetails(policiesDetails)
+	if len(attachedPoliciesDetails) == 1 {
+		return attachedPoliciesDetails[0].PolicyArn, nil
+	}
+	return generatedPolicyARN, nil
+}
+
+func sortedAccountRoleFiles() []string {
+	files := make([]string, 0, len(aws.AccountRoles))
+	for file := range aws.AccountRoles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// PrintPolicyDiffs prints the permissions that the upgrade adds and removes, and warns about the
+// policies that grant new permissions. In plan mode the changes are added to the plan instead.
+func PrintPolicyDiffs(r *rosa.Runtime, diffs []*aws.PolicyDiff) error {
+	if r.Plan != nil {
+		r.Plan.PolicyDiffs = append(r.Plan.PolicyDiffs, diffs...)
+		return nil
+	}
+	changed := []*aws.PolicyDiff{}
+	for _, diff := range diffs {
+		if diff.HasChanges() {
+			changed = append(changed, diff)
+		}
+	}
+	if !output.HasFlag() {
+		if len(changed) == 0 {
+			r.Reporter.Infof("The upgrade doesn't change the permissions of the policies")
+			return nil
+		}
+		r.Reporter.Infof("The upgrade changes the following permissions:")
+	}
+
+	table := output.NewTable("ROLE", "POLICY", "CHANGE", "EFFECT", "ACTIONS", "RESOURCE", "CONDITIONS")
+	for _, diff := range changed {
+		policyName, err := aws.GetResourceIdFromARN(diff.PolicyARN)
+		if err != nil {
+			return err
+		}
+		addPolicyPermissionsRows(table, diff.RoleName, policyName, "added", diff.Added)
+		addPolicyPermissionsRows(table, diff.RoleName, policyName, "removed", diff.Removed)
+	}
+	err := output.PrintTable(changed, table)
+	if err != nil {
+		return err
+	}
+
+	if !output.HasFlag() {
+		for _, diff := range changed {
+			if diff.ExpandsPermissions() {
+				r.Reporter.Warnf("The upgrade of policy '%s' expands its permissions", diff.PolicyARN)
+			}
+		}
+	}
+	return nil
+}
+
+func addPolicyPermissionsRows(table *output.Table, roleName string, policyName string, change string,
+	permissions []aws.PolicyPermission) {
+	for _, permission := range permissions {
+		actions := strings.Join(permission.Actions, ", ")
+		if len(permission.NotActions) > 0 {
+			actions = "all except " + strings.Join(permission.NotActions, ", ")
+		}
+		resource := permission.Resource
+		if len(permission.NotResources) > 0 {
+			resource = "all except " + strings.Join(permission.NotResources, ", ")
+		}
+		table.AddRow(roleName, policyName, change, permission.Effect, actions, resource,
+			strings.Join(permission.Conditions, "; "))
+	}
+}
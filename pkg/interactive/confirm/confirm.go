@@ -21,6 +21,8 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/reporter"
 )
 
 var yes bool
@@ -40,6 +42,11 @@ func Yes() bool {
 	return yes
 }
 
+// SetYes changes the answer to the confirmation prompts, same as the --yes flag.
+func SetYes(value bool) {
+	yes = value
+}
+
 func Confirm(q string, v ...interface{}) bool {
 	msg := fmt.Sprintf("Are you sure you want to %s?", fmt.Sprintf(q, v...))
 	return Prompt(false, msg)
@@ -49,6 +56,11 @@ func Prompt(dflt bool, q string, v ...interface{}) bool {
 	if yes {
 		return yes
 	}
+	// Without a terminal to answer the question nothing is confirmed, same as when the prompt
+	// fails to read the answer:
+	if reporter.UseJSON() {
+		return false
+	}
 	prompt := &survey.Confirm{
 		Message: fmt.Sprintf(q, v...),
 		Default: dflt,
@@ -20,6 +20,8 @@ package interactive
 
 import (
 	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/reporter"
 )
 
 // AddFlag adds the interactive flag to the given set of command line flags.
@@ -33,9 +35,10 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
-// Enabled returns a boolean flag that indicates if the interactive mode is enabled.
+// Enabled returns a boolean flag that indicates if the interactive mode is enabled. It is always
+// disabled when the JSON log format is used.
 func Enabled() bool {
-	return enabled
+	return enabled && !reporter.UseJSON()
 }
 
 // Enable enables the interactive mode
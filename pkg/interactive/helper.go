@@ -44,8 +44,11 @@ func GetOidcConfigID(r *rosa.Runtime, cmd *cobra.Command) string {
 
 func GetInstallerRoleArn(r *rosa.Runtime, cmd *cobra.Command,
 	defaultInstallerRoleArn string, minMinorVersion string) string {
-	spin := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	spin.Start()
+	var spin *spinner.Spinner
+	if r.Reporter.IsTerminal() {
+		spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		spin.Start()
+	}
 	awsClient := r.AWSClient
 	role := aws.AccountRoles[aws.InstallerAccountRole]
 	roleARN := defaultInstallerRoleArn
@@ -55,7 +58,9 @@ func GetInstallerRoleArn(r *rosa.Runtime, cmd *cobra.Command,
 		r.Reporter.Errorf("Failed to find %s role: %s", role.Name, err)
 		os.Exit(1)
 	}
-	spin.Stop()
+	if spin != nil {
+		spin.Stop()
+	}
 
 	if len(roleARNs) > 1 {
 		defaultRoleARN := roleARNs[0]
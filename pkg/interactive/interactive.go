@@ -29,6 +29,7 @@ import (
 	"github.com/openshift/rosa/pkg/color"
 	"github.com/openshift/rosa/pkg/helper"
 	"github.com/openshift/rosa/pkg/interactive/consts"
+	"github.com/openshift/rosa/pkg/reporter"
 )
 
 type Input struct {
@@ -60,7 +61,7 @@ func GetString(input Input) (a string, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &a, survey.WithValidator(compose(input.Validators)))
 	a = transformer(a).(string)
 	return
 }
@@ -89,7 +90,7 @@ func GetInt(input Input) (a int, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &str, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &str, survey.WithValidator(compose(input.Validators)))
 	if err != nil {
 		return
 	}
@@ -129,7 +130,7 @@ func GetFloat(input Input) (a float64, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &str, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &str, survey.WithValidator(compose(input.Validators)))
 	if err != nil {
 		return
 	}
@@ -165,7 +166,7 @@ func GetMultipleOptions(input Input) ([]string, error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &res, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &res, survey.WithValidator(compose(input.Validators)))
 	return res, err
 }
 
@@ -196,7 +197,7 @@ func GetOption(input Input) (a string, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &a, survey.WithValidator(compose(input.Validators)))
 	if a == consts.SkipSelectionOption {
 		return "", nil
 	}
@@ -232,7 +233,7 @@ func GetBool(input Input) (a bool, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &a, survey.WithValidator(compose(input.Validators)))
 	return
 }
 
@@ -260,7 +261,7 @@ func GetIPNet(input Input) (a net.IPNet, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &str, survey.WithValidator(compose(input.Validators)), survey.WithValidator(IsCIDR))
+	err = ask(prompt, &str, survey.WithValidator(compose(input.Validators)), survey.WithValidator(IsCIDR))
 	if err != nil {
 		return
 	}
@@ -291,7 +292,7 @@ func GetPassword(input Input) (a string, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(compose(input.Validators)))
+	err = ask(prompt, &a, survey.WithValidator(compose(input.Validators)))
 	return
 }
 
@@ -314,7 +315,7 @@ func GetCert(input Input) (a string, err error) {
 	if input.Required {
 		input.Validators = append([]Validator{required}, input.Validators...)
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(compose(input.Validators)), survey.WithValidator(IsCert))
+	err = ask(prompt, &a, survey.WithValidator(compose(input.Validators)), survey.WithValidator(IsCert))
 	return
 }
 
@@ -336,3 +337,12 @@ func PrintHelp(help Help) error {
 	fmt.Fprint(terminal.NewAnsiStdout(os.Stdout), out)
 	return nil
 }
+
+// ask shows the prompt unless the JSON log format is used, as the prompts would be mixed with
+// the JSON events and nobody is expected to answer them.
+func ask(prompt survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	if reporter.UseJSON() {
+		return fmt.Errorf("prompts are disabled when the log format is '%s'", reporter.JSONLogFormat)
+	}
+	return survey.AskOne(prompt, response, opts...)
+}
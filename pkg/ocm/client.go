@@ -17,7 +17,10 @@ limitations under the License.
 package ocm
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -26,6 +29,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/rosa/pkg/config"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/fedramp"
 	"github.com/openshift/rosa/pkg/info"
 	"github.com/openshift/rosa/pkg/logging"
@@ -61,7 +65,7 @@ func CreateNewClientOrExit(logger *logrus.Logger, reporter *reporter.Object) *Cl
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 
 	return client
@@ -90,7 +94,7 @@ func (b *ClientBuilder) Build() (result *Client, err error) {
 			return nil, err
 		}
 		if b.cfg == nil {
-			err = fmt.Errorf("Not logged in, run the 'rosa login' command")
+			err = exitcode.NotLoggedIn.Errorf("Not logged in, run the 'rosa login' command")
 			return nil, err
 		}
 	}
@@ -151,16 +155,48 @@ func (b *ClientBuilder) Build() (result *Client, err error) {
 	_, _, err = conn.Tokens(10 * time.Minute)
 	if err != nil {
 		if strings.Contains(err.Error(), "invalid_grant") {
-			return nil, fmt.Errorf("your authorization token needs to be updated. " +
+			return nil, exitcode.NotLoggedIn.Errorf("your authorization token needs to be updated. " +
 				"Please login again using rosa login")
 		}
-		return nil, fmt.Errorf("error creating connection. Not able to get authentication token: %s", err)
+		err = fmt.Errorf("error creating connection. Not able to get authentication token: %w", err)
+		if isAuthenticationError(err) {
+			err = exitcode.NotLoggedIn.Wrap(err)
+		}
+		return nil, err
 	}
 	return &Client{
 		ocm: conn,
 	}, nil
 }
 
+// authenticationErrors are the errors returned by the token server when the credentials aren't
+// valid, and the error of the SDK when the stored tokens have expired and can't be renewed.
+var authenticationErrors = []string{
+	"invalid_client",
+	"invalid_grant",
+	"invalid_request",
+	"invalid_scope",
+	"invalid_token",
+	"unauthorized_client",
+	"tokens are unavailable or expired",
+}
+
+// isAuthenticationError checks if the error returned when requesting the tokens means that the
+// user needs to log in again. Network errors, timeouts and failures of the token server don't,
+// so they don't select the NotLoggedIn exit code.
+func isAuthenticationError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, authenticationError := range authenticationErrors {
+		if strings.Contains(err.Error(), authenticationError) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) Close() error {
 	return c.ocm.Close()
 }
@@ -0,0 +1,53 @@
+/**
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authentication errors", func() {
+	DescribeTable("Only selects the errors that require logging in again",
+		func(err error, expected bool) {
+			Expect(isAuthenticationError(err)).To(Equal(expected))
+		},
+		Entry("Expired refresh token",
+			errors.New("invalid_grant: Token is not active"), true),
+		Entry("Invalid client credentials",
+			errors.New("invalid_client: Invalid client credentials"), true),
+		Entry("Expired tokens without credentials",
+			errors.New("access and refresh tokens are unavailable or expired, and there are no "+
+				"password or client secret to request new ones"), true),
+		Entry("Token server not reachable",
+			fmt.Errorf("can't send request: %w", &url.Error{
+				Op:  "Post",
+				URL: "https://sso.redhat.com/token",
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			}), false),
+		Entry("Timeout",
+			fmt.Errorf("can't send request: %w", context.DeadlineExceeded), false),
+		Entry("Token server failure",
+			errors.New("token response status code is '503'"), false),
+	)
+})
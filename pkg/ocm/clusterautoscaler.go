@@ -16,6 +16,31 @@ limitations under the License.
 
 package ocm
 
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// GetClusterAutoscaler returns the autoscaler configuration of the cluster, or nil if the
+// cluster doesn't have one.
+func (c *Client) GetClusterAutoscaler(clusterID string) (*cmv1.ClusterAutoscaler, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Autoscaler().
+		Get().
+		Send()
+	if err != nil {
+		if response.Status() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
 func (c *Client) DeleteClusterAutoscaler(clusterID string) error {
 	response, err := c.ocm.ClustersMgmt().V1().
 		Clusters().Cluster(clusterID).
@@ -27,3 +52,108 @@ func (c *Client) DeleteClusterAutoscaler(clusterID string) error {
 	}
 	return nil
 }
+
+func (c *Client) CreateClusterAutoscaler(clusterID string,
+	config *AutoscalerConfig) (*cmv1.ClusterAutoscaler, error) {
+	autoscaler, err := BuildClusterAutoscaler(config).Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Autoscaler().
+		Post().
+		Request(autoscaler).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func (c *Client) UpdateClusterAutoscaler(clusterID string,
+	config *AutoscalerConfig) (*cmv1.ClusterAutoscaler, error) {
+	autoscaler, err := BuildClusterAutoscaler(config).Build()
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Autoscaler().
+		Update().
+		Body(autoscaler).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// BuildClusterAutoscaler returns the builder of the autoscaler with the given configuration.
+func BuildClusterAutoscaler(config *AutoscalerConfig) *cmv1.ClusterAutoscalerBuilder {
+	return cmv1.NewClusterAutoscaler().
+		BalanceSimilarNodeGroups(config.BalanceSimilarNodeGroups).
+		SkipNodesWithLocalStorage(config.SkipNodesWithLocalStorage).
+		LogVerbosity(config.LogVerbosity).
+		MaxPodGracePeriod(config.MaxPodGracePeriod).
+		PodPriorityThreshold(config.PodPriorityThreshold).
+		IgnoreDaemonsetsUtilization(config.IgnoreDaemonsetsUtilization).
+		MaxNodeProvisionTime(config.MaxNodeProvisionTime).
+		BalancingIgnoredLabels(config.BalancingIgnoredLabels...).
+		ResourceLimits(cmv1.NewAutoscalerResourceLimits().
+			MaxNodesTotal(config.ResourceLimits.MaxNodesTotal).
+			Cores(cmv1.NewResourceRange().
+				Min(config.ResourceLimits.Cores.Min).
+				Max(config.ResourceLimits.Cores.Max)).
+			Memory(cmv1.NewResourceRange().
+				Min(config.ResourceLimits.Memory.Min).
+				Max(config.ResourceLimits.Memory.Max))).
+		ScaleDown(cmv1.NewAutoscalerScaleDownConfig().
+			Enabled(config.ScaleDown.Enabled).
+			UnneededTime(config.ScaleDown.UnneededTime).
+			UtilizationThreshold(fmt.Sprintf("%f", config.ScaleDown.UtilizationThreshold)).
+			DelayAfterAdd(config.ScaleDown.DelayAfterAdd).
+			DelayAfterDelete(config.ScaleDown.DelayAfterDelete).
+			DelayAfterFailure(config.ScaleDown.DelayAfterFailure))
+}
+
+// GetAutoscalerConfig returns the configuration of an existing autoscaler. It is the reverse of
+// BuildClusterAutoscaler.
+func GetAutoscalerConfig(autoscaler *cmv1.ClusterAutoscaler) (*AutoscalerConfig, error) {
+	config := &AutoscalerConfig{
+		BalanceSimilarNodeGroups:    autoscaler.BalanceSimilarNodeGroups(),
+		SkipNodesWithLocalStorage:   autoscaler.SkipNodesWithLocalStorage(),
+		LogVerbosity:                autoscaler.LogVerbosity(),
+		MaxPodGracePeriod:           autoscaler.MaxPodGracePeriod(),
+		PodPriorityThreshold:        autoscaler.PodPriorityThreshold(),
+		IgnoreDaemonsetsUtilization: autoscaler.IgnoreDaemonsetsUtilization(),
+		MaxNodeProvisionTime:        autoscaler.MaxNodeProvisionTime(),
+		BalancingIgnoredLabels:      autoscaler.BalancingIgnoredLabels(),
+		ResourceLimits: ResourceLimits{
+			MaxNodesTotal: autoscaler.ResourceLimits().MaxNodesTotal(),
+			Cores: ResourceRange{
+				Min: autoscaler.ResourceLimits().Cores().Min(),
+				Max: autoscaler.ResourceLimits().Cores().Max(),
+			},
+			Memory: ResourceRange{
+				Min: autoscaler.ResourceLimits().Memory().Min(),
+				Max: autoscaler.ResourceLimits().Memory().Max(),
+			},
+		},
+		ScaleDown: ScaleDownConfig{
+			Enabled:           autoscaler.ScaleDown().Enabled(),
+			UnneededTime:      autoscaler.ScaleDown().UnneededTime(),
+			DelayAfterAdd:     autoscaler.ScaleDown().DelayAfterAdd(),
+			DelayAfterDelete:  autoscaler.ScaleDown().DelayAfterDelete(),
+			DelayAfterFailure: autoscaler.ScaleDown().DelayAfterFailure(),
+		},
+	}
+	if threshold := autoscaler.ScaleDown().UtilizationThreshold(); threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scale down utilization threshold '%s': %v", threshold, err)
+		}
+		config.ScaleDown.UtilizationThreshold = value
+	}
+	return config, nil
+}
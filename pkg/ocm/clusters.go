@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
@@ -250,7 +251,12 @@ func (c *Client) CreateCluster(config Spec) (*cmv1.Cluster, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create AWS client: %v", err)
 	}
+	return c.CreateClusterWithAWS(config, awsClient)
+}
 
+// CreateClusterWithAWS is like CreateCluster, but uses the given AWS client to complete the
+// specification instead of creating a new one.
+func (c *Client) CreateClusterWithAWS(config Spec, awsClient aws.Client) (*cmv1.Cluster, error) {
 	spec, err := c.createClusterSpec(config, awsClient)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
@@ -307,6 +313,35 @@ func (c *Client) GetClusters(creator *aws.Creator, count int) (clusters []*cmv1.
 	return clusters, nil
 }
 
+// ListClusters returns all the clusters that match the search, going through all the pages of
+// results. The search is combined with the filter of the creator, and an empty orderBy keeps the
+// default order.
+func (c *Client) ListClusters(creator *aws.Creator, search string, orderBy string) ([]*cmv1.Cluster, error) {
+	query := getClusterFilter(creator)
+	if search != "" {
+		query = fmt.Sprintf("%s AND (%s)", query, search)
+	}
+	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query)
+	if orderBy != "" {
+		request = request.Order(orderBy)
+	}
+	var clusters []*cmv1.Cluster
+	page := 1
+	size := 100
+	for {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		clusters = append(clusters, response.Items().Slice()...)
+		if response.Size() < size {
+			break
+		}
+		page++
+	}
+	return clusters, nil
+}
+
 func (c *Client) GetAllClusters(creator *aws.Creator) (clusters []*cmv1.Cluster, err error) {
 	query := getClusterFilter(creator)
 	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query)
@@ -521,6 +556,33 @@ func (c *Client) HasAClusterUsingOidcEndpointUrl(issuerUrl string) (bool, error)
 	return false, nil
 }
 
+// HasAClusterUsingAccountRoles checks if any cluster uses one of the given account roles as its
+// installer, support, control plane or worker role. Only the clusters visible to the current OCM
+// account are checked, so roles used by clusters of other OCM organizations that share the AWS
+// account aren't detected: they are listed as orphans and deleted with them.
+func (c *Client) HasAClusterUsingAccountRoles(roleARNs []string) (bool, error) {
+	if len(roleARNs) == 0 {
+		return false, nil
+	}
+	values := make([]string, len(roleARNs))
+	for i, roleARN := range roleARNs {
+		values[i] = fmt.Sprintf("'%s'", roleARN)
+	}
+	list := strings.Join(values, ", ")
+	query := fmt.Sprintf(
+		"aws.sts.role_arn in (%s) OR "+
+			"aws.sts.support_role_arn in (%s) OR "+
+			"aws.sts.instance_iam_roles.master_role_arn in (%s) OR "+
+			"aws.sts.instance_iam_roles.worker_role_arn in (%s)",
+		list, list, list, list,
+	)
+	response, err := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query).Size(1).Send()
+	if err != nil {
+		return false, err
+	}
+	return response.Total() > 0, nil
+}
+
 func (c *Client) IsSTSClusterExists(creator *aws.Creator, count int, roleARN string) (exists bool, err error) {
 	if count < 1 {
 		err = errors.Errorf("Cannot fetch fewer than 1 cluster")
@@ -1046,31 +1108,7 @@ func (c *Client) createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Clu
 	}
 
 	if config.AutoscalerConfig != nil {
-		clusterBuilder.Autoscaler(cmv1.NewClusterAutoscaler().
-			BalanceSimilarNodeGroups(config.AutoscalerConfig.BalanceSimilarNodeGroups).
-			SkipNodesWithLocalStorage(config.AutoscalerConfig.SkipNodesWithLocalStorage).
-			LogVerbosity(config.AutoscalerConfig.LogVerbosity).
-			MaxPodGracePeriod(config.AutoscalerConfig.MaxPodGracePeriod).
-			PodPriorityThreshold(config.AutoscalerConfig.PodPriorityThreshold).
-			IgnoreDaemonsetsUtilization(config.AutoscalerConfig.IgnoreDaemonsetsUtilization).
-			MaxNodeProvisionTime(config.AutoscalerConfig.MaxNodeProvisionTime).
-			BalancingIgnoredLabels(config.AutoscalerConfig.BalancingIgnoredLabels...).
-			ResourceLimits(cmv1.NewAutoscalerResourceLimits().
-				MaxNodesTotal(config.AutoscalerConfig.ResourceLimits.MaxNodesTotal).
-				Cores(cmv1.NewResourceRange().
-					Min(config.AutoscalerConfig.ResourceLimits.Cores.Min).
-					Max(config.AutoscalerConfig.ResourceLimits.Cores.Max)).
-				Memory(cmv1.NewResourceRange().
-					Min(config.AutoscalerConfig.ResourceLimits.Memory.Min).
-					Max(config.AutoscalerConfig.ResourceLimits.Memory.Max))).
-			ScaleDown(cmv1.NewAutoscalerScaleDownConfig().
-				Enabled(config.AutoscalerConfig.ScaleDown.Enabled).
-				UnneededTime(config.AutoscalerConfig.ScaleDown.UnneededTime).
-				UtilizationThreshold(fmt.Sprintf("%f", config.AutoscalerConfig.ScaleDown.UtilizationThreshold)).
-				DelayAfterAdd(config.AutoscalerConfig.ScaleDown.DelayAfterAdd).
-				DelayAfterDelete(config.AutoscalerConfig.ScaleDown.DelayAfterDelete).
-				DelayAfterFailure(config.AutoscalerConfig.ScaleDown.DelayAfterFailure)),
-		)
+		clusterBuilder.Autoscaler(BuildClusterAutoscaler(config.AutoscalerConfig))
 	}
 
 	clusterSpec, err := clusterBuilder.Build()
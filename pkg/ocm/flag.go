@@ -17,11 +17,10 @@ limitations under the License.
 package ocm
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
 	"github.com/openshift/rosa/pkg/logging"
 )
 
@@ -58,7 +57,7 @@ func GetClusterKey() (string, error) {
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	if !IsValidClusterKey(clusterKey) {
-		return "", fmt.Errorf(
+		return "", exitcode.InvalidInput.Errorf(
 			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
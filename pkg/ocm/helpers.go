@@ -839,7 +839,7 @@ func ValidateOperatorRolesMatchOidcProvider(reporter *reporter.Object, awsClient
 			return errors.Errorf("Computed Operator Role '%s' does not match role ARN found in AWS '%s', "+
 				"please check if the correct parameters have been supplied.", operatorIAMRole.RoleARN, roleARN)
 		}
-		err = validateIssuerUrlMatchesAssumePolicyDocument(
+		err = ValidateIssuerUrlMatchesAssumePolicyDocument(
 			roleARN, parsedUrl, *roleObject.AssumeRolePolicyDocument)
 		if err != nil {
 			return err
@@ -896,7 +896,9 @@ func ValidateHttpTokensValue(val interface{}) error {
 	return fmt.Errorf("can only validate strings, got %v", val)
 }
 
-func validateIssuerUrlMatchesAssumePolicyDocument(
+// ValidateIssuerUrlMatchesAssumePolicyDocument checks that the trust policy of an operator role
+// allows the OIDC provider of the given issuer URL.
+func ValidateIssuerUrlMatchesAssumePolicyDocument(
 	roleArn string, parsedUrl *url.URL, assumePolicyDocument string) error {
 	issuerUrl := parsedUrl.Host
 	if parsedUrl.Path != "" {
@@ -121,6 +121,18 @@ func (c *Client) DeleteHTPasswdUser(username, clusterID string, htpasswdIDP *cmv
 	return nil
 }
 
+func (c *Client) UpdateIdentityProvider(clusterID string, idp *cmv1.IdentityProvider) (*cmv1.IdentityProvider, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		IdentityProviders().IdentityProvider(idp.ID()).
+		Update().Body(idp).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
 func (c *Client) DeleteIdentityProvider(clusterID string, idpID string) error {
 	response, err := c.ocm.ClustersMgmt().V1().
 		Clusters().Cluster(clusterID).
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// The functions in this file repeatedly get a resource until the callback returns true or the
+// context expires, and return the last version of the resource. The callback receives nil once
+// the resource doesn't exist, so that it is possible to wait for it to be deleted. When the
+// context expires before the callback returns true the last version is returned without error,
+// unless the last request failed.
+
+func (c *Client) PollCluster(ctx context.Context, clusterID string, interval time.Duration,
+	cb func(*cmv1.Cluster) bool) (*cmv1.Cluster, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Poll().
+		Interval(interval).
+		Status(http.StatusOK).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.ClusterGetResponse) bool {
+			return cb(response.Body())
+		}).
+		StartContext(ctx)
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func (c *Client) PollNodePool(ctx context.Context, clusterID string, nodePoolID string,
+	interval time.Duration, cb func(*cmv1.NodePool) bool) (*cmv1.NodePool, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		NodePools().NodePool(nodePoolID).
+		Poll().
+		Interval(interval).
+		Status(http.StatusOK).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.NodePoolGetResponse) bool {
+			return cb(response.Body())
+		}).
+		StartContext(ctx)
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func (c *Client) PollUpgradePolicyState(ctx context.Context, clusterID string, upgradePolicyID string,
+	interval time.Duration, cb func(*cmv1.UpgradePolicyState) bool) (*cmv1.UpgradePolicyState, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		UpgradePolicies().UpgradePolicy(upgradePolicyID).
+		State().
+		Poll().
+		Interval(interval).
+		Status(http.StatusOK).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.UpgradePolicyStateGetResponse) bool {
+			return cb(response.Body())
+		}).
+		StartContext(ctx)
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func (c *Client) PollControlPlaneUpgradePolicy(ctx context.Context, clusterID string, upgradePolicyID string,
+	interval time.Duration, cb func(*cmv1.ControlPlaneUpgradePolicy) bool) (*cmv1.ControlPlaneUpgradePolicy, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		ControlPlane().UpgradePolicies().ControlPlaneUpgradePolicy(upgradePolicyID).
+		Poll().
+		Interval(interval).
+		Status(http.StatusOK).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.ControlPlaneUpgradePolicyGetResponse) bool {
+			return cb(response.Body())
+		}).
+		StartContext(ctx)
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func (c *Client) PollAddOnInstallation(ctx context.Context, clusterID string, addOnID string,
+	interval time.Duration, cb func(*cmv1.AddOnInstallation) bool) (*cmv1.AddOnInstallation, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Addons().Addoninstallation(addOnID).
+		Poll().
+		Interval(interval).
+		Status(http.StatusOK).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.AddOnInstallationGetResponse) bool {
+			return cb(response.Body())
+		}).
+		StartContext(ctx)
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
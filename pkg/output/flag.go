@@ -26,30 +26,30 @@ import (
 
 var o string
 
-var formats = []string{"json", "yaml"}
-
-// AddFlag adds the interactive flag to the given set of command line flags.
+// AddFlag adds the output flag to the given set of command line flags.
 func AddFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(
 		&o,
 		"output",
 		"o",
 		"",
-		fmt.Sprintf("Output format. Allowed formats are %s", formats),
+		fmt.Sprintf("Output format. Allowed formats are %s. The 'jsonpath', 'go-template' and "+
+			"'custom-columns' formats take an argument, like '-o jsonpath={.id}'", Formats()),
 	)
 
 	cmd.RegisterFlagCompletionFunc("output", completion)
 }
 
 func completion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	return formats, cobra.ShellCompDirectiveDefault
+	return Formats(), cobra.ShellCompDirectiveNoSpace
 }
 
+// HasFlag returns true if the output has to be written in a format other than the default table.
 func HasFlag() bool {
-	return o != ""
+	return o != "" && o != TableFormat
 }
 
-// Enabled retursn a boolean flag that indicates if the interactive mode is enabled.
+// Output returns the value of the output flag.
 func Output() string {
 	return o
 }
@@ -0,0 +1,308 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the formatters that implement the formats of the '--output' command line
+// option.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+)
+
+// Names of the formats that are always available.
+const (
+	JSONFormat          = "json"
+	YAMLFormat          = "yaml"
+	TableFormat         = "table"
+	CSVFormat           = "csv"
+	NameFormat          = "name"
+	JSONPathFormat      = "jsonpath"
+	GoTemplateFormat    = "go-template"
+	CustomColumnsFormat = "custom-columns"
+)
+
+// Formatter writes a resource in one of the formats of the '--output' flag.
+type Formatter interface {
+	// Format writes the resource, given in its JSON representation. The table is the one built
+	// by the command for its default output, nil for commands that don't have one.
+	Format(w io.Writer, resource []byte, table *Table) error
+}
+
+// FormatterFunc is a function that implements the Formatter interface.
+type FormatterFunc func(w io.Writer, resource []byte, table *Table) error
+
+func (f FormatterFunc) Format(w io.Writer, resource []byte, table *Table) error {
+	return f(w, resource, table)
+}
+
+// FormatterFactory creates a formatter. The argument is the text after the '=' of the format,
+// like the template of '-o go-template=...', and is empty when there is none.
+type FormatterFactory func(argument string) (Formatter, error)
+
+var factories = map[string]FormatterFactory{}
+
+// Register makes a format available to the '--output' flag of all the commands.
+func Register(name string, factory FormatterFactory) {
+	factories[name] = factory
+}
+
+func init() {
+	Register(JSONFormat, noArgument(JSONFormat, FormatterFunc(formatJSON)))
+	Register(YAMLFormat, noArgument(YAMLFormat, FormatterFunc(formatYAML)))
+	Register(TableFormat, noArgument(TableFormat, FormatterFunc(formatTable)))
+	Register(CSVFormat, noArgument(CSVFormat, FormatterFunc(formatCSV)))
+	Register(NameFormat, noArgument(NameFormat, FormatterFunc(formatName)))
+	Register(JSONPathFormat, newJSONPathFormatter)
+	Register(GoTemplateFormat, newGoTemplateFormatter)
+	Register(CustomColumnsFormat, newCustomColumnsFormatter)
+}
+
+// Formats returns the names of the registered formats, sorted alphabetically.
+func Formats() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewFormatter returns the formatter for a value of the '--output' flag, like 'json' or
+// 'jsonpath={.id}'.
+func NewFormatter(format string) (Formatter, error) {
+	name, argument, _ := strings.Cut(format, "=")
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown format '%s'. Valid formats are %s", name, Formats())
+	}
+	return factory(argument)
+}
+
+func noArgument(name string, formatter Formatter) FormatterFactory {
+	return func(argument string) (Formatter, error) {
+		if argument != "" {
+			return nil, fmt.Errorf("Format '%s' doesn't accept an argument", name)
+		}
+		return formatter, nil
+	}
+}
+
+func requireArgument(name string, argument string) error {
+	if argument == "" {
+		return fmt.Errorf("Format '%s' requires an argument, like '-o %s=...'", name, name)
+	}
+	return nil
+}
+
+func formatJSON(w io.Writer, resource []byte, _ *Table) error {
+	return prettifyJSON(w, resource)
+}
+
+func formatYAML(w io.Writer, resource []byte, _ *Table) error {
+	out, err := yaml.JSONToYAML(resource)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func formatTable(w io.Writer, _ []byte, table *Table) error {
+	if table == nil {
+		return fmt.Errorf("Format '%s' isn't supported by this command", TableFormat)
+	}
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(table.Columns) > 0 {
+		fmt.Fprintf(writer, "%s\n", strings.Join(table.Columns, "\t"))
+	}
+	for _, row := range table.Rows {
+		// Cells with several lines continue in the following lines of the table:
+		lines := make([][]string, len(row))
+		count := 1
+		for i, cell := range row {
+			lines[i] = strings.Split(cell, "\n")
+			if len(lines[i]) > count {
+				count = len(lines[i])
+			}
+		}
+		for j := 0; j < count; j++ {
+			line := make([]string, len(row))
+			for i := range row {
+				if j < len(lines[i]) {
+					line[i] = lines[i][j]
+				}
+			}
+			fmt.Fprintf(writer, "%s\n", strings.Join(line, "\t"))
+		}
+	}
+	return writer.Flush()
+}
+
+// formatCSV writes the columns of the table or, for commands that don't have one, the fields of
+// the resource with nested fields joined with dots.
+func formatCSV(w io.Writer, resource []byte, table *Table) error {
+	writer := csv.NewWriter(w)
+	if table != nil {
+		err := writer.Write(table.Columns)
+		if err != nil {
+			return err
+		}
+		err = writer.WriteAll(table.Rows)
+		if err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+
+	items, err := decodeItems(resource)
+	if err != nil {
+		return err
+	}
+	rows := make([]map[string]string, len(items))
+	columns := map[string]bool{}
+	for i, item := range items {
+		rows[i] = map[string]string{}
+		flatten("", item, rows[i])
+		for column := range rows[i] {
+			columns[column] = true
+		}
+	}
+	header := make([]string, 0, len(columns))
+	for column := range columns {
+		header = append(header, column)
+	}
+	sort.Strings(header)
+	err = writer.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, column := range header {
+			record[i] = row[column]
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// nameFields are the fields used as the name of a resource by the 'name' format, in order of
+// preference. They are compared ignoring case.
+var nameFields = []string{"name", "rolename", "id", "arn"}
+
+// formatName writes the name of each resource in a separate line.
+func formatName(w io.Writer, resource []byte, _ *Table) error {
+	items, err := decodeItems(resource)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			fmt.Fprintf(w, "%s\n", formatValue(item))
+			continue
+		}
+		name := ""
+		for _, field := range nameFields {
+			for key, value := range fields {
+				if strings.EqualFold(key, field) {
+					name = formatValue(value)
+					break
+				}
+			}
+			if name != "" {
+				break
+			}
+		}
+		if name == "" {
+			return fmt.Errorf("Format '%s' isn't supported by this command", NameFormat)
+		}
+		fmt.Fprintf(w, "%s\n", name)
+	}
+	return nil
+}
+
+// decode returns the generic representation of a JSON document. Numbers are kept as they are
+// instead of being converted to floating point numbers.
+func decode(resource []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(resource))
+	decoder.UseNumber()
+	var result interface{}
+	err := decoder.Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeItems returns the items of a list, or the resource itself if it isn't a list.
+func decodeItems(resource []byte) ([]interface{}, error) {
+	decoded, err := decode(resource)
+	if err != nil {
+		return nil, err
+	}
+	if items, ok := decoded.([]interface{}); ok {
+		return items, nil
+	}
+	return []interface{}{decoded}, nil
+}
+
+func flatten(path string, value interface{}, result map[string]string) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		result[path] = formatValue(value)
+		return
+	}
+	for key, field := range fields {
+		if path == "" {
+			flatten(key, field, result)
+		} else {
+			flatten(path+"."+key, field, result)
+		}
+	}
+}
+
+// formatValue returns the text of a value of a JSON document. Strings and numbers are written as
+// they are, while lists and objects are written as JSON.
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number, bool:
+		return fmt.Sprintf("%v", typed)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
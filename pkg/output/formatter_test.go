@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Formatters", func() {
+	var clusters []*cmv1.Cluster
+	var table *Table
+
+	BeforeEach(func() {
+		first, err := cmv1.NewCluster().ID("a1").Name("first").State(cmv1.ClusterStateReady).
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).Build()
+		Expect(err).ToNot(HaveOccurred())
+		second, err := cmv1.NewCluster().ID("b2").Name("second").State(cmv1.ClusterStateInstalling).
+			Region(cmv1.NewCloudRegion().ID("eu-west-1")).Build()
+		Expect(err).ToNot(HaveOccurred())
+		clusters = []*cmv1.Cluster{first, second}
+		table = NewTable("ID", "NAME", "STATE")
+		for _, cluster := range clusters {
+			table.AddRow(cluster.ID(), cluster.Name(), cluster.State())
+		}
+	})
+
+	format := func(format string, resource interface{}, table *Table) (string, error) {
+		var b bytes.Buffer
+		err := write(&b, format, resource, table)
+		return b.String(), err
+	}
+
+	It("Writes the table by default", func() {
+		out, err := format("", clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("ID  NAME    STATE\na1  first   ready\nb2  second  installing\n"))
+	})
+
+	It("Rejects the table format for commands without a table", func() {
+		_, err := format(TableFormat, clusters, nil)
+		Expect(err).To(MatchError("Format 'table' isn't supported by this command"))
+	})
+
+	It("Writes the table as CSV", func() {
+		out, err := format(CSVFormat, clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("ID,NAME,STATE\na1,first,ready\nb2,second,installing\n"))
+	})
+
+	It("Writes the fields of the resource as CSV without a table", func() {
+		out, err := format(CSVFormat, clusters[0], nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("id,kind,name,region.id,region.kind,state\n" +
+			"a1,Cluster,first,us-east-1,CloudRegion,ready\n"))
+	})
+
+	It("Writes the names", func() {
+		out, err := format(NameFormat, clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("first\nsecond\n"))
+	})
+
+	It("Writes the result of a JSONPath template", func() {
+		out, err := format(`jsonpath={range [?(@.state=="ready")]}{.id}{"\n"}{end}`, clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("a1\n"))
+	})
+
+	It("Writes the result of a Go template", func() {
+		out, err := format(`go-template={{range .}}{{.region.id}} {{end}}`, clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("us-east-1 eu-west-1 "))
+	})
+
+	It("Writes custom columns", func() {
+		out, err := format("custom-columns=NAME:.name,REGION:{.region.id},VERSION:.version.id", clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("NAME    REGION     VERSION\nfirst   us-east-1  <none>\nsecond  eu-west-1  <none>\n"))
+	})
+
+	It("Writes YAML", func() {
+		out, err := format(YAMLFormat, clusters[1], nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("state: installing\n"))
+	})
+
+	It("Rejects unknown formats", func() {
+		_, err := format("xml", clusters, table)
+		Expect(err).To(MatchError(ContainSubstring("Unknown format 'xml'")))
+	})
+
+	It("Requires the argument of the template formats", func() {
+		_, err := format(GoTemplateFormat, clusters, table)
+		Expect(err).To(MatchError("Format 'go-template' requires an argument, like '-o go-template=...'"))
+	})
+
+	It("Uses registered formats", func() {
+		Register("count", noArgument("count", FormatterFunc(func(w io.Writer, _ []byte, table *Table) error {
+			_, err := fmt.Fprintf(w, "%d\n", len(table.Rows))
+			return err
+		})))
+		defer delete(factories, "count")
+		out, err := format("count", clusters, table)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("2\n"))
+	})
+})
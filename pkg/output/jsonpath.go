@@ -0,0 +1,477 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the 'jsonpath' and 'custom-columns' output formats.
+// The syntax of the templates is the same as in 'kubectl', for example:
+//
+//	rosa list clusters -o jsonpath='{range .[*]}{.id}{"\t"}{.state}{"\n"}{end}'
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// jsonPathNode is a piece of a JSONPath template: literal text, an expression, or a range over
+// the results of an expression.
+type jsonPathNode struct {
+	text       string
+	expression bool
+	steps      []jsonPathStep
+	body       []jsonPathNode
+	loop       bool
+}
+
+type jsonPathStepKind int
+
+const (
+	fieldStep jsonPathStepKind = iota
+	wildcardStep
+	recursiveStep
+	indexStep
+	filterStep
+)
+
+type jsonPathStep struct {
+	kind  jsonPathStepKind
+	field string
+	index int
+
+	// Filters compare the result of a path relative to each item with a value. An empty
+	// operator only checks that the path has a result.
+	filter   []jsonPathStep
+	operator string
+	value    string
+}
+
+// JSONPath is a parsed JSONPath template.
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// ParseJSONPath parses a JSONPath template, where expressions are written between braces. The
+// supported syntax includes fields, indexes, wildcards, recursive descent, filters like
+// '[?(@.state=="ready")]', string literals like '{"\n"}' and '{range ...}...{end}' loops.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	nodes, _, err := parseJSONPathNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseJSONPathNodes parses the template until its end or until an '{end}' action, returning the
+// text that follows the '{end}'.
+func parseJSONPathNodes(template string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+	for template != "" {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			nodes = append(nodes, jsonPathNode{text: template})
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, jsonPathNode{text: template[:start]})
+		}
+		end := closingBrace(template, start)
+		if end < 0 {
+			return nil, "", fmt.Errorf("Unclosed action in JSONPath template '%s'", template)
+		}
+		action := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("Unexpected '{end}' in JSONPath template")
+			}
+			return nodes, template, nil
+		case strings.HasPrefix(action, "range "):
+			steps, err := parseJSONPathSteps(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(template, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{expression: true, steps: steps, body: body, loop: true})
+			template = rest
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+			text, err := unquote(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+		default:
+			steps, err := parseJSONPathSteps(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{expression: true, steps: steps})
+		}
+	}
+	if inRange {
+		return nil, "", fmt.Errorf("Missing '{end}' in JSONPath template")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace returns the position of the brace that closes the one at the given position,
+// ignoring the braces inside quoted strings.
+func closingBrace(template string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(text string) (string, error) {
+	if strings.HasPrefix(text, "'") {
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return "", fmt.Errorf("Invalid string literal %s", text)
+		}
+		return text[1 : len(text)-1], nil
+	}
+	result, err := strconv.Unquote(text)
+	if err != nil {
+		return "", fmt.Errorf("Invalid string literal %s", text)
+	}
+	return result, nil
+}
+
+func parseJSONPathSteps(path string) ([]jsonPathStep, error) {
+	expression := path
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, "@")
+	var steps []jsonPathStep
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, ".."):
+			steps = append(steps, jsonPathStep{kind: recursiveStep})
+			path = path[1:]
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			name := path[:end]
+			path = path[end:]
+			switch name {
+			case "":
+				// A single dot refers to the current value
+			case "*":
+				steps = append(steps, jsonPathStep{kind: wildcardStep})
+			default:
+				steps = append(steps, jsonPathStep{kind: fieldStep, field: name})
+			}
+		case strings.HasPrefix(path, "["):
+			end := closingBracket(path)
+			if end < 0 {
+				return nil, fmt.Errorf("Unclosed '[' in JSONPath expression '%s'", expression)
+			}
+			step, err := parseJSONPathSubscript(strings.TrimSpace(path[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid JSONPath expression '%s': %v", expression, err)
+			}
+			steps = append(steps, step)
+			path = path[end+1:]
+		default:
+			return nil, fmt.Errorf("Invalid JSONPath expression '%s'", expression)
+		}
+	}
+	return steps, nil
+}
+
+// closingBracket returns the position of the bracket that closes the one at the start of the path.
+func closingBracket(path string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseJSONPathSubscript(subscript string) (jsonPathStep, error) {
+	switch {
+	case subscript == "*":
+		return jsonPathStep{kind: wildcardStep}, nil
+	case strings.HasPrefix(subscript, "?(") && strings.HasSuffix(subscript, ")"):
+		return parseJSONPathFilter(strings.TrimSpace(subscript[2 : len(subscript)-1]))
+	case strings.HasPrefix(subscript, "'") || strings.HasPrefix(subscript, `"`):
+		field, err := unquote(subscript)
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{kind: fieldStep, field: field}, nil
+	}
+	index, err := strconv.Atoi(subscript)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("unsupported subscript '[%s]'", subscript)
+	}
+	return jsonPathStep{kind: indexStep, index: index}, nil
+}
+
+func parseJSONPathFilter(filter string) (jsonPathStep, error) {
+	step := jsonPathStep{kind: filterStep}
+	path := filter
+	for _, operator := range []string{"==", "!="} {
+		left, right, found := strings.Cut(filter, operator)
+		if !found {
+			continue
+		}
+		path = strings.TrimSpace(left)
+		step.operator = operator
+		step.value = strings.TrimSpace(right)
+		if strings.HasPrefix(step.value, "'") || strings.HasPrefix(step.value, `"`) {
+			value, err := unquote(step.value)
+			if err != nil {
+				return step, err
+			}
+			step.value = value
+		}
+		break
+	}
+	if !strings.HasPrefix(path, "@") {
+		return step, fmt.Errorf("filter '%s' must start with '@'", filter)
+	}
+	steps, err := parseJSONPathSteps(path)
+	if err != nil {
+		return step, err
+	}
+	step.filter = steps
+	return step, nil
+}
+
+// Execute writes the result of the template for the given document, as returned by decode.
+// Expressions with several results write them separated by spaces, and fields that don't exist
+// are ignored.
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	return executeJSONPathNodes(w, j.nodes, data)
+}
+
+func executeJSONPathNodes(w io.Writer, nodes []jsonPathNode, data interface{}) error {
+	for _, node := range nodes {
+		if !node.expression {
+			_, err := io.WriteString(w, node.text)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		results := evaluateJSONPath(node.steps, []interface{}{data})
+		if node.loop {
+			for _, result := range results {
+				err := executeJSONPathNodes(w, node.body, result)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		values := make([]string, len(results))
+		for i, result := range results {
+			values[i] = formatValue(result)
+		}
+		_, err := io.WriteString(w, strings.Join(values, " "))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func evaluateJSONPath(steps []jsonPathStep, values []interface{}) []interface{} {
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, evaluateJSONPathStep(step, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func evaluateJSONPathStep(step jsonPathStep, value interface{}) []interface{} {
+	switch step.kind {
+	case fieldStep:
+		if fields, ok := value.(map[string]interface{}); ok {
+			if field, ok := fields[step.field]; ok {
+				return []interface{}{field}
+			}
+		}
+	case wildcardStep:
+		return children(value)
+	case recursiveStep:
+		result := []interface{}{value}
+		for _, child := range children(value) {
+			result = append(result, evaluateJSONPathStep(step, child)...)
+		}
+		return result
+	case indexStep:
+		if items, ok := value.([]interface{}); ok {
+			index := step.index
+			if index < 0 {
+				index += len(items)
+			}
+			if index >= 0 && index < len(items) {
+				return []interface{}{items[index]}
+			}
+		}
+	case filterStep:
+		var result []interface{}
+		for _, child := range children(value) {
+			matches := evaluateJSONPath(step.filter, []interface{}{child})
+			switch step.operator {
+			case "":
+				if len(matches) > 0 {
+					result = append(result, child)
+				}
+			case "==":
+				if len(matches) > 0 && formatValue(matches[0]) == step.value {
+					result = append(result, child)
+				}
+			case "!=":
+				if len(matches) == 0 || formatValue(matches[0]) != step.value {
+					result = append(result, child)
+				}
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// children returns the items of a list or the values of an object, sorted by key.
+func children(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]interface{}, len(keys))
+		for i, key := range keys {
+			result[i] = typed[key]
+		}
+		return result
+	}
+	return nil
+}
+
+func newJSONPathFormatter(argument string) (Formatter, error) {
+	err := requireArgument(JSONPathFormat, argument)
+	if err != nil {
+		return nil, err
+	}
+	path, err := ParseJSONPath(argument)
+	if err != nil {
+		return nil, err
+	}
+	return FormatterFunc(func(w io.Writer, resource []byte, _ *Table) error {
+		data, err := decode(resource)
+		if err != nil {
+			return err
+		}
+		return path.Execute(w, data)
+	}), nil
+}
+
+// newCustomColumnsFormatter creates the formatter of the 'custom-columns' format, where the
+// argument is a comma separated list of headers and expressions evaluated for each item, like
+// 'ID:.id,STATE:.state'. The braces around the expressions are optional.
+func newCustomColumnsFormatter(argument string) (Formatter, error) {
+	err := requireArgument(CustomColumnsFormat, argument)
+	if err != nil {
+		return nil, err
+	}
+	var headers []string
+	var paths []*JSONPath
+	for _, column := range strings.Split(argument, ",") {
+		header, expression, found := strings.Cut(column, ":")
+		if !found || header == "" || expression == "" {
+			return nil, fmt.Errorf("Invalid custom column '%s', expected 'HEADER:.path'", column)
+		}
+		if !strings.HasPrefix(expression, "{") {
+			expression = fmt.Sprintf("{%s}", expression)
+		}
+		path, err := ParseJSONPath(expression)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+		paths = append(paths, path)
+	}
+	return FormatterFunc(func(w io.Writer, resource []byte, _ *Table) error {
+		items, err := decodeItems(resource)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "%s\n", strings.Join(headers, "\t"))
+		for _, item := range items {
+			values := make([]string, len(paths))
+			for i, path := range paths {
+				var value strings.Builder
+				err = path.Execute(&value, item)
+				if err != nil {
+					return err
+				}
+				values[i] = value.String()
+				if values[i] == "" {
+					values[i] = "<none>"
+				}
+			}
+			fmt.Fprintf(writer, "%s\n", strings.Join(values, "\t"))
+		}
+		return writer.Flush()
+	}), nil
+}
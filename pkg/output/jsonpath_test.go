@@ -0,0 +1,57 @@
+package output
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONPath", func() {
+	const document = `{
+		"name": "mycluster",
+		"nodes": {"compute": 3, "master": 3},
+		"pools": [
+			{"id": "worker", "replicas": 2, "labels": {"app": "web"}},
+			{"id": "db", "replicas": 3, "labels": {"app": "db"}}
+		]
+	}`
+
+	DescribeTable("Executes templates",
+		func(template string, expected string) {
+			path, err := ParseJSONPath(template)
+			Expect(err).ToNot(HaveOccurred())
+			data, err := decode([]byte(document))
+			Expect(err).ToNot(HaveOccurred())
+			var out strings.Builder
+			Expect(path.Execute(&out, data)).To(Succeed())
+			Expect(out.String()).To(Equal(expected))
+		},
+		Entry("field", "{.name}", "mycluster"),
+		Entry("root", "{$.nodes.compute}", "3"),
+		Entry("text", "name: {.name}", "name: mycluster"),
+		Entry("index", "{.pools[1].id}", "db"),
+		Entry("negative index", "{.pools[-1].id}", "db"),
+		Entry("quoted field", "{.pools[0].labels['app']}", "web"),
+		Entry("wildcard", "{.pools[*].id}", "worker db"),
+		Entry("object wildcard", "{.nodes.*}", "3 3"),
+		Entry("recursive descent", "{..app}", "web db"),
+		Entry("filter", `{.pools[?(@.labels.app=="db")].replicas}`, "3"),
+		Entry("inequality filter", `{.pools[?(@.id!='db')].id}`, "worker"),
+		Entry("existence filter", "{.pools[?(@.labels)].id}", "worker db"),
+		Entry("range", `{range .pools[*]}{.id}={.replicas}{"\n"}{end}`, "worker=2\ndb=3\n"),
+		Entry("missing field", "{.missing}", ""),
+		Entry("object", "{.nodes}", `{"compute":3,"master":3}`),
+	)
+
+	DescribeTable("Rejects invalid templates",
+		func(template string, message string) {
+			_, err := ParseJSONPath(template)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unclosed action", "{.name", "Unclosed action"),
+		Entry("missing end", "{range .pools[*]}{.id}", "Missing '{end}'"),
+		Entry("unexpected end", "{.id}{end}", "Unexpected '{end}'"),
+		Entry("invalid subscript", "{.pools[a]}", "unsupported subscript '[a]'"),
+	)
+})
@@ -21,11 +21,11 @@ package output
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io"
+	"os"
 	"reflect"
+	"sort"
 
-	"github.com/ghodss/yaml"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	msv1 "github.com/openshift-online/ocm-sdk-go/servicemgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
@@ -38,7 +38,37 @@ import (
 // that the output can be shown correctly.
 var emptyBuffer = []byte{91, 10, 32, 32, 10, 93}
 
+// Print writes the resource to the standard output in the format selected with the '--output'
+// flag.
 func Print(resource interface{}) error {
+	return write(os.Stdout, o, resource, nil)
+}
+
+// PrintTable writes the resource to the standard output in the format selected with the
+// '--output' flag. The table is the default format, and is also used by the formats that print
+// columns, like 'csv'.
+func PrintTable(resource interface{}, table *Table) error {
+	return write(os.Stdout, o, resource, table)
+}
+
+func write(w io.Writer, format string, resource interface{}, table *Table) error {
+	if format == "" {
+		format = TableFormat
+	}
+	formatter, err := NewFormatter(format)
+	if err != nil {
+		return err
+	}
+	body, err := marshal(resource)
+	if err != nil {
+		return err
+	}
+	return formatter.Format(w, body, table)
+}
+
+// marshal returns the JSON representation of the resource, using the marshalling functions of
+// the SDK for the types that have them.
+func marshal(resource interface{}) ([]byte, error) {
 	var b bytes.Buffer
 	switch reflect.TypeOf(resource).String() {
 	case "[]*v1.ManagedService":
@@ -49,6 +79,18 @@ func Print(resource interface{}) error {
 		if cloudRegions, ok := resource.([]*cmv1.CloudRegion); ok {
 			cmv1.MarshalCloudRegionList(cloudRegions, &b)
 		}
+	case "*v1.AddOn":
+		if addOn, ok := resource.(*cmv1.AddOn); ok {
+			cmv1.MarshalAddOn(addOn, &b)
+		}
+	case "*v1.AddOnInstallation":
+		if installation, ok := resource.(*cmv1.AddOnInstallation); ok {
+			cmv1.MarshalAddOnInstallation(installation, &b)
+		}
+	case "*v1.ManagedService":
+		if managedService, ok := resource.(*msv1.ManagedService); ok {
+			msv1.MarshalManagedService(managedService, &b)
+		}
 	case "*v1.Cluster":
 		if cluster, ok := resource.(*cmv1.Cluster); ok {
 			cmv1.MarshalCluster(cluster, &b)
@@ -57,6 +99,10 @@ func Print(resource interface{}) error {
 		if clusters, ok := resource.([]*cmv1.Cluster); ok {
 			cmv1.MarshalClusterList(clusters, &b)
 		}
+	case "*v1.ClusterAutoscaler":
+		if autoscaler, ok := resource.(*cmv1.ClusterAutoscaler); ok {
+			cmv1.MarshalClusterAutoscaler(autoscaler, &b)
+		}
 	case "[]*v1.DNSDomain":
 		if dnsdomains, ok := resource.([]*cmv1.DNSDomain); ok {
 			cmv1.MarshalDNSDomainList(dnsdomains, &b)
@@ -81,17 +127,33 @@ func Print(resource interface{}) error {
 		if machineTypes, ok := resource.([]*cmv1.MachineType); ok {
 			cmv1.MarshalMachineTypeList(machineTypes, &b)
 		}
+	case "[]*v1.NodePool":
+		if nodePools, ok := resource.([]*cmv1.NodePool); ok {
+			cmv1.MarshalNodePoolList(nodePools, &b)
+		}
 	case "*v1.NodePool":
 		if nodePool, ok := resource.(*cmv1.NodePool); ok {
 			cmv1.MarshalNodePool(nodePool, &b)
 		}
+	case "[]*v1.UpgradePolicy":
+		if upgradePolicies, ok := resource.([]*cmv1.UpgradePolicy); ok {
+			cmv1.MarshalUpgradePolicyList(upgradePolicies, &b)
+		}
+	case "[]*v1.ControlPlaneUpgradePolicy":
+		if upgradePolicies, ok := resource.([]*cmv1.ControlPlaneUpgradePolicy); ok {
+			cmv1.MarshalControlPlaneUpgradePolicyList(upgradePolicies, &b)
+		}
+	case "[]*v1.NodePoolUpgradePolicy":
+		if upgradePolicies, ok := resource.([]*cmv1.NodePoolUpgradePolicy); ok {
+			cmv1.MarshalNodePoolUpgradePolicyList(upgradePolicies, &b)
+		}
 	case "[]*v1.Version":
 		if versions, ok := resource.([]*cmv1.Version); ok {
 			cmv1.MarshalVersionList(versions, &b)
 		}
 	case "[]*v1.VersionGate":
-		if versionGate, ok := resource.(*cmv1.VersionGate); ok {
-			cmv1.MarshalVersionGate(versionGate, &b)
+		if versionGates, ok := resource.([]*cmv1.VersionGate); ok {
+			cmv1.MarshalVersionGateList(versionGates, &b)
 		}
 	case "[]*v1.OidcConfig":
 		if oidcConfigs, ok := resource.([]*cmv1.OidcConfig); ok {
@@ -114,18 +176,25 @@ func Print(resource interface{}) error {
 			if roles, ok := resource.([]aws.Role); ok {
 				err := aws.MarshalRoles(roles, &b)
 				if err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
 	case "map[string][]aws.Role":
 		{
-			for _, operatorRoles := range resource.(map[string][]aws.Role) {
-				err := Print(operatorRoles)
-				if err != nil {
-					return err
-				}
+			// The roles of all the prefixes are returned as a single list, so that the result
+			// is a valid document:
+			operatorRoles := resource.(map[string][]aws.Role)
+			prefixes := make([]string, 0, len(operatorRoles))
+			for prefix := range operatorRoles {
+				prefixes = append(prefixes, prefix)
+			}
+			sort.Strings(prefixes)
+			roles := []aws.Role{}
+			for _, prefix := range prefixes {
+				roles = append(roles, operatorRoles[prefix]...)
 			}
+			return marshal(roles)
 		}
 	// default to catch non concrete types
 	default:
@@ -134,38 +203,16 @@ func Print(resource interface{}) error {
 			json.NewEncoder(reqBodyBytes).Encode(resource)
 			err := json.Indent(&b, reqBodyBytes.Bytes(), "", "  ")
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 	// Verify if the resource is an empty string and ensure that the JSON
 	// representation looks correct for STDOUT.
 	if b.String() == string(emptyBuffer) {
-		b = *bytes.NewBufferString("[]")
-	}
-	str, err := parseResource(b)
-	if err != nil {
-		return err
-	}
-	fmt.Print(str)
-	return nil
-}
-
-func parseResource(body bytes.Buffer) (string, error) {
-	switch o {
-	case "json":
-		var out bytes.Buffer
-		prettifyJSON(&out, body.Bytes())
-		return out.String(), nil
-	case "yaml":
-		out, err := yaml.JSONToYAML(body.Bytes())
-		if err != nil {
-			return "", err
-		}
-		return string(out), nil
-	default:
-		return "", fmt.Errorf("Unknown format '%s'. Valid formats are %s", o, formats)
+		return []byte("[]"), nil
 	}
+	return b.Bytes(), nil
 }
 
 func prettifyJSON(stream io.Writer, body []byte) error {
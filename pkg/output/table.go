@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the table used as the default output of the commands that list resources.
+
+package output
+
+import (
+	"fmt"
+)
+
+// Table is the tabulated representation of a resource, with one row per item of a list.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// NewTable creates an empty table with the given column headers.
+func NewTable(columns ...string) *Table {
+	return &Table{
+		Columns: columns,
+	}
+}
+
+// AddRow adds a row to the table. Values that aren't strings are written with their default
+// format.
+func (t *Table) AddRow(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = fmt.Sprintf("%v", value)
+	}
+	t.Rows = append(t.Rows, row)
+}
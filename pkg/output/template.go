@@ -0,0 +1,45 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the 'go-template' output format.
+
+package output
+
+import (
+	"io"
+	"text/template"
+)
+
+// newGoTemplateFormatter creates the formatter of the 'go-template' format. The template is
+// executed with the JSON representation of the resource, so fields are referenced by their JSON
+// names, like in '{{range .}}{{.id}}{{"\n"}}{{end}}'.
+func newGoTemplateFormatter(argument string) (Formatter, error) {
+	err := requireArgument(GoTemplateFormat, argument)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(GoTemplateFormat).Parse(argument)
+	if err != nil {
+		return nil, err
+	}
+	return FormatterFunc(func(w io.Writer, resource []byte, _ *Table) error {
+		data, err := decode(resource)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, data)
+	}), nil
+}
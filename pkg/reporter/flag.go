@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--log-format' command line option.
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// LogFormatEnv is the environment variable that sets the default of the '--log-format' flag.
+const LogFormatEnv = "ROSA_LOG_FORMAT"
+
+const (
+	TextLogFormat = "text"
+	JSONLogFormat = "json"
+)
+
+var logFormat string
+
+var logFormats = []string{TextLogFormat, JSONLogFormat}
+
+// AddFlag adds the log format flag to the given command. The default value is taken from the
+// ROSA_LOG_FORMAT environment variable, so that CI jobs can enable it without changing every
+// command line.
+func AddFlag(cmd *cobra.Command) {
+	dflt := os.Getenv(LogFormatEnv)
+	if dflt == "" {
+		dflt = TextLogFormat
+	}
+	cmd.PersistentFlags().StringVar(
+		&logFormat,
+		"log-format",
+		dflt,
+		fmt.Sprintf("Format of the messages written by the tool. The 'json' format writes one JSON "+
+			"object per message to the standard error stream and disables spinners and prompts. "+
+			"Can also be set with the %s environment variable. Allowed options are %s",
+			LogFormatEnv, logFormats),
+	)
+
+	cmd.RegisterFlagCompletionFunc("log-format", logFormatCompletion)
+}
+
+func logFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string,
+	cobra.ShellCompDirective) {
+	return logFormats, cobra.ShellCompDirectiveDefault
+}
+
+// ValidateLogFormat checks that the value given to the log format flag is supported.
+func ValidateLogFormat() error {
+	for _, format := range logFormats {
+		if strings.EqualFold(logFormat, format) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unsupported log format '%s', allowed options are %s", logFormat, logFormats)
+}
+
+// SetLogFormat changes the log format used by the reporters.
+func SetLogFormat(format string) {
+	logFormat = format
+}
+
+// UseJSON returns a bool that indicates whether messages are written as JSON events.
+func UseJSON() bool {
+	return strings.EqualFold(logFormat, JSONLogFormat)
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package reporter
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/openshift/rosa/pkg/color"
 	"github.com/openshift/rosa/pkg/debug"
@@ -54,21 +57,37 @@ func (r *Object) Debugf(format string, args ...interface{}) {
 	if !debug.Enabled() {
 		return
 	}
+	if UseJSON() {
+		r.event(debugLevel, format, args...)
+		return
+	}
 	r.Infof(format, args...)
 }
 
 // Infof prints an informative message with the given format and arguments.
 func (r *Object) Infof(format string, args ...interface{}) {
+	if UseJSON() {
+		r.event(infoLevel, format, args...)
+		return
+	}
 	message := fmt.Sprintf(format, args...)
+	var out io.Writer = os.Stdout
+	if infos != nil {
+		out = infos
+	}
 	if color.UseColor() {
-		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", infoPrefix, message)
+		_, _ = fmt.Fprintf(out, "%s%s\n", infoPrefix, message)
 	} else {
-		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "INFO: ", message)
+		_, _ = fmt.Fprintf(out, "%s%s\n", "INFO: ", message)
 	}
 }
 
 // Warnf prints an warning message with the given format and arguments.
 func (r *Object) Warnf(format string, args ...interface{}) {
+	if UseJSON() {
+		r.event(warnLevel, format, args...)
+		return
+	}
 	message := fmt.Sprintf(format, args...)
 	if color.UseColor() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", warnPrefix, message)
@@ -82,13 +101,32 @@ func (r *Object) Warnf(format string, args ...interface{}) {
 // report the error and also return it.
 func (r *Object) Errorf(format string, args ...interface{}) error {
 	message := fmt.Sprintf(format, args...)
-	if color.UseColor() {
+	if UseJSON() {
+		r.event(errorLevel, format, args...)
+	} else if color.UseColor() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", errorPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", "ERR: ", message)
 	}
 	r.errors++
-	return errors.New(message)
+	return &reportedError{message: message}
+}
+
+// reportedError is the error returned by Errorf. It tells callers further up that the error has
+// already been shown to the user.
+type reportedError struct {
+	message string
+}
+
+func (e *reportedError) Error() string {
+	return e.message
+}
+
+// IsReported checks if the given error, or any error that it wraps, was returned by Errorf and
+// has therefore already been reported.
+func IsReported(err error) bool {
+	var reported *reportedError
+	return errors.As(err, &reported)
 }
 
 // Errors returns the number of errors that have been reported via this reporter.
@@ -103,9 +141,75 @@ const (
 	errorPrefix = "\033[0;31mE:\033[m "
 )
 
+// Levels of the JSON events:
+const (
+	debugLevel = "debug"
+	infoLevel  = "info"
+	warnLevel  = "warn"
+	errorLevel = "error"
+)
+
+// Event is the JSON object written for each message when the JSON log format is used.
+type Event struct {
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Command   string    `json:"command,omitempty"`
+	ClusterID string    `json:"cluster_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// events is where the JSON events are written. It is only replaced by the tests.
+var events io.Writer = os.Stderr
+
+// infos is where the informative messages are written when the text log format is used. When it
+// is nil they are written to the standard output.
+var infos io.Writer
+
+// SetInfoOutput changes where the informative messages are written, so that commands that write
+// machine-readable results to the standard output, like the plan mode, can keep it clean. Spinners
+// are disabled when the messages aren't written to the standard output.
+func SetInfoOutput(w io.Writer) {
+	infos = w
+}
+
+// command and clusterID are added to every JSON event. They are global because each command
+// creates its own reporters.
+var command string
+var clusterID string
+
+// SetCommand sets the name of the command that is added to the JSON events, for example
+// 'rosa create cluster'.
+func SetCommand(value string) {
+	command = value
+}
+
+// SetClusterID sets the identifier of the cluster that the command is working on, so that it is
+// added to the JSON events.
+func SetClusterID(value string) {
+	clusterID = value
+}
+
+func (r *Object) event(level string, format string, args ...interface{}) {
+	data, err := json.Marshal(&Event{
+		Level:     level,
+		Message:   fmt.Sprintf(format, args...),
+		Command:   command,
+		ClusterID: clusterID,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(events, "%s\n", data)
+}
+
 // Determine whether the reporter output is meant for the terminal
-// or whether it's piped or redirected to a file.
+// or whether it's piped or redirected to a file. It is never the case when the JSON log format
+// is used, so that spinners and other decorations are disabled.
 func (r *Object) IsTerminal() bool {
+	if UseJSON() || infos != nil {
+		return false
+	}
 	stdout, err := os.Stdout.Stat()
 	if err != nil {
 		return true
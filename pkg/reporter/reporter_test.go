@@ -0,0 +1,136 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON log format", func() {
+	var buffer *bytes.Buffer
+	var r *Object
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		events = buffer
+		SetLogFormat(JSONLogFormat)
+		SetCommand("rosa describe cluster")
+		SetClusterID("")
+		r = CreateReporterOrExit()
+	})
+
+	AfterEach(func() {
+		events = os.Stderr
+		SetLogFormat(TextLogFormat)
+		SetCommand("")
+		SetClusterID("")
+	})
+
+	decodeEvents := func() []Event {
+		var result []Event
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			event := Event{}
+			Expect(json.Unmarshal([]byte(line), &event)).To(Succeed())
+			result = append(result, event)
+		}
+		return result
+	}
+
+	It("Writes one event per message", func() {
+		r.Infof("Loading cluster '%s'", "mycluster")
+		SetClusterID("123")
+		r.Warnf("Cluster is hibernating")
+		err := r.Errorf("Failed to get cluster")
+		Expect(err).To(MatchError("Failed to get cluster"))
+		Expect(r.Errors()).To(Equal(1))
+
+		result := decodeEvents()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Level).To(Equal("info"))
+		Expect(result[0].Message).To(Equal("Loading cluster 'mycluster'"))
+		Expect(result[0].Command).To(Equal("rosa describe cluster"))
+		Expect(result[0].ClusterID).To(BeEmpty())
+		Expect(result[0].Timestamp.IsZero()).To(BeFalse())
+		Expect(result[1].Level).To(Equal("warn"))
+		Expect(result[1].ClusterID).To(Equal("123"))
+		Expect(result[2].Level).To(Equal("error"))
+	})
+
+	It("Omits the cluster identifier when it isn't known", func() {
+		r.Infof("Hello")
+		Expect(buffer.String()).ToNot(ContainSubstring("cluster_id"))
+	})
+
+	It("Doesn't write to the standard output", func() {
+		stdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		Expect(err).ToNot(HaveOccurred())
+		os.Stdout = writer
+		r.Infof("Hello")
+		os.Stdout = stdout
+		Expect(writer.Close()).To(Succeed())
+		data, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(BeEmpty())
+		Expect(decodeEvents()).To(HaveLen(1))
+	})
+
+	It("Is never a terminal", func() {
+		Expect(r.IsTerminal()).To(BeFalse())
+	})
+})
+
+var _ = Describe("ValidateLogFormat", func() {
+	AfterEach(func() {
+		SetLogFormat(TextLogFormat)
+	})
+
+	It("Accepts the supported formats", func() {
+		SetLogFormat("JSON")
+		Expect(ValidateLogFormat()).To(Succeed())
+		Expect(UseJSON()).To(BeTrue())
+	})
+
+	It("Rejects unknown formats", func() {
+		SetLogFormat("xml")
+		Expect(ValidateLogFormat()).To(MatchError("Unsupported log format 'xml', allowed options are [text json]"))
+	})
+})
+
+var _ = Describe("IsReported", func() {
+	It("Detects errors returned by Errorf", func() {
+		r := CreateReporterOrExit()
+		events = io.Discard
+		SetLogFormat(JSONLogFormat)
+		defer func() {
+			events = os.Stderr
+			SetLogFormat(TextLogFormat)
+		}()
+		err := r.Errorf("Failed to get cluster")
+		Expect(IsReported(err)).To(BeTrue())
+		Expect(IsReported(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+		Expect(IsReported(errors.New("Failed to get cluster"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("SetInfoOutput", func() {
+	AfterEach(func() {
+		SetInfoOutput(nil)
+	})
+
+	It("Writes the informative messages to the given writer", func() {
+		buffer := &bytes.Buffer{}
+		SetInfoOutput(buffer)
+		r := CreateReporterOrExit()
+		r.Infof("Hello %s", "world")
+		Expect(buffer.String()).To(HaveSuffix("Hello world\n"))
+		Expect(r.IsTerminal()).To(BeFalse())
+	})
+})
@@ -1,10 +1,14 @@
 package rosa
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/reporter"
@@ -19,6 +23,9 @@ type Runtime struct {
 	Creator    *aws.Creator
 	ClusterKey string
 	Cluster    *cmv1.Cluster
+
+	// Plan contains the changes to IAM resources recorded when the command runs in plan mode.
+	Plan *aws.Plan
 }
 
 func NewRuntime() *Runtime {
@@ -45,12 +52,60 @@ func (r *Runtime) WithAWS() *Runtime {
 		r.Creator, err = r.AWSClient.GetCreator()
 		if err != nil {
 			r.Reporter.Errorf("Failed to get AWS creator: %v", err)
-			os.Exit(1)
+			os.Exit(int(exitcode.Of(err)))
 		}
 	}
 	return r
 }
 
+// Replaces the AWS client of the runtime with one that records the changes to IAM resources
+// instead of applying them. Confirmation prompts are answered automatically and informative
+// messages are written to the standard error, so that the standard output only contains the plan
+// printed by `.PrintPlan()`.
+func (r *Runtime) StartPlan() {
+	r.WithAWS()
+	client, plan, err := aws.NewPlanClient(r.AWSClient, r.Creator)
+	if err != nil {
+		r.Reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	r.AWSClient = client
+	r.Plan = plan
+	confirm.SetYes(true)
+	reporter.SetInfoOutput(os.Stderr)
+}
+
+// Returns the value of the '--mode' flag of commands that support the plan mode. The plan mode
+// runs the same code as the auto mode, but the changes are recorded instead of applied, so in that
+// case the plan is started with `.StartPlan()` and the auto mode is returned.
+func (r *Runtime) GetPlanMode() string {
+	mode, err := aws.GetPlanMode()
+	if err != nil {
+		r.Reporter.Errorf("%s", err)
+		os.Exit(int(exitcode.InvalidInput))
+	}
+	if mode == aws.ModePlan {
+		r.StartPlan()
+		mode = aws.ModeAuto
+		aws.SetModeKey(mode)
+	}
+	return mode
+}
+
+// Prints the plan started with `.StartPlan()` as JSON to the standard output. It does nothing if
+// the command isn't running in plan mode.
+func (r *Runtime) PrintPlan() error {
+	if r.Plan == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.Plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to print plan: %v", err)
+	}
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", data)
+	return err
+}
+
 func (r *Runtime) Cleanup() {
 	if r.OCMClient != nil {
 		if err := r.OCMClient.Close(); err != nil {
@@ -64,7 +119,7 @@ func (r *Runtime) GetClusterKey() string {
 	clusterKey, err := ocm.GetClusterKey()
 	if err != nil {
 		r.Reporter.Errorf("%s", err)
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 	r.ClusterKey = clusterKey
 	return clusterKey
@@ -91,8 +146,9 @@ func (r *Runtime) FetchCluster() *cmv1.Cluster {
 	cluster, err := r.OCMClient.GetCluster(r.ClusterKey, r.Creator)
 	if err != nil {
 		r.Reporter.Errorf("Failed to get cluster '%s': %v", r.ClusterKey, err)
-		os.Exit(1)
+		os.Exit(int(exitcode.Of(err)))
 	}
 	r.Cluster = cluster
+	reporter.SetClusterID(cluster.ID())
 	return cluster
 }
@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that creates OIDC configurations.
+
+package workflows
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/constants"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+)
+
+// MaxOidcConfigPrefixLength is the maximum length of the prefix of an unmanaged OIDC
+// configuration.
+const MaxOidcConfigPrefixLength = 15
+
+// Keys of the documents stored in the bucket of an unmanaged OIDC configuration:
+const (
+	DiscoveryDocumentKey = ".well-known/openid-configuration"
+	JwksKey              = "keys.json"
+)
+
+// OidcConfigOptions are the options of the CreateOidcConfig workflow. The prefix, region and
+// installer role are only used for unmanaged configurations.
+type OidcConfigOptions struct {
+	// Managed selects a configuration hosted by Red Hat instead of one stored in the AWS account.
+	Managed bool
+
+	// Prefix is added to the name of the bucket and of the secret.
+	Prefix string
+
+	// Region is where the bucket and the secret are created.
+	Region string
+
+	// InstallerRoleARN is the installer account role used by OCM to read the private key.
+	InstallerRoleARN string
+
+	// Input contains the bucket name, documents and keys of the configuration. When it is nil
+	// it is generated from the prefix and the region.
+	Input *oidc_config.OidcConfigInput
+}
+
+// OidcConfigResult is the result of the CreateOidcConfig workflow. The bucket and secret are
+// only set for unmanaged configurations.
+type OidcConfigResult struct {
+	OidcConfig *cmv1.OidcConfig
+	BucketName string
+	SecretARN  string
+}
+
+// CreateOidcConfig creates an OIDC configuration and registers it in OCM. For an unmanaged
+// configuration the bucket with the discovery documents and the secret with the private key are
+// created in the AWS account first.
+func CreateOidcConfig(ctx context.Context, clients *Clients,
+	options OidcConfigOptions) (*OidcConfigResult, error) {
+	err := clients.validate(ctx, !options.Managed)
+	if err != nil {
+		return nil, err
+	}
+	if options.Managed {
+		return createManagedOidcConfig(clients)
+	}
+	return createUnmanagedOidcConfig(ctx, clients, options)
+}
+
+func createManagedOidcConfig(clients *Clients) (*OidcConfigResult, error) {
+	oidcConfig, err := cmv1.NewOidcConfig().Managed(true).Build()
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem building the managed OIDC Configuration: %v", err)
+	}
+	oidcConfig, err = clients.OCM.CreateOidcConfig(oidcConfig)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem registering your managed OIDC Configuration: %w", err)
+	}
+	return &OidcConfigResult{OidcConfig: oidcConfig}, nil
+}
+
+func createUnmanagedOidcConfig(ctx context.Context, clients *Clients,
+	options OidcConfigOptions) (*OidcConfigResult, error) {
+	prefix := strings.Trim(options.Prefix, " \t")
+	if len([]rune(prefix)) > MaxOidcConfigPrefixLength {
+		return nil, exitcode.InvalidInput.Errorf("Expected a valid prefix for the configuration: "+
+			"length of prefix is limited to %d characters", MaxOidcConfigPrefixLength)
+	}
+	if options.Region == "" {
+		return nil, exitcode.InvalidInput.Errorf("Region is mandatory for unmanaged OIDC configurations")
+	}
+	if options.InstallerRoleARN != "" {
+		err := ValidateInstallerRole(clients.AWS, options.InstallerRoleARN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input := options.Input
+	if input == nil {
+		generated, err := oidc_config.BuildOidcConfigInput(prefix, options.Region)
+		if err != nil {
+			return nil, err
+		}
+		input = &generated
+	}
+	err := clients.AWS.CreateS3Bucket(input.BucketName, options.Region)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem creating S3 bucket '%s': %w", input.BucketName, err)
+	}
+	err = clients.AWS.PutPublicReadObjectInS3Bucket(
+		input.BucketName, strings.NewReader(input.DiscoveryDocument), DiscoveryDocumentKey)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem populating discovery "+
+			"document to S3 bucket '%s': %w", input.BucketName, err)
+	}
+	err = clients.AWS.PutPublicReadObjectInS3Bucket(input.BucketName, bytes.NewReader(input.Jwks), JwksKey)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem populating JWKS "+
+			"to S3 bucket '%s': %w", input.BucketName, err)
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	secretARN, err := clients.AWS.CreateSecretInSecretsManager(input.PrivateKeySecretName, string(input.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem saving private key to secrets manager: %w", err)
+	}
+	oidcConfig, err := cmv1.NewOidcConfig().
+		Managed(false).
+		SecretArn(secretARN).
+		IssuerUrl(input.IssuerUrl).
+		InstallerRoleArn(options.InstallerRoleARN).
+		Build()
+	if err == nil {
+		oidcConfig, err = clients.OCM.CreateOidcConfig(oidcConfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem building your unmanaged OIDC Configuration %w.\n"+
+			"Please refer to documentation and try again through:\n"+
+			"\trosa register oidc-config --issuer-url %s --secret-arn %s --installer-role-arn %s",
+			err, input.IssuerUrl, secretARN, options.InstallerRoleARN)
+	}
+	return &OidcConfigResult{
+		OidcConfig: oidcConfig,
+		BucketName: input.BucketName,
+		SecretARN:  secretARN,
+	}, nil
+}
+
+// ValidateInstallerRole checks that the given installer account role exists and that it is
+// recent enough to read the private key of an unmanaged OIDC configuration.
+func ValidateInstallerRole(awsClient aws.Client, roleARN string) error {
+	err := aws.ARNValidator(roleARN)
+	if err != nil {
+		return exitcode.InvalidInput.Errorf("Expected a valid ARN: %s", err)
+	}
+	roleName, err := aws.GetResourceIdFromARN(roleARN)
+	if err != nil {
+		return exitcode.InvalidInput.Wrap(err)
+	}
+	roleExists, _, err := awsClient.CheckRoleExists(roleName)
+	if err != nil {
+		return fmt.Errorf("There was a problem checking if role '%s' exists: %w", roleARN, err)
+	}
+	if !roleExists {
+		return exitcode.NotFound.Errorf("Role '%s' does not exist", roleARN)
+	}
+	isValid, err := awsClient.ValidateAccountRoleVersionCompatibility(
+		roleName, aws.InstallerAccountRole, constants.MinorVersionForGetSecret)
+	if err != nil {
+		return fmt.Errorf("There was a problem listing role tags: %w", err)
+	}
+	if !isValid {
+		return exitcode.InvalidInput.Errorf("Role '%s' is not of minimum version '%s'",
+			roleARN, constants.MinorVersionForGetSecret)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that rotates the signing key of unmanaged OIDC configurations.
+
+package workflows
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+)
+
+// OidcConfigKeysOptions are the options of the PrepareOidcConfigKeyRotation workflow.
+type OidcConfigKeysOptions struct {
+	// OidcConfigID is the identifier of the unmanaged OIDC configuration in OCM.
+	OidcConfigID string
+
+	// Retire removes the keys that aren't used to sign tokens anymore from the published key
+	// set, instead of adding a new key. It should be used once the tokens signed with the old
+	// keys have expired.
+	Retire bool
+}
+
+// OidcConfigKeyRotation contains the documents that rotate the keys of an OIDC configuration.
+// A rotation is done in two steps: first a new private key is stored in the secret and its public
+// key is published along with the old ones, later the old keys are retired.
+type OidcConfigKeyRotation struct {
+	OidcConfig *cmv1.OidcConfig
+	BucketName string
+	SecretARN  string
+	Region     string
+
+	// PrivateKey is the new private key. It is empty when the old keys are retired.
+	PrivateKey []byte
+
+	// Jwks is the key set that is published in the bucket.
+	Jwks []byte
+
+	// KeyIDs are the identifiers of the published keys, and RetiredKeyIDs the identifiers of the
+	// keys that are removed from the bucket.
+	KeyIDs        []string
+	RetiredKeyIDs []string
+}
+
+// PrepareOidcConfigKeyRotation generates the new key and key set of an unmanaged OIDC
+// configuration, without changing anything. The AWS client has to use the region of the secret
+// of the configuration.
+func PrepareOidcConfigKeyRotation(ctx context.Context, clients *Clients,
+	options OidcConfigKeysOptions) (*OidcConfigKeyRotation, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	oidcConfig, err := clients.OCM.GetOidcConfig(options.OidcConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem retrieving the OIDC Config '%s': %w",
+			options.OidcConfigID, err)
+	}
+	if oidcConfig.Managed() {
+		return nil, exitcode.InvalidInput.Errorf("OIDC Config '%s' is managed by Red Hat, "+
+			"only the keys of unmanaged OIDC Configs can be rotated", options.OidcConfigID)
+	}
+	secretARN, err := arn.Parse(oidcConfig.SecretArn())
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem parsing secret ARN '%s': %w", oidcConfig.SecretArn(), err)
+	}
+	region := clients.AWS.GetRegion()
+	if secretARN.Region != region {
+		return nil, exitcode.InvalidInput.Errorf("Secret region '%s' differs from chosen region '%s', "+
+			"please run the command supplying region parameter", secretARN.Region, region)
+	}
+	bucketName, err := oidc_config.GetBucketNameFromSecretArn(oidcConfig.SecretArn())
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem parsing secret ARN '%s': %w", oidcConfig.SecretArn(), err)
+	}
+
+	currentJwks, err := clients.AWS.GetObjectFromS3Bucket(bucketName, JwksKey)
+	if err != nil {
+		return nil, fmt.Errorf("There was a problem reading JWKS from S3 bucket '%s': %w", bucketName, err)
+	}
+	currentKeySet, err := oidc_config.ParseJSONWebKeySet(currentJwks)
+	if err != nil {
+		return nil, err
+	}
+	rotation := &OidcConfigKeyRotation{
+		OidcConfig: oidcConfig,
+		BucketName: bucketName,
+		SecretARN:  oidcConfig.SecretArn(),
+		Region:     secretARN.Region,
+	}
+	if options.Retire {
+		privateKey, err := clients.AWS.GetSecretFromSecretsManager(rotation.SecretARN)
+		if err != nil {
+			return nil, fmt.Errorf("There was a problem reading private key from secrets manager: %w", err)
+		}
+		publicKey, err := oidc_config.GetPublicKey([]byte(privateKey))
+		if err != nil {
+			return nil, err
+		}
+		rotation.Jwks, err = oidc_config.RetainJSONWebKey(currentJwks, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("The key stored in the secret isn't published in S3 bucket '%s': %w",
+				bucketName, err)
+		}
+	} else {
+		privateKey, publicKey, err := oidc_config.CreateKeyPair()
+		if err != nil {
+			return nil, fmt.Errorf("There was a problem generating key pair: %w", err)
+		}
+		rotation.PrivateKey = privateKey
+		rotation.Jwks, err = oidc_config.AddJSONWebKey(currentJwks, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("There was a problem generating JSON Web Key Set: %w", err)
+		}
+	}
+
+	keySet, err := oidc_config.ParseJSONWebKeySet(rotation.Jwks)
+	if err != nil {
+		return nil, err
+	}
+	rotation.KeyIDs = keySet.KeyIDs()
+	for _, keyID := range currentKeySet.KeyIDs() {
+		if !helper.Contains(rotation.KeyIDs, keyID) {
+			rotation.RetiredKeyIDs = append(rotation.RetiredKeyIDs, keyID)
+		}
+	}
+	return rotation, nil
+}
+
+// ApplyOidcConfigKeyRotation publishes the key set of the rotation and then stores the new private
+// key in the secret, so that the new key is published before it is used to sign tokens.
+func ApplyOidcConfigKeyRotation(ctx context.Context, clients *Clients, rotation *OidcConfigKeyRotation) error {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return err
+	}
+	err = clients.AWS.PutPublicReadObjectInS3Bucket(rotation.BucketName, bytes.NewReader(rotation.Jwks), JwksKey)
+	if err != nil {
+		return fmt.Errorf("There was a problem populating JWKS to S3 bucket '%s': %w", rotation.BucketName, err)
+	}
+	if len(rotation.PrivateKey) == 0 {
+		return nil
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	err = clients.AWS.PutSecretValueInSecretsManager(rotation.SecretARN, string(rotation.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("There was a problem saving private key to secrets manager: %w", err)
+	}
+	return nil
+}
+
+// OidcConfigKeyRotationCommands returns the AWS CLI commands that apply the rotation, using the
+// key set and the private key saved in the given files. The private key file isn't used when the
+// old keys are retired.
+func OidcConfigKeyRotationCommands(rotation *OidcConfigKeyRotation, jwksFilename string,
+	privateKeyFilename string) []string {
+	commands := []string{
+		awscb.NewS3ApiCommandBuilder().
+			SetCommand(awscb.PutObject).
+			AddParam(awscb.Body, fmt.Sprintf("./%s", jwksFilename)).
+			AddParam(awscb.Bucket, rotation.BucketName).
+			AddParam(awscb.Key, JwksKey).
+			AddParam(awscb.Tagging, fmt.Sprintf("'%s=%s'", tags.RedHatManaged, tags.True)).
+			Build(),
+		fmt.Sprintf("rm %s", jwksFilename),
+	}
+	if len(rotation.PrivateKey) == 0 {
+		return commands
+	}
+	return append(commands,
+		awscb.NewSecretsManagerCommandBuilder().
+			SetCommand(awscb.PutSecretValue).
+			AddParam(awscb.SecretID, rotation.SecretARN).
+			AddParam(awscb.SecretString, fmt.Sprintf("file://%s", privateKeyFilename)).
+			AddParam(awscb.Region, rotation.Region).
+			Build(),
+		fmt.Sprintf("rm %s", privateKeyFilename),
+	)
+}
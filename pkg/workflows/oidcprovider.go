@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that creates the OIDC providers used by STS clusters.
+
+package workflows
+
+import (
+	// nolint:gosec
+	"bytes"
+	"context"
+	"crypto/sha1" //#nosec GSC-G505 -- Import blacklist: crypto/sha1
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift/rosa/pkg/exitcode"
+)
+
+// OidcProviderOptions are the options of the CreateOidcProvider workflow.
+type OidcProviderOptions struct {
+	// IssuerURL is the URL of the OIDC endpoint of the cluster or of the OIDC configuration.
+	IssuerURL string
+
+	// ClusterID is added as a tag to the provider. It should be empty when the provider belongs
+	// to a reusable OIDC configuration.
+	ClusterID string
+}
+
+// OidcProviderResult is the result of the CreateOidcProvider workflow.
+type OidcProviderResult struct {
+	// ARN is the identifier of the provider. It is empty if the provider already existed.
+	ARN string
+
+	// Thumbprint is the thumbprint of the certificate of the OIDC endpoint.
+	Thumbprint string
+
+	// Existed is set when the account already had a provider for the issuer URL.
+	Existed bool
+}
+
+// CreateOidcProvider creates the OIDC provider for the given issuer URL, unless the AWS account
+// already has one.
+func CreateOidcProvider(ctx context.Context, clients *Clients,
+	options OidcProviderOptions) (*OidcProviderResult, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if options.IssuerURL == "" {
+		return nil, exitcode.InvalidInput.Errorf("Issuer URL is mandatory")
+	}
+	// Users without permission to list the providers can still create them:
+	exists, err := clients.AWS.HasOpenIDConnectProvider(options.IssuerURL, clients.Creator.AccountID)
+	if err != nil && !strings.Contains(err.Error(), "AccessDenied") {
+		return nil, fmt.Errorf("Failed to verify if OIDC provider exists: %w", err)
+	}
+	if exists {
+		return &OidcProviderResult{Existed: true}, nil
+	}
+	thumbprint, err := Thumbprint(options.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	arn, err := clients.AWS.CreateOpenIDConnectProvider(options.IssuerURL, thumbprint, options.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	return &OidcProviderResult{
+		ARN:        arn,
+		Thumbprint: thumbprint,
+	}, nil
+}
+
+// Thumbprint returns the SHA1 thumbprint of the certificate of the root CA that signs the
+// certificate of the given OIDC endpoint. If the chain has no root CA the last certificate of the
+// chain is used instead.
+func Thumbprint(oidcEndpointURL string) (string, error) {
+	connect, err := url.ParseRequestURI(oidcEndpointURL)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := http.Get(fmt.Sprintf("https://%s:443", connect.Host))
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	return thumbprintOfChain(response.TLS.PeerCertificates), nil
+}
+
+// thumbprintOfChain returns the thumbprint of the root CA of the chain, or of the last certificate
+// if the chain has no root CA.
+func thumbprintOfChain(certChain []*x509.Certificate) string {
+	// Grab the CA in the chain
+	for _, cert := range certChain {
+		if cert.IsCA {
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+				return sha1Hash(cert.Raw)
+			}
+		}
+	}
+
+	// Fall back to using the last certficiate in the chain
+	cert := certChain[len(certChain)-1]
+	return sha1Hash(cert.Raw)
+}
+
+// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
+func sha1Hash(data []byte) string {
+	// nolint:gosec
+	hasher := sha1.New()
+	hasher.Write(data)
+	hashed := hasher.Sum(nil)
+	return hex.EncodeToString(hashed)
+}
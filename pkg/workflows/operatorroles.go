@@ -0,0 +1,258 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that creates the operator roles of STS clusters.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// OperatorRolesOptions are the options of the CreateOperatorRoles workflow.
+type OperatorRolesOptions struct {
+	// Cluster is the STS cluster that the roles are created for.
+	Cluster *cmv1.Cluster
+
+	// PermissionsBoundary is the ARN of the policy used as permissions boundary of the roles.
+	PermissionsBoundary string
+
+	// ForcePolicyCreation updates the unmanaged operator policies even if they already exist.
+	ForcePolicyCreation bool
+
+	// Policies and PolicyVersion are loaded from OCM when they aren't set.
+	Policies      map[string]*cmv1.AWSSTSPolicy
+	PolicyVersion string
+}
+
+// OperatorRole is an operator role created by the CreateOperatorRoles workflow.
+type OperatorRole struct {
+	Name      string
+	ARN       string
+	PolicyARN string
+	Namespace string
+	Operator  string
+}
+
+// OperatorRolesResult is the result of the CreateOperatorRoles workflow.
+type OperatorRolesResult struct {
+	Roles []OperatorRole
+
+	// Skipped is set when the cluster uses a reusable OIDC configuration and all the roles
+	// already exist, so nothing was created.
+	Skipped bool
+}
+
+// CreateOperatorRoles creates the operator roles of a cluster and their policies, or attaches
+// the managed policies when the cluster uses them. Existing roles are updated. If it fails, the
+// result returned along with the error contains the roles that were already created.
+func CreateOperatorRoles(ctx context.Context, clients *Clients,
+	options OperatorRolesOptions) (*OperatorRolesResult, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	cluster := options.Cluster
+	if cluster == nil {
+		return nil, exitcode.InvalidInput.Errorf("Cluster is mandatory")
+	}
+	if cluster.AWS().STS().RoleARN() == "" {
+		return nil, exitcode.InvalidInput.Errorf("Cluster '%s' is not an STS cluster.", cluster.ID())
+	}
+	if options.PermissionsBoundary != "" {
+		err = aws.ARNValidator(options.PermissionsBoundary)
+		if err != nil {
+			return nil, exitcode.InvalidInput.Errorf(
+				"Expected a valid policy ARN for permissions boundary: %s", err)
+		}
+	}
+	managedPolicies := cluster.AWS().STS().ManagedPolicies()
+	if options.ForcePolicyCreation && managedPolicies {
+		return nil, exitcode.InvalidInput.Errorf("Forcing creation of policies only works for unmanaged policies")
+	}
+
+	if ocm.IsOidcConfigReusable(cluster) && !options.ForcePolicyCreation {
+		missingRoles, err := MissingOperatorRoles(clients.AWS, cluster)
+		if err != nil && !strings.Contains(err.Error(), "AccessDenied") {
+			return nil, fmt.Errorf("Failed to verify if operator roles exist: %w", err)
+		}
+		if err == nil && len(missingRoles) == 0 {
+			return &OperatorRolesResult{Skipped: true}, nil
+		}
+	}
+
+	policies := options.Policies
+	if policies == nil {
+		policies, err = clients.OCM.GetPolicies("OperatorRole")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get operator role policies: %w", err)
+		}
+	}
+	policyVersion := options.PolicyVersion
+	if policyVersion == "" {
+		policyVersion, err = clients.OCM.GetLatestVersion(cluster.Version().ChannelGroup())
+		if err != nil {
+			return nil, fmt.Errorf("Error getting latest version: %w", err)
+		}
+	}
+	roleName, err := aws.GetInstallerAccountRoleName(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("Expected parsing role account role '%s': %w", cluster.AWS().STS().RoleARN(), err)
+	}
+	path, err := aws.GetPathFromAccountRole(cluster, aws.AccountRoles[aws.InstallerAccountRole].Name)
+	if err != nil {
+		return nil, fmt.Errorf("Expected a valid path for '%s': %w", cluster.AWS().STS().RoleARN(), err)
+	}
+	accountRoleVersion, err := clients.AWS.GetAccountRoleVersion(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting account role version %w", err)
+	}
+	credRequests, err := clients.OCM.GetCredRequests(cluster.Hypershift().Enabled())
+	if err != nil {
+		return nil, fmt.Errorf("Error getting operator credential request from OCM %w", err)
+	}
+	prefix, err := aws.GetOperatorRolePolicyPrefixFromCluster(cluster, clients.AWS)
+	if err != nil {
+		return nil, err
+	}
+	hostedCPPolicies := aws.IsHostedCPManagedPolicies(cluster)
+
+	result := &OperatorRolesResult{}
+	for credRequest, operator := range credRequests {
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+		ver := cluster.Version()
+		if ver != nil && operator.MinVersion() != "" {
+			isSupported, err := ocm.CheckSupportedVersion(ocm.GetVersionMinor(ver.ID()), operator.MinVersion())
+			if err != nil {
+				return result, fmt.Errorf("Error validating operator role '%s' version %w", operator.Name(), err)
+			}
+			if !isSupported {
+				continue
+			}
+		}
+		roleName, _ := aws.FindOperatorRoleNameBySTSOperator(cluster, operator)
+		if roleName == "" {
+			return result, fmt.Errorf("Failed to find operator IAM role")
+		}
+
+		var policyARN string
+		filename := aws.GetOperatorPolicyKey(credRequest, hostedCPPolicies, false)
+		if managedPolicies {
+			policyARN, err = aws.GetManagedPolicyARN(policies, filename)
+			if err != nil {
+				return result, err
+			}
+		} else {
+			policyARN = aws.GetOperatorPolicyARN(clients.Creator.AccountID, prefix, operator.Namespace(),
+				operator.Name(), path)
+			policyDetails := aws.GetPolicyDetails(policies, filename)
+
+			operatorPolicyTags := map[string]string{
+				tags.OpenShiftVersion:  accountRoleVersion,
+				tags.RolePrefix:        prefix,
+				tags.RedHatManaged:     helper.True,
+				tags.OperatorNamespace: operator.Namespace(),
+				tags.OperatorName:      operator.Name(),
+			}
+
+			if options.ForcePolicyCreation {
+				policyARN, err = clients.AWS.ForceEnsurePolicy(policyARN, policyDetails,
+					policyVersion, operatorPolicyTags, path)
+			} else {
+				policyARN, err = clients.AWS.EnsurePolicy(policyARN, policyDetails,
+					policyVersion, operatorPolicyTags, path)
+			}
+			if err != nil {
+				return result, err
+			}
+		}
+
+		policyDetails := aws.GetPolicyDetails(policies, "operator_iam_role_policy")
+		policy, err := aws.GenerateOperatorRolePolicyDoc(cluster, clients.Creator.AccountID, operator, policyDetails)
+		if err != nil {
+			return result, err
+		}
+
+		tagsList := map[string]string{
+			tags.OperatorNamespace: operator.Namespace(),
+			tags.OperatorName:      operator.Name(),
+			tags.RedHatManaged:     helper.True,
+		}
+		if !ocm.IsOidcConfigReusable(cluster) {
+			tagsList[tags.ClusterID] = cluster.ID()
+		}
+		if managedPolicies {
+			tagsList[tags.ManagedPolicies] = helper.True
+		}
+		if hostedCPPolicies {
+			tagsList[tags.HypershiftPolicies] = helper.True
+		}
+
+		roleARN, err := clients.AWS.EnsureRole(roleName, policy, options.PermissionsBoundary, accountRoleVersion,
+			tagsList, path, managedPolicies)
+		if err != nil {
+			return result, err
+		}
+		err = clients.AWS.AttachRolePolicy(roleName, policyARN)
+		if err != nil {
+			return result, err
+		}
+		result.Roles = append(result.Roles, OperatorRole{
+			Name:      roleName,
+			ARN:       roleARN,
+			PolicyARN: policyARN,
+			Namespace: operator.Namespace(),
+			Operator:  operator.Name(),
+		})
+	}
+	return result, nil
+}
+
+// MissingOperatorRoles returns the names of the operator roles of the cluster that don't exist in
+// the AWS account.
+func MissingOperatorRoles(awsClient aws.Client, cluster *cmv1.Cluster) ([]string, error) {
+	var missingRoles []string
+	operatorIAMRoles := cluster.AWS().STS().OperatorIAMRoles()
+	if len(operatorIAMRoles) == 0 {
+		return missingRoles, fmt.Errorf("No Operator IAM roles found for cluster %s", cluster.Name())
+	}
+	for _, operatorIAMRole := range operatorIAMRoles {
+		roleName, err := aws.GetResourceIdFromARN(operatorIAMRole.RoleARN())
+		if err != nil {
+			return missingRoles, err
+		}
+		exists, _, err := awsClient.CheckRoleExists(roleName)
+		if err != nil {
+			return missingRoles, err
+		}
+		if !exists {
+			missingRoles = append(missingRoles, roleName)
+		}
+	}
+	return missingRoles, nil
+}
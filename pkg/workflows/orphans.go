@@ -0,0 +1,449 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awscb "github.com/openshift/rosa/pkg/aws/commandbuilder"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+)
+
+// Types of the resources that can be orphaned.
+const (
+	OrphanOperatorRole = "operator-role"
+	OrphanAccountRole  = "account-role"
+	OrphanOidcProvider = "oidc-provider"
+	OrphanOidcConfig   = "oidc-config"
+)
+
+// OrphanTypes are the types of resources that can be orphaned, in the order in which they are
+// deleted: roles first, so that the OIDC providers and configurations they trust are deleted last.
+var OrphanTypes = []string{
+	OrphanOperatorRole,
+	OrphanAccountRole,
+	OrphanOidcProvider,
+	OrphanOidcConfig,
+}
+
+// FindOrphansOptions selects the orphaned resources to find.
+type FindOrphansOptions struct {
+	// Types of the resources to find. All the types are found when empty.
+	Types []string
+
+	// Env is the OCM environment. Only the account roles trusted by this environment are
+	// considered, as the clusters of other environments can't be checked.
+	Env string
+}
+
+// Orphan is a resource created by ROSA that no cluster uses anymore.
+type Orphan struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	ARN       string `json:"arn,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	ClusterID string `json:"cluster_id,omitempty"`
+	Reason    string `json:"reason"`
+
+	// ManagedPolicies is set for the roles that use managed policies, which aren't deleted with
+	// the role.
+	ManagedPolicies bool `json:"managed_policies,omitempty"`
+
+	// IssuerURL, BucketName and SecretARN are set for the OIDC configurations. The bucket and the
+	// secret, which are stored in the region of the secret, only exist for unmanaged
+	// configurations.
+	IssuerURL  string `json:"issuer_url,omitempty"`
+	BucketName string `json:"bucket_name,omitempty"`
+	SecretARN  string `json:"secret_arn,omitempty"`
+	Region     string `json:"region,omitempty"`
+}
+
+// IsRole checks if the orphan is an account or operator role.
+func (o *Orphan) IsRole() bool {
+	return o.Type == OrphanAccountRole || o.Type == OrphanOperatorRole
+}
+
+// FindOrphans returns the operator roles, account roles, OIDC providers and OIDC configurations
+// of the AWS account that no cluster uses, with the reason why each one is considered orphaned.
+// The orphans are sorted in the order in which they should be deleted.
+//
+// Only the clusters visible to the current OCM organization are checked. When other OCM
+// organizations create clusters in the same AWS account, the resources used by those clusters
+// are returned as orphans too, so callers shouldn't delete them without asking the user.
+func FindOrphans(ctx context.Context, clients *Clients, options FindOrphansOptions) ([]*Orphan, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	types := options.Types
+	if len(types) == 0 {
+		types = OrphanTypes
+	}
+	for _, orphanType := range types {
+		if !helper.Contains(OrphanTypes, orphanType) {
+			return nil, fmt.Errorf("Invalid orphan type '%s'. Allowed values are %s", orphanType, OrphanTypes)
+		}
+	}
+
+	result := []*Orphan{}
+	for _, orphanType := range OrphanTypes {
+		if !helper.Contains(types, orphanType) {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+		var orphans []*Orphan
+		switch orphanType {
+		case OrphanOperatorRole:
+			orphans, err = findOrphanOperatorRoles(clients)
+		case OrphanAccountRole:
+			orphans, err = findOrphanAccountRoles(clients, options.Env)
+		case OrphanOidcProvider:
+			orphans, err = findOrphanOidcProviders(clients)
+		case OrphanOidcConfig:
+			orphans, err = findOrphanOidcConfigs(clients)
+		}
+		if err != nil {
+			return result, err
+		}
+		result = append(result, orphans...)
+	}
+	return result, nil
+}
+
+// findOrphanOperatorRoles returns the operator roles whose prefix isn't used by any cluster. Only
+// the roles tagged by ROSA with their operator or cluster are considered.
+func findOrphanOperatorRoles(clients *Clients) ([]*Orphan, error) {
+	rolesByPrefix, err := clients.AWS.ListOperatorRoles("", "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list operator roles: %w", err)
+	}
+	result := []*Orphan{}
+	for _, key := range sortedKeys(rolesByPrefix) {
+		roles := []aws.OperatorRoleDetail{}
+		for _, role := range rolesByPrefix[key] {
+			if role.OperatorNamespace != "" || role.ClusterID != "" {
+				roles = append(roles, role)
+			}
+		}
+		if len(roles) == 0 {
+			continue
+		}
+		// The roles are grouped by the lower case prefix, but clusters reference the roles with
+		// the original case:
+		prefix := roles[0].RoleName[:len(key)]
+		inUse, err := clients.OCM.HasAClusterUsingOperatorRolesPrefix(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check if a cluster uses operator roles prefix '%s': %w", prefix, err)
+		}
+		if inUse {
+			continue
+		}
+		for _, role := range roles {
+			reason := fmt.Sprintf("No cluster visible to this OCM organization uses operator roles with prefix '%s'",
+				prefix)
+			if role.ClusterID != "" {
+				reason = fmt.Sprintf("%s, the role was created for cluster '%s'", reason, role.ClusterID)
+			}
+			result = append(result, &Orphan{
+				Type:            OrphanOperatorRole,
+				Name:            role.RoleName,
+				ARN:             role.RoleARN,
+				Prefix:          prefix,
+				ClusterID:       role.ClusterID,
+				Reason:          reason,
+				ManagedPolicies: role.ManagedPolicy,
+			})
+		}
+	}
+	return result, nil
+}
+
+// findOrphanAccountRoles returns the account roles of the prefixes that no cluster uses. Only the
+// roles tagged by ROSA and trusted by the OCM environment are considered.
+func findOrphanAccountRoles(clients *Clients, env string) ([]*Orphan, error) {
+	envRoles, err := clients.AWS.GetAccountRolesForCurrentEnv(env, clients.Creator.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list account roles: %w", err)
+	}
+	trusted := map[string]bool{}
+	for _, role := range envRoles {
+		trusted[role.RoleName] = true
+	}
+	roles, err := clients.AWS.ListAccountRoles("")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list account roles: %w", err)
+	}
+	rolesByPrefix := map[string][]aws.Role{}
+	for _, role := range roles {
+		prefix := accountRolePrefix(role.RoleName)
+		if prefix == "" || !trusted[role.RoleName] {
+			continue
+		}
+		rolesByPrefix[prefix] = append(rolesByPrefix[prefix], role)
+	}
+
+	result := []*Orphan{}
+	for _, prefix := range sortedKeys(rolesByPrefix) {
+		roleARNs := []string{}
+		for _, role := range rolesByPrefix[prefix] {
+			roleARNs = append(roleARNs, role.RoleARN)
+		}
+		inUse, err := clients.OCM.HasAClusterUsingAccountRoles(roleARNs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check if a cluster uses account roles prefix '%s': %w", prefix, err)
+		}
+		if inUse {
+			continue
+		}
+		reason := fmt.Sprintf("No cluster visible to this OCM organization uses account roles with prefix '%s'",
+			prefix)
+		for _, role := range rolesByPrefix[prefix] {
+			result = append(result, &Orphan{
+				Type:            OrphanAccountRole,
+				Name:            role.RoleName,
+				ARN:             role.RoleARN,
+				Prefix:          prefix,
+				Reason:          reason,
+				ManagedPolicies: role.ManagedPolicy,
+			})
+		}
+	}
+	return result, nil
+}
+
+// accountRolePrefix returns the prefix of an account role name, or an empty string if the name
+// isn't the name of an account role.
+func accountRolePrefix(roleName string) string {
+	// The hosted control plane roles are checked first, as their names end like the names of the
+	// classic roles:
+	for _, accountRoles := range []map[string]aws.AccountRole{aws.HCPAccountRoles, aws.AccountRoles} {
+		for _, accountRole := range accountRoles {
+			suffix := aws.GetRoleName("", accountRole.Name)
+			if strings.HasSuffix(roleName, suffix) && len(roleName) > len(suffix) {
+				return strings.TrimSuffix(roleName, suffix)
+			}
+		}
+	}
+	return ""
+}
+
+// findOrphanOidcProviders returns the OIDC providers created by ROSA that no cluster of the AWS
+// account uses.
+func findOrphanOidcProviders(clients *Clients) ([]*Orphan, error) {
+	providers, err := clients.AWS.ListOidcProviders("")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list OIDC providers: %w", err)
+	}
+	result := []*Orphan{}
+	for _, provider := range providers {
+		resourceID, err := aws.GetResourceIdFromOidcProviderARN(provider.Arn)
+		if err != nil {
+			return nil, err
+		}
+		issuerURL := fmt.Sprintf("https://%s", resourceID)
+		inUse, err := clients.OCM.HasAClusterUsingOidcProvider(issuerURL, clients.Creator.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check if a cluster uses OIDC provider '%s': %w", issuerURL, err)
+		}
+		if inUse {
+			continue
+		}
+		reason := fmt.Sprintf("No cluster of account '%s' visible to this OCM organization uses issuer '%s'",
+			clients.Creator.AccountID, issuerURL)
+		if provider.ClusterId != "" {
+			reason = fmt.Sprintf("%s, the provider was created for cluster '%s'", reason, provider.ClusterId)
+		}
+		result = append(result, &Orphan{
+			Type:      OrphanOidcProvider,
+			Name:      resourceID,
+			ARN:       provider.Arn,
+			ClusterID: provider.ClusterId,
+			Reason:    reason,
+			IssuerURL: issuerURL,
+		})
+	}
+	return result, nil
+}
+
+// findOrphanOidcConfigs returns the unmanaged OIDC configurations stored in the AWS account that
+// no cluster uses. Managed configurations are ignored, as they don't have resources in the
+// account.
+func findOrphanOidcConfigs(clients *Clients) ([]*Orphan, error) {
+	configs, err := clients.OCM.ListOidcConfigs(clients.Creator.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list OIDC configurations: %w", err)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].ID() < configs[j].ID()
+	})
+	result := []*Orphan{}
+	for _, config := range configs {
+		if config.Managed() || config.SecretArn() == "" {
+			continue
+		}
+		secretARN, err := arn.Parse(config.SecretArn())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse secret ARN of OIDC configuration '%s': %w", config.ID(), err)
+		}
+		if secretARN.AccountID != clients.Creator.AccountID {
+			continue
+		}
+		inUse, err := clients.OCM.HasAClusterUsingOidcEndpointUrl(config.IssuerUrl())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check if a cluster uses OIDC configuration '%s': %w", config.ID(), err)
+		}
+		if inUse {
+			continue
+		}
+		bucketName, err := oidc_config.GetBucketNameFromSecretArn(config.SecretArn())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &Orphan{
+			Type:       OrphanOidcConfig,
+			Name:       config.ID(),
+			Reason:     fmt.Sprintf("No cluster visible to this OCM organization uses issuer '%s'", config.IssuerUrl()),
+			IssuerURL:  config.IssuerUrl(),
+			BucketName: bucketName,
+			SecretARN:  config.SecretArn(),
+			Region:     secretARN.Region,
+		})
+	}
+	return result, nil
+}
+
+// DeleteOrphan deletes an orphaned resource. OIDC configurations are deleted from AWS and from
+// OCM, and can only be deleted when the AWS client uses the region of their secret.
+func DeleteOrphan(ctx context.Context, clients *Clients, orphan *Orphan) error {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return err
+	}
+	switch orphan.Type {
+	case OrphanOperatorRole:
+		err = clients.AWS.DeleteOperatorRole(orphan.Name, orphan.ManagedPolicies)
+	case OrphanAccountRole:
+		err = clients.AWS.DeleteAccountRole(orphan.Name, orphan.ManagedPolicies)
+	case OrphanOidcProvider:
+		err = clients.AWS.DeleteOpenIDConnectProvider(orphan.ARN)
+	case OrphanOidcConfig:
+		err = deleteOrphanOidcConfig(clients, orphan)
+	default:
+		err = fmt.Errorf("Invalid orphan type '%s'. Allowed values are %s", orphan.Type, OrphanTypes)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to delete %s '%s': %w", orphan.Type, orphan.Name, err)
+	}
+	return nil
+}
+
+func deleteOrphanOidcConfig(clients *Clients, orphan *Orphan) error {
+	region := clients.AWS.GetRegion()
+	if orphan.Region != region {
+		return fmt.Errorf("the secret is stored in region '%s' but region '%s' is used, "+
+			"run the command with '--region %s'", orphan.Region, region, orphan.Region)
+	}
+	err := clients.AWS.DeleteSecretInSecretsManager(orphan.SecretARN)
+	if err != nil {
+		return err
+	}
+	err = clients.AWS.DeleteS3Bucket(orphan.BucketName)
+	if err != nil {
+		return err
+	}
+	return clients.OCM.DeleteOidcConfig(orphan.Name)
+}
+
+// OrphanCommands returns the AWS CLI commands that delete an orphaned resource. OIDC configurations
+// also have to be deleted from OCM, which can't be done with these commands.
+func OrphanCommands(clients *Clients, orphan *Orphan) ([]string, error) {
+	switch orphan.Type {
+	case OrphanOperatorRole, OrphanAccountRole:
+		return orphanRoleCommands(clients, orphan)
+	case OrphanOidcProvider:
+		return []string{
+			awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeleteOpenIdConnectProvider).
+				AddParam(awscb.OpenIdConnectProviderArn, orphan.ARN).
+				Build(),
+		}, nil
+	case OrphanOidcConfig:
+		return []string{
+			awscb.NewSecretsManagerCommandBuilder().
+				SetCommand(awscb.DeleteSecret).
+				AddParam(awscb.SecretID, orphan.SecretARN).
+				AddParam(awscb.Region, orphan.Region).
+				Build(),
+			awscb.NewS3CommandBuilder().
+				SetCommand(awscb.Remove).
+				AddValueNoParam(fmt.Sprintf("s3://%s", orphan.BucketName)).
+				AddParamNoValue(awscb.Recursive).
+				Build(),
+			awscb.NewS3CommandBuilder().
+				SetCommand(awscb.RemoveBucket).
+				AddValueNoParam(fmt.Sprintf("s3://%s", orphan.BucketName)).
+				Build(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("Invalid orphan type '%s'. Allowed values are %s", orphan.Type, OrphanTypes)
+	}
+}
+
+func orphanRoleCommands(clients *Clients, orphan *Orphan) ([]string, error) {
+	policies, err := clients.AWS.GetAttachedPolicy(&orphan.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the policies of role '%s': %w", orphan.Name, err)
+	}
+	commands := []string{}
+	for _, policy := range policies {
+		if policy.PolicyType == aws.Inline && policy.PolicyName != "" {
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeleteRolePolicy).
+				AddParam(awscb.RoleName, orphan.Name).
+				AddParam(awscb.PolicyName, policy.PolicyName).
+				Build())
+		}
+		if policy.PolicyType != aws.Attached || policy.PolicyArn == "" {
+			continue
+		}
+		commands = append(commands, awscb.NewIAMCommandBuilder().
+			SetCommand(awscb.DetachRolePolicy).
+			AddParam(awscb.RoleName, orphan.Name).
+			AddParam(awscb.PolicyArn, policy.PolicyArn).
+			Build())
+		if !orphan.ManagedPolicies {
+			commands = append(commands, awscb.NewIAMCommandBuilder().
+				SetCommand(awscb.DeletePolicy).
+				AddParam(awscb.PolicyArn, policy.PolicyArn).
+				Build())
+		}
+	}
+	commands = append(commands, awscb.NewIAMCommandBuilder().
+		SetCommand(awscb.DeleteRole).
+		AddParam(awscb.RoleName, orphan.Name).
+		Build())
+	return commands, nil
+}
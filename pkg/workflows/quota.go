@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that checks the AWS quotas needed by a cluster or machine pool.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/exitcode"
+)
+
+// DefaultComputeRootDiskSize is the size in GiB of the root disks of the compute nodes when it
+// isn't given.
+const DefaultComputeRootDiskSize = 300
+
+// Nodes and root disks that a classic cluster creates besides the compute nodes.
+const (
+	controlPlaneNodes          = 3
+	controlPlaneRootDiskSize   = 350
+	controlPlaneRootDiskIOPS   = 1000
+	infraRootDiskSize          = 300
+	bootstrapMachineType       = "m5.xlarge"
+	bootstrapRootDiskSize      = 120
+	singleAZInfraNodes         = 2
+	multiAZInfraNodes          = 3
+	singleAZNatGateways        = 1
+	multiAZNatGateways         = 3
+	publicAPILoadBalancers     = 2
+	privateAPILoadBalancers    = 1
+	defaultIngressLoadBalancer = 1
+)
+
+// QuotaOptions describe the cluster or the machine pool whose quotas are checked by the
+// VerifyQuota workflow.
+type QuotaOptions struct {
+	// Cluster adds the control plane and infra nodes, the network and the load balancers of a new
+	// cluster to the compute nodes. Without it only the compute nodes are checked, like for a new
+	// machine pool.
+	Cluster bool
+
+	Hypershift bool
+	MultiAZ    bool
+	Private    bool
+
+	// ExistingVPC is set when the cluster is installed in existing subnets, so that it doesn't
+	// create a VPC, an internet gateway or NAT gateways.
+	ExistingVPC bool
+
+	ComputeMachineType string
+	ComputeNodes       int
+
+	// ComputeRootDiskSize is in GiB. DefaultComputeRootDiskSize is used when it is zero.
+	ComputeRootDiskSize int
+}
+
+// VerifyQuota returns the checks of the AWS quotas needed by the cluster or the machine pool
+// described by the options, comparing the required amounts with the current usage and the limits
+// of the quotas in the region of the AWS client. The vCPUs of the instance types are taken from
+// the machine types catalogue of OCM.
+func VerifyQuota(ctx context.Context, clients *Clients, options QuotaOptions) ([]*aws.QuotaCheck, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := BuildQuotaSpec(clients, options)
+	if err != nil {
+		return nil, err
+	}
+	return clients.AWS.ValidateQuotaForSpec(*spec)
+}
+
+// BuildQuotaSpec returns the resources that the cluster or the machine pool described by the
+// options will create in the account. The control plane and infra nodes of a classic cluster are
+// sized like the service does it for the number of compute nodes.
+func BuildQuotaSpec(clients *Clients, options QuotaOptions) (*aws.QuotaSpec, error) {
+	if options.ComputeMachineType == "" {
+		return nil, exitcode.InvalidInput.Errorf("Compute machine type is mandatory")
+	}
+	if options.ComputeNodes < 0 {
+		return nil, exitcode.InvalidInput.Errorf("The number of compute nodes can't be negative")
+	}
+	rootDiskSize := options.ComputeRootDiskSize
+	if rootDiskSize == 0 {
+		rootDiskSize = DefaultComputeRootDiskSize
+	}
+
+	machineTypes, err := clients.OCM.GetAvailableMachineTypes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine types: %w", err)
+	}
+	vcpus := func(machineType string) (int, error) {
+		found := machineTypes.Find(machineType)
+		if found == nil {
+			return 0, exitcode.InvalidInput.Errorf("Machine type '%s' not found", machineType)
+		}
+		return int(found.MachineType.CPU().Value()), nil
+	}
+	nodes := func(machineType string, count int, volumeType string, volumeSize int,
+		volumeIOPS int) (aws.QuotaNodes, error) {
+		machineTypeVCPUs, err := vcpus(machineType)
+		if err != nil {
+			return aws.QuotaNodes{}, err
+		}
+		return aws.QuotaNodes{
+			InstanceType: machineType,
+			VCPUs:        machineTypeVCPUs,
+			Count:        count,
+			VolumeType:   volumeType,
+			VolumeSize:   volumeSize,
+			VolumeIOPS:   volumeIOPS,
+		}, nil
+	}
+
+	spec := &aws.QuotaSpec{}
+	compute, err := nodes(options.ComputeMachineType, options.ComputeNodes, aws.VolumeTypeGP3, rootDiskSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	spec.Nodes = append(spec.Nodes, compute)
+	if !options.Cluster {
+		return spec, nil
+	}
+
+	if options.Hypershift {
+		// The control plane of a hosted cluster runs in the service account, and the cluster is
+		// always installed in existing subnets
+		spec.NetworkLoadBalancers = defaultIngressLoadBalancer
+		return spec, nil
+	}
+
+	infraNodes := singleAZInfraNodes
+	natGateways := singleAZNatGateways
+	if options.MultiAZ {
+		infraNodes = multiAZInfraNodes
+		natGateways = multiAZNatGateways
+	}
+	for _, extra := range []struct {
+		machineType string
+		count       int
+		volumeType  string
+		volumeSize  int
+		volumeIOPS  int
+	}{
+		{controlPlaneMachineType(options.ComputeNodes), controlPlaneNodes, aws.VolumeTypeIO1,
+			controlPlaneRootDiskSize, controlPlaneRootDiskIOPS},
+		{infraMachineType(options.ComputeNodes), infraNodes, aws.VolumeTypeGP3, infraRootDiskSize, 0},
+		{bootstrapMachineType, 1, aws.VolumeTypeGP3, bootstrapRootDiskSize, 0},
+	} {
+		extraNodes, err := nodes(extra.machineType, extra.count, extra.volumeType, extra.volumeSize,
+			extra.volumeIOPS)
+		if err != nil {
+			return nil, err
+		}
+		spec.Nodes = append(spec.Nodes, extraNodes)
+	}
+
+	if !options.ExistingVPC {
+		spec.VPCs = 1
+		spec.InternetGateways = 1
+		spec.NatGateways = natGateways
+	}
+	spec.NetworkLoadBalancers = publicAPILoadBalancers
+	if options.Private {
+		spec.NetworkLoadBalancers = privateAPILoadBalancers
+	}
+	spec.ClassicLoadBalancers = defaultIngressLoadBalancer
+	return spec, nil
+}
+
+// controlPlaneMachineType returns the instance type of the control plane nodes of a classic
+// cluster with the given number of compute nodes.
+func controlPlaneMachineType(computeNodes int) string {
+	switch {
+	case computeNodes <= 25:
+		return "m5.2xlarge"
+	case computeNodes <= 100:
+		return "m5.4xlarge"
+	case computeNodes <= 180:
+		return "m5.8xlarge"
+	default:
+		return "m5.12xlarge"
+	}
+}
+
+// infraMachineType returns the instance type of the infra nodes of a classic cluster with the
+// given number of compute nodes.
+func infraMachineType(computeNodes int) string {
+	switch {
+	case computeNodes <= 25:
+		return "r5.xlarge"
+	case computeNodes <= 100:
+		return "r5.2xlarge"
+	default:
+		return "r5.4xlarge"
+	}
+}
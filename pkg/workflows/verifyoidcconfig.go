@@ -0,0 +1,361 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that verifies the documents of an OIDC configuration and the
+// IAM resources that trust it.
+
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/helper"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Names of the checks run on OIDC configurations.
+const (
+	OidcCheckReachability      = "reachability"
+	OidcCheckDiscoveryDocument = "discovery-document"
+	OidcCheckKeys              = "keys"
+	OidcCheckProvider          = "oidc-provider"
+	OidcCheckOperatorRoles     = "operator-roles"
+)
+
+// VerifyOidcConfigOptions selects the OIDC configuration to verify, either by the ID of a
+// configuration registered in OCM or by its issuer URL.
+type VerifyOidcConfigOptions struct {
+	OidcConfigID string
+	IssuerURL    string
+
+	// OperatorRolesPrefix selects the operator roles whose trust policies must trust the issuer.
+	// The operator roles aren't checked when it is empty.
+	OperatorRolesPrefix string
+
+	// HTTPClient is used to download the documents of the issuer. The default client is used when
+	// it is nil.
+	HTTPClient *http.Client
+}
+
+// OidcConfigVerification contains the issues found in an OIDC configuration.
+type OidcConfigVerification struct {
+	IssuerURL   string             `json:"issuer_url"`
+	ProviderARN string             `json:"provider_arn,omitempty"`
+	Thumbprint  string             `json:"thumbprint,omitempty"`
+	KeyIDs      []string           `json:"key_ids,omitempty"`
+	Issues      []*OidcConfigIssue `json:"issues"`
+}
+
+// OidcConfigIssue is a problem found in an OIDC configuration, with the commands that fix it.
+type OidcConfigIssue struct {
+	Check       string   `json:"check"`
+	Message     string   `json:"message"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// HasIssues returns true if any check failed.
+func (v *OidcConfigVerification) HasIssues() bool {
+	return len(v.Issues) > 0
+}
+
+func (v *OidcConfigVerification) addIssue(check string, message string, remediation ...string) {
+	v.Issues = append(v.Issues, &OidcConfigIssue{
+		Check:       check,
+		Message:     message,
+		Remediation: remediation,
+	})
+}
+
+// discoveryDocument contains the fields of the discovery document that are checked.
+type discoveryDocument struct {
+	Issuer           string   `json:"issuer"`
+	JwksURI          string   `json:"jwks_uri"`
+	ResponseTypes    []string `json:"response_types_supported"`
+	SigningAlgValues []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// VerifyOidcConfig downloads the discovery document and the key set of the issuer, checks that
+// they can be used by AWS STS to verify the service account tokens, and checks that the OIDC
+// provider and the operator roles trust the issuer. Problems with the configuration are returned
+// as issues of the result, and errors are only returned when the checks can't be run.
+func VerifyOidcConfig(ctx context.Context, clients *Clients,
+	options VerifyOidcConfigOptions) (*OidcConfigVerification, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	issuerURL := options.IssuerURL
+	secretARN := ""
+	createProviderCommand := fmt.Sprintf("rosa create oidc-provider --oidc-endpoint-url %s", issuerURL)
+	if options.OidcConfigID != "" {
+		oidcConfig, err := clients.OCM.GetOidcConfig(options.OidcConfigID)
+		if err != nil {
+			return nil, fmt.Errorf("There was a problem retrieving the OIDC Config '%s': %w",
+				options.OidcConfigID, err)
+		}
+		issuerURL = oidcConfig.IssuerUrl()
+		if !oidcConfig.Managed() {
+			secretARN = oidcConfig.SecretArn()
+		}
+		createProviderCommand = fmt.Sprintf("rosa create oidc-provider --oidc-config-id %s", options.OidcConfigID)
+	}
+	if issuerURL == "" {
+		return nil, fmt.Errorf("Either an OIDC Config ID or an issuer URL is mandatory")
+	}
+	issuerURL = strings.TrimSuffix(issuerURL, "/")
+	parsedIssuerURL, err := url.ParseRequestURI(issuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("Expected a valid issuer URL: %w", err)
+	}
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	result := &OidcConfigVerification{
+		IssuerURL: issuerURL,
+		Issues:    []*OidcConfigIssue{},
+	}
+	if parsedIssuerURL.Scheme != helper.ProtocolHttps {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			fmt.Sprintf("Issuer URL '%s' must use the 'https' scheme", issuerURL))
+	}
+	keySet := verifyIssuerDocuments(ctx, httpClient, result)
+	if keySet != nil && secretARN != "" {
+		err = verifySecretKey(clients, secretARN, keySet, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = verifyOidcProvider(clients, parsedIssuerURL, createProviderCommand, result)
+	if err != nil {
+		return nil, err
+	}
+	if options.OperatorRolesPrefix != "" {
+		err = verifyOperatorRolesTrust(clients, options.OperatorRolesPrefix, parsedIssuerURL, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// verifyIssuerDocuments checks the discovery document and the key set published by the issuer,
+// and returns the key set if it could be downloaded.
+func verifyIssuerDocuments(ctx context.Context, httpClient *http.Client,
+	result *OidcConfigVerification) *oidc_config.JSONWebKeySet {
+	discoveryURL := fmt.Sprintf("%s/%s", result.IssuerURL, DiscoveryDocumentKey)
+	body, response, err := download(ctx, httpClient, discoveryURL)
+	if err != nil {
+		result.addIssue(OidcCheckReachability, err.Error())
+		return nil
+	}
+	if response.TLS != nil && len(response.TLS.PeerCertificates) > 0 {
+		result.Thumbprint = thumbprintOfChain(response.TLS.PeerCertificates)
+	}
+
+	document := &discoveryDocument{}
+	err = json.Unmarshal(body, document)
+	if err != nil {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			fmt.Sprintf("Discovery document '%s' is not valid JSON: %v", discoveryURL, err))
+		return nil
+	}
+	if strings.TrimSuffix(document.Issuer, "/") != result.IssuerURL {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			fmt.Sprintf("Discovery document declares issuer '%s' instead of '%s'", document.Issuer, result.IssuerURL))
+	}
+	if !helper.Contains(document.SigningAlgValues, "RS256") {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			"Discovery document doesn't declare 'RS256' as supported ID token signing algorithm")
+	}
+	if !helper.Contains(document.ResponseTypes, "id_token") {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			"Discovery document doesn't declare 'id_token' as supported response type")
+	}
+	if document.JwksURI == "" {
+		result.addIssue(OidcCheckDiscoveryDocument, "Discovery document doesn't declare the 'jwks_uri'")
+		return nil
+	}
+	if !strings.HasPrefix(document.JwksURI, helper.ProtocolHttps+"://") {
+		result.addIssue(OidcCheckDiscoveryDocument,
+			fmt.Sprintf("JSON Web Key Set URI '%s' must use the 'https' scheme", document.JwksURI))
+	}
+
+	body, _, err = download(ctx, httpClient, document.JwksURI)
+	if err != nil {
+		result.addIssue(OidcCheckReachability, err.Error())
+		return nil
+	}
+	keySet, err := oidc_config.ParseJSONWebKeySet(body)
+	if err != nil {
+		result.addIssue(OidcCheckKeys, fmt.Sprintf("JSON Web Key Set '%s' is not valid: %v", document.JwksURI, err))
+		return nil
+	}
+	result.KeyIDs = keySet.KeyIDs()
+	if len(keySet.Keys) == 0 {
+		result.addIssue(OidcCheckKeys, fmt.Sprintf("JSON Web Key Set '%s' doesn't contain any key", document.JwksURI))
+	}
+	seen := map[string]bool{}
+	for _, key := range keySet.Keys {
+		if seen[key.KeyID] {
+			result.addIssue(OidcCheckKeys, fmt.Sprintf("Key ID '%s' is used by more than one key", key.KeyID))
+		}
+		seen[key.KeyID] = true
+		err = oidc_config.ValidateJSONWebKey(key)
+		if err != nil {
+			result.addIssue(OidcCheckKeys, err.Error())
+		}
+	}
+	return keySet
+}
+
+// download returns the body of a successful response to a GET request.
+func download(ctx context.Context, httpClient *http.Client, documentURL string) ([]byte, *http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to download '%s': %v", documentURL, err)
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to download '%s': %v", documentURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, response, fmt.Errorf("Failed to download '%s': unexpected status '%s'",
+			documentURL, response.Status)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, response, fmt.Errorf("Failed to download '%s': %v", documentURL, err)
+	}
+	return body, response, nil
+}
+
+// verifySecretKey checks that the key stored in the secret of an unmanaged configuration is
+// published, as the tokens signed with it can't be verified otherwise. The secret can only be
+// read when the AWS client uses its region, so the check is skipped for other regions.
+func verifySecretKey(clients *Clients, secretARN string, keySet *oidc_config.JSONWebKeySet,
+	result *OidcConfigVerification) error {
+	parsedSecretARN, err := arn.Parse(secretARN)
+	if err != nil {
+		return fmt.Errorf("There was a problem parsing secret ARN '%s': %w", secretARN, err)
+	}
+	if parsedSecretARN.Region != clients.AWS.GetRegion() {
+		return nil
+	}
+	privateKey, err := clients.AWS.GetSecretFromSecretsManager(secretARN)
+	if err != nil {
+		return fmt.Errorf("There was a problem reading private key from secrets manager: %w", err)
+	}
+	publicKey, err := oidc_config.GetPublicKey([]byte(privateKey))
+	if err != nil {
+		result.addIssue(OidcCheckKeys, fmt.Sprintf("Secret '%s' doesn't contain a valid private key: %v",
+			secretARN, err))
+		return nil
+	}
+	jwks, err := json.Marshal(keySet)
+	if err != nil {
+		return err
+	}
+	_, err = oidc_config.RetainJSONWebKey(jwks, publicKey)
+	if err != nil {
+		result.addIssue(OidcCheckKeys, fmt.Sprintf("The key stored in secret '%s' is not published: %v",
+			secretARN, err))
+	}
+	return nil
+}
+
+// verifyOidcProvider checks that the account has an OIDC provider for the issuer, that it allows
+// the audiences used by the cluster and that it has the thumbprint of the certificate of the
+// issuer.
+func verifyOidcProvider(clients *Clients, issuerURL *url.URL, createProviderCommand string,
+	result *OidcConfigVerification) error {
+	providerARN, err := clients.AWS.GetOpenIDConnectProviderByOidcEndpointUrl(result.IssuerURL)
+	if err != nil {
+		return fmt.Errorf("Failed to find OIDC provider for issuer '%s': %w", result.IssuerURL, err)
+	}
+	if providerARN == "" {
+		result.addIssue(OidcCheckProvider,
+			fmt.Sprintf("There is no OIDC provider for issuer '%s'", result.IssuerURL), createProviderCommand)
+		return nil
+	}
+	result.ProviderARN = providerARN
+	provider, err := clients.AWS.GetOpenIDConnectProvider(providerARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get OIDC provider '%s': %w", providerARN, err)
+	}
+	providerURL := issuerURL.Host + issuerURL.Path
+	if provider.URL != providerURL {
+		result.addIssue(OidcCheckProvider,
+			fmt.Sprintf("OIDC provider '%s' has URL '%s' instead of '%s'", providerARN, provider.URL, providerURL),
+			createProviderCommand)
+	}
+	for _, clientID := range []string{aws.OIDCClientIDOpenShift, aws.OIDCClientIDSTSAWS} {
+		if !helper.Contains(provider.ClientIDs, clientID) {
+			result.addIssue(OidcCheckProvider,
+				fmt.Sprintf("OIDC provider '%s' doesn't allow client ID '%s'", providerARN, clientID),
+				fmt.Sprintf("aws iam add-client-id-to-open-id-connect-provider "+
+					"--open-id-connect-provider-arn %s --client-id %s", providerARN, clientID))
+		}
+	}
+	if result.Thumbprint != "" && !helper.Contains(provider.Thumbprints, result.Thumbprint) {
+		result.addIssue(OidcCheckProvider,
+			fmt.Sprintf("OIDC provider '%s' doesn't have the thumbprint '%s' of the certificate of the issuer",
+				providerARN, result.Thumbprint),
+			fmt.Sprintf("aws iam update-open-id-connect-provider-thumbprint "+
+				"--open-id-connect-provider-arn %s --thumbprint-list %s", providerARN, result.Thumbprint))
+	}
+	return nil
+}
+
+// verifyOperatorRolesTrust checks that the operator roles created with the prefix trust the
+// issuer.
+func verifyOperatorRolesTrust(clients *Clients, prefix string, issuerURL *url.URL,
+	result *OidcConfigVerification) error {
+	rolesByPrefix, err := clients.AWS.ListOperatorRoles("", "")
+	if err != nil {
+		return fmt.Errorf("Failed to list operator roles: %w", err)
+	}
+	roles := rolesByPrefix[strings.ToLower(prefix)]
+	if len(roles) == 0 {
+		result.addIssue(OidcCheckOperatorRoles, fmt.Sprintf("There are no operator roles with prefix '%s'", prefix))
+		return nil
+	}
+	for _, operatorRole := range roles {
+		role, err := clients.AWS.GetRoleByARN(operatorRole.RoleARN)
+		if err != nil {
+			return fmt.Errorf("Failed to get operator role '%s': %w", operatorRole.RoleARN, err)
+		}
+		err = ocm.ValidateIssuerUrlMatchesAssumePolicyDocument(
+			operatorRole.RoleARN, issuerURL, awssdk.StringValue(role.AssumeRolePolicyDocument))
+		if err != nil {
+			result.addIssue(OidcCheckOperatorRoles, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the workflow that checks if a permissions boundary or service control
+// policies allow the permissions needed by the account and operator roles.
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+// VerifyPermissionsOptions contains the policy documents that limit the permissions of the
+// roles. The region is used as the value of the 'aws:RequestedRegion' condition key.
+type VerifyPermissionsOptions struct {
+	PermissionsBoundary string
+	SCPs                []string
+	Region              string
+}
+
+// DeniedPermission is a permission needed by a role policy that the permissions boundary or the
+// service control policies don't allow.
+type DeniedPermission struct {
+	Policy   string             `json:"policy"`
+	Action   string             `json:"action"`
+	Resource string             `json:"resource"`
+	Decision aws.PolicyDecision `json:"decision"`
+	DeniedBy string             `json:"denied_by"`
+}
+
+// VerifyPermissions evaluates the permissions of the account role and operator role policies
+// published by OCM against the permissions boundary and the service control policies, without
+// calling AWS. Each action and resource allowed by the policies is evaluated as a request, using
+// the values required by the conditions of the statement as the request context.
+func VerifyPermissions(ctx context.Context, clients *Clients,
+	options VerifyPermissionsOptions) ([]*DeniedPermission, error) {
+	err := clients.validate(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if options.PermissionsBoundary == "" && len(options.SCPs) == 0 {
+		return nil, fmt.Errorf("Either a permissions boundary or a service control policy is mandatory")
+	}
+	policySet := &aws.PolicySet{}
+	if options.PermissionsBoundary != "" {
+		policySet.PermissionsBoundary, err = aws.ParsePolicyDocument(options.PermissionsBoundary)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse permissions boundary: %w", err)
+		}
+	}
+	for _, scp := range options.SCPs {
+		document, err := aws.ParsePolicyDocument(scp)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse service control policy: %w", err)
+		}
+		policySet.SCPs = append(policySet.SCPs, document)
+	}
+
+	policyKeys, policies, err := rolePolicies(clients)
+	if err != nil {
+		return nil, err
+	}
+	result := []*DeniedPermission{}
+	for _, key := range policyKeys {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		document, err := aws.ParsePolicyDocument(aws.InterpolatePolicyDocument(policies[key], nil))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse policy '%s': %w", key, err)
+		}
+		policySet.Identity = []*aws.PolicyDocument{document}
+		denied, err := evaluatePolicyPermissions(policySet, key, document, options.Region)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to evaluate policy '%s': %w", key, err)
+		}
+		result = append(result, denied...)
+	}
+	return result, nil
+}
+
+// rolePolicies returns the documents of the permission policies of the classic account roles and
+// of the operator roles, and their keys in the order in which they are evaluated.
+func rolePolicies(clients *Clients) ([]string, map[string]string, error) {
+	accountPolicies, err := clients.OCM.GetPolicies("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get account role policies: %w", err)
+	}
+	operatorPolicies, err := clients.OCM.GetPolicies("OperatorRole")
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get operator role policies: %w", err)
+	}
+	credRequests, err := clients.OCM.GetCredRequests(false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get operator credential requests: %w", err)
+	}
+
+	keys := []string{}
+	policies := map[string]string{}
+	for _, roleType := range sortedKeys(aws.AccountRoles) {
+		key := fmt.Sprintf("sts_%s_permission_policy", roleType)
+		details := aws.GetPolicyDetails(accountPolicies, key)
+		if details == "" {
+			return nil, nil, fmt.Errorf("Failed to find policy '%s'", key)
+		}
+		keys = append(keys, key)
+		policies[key] = details
+	}
+	for _, credRequest := range sortedKeys(credRequests) {
+		key := aws.GetOperatorPolicyKey(credRequest, false, false)
+		details := aws.GetPolicyDetails(operatorPolicies, key)
+		if details == "" {
+			return nil, nil, fmt.Errorf("Failed to find policy '%s'", key)
+		}
+		keys = append(keys, key)
+		policies[key] = details
+	}
+	return keys, policies, nil
+}
+
+// isPositiveOperator checks if the condition operator requires the key to have the values of the
+// condition, so that they can be used as the request context.
+func isPositiveOperator(operator string) bool {
+	operator = strings.TrimPrefix(operator, "ForAllValues:")
+	operator = strings.TrimPrefix(operator, "ForAnyValue:")
+	operator = strings.TrimSuffix(operator, "IfExists")
+	if strings.Contains(operator, "Not") {
+		return false
+	}
+	return strings.HasPrefix(operator, "String") || strings.HasPrefix(operator, "Arn") || operator == "Bool"
+}
+
+// evaluatePolicyPermissions evaluates each action and resource allowed by the statements of the
+// policy.
+func evaluatePolicyPermissions(policySet *aws.PolicySet, key string, document *aws.PolicyDocument,
+	region string) ([]*DeniedPermission, error) {
+	result := []*DeniedPermission{}
+	for _, statement := range document.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		values := map[string][]string{}
+		if region != "" {
+			values["aws:RequestedRegion"] = []string{region}
+		}
+		for operator, keys := range statement.Condition {
+			if !isPositiveOperator(operator) {
+				continue
+			}
+			for conditionKey, value := range keys {
+				values[conditionKey] = append(values[conditionKey], aws.GetStringList(value)...)
+			}
+		}
+		requestContext := aws.NewPolicyRequestContext(values)
+		resources := aws.GetStringList(statement.Resource)
+		if len(resources) == 0 {
+			resources = []string{"*"}
+		}
+		for _, action := range aws.GetStringList(statement.Action) {
+			for _, resource := range resources {
+				evaluation, err := policySet.Evaluate(aws.PolicyRequest{
+					Action:   action,
+					Resource: resource,
+					Context:  requestContext,
+				})
+				if err != nil {
+					return nil, err
+				}
+				// Requests that the policy itself doesn't allow, because of conditions that the
+				// request context doesn't satisfy, can't be attributed to the other policies:
+				if evaluation.Decision == aws.PolicyDecisionAllowed ||
+					evaluation.DeniedBy == aws.PolicyKindIdentity {
+					continue
+				}
+				result = append(result, &DeniedPermission{
+					Policy:   key,
+					Action:   action,
+					Resource: resource,
+					Decision: evaluation.Decision,
+					DeniedBy: evaluation.DeniedBy,
+				})
+			}
+		}
+	}
+	return result, nil
+}
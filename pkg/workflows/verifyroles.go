@@ -0,0 +1,516 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// VerifyRolesOptions selects the roles to verify: the roles of a cluster, or the roles created
+// with a prefix. The OCM and user roles linked to the current organization and account are always
+// verified.
+type VerifyRolesOptions struct {
+	Cluster *cmv1.Cluster
+
+	// Prefix of the account and operator roles, used when there is no cluster. HostedCP selects
+	// the hosted control plane roles, and the OIDC configuration, when given, is used to check
+	// the trust policies of the operator roles.
+	Prefix       string
+	HostedCP     bool
+	OidcConfigID string
+
+	Env string
+
+	// PermissionsBoundary is only checked when CheckPermissionsBoundary is set.
+	PermissionsBoundary      string
+	CheckPermissionsBoundary bool
+
+	// Version is the OpenShift version that the unmanaged policies must support. The version of
+	// the cluster is used when it is empty.
+	Version string
+}
+
+// expectedRole adds to the expected role the OIDC issuer that an operator role must trust.
+type expectedRole struct {
+	aws.ExpectedRole
+	issuerURL string
+}
+
+// VerifyRoles compares the roles with the roles expected by OCM, and returns the differences found
+// for each role, including the roles without differences.
+func VerifyRoles(ctx context.Context, clients *Clients, options VerifyRolesOptions) ([]*aws.RoleDrift, error) {
+	err := clients.validate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if options.Cluster == nil && options.Prefix == "" {
+		return nil, fmt.Errorf("Either a cluster or a prefix is mandatory")
+	}
+	policies, err := clients.OCM.GetPolicies("")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get policies: %w", err)
+	}
+
+	var roles []*expectedRole
+	if options.Cluster != nil {
+		roles, err = expectedClusterRoles(clients, options, policies)
+	} else {
+		roles, err = expectedPrefixRoles(clients, options, policies)
+	}
+	if err != nil {
+		return nil, err
+	}
+	linkedRoles, err := expectedLinkedRoles(clients, options, policies)
+	if err != nil {
+		return nil, err
+	}
+	roles = append(roles, linkedRoles...)
+
+	result := []*aws.RoleDrift{}
+	for _, role := range roles {
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+		role.PermissionsBoundary = options.PermissionsBoundary
+		role.CheckPermissionsBoundary = options.CheckPermissionsBoundary
+		drift, err := clients.AWS.VerifyRole(role.ExpectedRole)
+		if err != nil {
+			return result, fmt.Errorf("Failed to verify role '%s': %w", role.Name, err)
+		}
+		if role.issuerURL != "" && drift.RoleARN != "" {
+			issuerURL, err := url.Parse(role.issuerURL)
+			if err != nil {
+				return result, err
+			}
+			err = ocm.ValidateIssuerUrlMatchesAssumePolicyDocument(drift.RoleARN, issuerURL, drift.TrustPolicy)
+			if err != nil {
+				drift.AddIssue(aws.DriftCheckTrustPolicy, err.Error(), role.CreateCommand)
+			}
+		}
+		result = append(result, drift)
+	}
+	return result, nil
+}
+
+// expectedClusterRoles returns the account and operator roles used by the cluster.
+func expectedClusterRoles(clients *Clients, options VerifyRolesOptions,
+	policies map[string]*cmv1.AWSSTSPolicy) ([]*expectedRole, error) {
+	cluster := options.Cluster
+	if cluster.AWS().STS().RoleARN() == "" {
+		return nil, fmt.Errorf("Cluster '%s' is not an STS cluster", cluster.Name())
+	}
+	version := options.Version
+	if version == "" {
+		version = ocm.GetVersionMinor(cluster.Version().RawID())
+	}
+	hostedCP := cluster.Hypershift().Enabled()
+	accountManaged := cluster.AWS().STS().ManagedPolicies() || hostedCP
+	prefix, err := aws.GetPrefixFromAccountRole(cluster, aws.AccountRoles[aws.InstallerAccountRole].Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*expectedRole{}
+	roleARNs := aws.GetAccountRolesArnsMap(cluster)
+	for _, roleType := range sortedKeys(aws.AccountRoles) {
+		roleARN := roleARNs[aws.AccountRoles[roleType].Name]
+		if roleARN == "" {
+			continue
+		}
+		name, err := aws.GetResourceIdFromARN(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		path, err := aws.GetPathFromARN(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		role, err := expectedAccountRole(clients, options, policies, roleType, name, path,
+			prefix, hostedCP, accountManaged)
+		if err != nil {
+			return nil, err
+		}
+		role.Version = version
+		result = append(result, role)
+	}
+
+	credRequests, err := clients.OCM.GetCredRequests(hostedCP)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting operator credential request from OCM %w", err)
+	}
+	operatorPolicyPrefix, err := aws.GetOperatorRolePolicyPrefixFromCluster(cluster, clients.AWS)
+	if err != nil {
+		return nil, err
+	}
+	operatorManaged := cluster.AWS().STS().ManagedPolicies()
+	hostedCPPolicies := aws.IsHostedCPManagedPolicies(cluster)
+	for _, credRequest := range sortedKeys(credRequests) {
+		operator := credRequests[credRequest]
+		roleARN := aws.FindOperatorRoleBySTSOperator(cluster.AWS().STS().OperatorIAMRoles(), operator)
+		if roleARN == "" {
+			continue
+		}
+		name, err := aws.GetResourceIdFromARN(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		path, err := aws.GetPathFromARN(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		trustPolicy, err := aws.GenerateOperatorRolePolicyDoc(cluster, clients.Creator.AccountID, operator,
+			aws.GetPolicyDetails(policies, "operator_iam_role_policy"))
+		if err != nil {
+			return nil, err
+		}
+		role := &expectedRole{
+			ExpectedRole: aws.ExpectedRole{
+				Name:           name,
+				Type:           fmt.Sprintf("%s/%s", operator.Namespace(), operator.Name()),
+				Path:           path,
+				TrustPolicy:    trustPolicy,
+				Version:        version,
+				CreateCommand:  fmt.Sprintf("rosa create operator-roles --cluster %s", cluster.ID()),
+				UpgradeCommand: fmt.Sprintf("rosa upgrade operator-roles --cluster %s", cluster.ID()),
+			},
+			issuerURL: cluster.AWS().STS().OIDCEndpointURL(),
+		}
+		policy, err := expectedOperatorPolicy(clients, policies, credRequest, operator,
+			operatorPolicyPrefix, path, operatorManaged, hostedCPPolicies)
+		if err != nil {
+			return nil, err
+		}
+		role.Policies = []aws.ExpectedPolicy{policy}
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+// expectedPrefixRoles returns the account roles with the prefix, and the operator roles with the
+// prefix that exist in the account.
+func expectedPrefixRoles(clients *Clients, options VerifyRolesOptions,
+	policies map[string]*cmv1.AWSSTSPolicy) ([]*expectedRole, error) {
+	prefix := options.Prefix
+	accountRoles := aws.AccountRoles
+	if options.HostedCP {
+		accountRoles = aws.HCPAccountRoles
+	}
+	// The path of the account roles is the path of the installer role, if it exists:
+	path := ""
+	installerRoleName := aws.GetRoleName(prefix, accountRoles[aws.InstallerAccountRole].Name)
+	exists, installerRoleARN, err := clients.AWS.CheckRoleExists(installerRoleName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		path, err = aws.GetPathFromARN(installerRoleARN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := []*expectedRole{}
+	for _, roleType := range sortedKeys(accountRoles) {
+		name := aws.GetRoleName(prefix, accountRoles[roleType].Name)
+		managed, err := hasManagedPolicies(clients, name)
+		if err != nil {
+			return nil, err
+		}
+		role, err := expectedAccountRole(clients, options, policies, roleType, name, path,
+			prefix, options.HostedCP, managed || options.HostedCP)
+		if err != nil {
+			return nil, err
+		}
+		role.Version = options.Version
+		result = append(result, role)
+	}
+
+	credRequests, err := clients.OCM.GetCredRequests(options.HostedCP)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting operator credential request from OCM %w", err)
+	}
+	operatorRoles, err := clients.AWS.GetOperatorRolesFromAccountByPrefix(prefix, credRequests)
+	if err != nil {
+		return nil, err
+	}
+	existingOperatorRoles := map[string]bool{}
+	for _, name := range operatorRoles {
+		existingOperatorRoles[name] = true
+	}
+	issuerURL := ""
+	createCommand := ""
+	if options.OidcConfigID != "" {
+		oidcConfig, err := clients.OCM.GetOidcConfig(options.OidcConfigID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get OIDC configuration '%s': %w", options.OidcConfigID, err)
+		}
+		issuerURL = oidcConfig.IssuerUrl()
+		if exists {
+			createCommand = fmt.Sprintf("rosa create operator-roles --prefix %s --oidc-config-id %s "+
+				"--installer-role-arn %s", prefix, options.OidcConfigID, installerRoleARN)
+			if options.HostedCP {
+				createCommand += " --hosted-cp"
+			}
+		}
+	}
+	for _, credRequest := range sortedKeys(credRequests) {
+		operator := credRequests[credRequest]
+		name := aws.GetOperatorRoleName(prefix, operator.Namespace(), operator.Name())
+		if !existingOperatorRoles[name] {
+			continue
+		}
+		_, roleARN, err := clients.AWS.CheckRoleExists(name)
+		if err != nil {
+			return nil, err
+		}
+		managed, err := clients.AWS.HasManagedPolicies(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		role := &expectedRole{
+			ExpectedRole: aws.ExpectedRole{
+				Name:          name,
+				Type:          fmt.Sprintf("%s/%s", operator.Namespace(), operator.Name()),
+				Path:          path,
+				Version:       options.Version,
+				CreateCommand: createCommand,
+			},
+			issuerURL: issuerURL,
+		}
+		if issuerURL != "" {
+			role.TrustPolicy, err = aws.GenerateOperatorRolePolicyDocByOidcEndpointUrl(issuerURL,
+				clients.Creator.AccountID, operator, aws.GetPolicyDetails(policies, "operator_iam_role_policy"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		policy, err := expectedOperatorPolicy(clients, policies, credRequest, operator,
+			prefix, path, managed || options.HostedCP, options.HostedCP)
+		if err != nil {
+			return nil, err
+		}
+		role.Policies = []aws.ExpectedPolicy{policy}
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+func expectedAccountRole(clients *Clients, options VerifyRolesOptions, policies map[string]*cmv1.AWSSTSPolicy,
+	roleType string, name string, path string, prefix string, hostedCP bool, managed bool) (*expectedRole, error) {
+	trustPolicy := aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_trust_policy", roleType)),
+		map[string]string{
+			"partition":      aws.GetPartition(),
+			"aws_account_id": aws.GetJumpAccount(options.Env),
+		})
+	createCommand := fmt.Sprintf("rosa create account-roles --prefix %s", prefix)
+	if hostedCP {
+		createCommand += " --hosted-cp"
+	} else {
+		createCommand += " --classic"
+	}
+	role := &expectedRole{
+		ExpectedRole: aws.ExpectedRole{
+			Name:           name,
+			Type:           aws.AccountRoles[roleType].Name,
+			Path:           path,
+			TrustPolicy:    trustPolicy,
+			CreateCommand:  createCommand,
+			UpgradeCommand: fmt.Sprintf("rosa upgrade account-roles --prefix %s", prefix),
+		},
+	}
+	if !managed {
+		role.Policies = []aws.ExpectedPolicy{{
+			ARN: aws.GetPolicyARN(clients.Creator.AccountID, name, path),
+			Document: aws.GetPolicyDetails(policies,
+				fmt.Sprintf("sts_%s_permission_policy", roleType)),
+		}}
+		return role, nil
+	}
+	policyKeys := aws.GetAccountRolePolicyKeys(roleType)
+	if hostedCP {
+		policyKeys = []string{fmt.Sprintf("sts_hcp_%s_permission_policy", roleType)}
+	}
+	for _, policyKey := range policyKeys {
+		policyARN, err := aws.GetManagedPolicyARN(policies, policyKey)
+		if err != nil {
+			return nil, err
+		}
+		role.Policies = append(role.Policies, aws.ExpectedPolicy{ARN: policyARN})
+	}
+	return role, nil
+}
+
+func expectedOperatorPolicy(clients *Clients, policies map[string]*cmv1.AWSSTSPolicy, credRequest string,
+	operator *cmv1.STSOperator, prefix string, path string, managed bool,
+	hostedCPPolicies bool) (aws.ExpectedPolicy, error) {
+	policyKey := aws.GetOperatorPolicyKey(credRequest, hostedCPPolicies, false)
+	if managed {
+		policyARN, err := aws.GetManagedPolicyARN(policies, policyKey)
+		return aws.ExpectedPolicy{ARN: policyARN}, err
+	}
+	return aws.ExpectedPolicy{
+		ARN:      aws.GetOperatorPolicyARN(clients.Creator.AccountID, prefix, operator.Namespace(), operator.Name(), path),
+		Document: aws.GetPolicyDetails(policies, policyKey),
+	}, nil
+}
+
+// expectedLinkedRoles returns the OCM roles linked to the current organization and the user roles
+// linked to the current account that belong to the AWS account.
+func expectedLinkedRoles(clients *Clients, options VerifyRolesOptions,
+	policies map[string]*cmv1.AWSSTSPolicy) ([]*expectedRole, error) {
+	account, err := clients.OCM.GetCurrentAccount()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get current OCM account: %w", err)
+	}
+	if account == nil {
+		return nil, nil
+	}
+	result := []*expectedRole{}
+
+	ocmRoleARNs, err := clients.OCM.GetOrganizationLinkedOCMRoles(account.Organization().ID())
+	if err != nil {
+		return nil, err
+	}
+	trustPolicy := aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_trust_policy", aws.OCMRolePolicyFile)),
+		map[string]string{
+			"partition":           aws.GetPartition(),
+			"aws_account_id":      aws.GetJumpAccount(options.Env),
+			"ocm_organization_id": account.Organization().ID(),
+		})
+	for _, roleARN := range ocmRoleARNs {
+		role, err := expectedLinkedRole(clients, roleARN, aws.OCMRole, trustPolicy, "rosa create ocm-role")
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		managed, err := clients.AWS.HasManagedPolicies(roleARN)
+		if err != nil {
+			return nil, err
+		}
+		admin, err := clients.AWS.IsAdminRole(role.Name)
+		if err != nil {
+			return nil, err
+		}
+		policyFiles := []string{aws.OCMRolePolicyFile}
+		if admin {
+			policyFiles = append(policyFiles, aws.OCMAdminRolePolicyFile)
+		}
+		for _, policyFile := range policyFiles {
+			policyKey := fmt.Sprintf("sts_%s_permission_policy", policyFile)
+			if managed {
+				policyARN, err := aws.GetManagedPolicyARN(policies, policyKey)
+				if err != nil {
+					return nil, err
+				}
+				role.Policies = append(role.Policies, aws.ExpectedPolicy{ARN: policyARN})
+				continue
+			}
+			policyARN := aws.GetPolicyARN(clients.Creator.AccountID, role.Name, role.Path)
+			if policyFile == aws.OCMAdminRolePolicyFile {
+				policyARN = aws.GetAdminPolicyARN(clients.Creator.AccountID, role.Name, role.Path)
+			}
+			role.Policies = append(role.Policies, aws.ExpectedPolicy{
+				ARN:      policyARN,
+				Document: aws.GetPolicyDetails(policies, policyKey),
+			})
+		}
+		result = append(result, role)
+	}
+
+	userRoleARNs, err := clients.OCM.GetAccountLinkedUserRoles(account.ID())
+	if err != nil {
+		return nil, err
+	}
+	trustPolicy = aws.InterpolatePolicyDocument(
+		aws.GetPolicyDetails(policies, fmt.Sprintf("sts_%s_trust_policy", aws.OCMUserRolePolicyFile)),
+		map[string]string{
+			"partition":      aws.GetPartition(),
+			"aws_account_id": aws.GetJumpAccount(options.Env),
+			"ocm_account_id": account.ID(),
+		})
+	for _, roleARN := range userRoleARNs {
+		role, err := expectedLinkedRole(clients, roleARN, aws.OCMUserRole, trustPolicy, "rosa create user-role")
+		if err != nil {
+			return nil, err
+		}
+		if role != nil {
+			result = append(result, role)
+		}
+	}
+	return result, nil
+}
+
+// expectedLinkedRole returns the expected OCM or user role, or nil if the role belongs to a
+// different AWS account.
+func expectedLinkedRole(clients *Clients, roleARN string, roleType string, trustPolicy string,
+	createCommand string) (*expectedRole, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.AccountID != clients.Creator.AccountID {
+		return nil, nil
+	}
+	name, err := aws.GetResourceIdFromARN(roleARN)
+	if err != nil {
+		return nil, err
+	}
+	path, err := aws.GetPathFromARN(roleARN)
+	if err != nil {
+		return nil, err
+	}
+	return &expectedRole{
+		ExpectedRole: aws.ExpectedRole{
+			Name:          name,
+			Type:          roleType,
+			Path:          path,
+			TrustPolicy:   trustPolicy,
+			CreateCommand: createCommand,
+		},
+	}, nil
+}
+
+// hasManagedPolicies returns true if the role exists and is tagged as using managed policies.
+func hasManagedPolicies(clients *Clients, name string) (bool, error) {
+	exists, roleARN, err := clients.AWS.CheckRoleExists(name)
+	if err != nil || !exists {
+		return false, err
+	}
+	return clients.AWS.HasManagedPolicies(roleARN)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
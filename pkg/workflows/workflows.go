@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workflows contains the operations behind commands like 'rosa create operator-roles',
+// 'rosa create oidc-config', 'rosa verify', 'rosa rotate' and 'rosa delete orphans', like creating
+// the operator roles of a cluster or an OIDC configuration, verifying roles, rotating the keys of
+// an OIDC configuration, or finding the resources that no cluster uses anymore, so that they can
+// also be used by other programs. The workflows don't read command line flags, don't prompt and
+// don't exit: they take explicit options and clients, and return their results or an error.
+//
+// Creating clusters isn't one of the workflows: resolving the defaults of the options, validating
+// them and building the specification is still done by 'rosa create cluster'. Other programs
+// submit their own specification with the CreateClusterWithAWS method of the OCM client.
+package workflows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Clients are the connections used by the workflows. The creator is the AWS identity that owns
+// the resources, as returned by the GetCreator method of the AWS client.
+type Clients struct {
+	OCM     *ocm.Client
+	AWS     aws.Client
+	Creator *aws.Creator
+}
+
+// validate checks that the clients needed by a workflow have been set. The context is checked as
+// well, so that a workflow that has already been cancelled doesn't start.
+func (c *Clients) validate(ctx context.Context, needsAWS bool) error {
+	if c.OCM == nil {
+		return fmt.Errorf("OCM client is mandatory")
+	}
+	if needsAWS {
+		if c.AWS == nil {
+			return fmt.Errorf("AWS client is mandatory")
+		}
+		if c.Creator == nil {
+			return fmt.Errorf("AWS creator is mandatory")
+		}
+	}
+	return ctx.Err()
+}
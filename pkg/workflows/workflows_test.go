@@ -0,0 +1,807 @@
+/*
+Copyright (c) 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflows_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //#nosec GSC-G505 -- Import blacklist: crypto/sha1
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	iamsdk "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	. "github.com/openshift-online/ocm-sdk-go/testing"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+	"github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/exitcode"
+	"github.com/openshift/rosa/pkg/helper/oidc_config"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/test"
+	"github.com/openshift/rosa/pkg/workflows"
+)
+
+var _ = Describe("Workflows", func() {
+	var (
+		apiServer  *ghttp.Server
+		mockCtrl   *gomock.Controller
+		mockIamAPI *mocks.MockIAMAPI
+		mockS3API  *mocks.MockS3API
+		mockSMAPI  *mocks.MockSecretsManagerAPI
+		clients    *workflows.Clients
+	)
+
+	BeforeEach(func() {
+		apiServer = MakeTCPServer()
+		apiServer.SetAllowUnhandledRequests(true)
+		apiServer.SetUnhandledRequestStatusCode(http.StatusInternalServerError)
+
+		logger, err := logging.NewGoLoggerBuilder().
+			Debug(false).
+			Build()
+		Expect(err).To(BeNil())
+		connection, err := sdk.NewConnectionBuilder().
+			Logger(logger).
+			Tokens(MakeTokenString("Bearer", 15*time.Minute)).
+			URL(apiServer.URL()).
+			Build()
+		Expect(err).To(BeNil())
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
+		mockS3API = mocks.NewMockS3API(mockCtrl)
+		mockSMAPI = mocks.NewMockSecretsManagerAPI(mockCtrl)
+		clients = &workflows.Clients{
+			OCM: ocm.NewClientWithConnection(connection),
+			AWS: aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mockS3API,
+				mockSMAPI,
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				&session.Session{Config: &awssdk.Config{Region: awssdk.String("us-east-1")}},
+				&aws.AccessKey{},
+				false,
+			),
+			Creator: &aws.Creator{
+				ARN:       "arn:aws:iam::123456789012:user/fake",
+				AccountID: "123456789012",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		apiServer.Close()
+	})
+
+	Context("Clients", func() {
+		It("Fails if the AWS client is missing", func() {
+			clients.AWS = nil
+			_, err := workflows.CreateOidcConfig(context.Background(), clients, workflows.OidcConfigOptions{
+				Region: "us-east-1",
+			})
+			Expect(err).To(MatchError("AWS client is mandatory"))
+		})
+		It("Doesn't need the AWS client for managed OIDC configurations", func() {
+			clients.AWS = nil
+			clients.Creator = nil
+			apiServer.AppendHandlers(RespondWithJSON(http.StatusCreated, `{
+				"kind": "OidcConfig",
+				"id": "123",
+				"managed": true,
+				"issuer_url": "https://oidc.example.com/123"
+			}`))
+			result, err := workflows.CreateOidcConfig(context.Background(), clients, workflows.OidcConfigOptions{
+				Managed: true,
+			})
+			Expect(err).To(BeNil())
+			Expect(result.OidcConfig.ID()).To(Equal("123"))
+			Expect(result.OidcConfig.IssuerUrl()).To(Equal("https://oidc.example.com/123"))
+			Expect(result.BucketName).To(BeEmpty())
+		})
+		It("Doesn't start if the context has been cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := workflows.CreateOidcConfig(ctx, clients, workflows.OidcConfigOptions{
+				Managed: true,
+			})
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+
+	Context("CreateOidcConfig", func() {
+		It("Returns the OCM error for managed OIDC configurations", func() {
+			apiServer.AppendHandlers(RespondWithJSON(http.StatusForbidden, `{
+				"kind": "Error",
+				"id": "403",
+				"reason": "Forbidden"
+			}`))
+			_, err := workflows.CreateOidcConfig(context.Background(), clients, workflows.OidcConfigOptions{
+				Managed: true,
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("managed OIDC Configuration"))
+			Expect(exitcode.Of(err)).To(Equal(exitcode.Forbidden))
+		})
+		It("Rejects prefixes that are too long", func() {
+			_, err := workflows.CreateOidcConfig(context.Background(), clients, workflows.OidcConfigOptions{
+				Prefix: "a-prefix-that-is-too-long",
+				Region: "us-east-1",
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(exitcode.Of(err)).To(Equal(exitcode.InvalidInput))
+		})
+		It("Rejects invalid installer role ARNs", func() {
+			_, err := workflows.CreateOidcConfig(context.Background(), clients, workflows.OidcConfigOptions{
+				Region:           "us-east-1",
+				InstallerRoleARN: "not-an-arn",
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("Expected a valid ARN"))
+			Expect(exitcode.Of(err)).To(Equal(exitcode.InvalidInput))
+		})
+	})
+
+	Context("CreateOperatorRoles", func() {
+		It("Requires a cluster", func() {
+			_, err := workflows.CreateOperatorRoles(context.Background(), clients, workflows.OperatorRolesOptions{})
+			Expect(err).To(MatchError("Cluster is mandatory"))
+		})
+		It("Rejects clusters that don't use STS", func() {
+			cluster, err := test.MockOCMCluster(func(c *cmv1.ClusterBuilder) {
+				c.ID("abc")
+			})
+			Expect(err).To(BeNil())
+			_, err = workflows.CreateOperatorRoles(context.Background(), clients, workflows.OperatorRolesOptions{
+				Cluster: cluster,
+			})
+			Expect(err).To(MatchError("Cluster 'abc' is not an STS cluster."))
+			Expect(exitcode.Of(err)).To(Equal(exitcode.InvalidInput))
+		})
+
+		Context("With an STS cluster", func() {
+			const (
+				installerRoleARN = "arn:aws:iam::123456789012:role/test/prefix-Installer-Role"
+				operatorRoleName = "op-openshift-ingress-operator-cloud-credentials"
+			)
+
+			mockSTSCluster := func(managedPolicies bool) *cmv1.Cluster {
+				cluster, err := test.MockOCMCluster(func(c *cmv1.ClusterBuilder) {
+					c.ID("abc")
+					c.Version(cmv1.NewVersion().ID("openshift-v4.13.0").ChannelGroup("stable"))
+					c.AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+						RoleARN(installerRoleARN).
+						OIDCEndpointURL("https://oidc.example.com/abc").
+						ManagedPolicies(managedPolicies).
+						OperatorIAMRoles(cmv1.NewOperatorIAMRole().
+							Name("cloud-credentials").
+							Namespace("openshift-ingress-operator").
+							RoleARN("arn:aws:iam::123456789012:role/test/" + operatorRoleName))))
+				})
+				Expect(err).To(BeNil())
+				return cluster
+			}
+
+			policies := func(managed bool) map[string]*cmv1.AWSSTSPolicy {
+				operatorPolicy := cmv1.NewAWSSTSPolicy().Details(`{"Version":"2012-10-17"}`)
+				if managed {
+					operatorPolicy.ARN("arn:aws:iam::aws:policy/service-role/ROSAIngressOperatorPolicy")
+				}
+				result := map[string]*cmv1.AWSSTSPolicy{}
+				for key, builder := range map[string]*cmv1.AWSSTSPolicyBuilder{
+					"openshift_ingress_operator_cloud_credentials_policy": operatorPolicy,
+					"operator_iam_role_policy": cmv1.NewAWSSTSPolicy().
+						Details(`{"Federated": "%{oidc_provider_arn}", "sub": ["%{service_accounts}"]}`),
+				} {
+					policy, err := builder.Build()
+					Expect(err).To(BeNil())
+					result[key] = policy
+				}
+				return result
+			}
+
+			BeforeEach(func() {
+				apiServer.AppendHandlers(RespondWithJSON(http.StatusOK,
+					`{"kind": "STSCredentialRequestList", "page": 1, "size": 1, "total": 1, "items": [`+
+						`{"name": "ingress_operator_cloud_credentials", "operator": {"name": "cloud-credentials", `+
+						`"namespace": "openshift-ingress-operator", "service_accounts": ["ingress-operator"]}}]}`))
+			})
+
+			expectRoleCreation := func(managedPolicies bool, policyARN string) {
+				gomock.InOrder(
+					mockIamAPI.EXPECT().GetRole(&iamsdk.GetRoleInput{
+						RoleName: awssdk.String(operatorRoleName),
+					}).Return(nil, awserr.New(iamsdk.ErrCodeNoSuchEntityException, "not found", nil)),
+					mockIamAPI.EXPECT().CreateRole(gomock.Any()).DoAndReturn(
+						func(input *iamsdk.CreateRoleInput) (*iamsdk.CreateRoleOutput, error) {
+							Expect(awssdk.StringValue(input.Path)).To(Equal("/test/"))
+							Expect(awssdk.StringValue(input.AssumeRolePolicyDocument)).To(ContainSubstring(
+								"system:serviceaccount:openshift-ingress-operator:ingress-operator"))
+							roleTags := map[string]string{}
+							for _, tag := range input.Tags {
+								roleTags[awssdk.StringValue(tag.Key)] = awssdk.StringValue(tag.Value)
+							}
+							Expect(roleTags).To(HaveKeyWithValue(tags.ClusterID, "abc"))
+							if managedPolicies {
+								Expect(roleTags).To(HaveKeyWithValue(tags.ManagedPolicies, "true"))
+							} else {
+								Expect(roleTags).ToNot(HaveKey(tags.ManagedPolicies))
+							}
+							return &iamsdk.CreateRoleOutput{Role: &iamsdk.Role{
+								Arn: awssdk.String("arn:aws:iam::123456789012:role/test/" + operatorRoleName),
+							}}, nil
+						}),
+					mockIamAPI.EXPECT().AttachRolePolicy(&iamsdk.AttachRolePolicyInput{
+						RoleName:  awssdk.String(operatorRoleName),
+						PolicyArn: awssdk.String(policyARN),
+					}).Return(&iamsdk.AttachRolePolicyOutput{}, nil),
+				)
+			}
+
+			BeforeEach(func() {
+				mockIamAPI.EXPECT().GetRole(&iamsdk.GetRoleInput{
+					RoleName: awssdk.String("prefix-Installer-Role"),
+				}).Return(&iamsdk.GetRoleOutput{Role: &iamsdk.Role{
+					Arn: awssdk.String(installerRoleARN),
+					Tags: []*iamsdk.Tag{{
+						Key:   awssdk.String(tags.OpenShiftVersion),
+						Value: awssdk.String("4.13"),
+					}},
+				}}, nil)
+			})
+
+			It("Creates the roles and their unmanaged policies", func() {
+				policyARN := "arn:aws:iam::123456789012:policy/test/" +
+					"prefix-openshift-ingress-operator-cloud-credentials"
+				mockIamAPI.EXPECT().GetPolicy(gomock.Any()).Return(nil,
+					awserr.New(iamsdk.ErrCodeNoSuchEntityException, "not found", nil))
+				mockIamAPI.EXPECT().CreatePolicy(gomock.Any()).DoAndReturn(
+					func(input *iamsdk.CreatePolicyInput) (*iamsdk.CreatePolicyOutput, error) {
+						Expect(awssdk.StringValue(input.PolicyName)).To(Equal(
+							"prefix-openshift-ingress-operator-cloud-credentials"))
+						Expect(awssdk.StringValue(input.Path)).To(Equal("/test/"))
+						return &iamsdk.CreatePolicyOutput{Policy: &iamsdk.Policy{
+							Arn: awssdk.String(policyARN),
+						}}, nil
+					})
+				expectRoleCreation(false, policyARN)
+
+				result, err := workflows.CreateOperatorRoles(context.Background(), clients,
+					workflows.OperatorRolesOptions{
+						Cluster:       mockSTSCluster(false),
+						Policies:      policies(false),
+						PolicyVersion: "4.13",
+					})
+				Expect(err).To(BeNil())
+				Expect(result.Skipped).To(BeFalse())
+				Expect(result.Roles).To(Equal([]workflows.OperatorRole{{
+					Name:      operatorRoleName,
+					ARN:       "arn:aws:iam::123456789012:role/test/" + operatorRoleName,
+					PolicyARN: policyARN,
+					Namespace: "openshift-ingress-operator",
+					Operator:  "cloud-credentials",
+				}}))
+			})
+
+			It("Attaches the managed policies to the roles", func() {
+				policyARN := "arn:aws:iam::aws:policy/service-role/ROSAIngressOperatorPolicy"
+				expectRoleCreation(true, policyARN)
+
+				result, err := workflows.CreateOperatorRoles(context.Background(), clients,
+					workflows.OperatorRolesOptions{
+						Cluster:       mockSTSCluster(true),
+						Policies:      policies(true),
+						PolicyVersion: "4.13",
+					})
+				Expect(err).To(BeNil())
+				Expect(result.Roles).To(HaveLen(1))
+				Expect(result.Roles[0].Name).To(Equal(operatorRoleName))
+				Expect(result.Roles[0].PolicyARN).To(Equal(policyARN))
+			})
+		})
+	})
+
+	Context("FindOrphans", func() {
+		It("Rejects invalid types", func() {
+			_, err := workflows.FindOrphans(context.Background(), clients, workflows.FindOrphansOptions{
+				Types: []string{"bucket"},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("Invalid orphan type 'bucket'"))
+		})
+		It("Returns the OIDC providers that no cluster uses", func() {
+			providerARN := "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"
+			mockIamAPI.EXPECT().ListOpenIDConnectProviders(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProvidersOutput{
+					OpenIDConnectProviderList: []*iamsdk.OpenIDConnectProviderListEntry{{
+						Arn: awssdk.String(providerARN),
+					}},
+				}, nil)
+			mockIamAPI.EXPECT().ListOpenIDConnectProviderTags(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProviderTagsOutput{
+					IsTruncated: awssdk.Bool(false),
+					Tags: []*iamsdk.Tag{{
+						Key:   awssdk.String(tags.RedHatManaged),
+						Value: awssdk.String("true"),
+					}, {
+						Key:   awssdk.String(tags.ClusterID),
+						Value: awssdk.String("123"),
+					}},
+				}, nil)
+			apiServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("search", "aws.sts.oidc_endpoint_url = 'https://oidc.example.com/abc' "+
+						"AND aws.sts.role_arn like '%123456789012%'"),
+					RespondWithJSON(http.StatusOK, `{"kind": "ClusterList", "page": 1, "size": 0, "total": 0}`),
+				),
+			)
+			orphans, err := workflows.FindOrphans(context.Background(), clients, workflows.FindOrphansOptions{
+				Types: []string{workflows.OrphanOidcProvider},
+			})
+			Expect(err).To(BeNil())
+			Expect(orphans).To(HaveLen(1))
+			Expect(orphans[0].Type).To(Equal(workflows.OrphanOidcProvider))
+			Expect(orphans[0].ARN).To(Equal(providerARN))
+			Expect(orphans[0].ClusterID).To(Equal("123"))
+			Expect(orphans[0].Reason).To(ContainSubstring("visible to this OCM organization"))
+			Expect(orphans[0].Reason).To(ContainSubstring("created for cluster '123'"))
+
+			commands, err := workflows.OrphanCommands(clients, orphans[0])
+			Expect(err).To(BeNil())
+			Expect(commands).To(HaveLen(1))
+			Expect(commands[0]).To(ContainSubstring("aws iam delete-open-id-connect-provider"))
+			Expect(commands[0]).To(ContainSubstring(providerARN))
+		})
+		It("Ignores the OIDC providers used by a cluster", func() {
+			mockIamAPI.EXPECT().ListOpenIDConnectProviders(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProvidersOutput{
+					OpenIDConnectProviderList: []*iamsdk.OpenIDConnectProviderListEntry{{
+						Arn: awssdk.String("arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"),
+					}},
+				}, nil)
+			mockIamAPI.EXPECT().ListOpenIDConnectProviderTags(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProviderTagsOutput{
+					IsTruncated: awssdk.Bool(false),
+					Tags: []*iamsdk.Tag{{
+						Key:   awssdk.String(tags.RedHatManaged),
+						Value: awssdk.String("true"),
+					}},
+				}, nil)
+			apiServer.AppendHandlers(
+				RespondWithJSON(http.StatusOK, `{"kind": "ClusterList", "page": 1, "size": 1, "total": 1}`),
+			)
+			orphans, err := workflows.FindOrphans(context.Background(), clients, workflows.FindOrphansOptions{
+				Types: []string{workflows.OrphanOidcProvider},
+			})
+			Expect(err).To(BeNil())
+			Expect(orphans).To(BeEmpty())
+		})
+	})
+
+	Context("OIDC config key rotation", func() {
+		const (
+			secretARN = "arn:aws:secretsmanager:us-east-1:123456789012:secret:" +
+				"rosa-private-key-test-oidc-abcd-Xy12Zw"
+			bucketName = "test-oidc-abcd"
+		)
+		var (
+			oldPrivateKey []byte
+			oldJwks       []byte
+			oldKeyID      string
+		)
+
+		BeforeEach(func() {
+			var oldPublicKey []byte
+			var err error
+			oldPrivateKey, oldPublicKey, err = oidc_config.CreateKeyPair()
+			Expect(err).To(BeNil())
+			oldJwks, err = oidc_config.BuildJSONWebKeySet(oldPublicKey)
+			Expect(err).To(BeNil())
+			keySet, err := oidc_config.ParseJSONWebKeySet(oldJwks)
+			Expect(err).To(BeNil())
+			oldKeyID = keySet.KeyIDs()[0]
+		})
+
+		respondWithOidcConfig := func(managed bool) {
+			body := fmt.Sprintf(`{"kind": "OidcConfig", "id": "123", "managed": %t, "secret_arn": "%s", `+
+				`"issuer_url": "https://%s.s3.us-east-1.amazonaws.com"}`, managed, secretARN, bucketName)
+			apiServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/oidc_configs/123"),
+					RespondWithJSON(http.StatusOK, body),
+				),
+			)
+		}
+
+		expectJwks := func(jwks []byte) {
+			mockS3API.EXPECT().GetObject(gomock.Any()).Return(&s3.GetObjectOutput{
+				Body: io.NopCloser(bytes.NewReader(jwks)),
+			}, nil)
+		}
+
+		It("Rejects managed OIDC configs", func() {
+			respondWithOidcConfig(true)
+			_, err := workflows.PrepareOidcConfigKeyRotation(context.Background(), clients,
+				workflows.OidcConfigKeysOptions{OidcConfigID: "123"})
+			Expect(err).ToNot(BeNil())
+			Expect(exitcode.Of(err)).To(Equal(exitcode.InvalidInput))
+		})
+		It("Publishes the new key along with the old one before updating the secret", func() {
+			respondWithOidcConfig(false)
+			expectJwks(oldJwks)
+			rotation, err := workflows.PrepareOidcConfigKeyRotation(context.Background(), clients,
+				workflows.OidcConfigKeysOptions{OidcConfigID: "123"})
+			Expect(err).To(BeNil())
+			Expect(rotation.BucketName).To(Equal(bucketName))
+			Expect(rotation.PrivateKey).ToNot(BeEmpty())
+			Expect(rotation.KeyIDs).To(HaveLen(2))
+			Expect(rotation.KeyIDs[1]).To(Equal(oldKeyID))
+			Expect(rotation.RetiredKeyIDs).To(BeEmpty())
+
+			gomock.InOrder(
+				mockS3API.EXPECT().PutObject(gomock.Any()).DoAndReturn(
+					func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+						Expect(awssdk.StringValue(input.Bucket)).To(Equal(bucketName))
+						Expect(awssdk.StringValue(input.Key)).To(Equal(workflows.JwksKey))
+						return &s3.PutObjectOutput{}, nil
+					}),
+				mockSMAPI.EXPECT().PutSecretValue(gomock.Any()).DoAndReturn(
+					func(input *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+						Expect(awssdk.StringValue(input.SecretId)).To(Equal(secretARN))
+						Expect(awssdk.StringValue(input.SecretString)).To(Equal(string(rotation.PrivateKey)))
+						return &secretsmanager.PutSecretValueOutput{}, nil
+					}),
+			)
+			err = workflows.ApplyOidcConfigKeyRotation(context.Background(), clients, rotation)
+			Expect(err).To(BeNil())
+
+			commands := workflows.OidcConfigKeyRotationCommands(rotation, "jwks.json", "private.key")
+			Expect(commands).To(HaveLen(4))
+			Expect(commands[0]).To(ContainSubstring("aws s3api put-object"))
+			Expect(commands[2]).To(ContainSubstring("aws secretsmanager put-secret-value"))
+		})
+		It("Retires the keys that aren't stored in the secret", func() {
+			newPrivateKey, newPublicKey, err := oidc_config.CreateKeyPair()
+			Expect(err).To(BeNil())
+			jwks, err := oidc_config.AddJSONWebKey(oldJwks, newPublicKey)
+			Expect(err).To(BeNil())
+
+			respondWithOidcConfig(false)
+			expectJwks(jwks)
+			mockSMAPI.EXPECT().GetSecretValue(gomock.Any()).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: awssdk.String(string(newPrivateKey)),
+			}, nil)
+			rotation, err := workflows.PrepareOidcConfigKeyRotation(context.Background(), clients,
+				workflows.OidcConfigKeysOptions{OidcConfigID: "123", Retire: true})
+			Expect(err).To(BeNil())
+			Expect(rotation.PrivateKey).To(BeEmpty())
+			Expect(rotation.KeyIDs).To(HaveLen(1))
+			Expect(rotation.KeyIDs[0]).ToNot(Equal(oldKeyID))
+			Expect(rotation.RetiredKeyIDs).To(Equal([]string{oldKeyID}))
+
+			commands := workflows.OidcConfigKeyRotationCommands(rotation, "jwks.json", "")
+			Expect(commands).To(HaveLen(2))
+		})
+		It("Doesn't retire the key stored in the secret", func() {
+			respondWithOidcConfig(false)
+			expectJwks([]byte(`{"keys": []}`))
+			mockSMAPI.EXPECT().GetSecretValue(gomock.Any()).Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: awssdk.String(string(oldPrivateKey)),
+			}, nil)
+			_, err := workflows.PrepareOidcConfigKeyRotation(context.Background(), clients,
+				workflows.OidcConfigKeysOptions{OidcConfigID: "123", Retire: true})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("isn't published"))
+		})
+	})
+
+	Context("VerifyOidcConfig", func() {
+		var (
+			issuerServer *ghttp.Server
+			issuerURL    string
+			providerARN  string
+			jwks         []byte
+			thumbprint   string
+		)
+
+		BeforeEach(func() {
+			issuerServer = ghttp.NewTLSServer()
+			issuerURL = issuerServer.URL()
+			providerARN = "arn:aws:iam::123456789012:oidc-provider/" + issuerServer.Addr()
+			_, publicKey, err := oidc_config.CreateKeyPair()
+			Expect(err).To(BeNil())
+			jwks, err = oidc_config.BuildJSONWebKeySet(publicKey)
+			Expect(err).To(BeNil())
+			// nolint:gosec
+			hash := sha1.Sum(issuerServer.HTTPTestServer.Certificate().Raw)
+			thumbprint = hex.EncodeToString(hash[:])
+		})
+
+		AfterEach(func() {
+			issuerServer.Close()
+		})
+
+		serveDocuments := func(discoveryDocument string, jwks []byte) {
+			issuerServer.RouteToHandler(http.MethodGet, "/"+workflows.DiscoveryDocumentKey,
+				ghttp.RespondWith(http.StatusOK, discoveryDocument))
+			issuerServer.RouteToHandler(http.MethodGet, "/"+workflows.JwksKey,
+				ghttp.RespondWith(http.StatusOK, jwks))
+		}
+
+		expectProvider := func(clientIDs ...string) {
+			mockIamAPI.EXPECT().ListOpenIDConnectProviders(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProvidersOutput{
+					OpenIDConnectProviderList: []*iamsdk.OpenIDConnectProviderListEntry{{
+						Arn: awssdk.String(providerARN),
+					}},
+				}, nil)
+			mockIamAPI.EXPECT().GetOpenIDConnectProvider(gomock.Any()).Return(
+				&iamsdk.GetOpenIDConnectProviderOutput{
+					Url:            awssdk.String(issuerServer.Addr()),
+					ClientIDList:   awssdk.StringSlice(clientIDs),
+					ThumbprintList: awssdk.StringSlice([]string{thumbprint}),
+				}, nil)
+		}
+
+		verify := func() *workflows.OidcConfigVerification {
+			verification, err := workflows.VerifyOidcConfig(context.Background(), clients,
+				workflows.VerifyOidcConfigOptions{
+					IssuerURL:  issuerURL,
+					HTTPClient: issuerServer.HTTPTestServer.Client(),
+				})
+			Expect(err).To(BeNil())
+			return verification
+		}
+
+		checks := func(verification *workflows.OidcConfigVerification) []string {
+			result := []string{}
+			for _, issue := range verification.Issues {
+				result = append(result, issue.Check)
+			}
+			return result
+		}
+
+		It("Accepts the documents generated by ROSA", func() {
+			serveDocuments(oidc_config.GenerateDiscoveryDocument(issuerURL), jwks)
+			expectProvider(aws.OIDCClientIDOpenShift, aws.OIDCClientIDSTSAWS)
+			verification := verify()
+			Expect(verification.Issues).To(BeEmpty())
+			Expect(verification.ProviderARN).To(Equal(providerARN))
+			Expect(verification.Thumbprint).To(Equal(thumbprint))
+			Expect(verification.KeyIDs).To(HaveLen(1))
+		})
+		It("Reports an issuer that doesn't match the discovery document", func() {
+			serveDocuments(oidc_config.GenerateDiscoveryDocument("https://other.example.com"), jwks)
+			expectProvider(aws.OIDCClientIDOpenShift, aws.OIDCClientIDSTSAWS)
+			verification := verify()
+			Expect(checks(verification)).To(ContainElement(workflows.OidcCheckDiscoveryDocument))
+			Expect(verification.Issues[0].Message).To(ContainSubstring("https://other.example.com"))
+		})
+		It("Reports keys whose ID doesn't match the public key", func() {
+			keySet, err := oidc_config.ParseJSONWebKeySet(jwks)
+			Expect(err).To(BeNil())
+			keySet.Keys[0].KeyID = "invalid"
+			invalidJwks, err := json.Marshal(keySet)
+			Expect(err).To(BeNil())
+			serveDocuments(oidc_config.GenerateDiscoveryDocument(issuerURL), invalidJwks)
+			expectProvider(aws.OIDCClientIDOpenShift, aws.OIDCClientIDSTSAWS)
+			verification := verify()
+			Expect(checks(verification)).To(Equal([]string{workflows.OidcCheckKeys}))
+		})
+		It("Reports unreachable documents and missing client IDs", func() {
+			issuerServer.SetAllowUnhandledRequests(true)
+			issuerServer.SetUnhandledRequestStatusCode(http.StatusNotFound)
+			expectProvider(aws.OIDCClientIDOpenShift)
+			verification := verify()
+			Expect(checks(verification)).To(Equal([]string{
+				workflows.OidcCheckReachability,
+				workflows.OidcCheckProvider,
+			}))
+			Expect(verification.Issues[1].Remediation[0]).To(ContainSubstring(aws.OIDCClientIDSTSAWS))
+		})
+		It("Reports a missing OIDC provider", func() {
+			serveDocuments(oidc_config.GenerateDiscoveryDocument(issuerURL), jwks)
+			mockIamAPI.EXPECT().ListOpenIDConnectProviders(gomock.Any()).Return(
+				&iamsdk.ListOpenIDConnectProvidersOutput{}, nil)
+			verification := verify()
+			Expect(checks(verification)).To(Equal([]string{workflows.OidcCheckProvider}))
+			Expect(verification.Issues[0].Remediation[0]).To(ContainSubstring("rosa create oidc-provider"))
+		})
+	})
+
+	Context("Thumbprint", func() {
+		It("Fails for invalid URLs", func() {
+			_, err := workflows.Thumbprint("://invalid")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("Verify permissions", func() {
+		respondWithPolicies := func() {
+			policies := map[string]string{
+				"sts_installer_permission_policy": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":["ec2:RunInstances","iam:PassRole"],"Resource":"*"}]}`,
+				"sts_instance_controlplane_permission_policy": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"ec2:DescribeInstances","Resource":"*"}]}`,
+				"sts_instance_worker_permission_policy": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"ec2:DescribeRegions","Resource":"*"}]}`,
+				"sts_support_permission_policy": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"cloudtrail:LookupEvents","Resource":"*"}]}`,
+				"openshift_ingress_operator_cloud_credentials_policy": `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"route53:ChangeResourceRecordSets","Resource":"*",
+					 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}}]}`,
+			}
+			items := []map[string]string{}
+			for _, key := range []string{
+				"sts_installer_permission_policy",
+				"sts_instance_controlplane_permission_policy",
+				"sts_instance_worker_permission_policy",
+				"sts_support_permission_policy",
+				"openshift_ingress_operator_cloud_credentials_policy",
+			} {
+				items = append(items, map[string]string{"kind": "AWSSTSPolicy", "id": key, "details": policies[key]})
+			}
+			body, err := json.Marshal(map[string]interface{}{
+				"kind":  "AWSSTSPolicyList",
+				"page":  1,
+				"size":  len(items),
+				"total": len(items),
+				"items": items,
+			})
+			Expect(err).To(BeNil())
+			apiServer.AppendHandlers(
+				RespondWithJSON(http.StatusOK, string(body)),
+				RespondWithJSON(http.StatusOK, string(body)),
+				RespondWithJSON(http.StatusOK, `{"kind": "STSCredentialRequestList", "page": 1, "size": 1, `+
+					`"total": 1, "items": [{"name": "ingress_operator_cloud_credentials", `+
+					`"operator": {"name": "cloud-credentials", "namespace": "openshift-ingress-operator"}}]}`),
+			)
+		}
+
+		It("Fails without a permissions boundary or an SCP", func() {
+			_, err := workflows.VerifyPermissions(context.Background(), clients, workflows.VerifyPermissionsOptions{})
+			Expect(err).To(MatchError(ContainSubstring("Either a permissions boundary or a service control policy")))
+		})
+
+		It("Reports the permissions denied by the permissions boundary and the SCPs", func() {
+			respondWithPolicies()
+			clients.AWS = nil
+			denied, err := workflows.VerifyPermissions(context.Background(), clients, workflows.VerifyPermissionsOptions{
+				PermissionsBoundary: `{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`,
+				SCPs: []string{`{"Version":"2012-10-17","Statement":[
+					{"Effect":"Allow","Action":"*","Resource":"*"},
+					{"Effect":"Deny","Action":"route53:*","Resource":"*",
+					 "Condition":{"StringEquals":{"aws:ResourceTag/red-hat-managed":"true"}}},
+					{"Effect":"Deny","Action":"*","Resource":"*",
+					 "Condition":{"StringNotEquals":{"aws:RequestedRegion":"us-east-1"}}}]}`},
+				Region: "us-east-1",
+			})
+			Expect(err).To(BeNil())
+			Expect(denied).To(HaveLen(2))
+			Expect(denied[0].Policy).To(Equal("sts_installer_permission_policy"))
+			Expect(denied[0].Action).To(Equal("iam:PassRole"))
+			Expect(denied[0].Decision).To(Equal(aws.PolicyDecisionImplicitDeny))
+			Expect(denied[0].DeniedBy).To(Equal(aws.PolicyKindPermissionsBoundary))
+			Expect(denied[1].Policy).To(Equal("openshift_ingress_operator_cloud_credentials_policy"))
+			Expect(denied[1].Action).To(Equal("route53:ChangeResourceRecordSets"))
+			Expect(denied[1].Decision).To(Equal(aws.PolicyDecisionExplicitDeny))
+			Expect(denied[1].DeniedBy).To(Equal(aws.PolicyKindSCP))
+		})
+	})
+
+	Context("Quota", func() {
+		respondWithMachineTypes := func() {
+			machineType := func(id string, cpu int) string {
+				return fmt.Sprintf(`{"kind": "MachineType", "id": "%s", "category": "general_purpose", `+
+					`"cpu": {"value": %d, "unit": "vCPU"}}`, id, cpu)
+			}
+			apiServer.AppendHandlers(
+				RespondWithJSON(http.StatusOK, fmt.Sprintf(`{"kind": "MachineTypeList", "page": 1, "size": 5, `+
+					`"total": 5, "items": [%s, %s, %s, %s, %s]}`,
+					machineType("m5.xlarge", 4), machineType("m5.2xlarge", 8), machineType("m5.4xlarge", 16),
+					machineType("r5.xlarge", 4), machineType("r5.2xlarge", 8))),
+				RespondWithJSON(http.StatusOK, `{"kind": "Account", "id": "123", "organization": {"id": "456"}}`),
+				RespondWithJSON(http.StatusOK, `{"kind": "QuotaCostList", "page": 1, "size": 0, "total": 0}`),
+			)
+		}
+
+		It("Builds the specification of a classic multi-AZ cluster", func() {
+			respondWithMachineTypes()
+			spec, err := workflows.BuildQuotaSpec(clients, workflows.QuotaOptions{
+				Cluster:            true,
+				MultiAZ:            true,
+				ComputeMachineType: "m5.xlarge",
+				ComputeNodes:       30,
+			})
+			Expect(err).To(BeNil())
+			Expect(spec.Nodes).To(HaveLen(4))
+			Expect(spec.Nodes[0]).To(Equal(aws.QuotaNodes{
+				InstanceType: "m5.xlarge",
+				VCPUs:        4,
+				Count:        30,
+				VolumeType:   aws.VolumeTypeGP3,
+				VolumeSize:   workflows.DefaultComputeRootDiskSize,
+			}))
+			Expect(spec.Nodes[1].InstanceType).To(Equal("m5.4xlarge"))
+			Expect(spec.Nodes[1].VCPUs).To(Equal(16))
+			Expect(spec.Nodes[1].Count).To(Equal(3))
+			Expect(spec.Nodes[1].VolumeType).To(Equal(aws.VolumeTypeIO1))
+			Expect(spec.Nodes[2].InstanceType).To(Equal("r5.2xlarge"))
+			Expect(spec.Nodes[2].Count).To(Equal(3))
+			Expect(spec.Nodes[3].InstanceType).To(Equal("m5.xlarge"))
+			Expect(spec.Nodes[3].Count).To(Equal(1))
+			Expect(spec.VPCs).To(Equal(1))
+			Expect(spec.InternetGateways).To(Equal(1))
+			Expect(spec.NatGateways).To(Equal(3))
+			Expect(spec.NetworkLoadBalancers).To(Equal(2))
+			Expect(spec.ClassicLoadBalancers).To(Equal(1))
+		})
+
+		It("Only counts the compute nodes of a machine pool", func() {
+			respondWithMachineTypes()
+			spec, err := workflows.BuildQuotaSpec(clients, workflows.QuotaOptions{
+				ComputeMachineType:  "m5.2xlarge",
+				ComputeNodes:        4,
+				ComputeRootDiskSize: 500,
+			})
+			Expect(err).To(BeNil())
+			Expect(spec).To(Equal(&aws.QuotaSpec{
+				Nodes: []aws.QuotaNodes{{
+					InstanceType: "m5.2xlarge",
+					VCPUs:        8,
+					Count:        4,
+					VolumeType:   aws.VolumeTypeGP3,
+					VolumeSize:   500,
+				}},
+			}))
+		})
+
+		It("Fails if the machine type isn't in the catalogue", func() {
+			respondWithMachineTypes()
+			_, err := workflows.BuildQuotaSpec(clients, workflows.QuotaOptions{
+				ComputeMachineType: "z9.huge",
+				ComputeNodes:       2,
+			})
+			Expect(err).To(MatchError("Machine type 'z9.huge' not found"))
+			Expect(exitcode.Of(err)).To(Equal(exitcode.InvalidInput))
+		})
+	})
+})